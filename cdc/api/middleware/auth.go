@@ -0,0 +1,280 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/security"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// tidbTopologyPrefix is the prefix of the keys of TiDB servers in the etcd
+	// of PD, e.g. `/topology/tidb/127.0.0.1:4000/info`.
+	tidbTopologyPrefix = "/topology/tidb/"
+	verifyUserTimeout  = 5 * time.Second
+	// verifiedUserTTL is how long a verified user and password is trusted
+	// without logging in TiDB again.
+	verifiedUserTTL = 30 * time.Second
+)
+
+// readOnlyRoutes are the routes which do not change anything but are not
+// called by GET.
+var readOnlyRoutes = map[string]struct{}{
+	"/api/v2/tso":                     {},
+	"/api/v2/verify_table":            {},
+	"/api/v2/dry_run_changefeed":      {},
+	"/capture/owner/changefeed/query": {},
+}
+
+// verifiedUsers is shared by all the routes, so a client is verified once
+// no matter which APIs it calls.
+var verifiedUsers = newUserCache(verifiedUserTTL)
+
+// verifyUserFunc verifies the user and password of a client.
+type verifyUserFunc func(ctx context.Context, user, password string) error
+
+// userCache caches the users and passwords verified recently, so clients
+// calling the API frequently don't log in TiDB for every request. Failed
+// verifications are not cached.
+type userCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+	// verified maps the keys of verified users to their expiration time.
+	verified map[string]time.Time
+}
+
+func newUserCache(ttl time.Duration) *userCache {
+	return &userCache{ttl: ttl, verified: make(map[string]time.Time)}
+}
+
+// wrap returns a verifyUserFunc which calls verify only if the user and
+// password are not verified in the last ttl.
+func (c *userCache) wrap(verify verifyUserFunc) verifyUserFunc {
+	return func(ctx context.Context, user, password string) error {
+		// The password is hashed, so it's not kept in the memory.
+		sum := sha256.Sum256([]byte(user + "\x00" + password))
+		key := string(sum[:])
+		now := time.Now()
+		c.mu.Lock()
+		expiration, ok := c.verified[key]
+		c.mu.Unlock()
+		if ok && now.Before(expiration) {
+			return nil
+		}
+		if err := verify(ctx, user, password); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for k, expiration := range c.verified {
+			if !now.Before(expiration) {
+				delete(c.verified, k)
+			}
+		}
+		c.verified[key] = now.Add(c.ttl)
+		return nil
+	}
+}
+
+// AuthenticateMiddleware authenticates clients of the HTTP API and checks
+// whether their roles are allowed to call the API, mutating calls are logged
+// for auditing.
+//
+// Clients are authenticated by the users of the upstream TiDB if
+// `client-user-required` is enabled, or by the common names of their
+// certificates if mutual TLS is enabled. Otherwise, all clients are allowed.
+//
+// Requests forwarded to the owner are sent with the certificate of the
+// forwarding capture, so the common name of the captures needs a role in
+// `client-roles` which allows the forwarded APIs.
+func AuthenticateMiddleware(c capture.Capture) gin.HandlerFunc {
+	credential := config.GetGlobalServerConfig().Security
+	if credential.IsMutualTLSEnabled() && !credential.ClientUserRequired {
+		selfCN, err := credential.SelfCommonName()
+		if err != nil {
+			log.Warn("failed to get the common name of the certificate", zap.Error(err))
+		} else if _, ok := credential.ClientRoles[selfCN]; !ok {
+			log.Warn("the common name of the certificate has no role, "+
+				"requests forwarded by other captures are granted the default role",
+				zap.String("commonName", selfCN),
+				zap.String("defaultRole", string(credential.ClientRole(selfCN))))
+		}
+	}
+	verify := verifiedUsers.wrap(func(ctx context.Context, user, password string) error {
+		return verifyTiDBUser(ctx, c, user, password)
+	})
+	return authenticate(credential, verify)
+}
+
+func authenticate(
+	credential *security.Credential, verify verifyUserFunc,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !credential.IsClientAuthEnabled() {
+			c.Next()
+			return
+		}
+
+		identity, role, err := authenticateClient(c, credential, verify)
+		if err != nil {
+			if credential.ClientUserRequired {
+				c.Header("WWW-Authenticate", `Basic realm="TiCDC"`)
+			}
+			c.IndentedJSON(http.StatusUnauthorized, model.NewHTTPError(err))
+			c.Abort()
+			return
+		}
+		required := requiredRole(c)
+		if !role.Allows(required) {
+			c.IndentedJSON(http.StatusForbidden, model.NewHTTPError(
+				cerror.ErrUnauthorized.GenWithStackByArgs(
+					identity, role, c.Request.Method, c.Request.URL.Path)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if required != security.RoleReadOnly {
+			log.Info("audit",
+				zap.String("client", identity),
+				zap.String("role", string(role)),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("query", c.Request.URL.RawQuery),
+				zap.String("ip", c.ClientIP()),
+				zap.Int("status", c.Writer.Status()))
+		}
+	}
+}
+
+// authenticateClient returns the identity and the role of the client.
+func authenticateClient(
+	c *gin.Context, credential *security.Credential, verify verifyUserFunc,
+) (string, security.Role, error) {
+	if credential.ClientUserRequired {
+		user, password, ok := c.Request.BasicAuth()
+		if !ok {
+			return "", "", cerror.ErrUnauthenticated.GenWithStackByArgs(
+				"user and password are required")
+		}
+		if !credential.IsClientUserAllowed(user) {
+			return "", "", cerror.ErrUnauthenticated.GenWithStackByArgs(
+				"user " + user + " is not allowed")
+		}
+		if err := verify(c.Request.Context(), user, password); err != nil {
+			return "", "", err
+		}
+		return user, credential.ClientRole(user), nil
+	}
+
+	// The common name is verified in the TLS handshake.
+	state := httputil.TLSStateFromRequest(c.Request)
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return "", "", cerror.ErrUnauthenticated.GenWithStackByArgs(
+			"client certificate is required")
+	}
+	cn := state.PeerCertificates[0].Subject.CommonName
+	return cn, credential.ClientRole(cn), nil
+}
+
+// requiredRole returns the lowest role allowed to call the API.
+func requiredRole(c *gin.Context) security.Role {
+	path := c.FullPath()
+	if strings.Contains(path, "/unsafe/") {
+		return security.RoleUnsafe
+	}
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return security.RoleReadOnly
+	}
+	if _, ok := readOnlyRoutes[path]; ok {
+		return security.RoleReadOnly
+	}
+	return security.RoleOperator
+}
+
+// verifyTiDBUser verifies the user by logging in TiDB servers of the upstream,
+// TiDB servers are found in the etcd of PD.
+func verifyTiDBUser(
+	ctx context.Context, c capture.Capture, user, password string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, verifyUserTimeout)
+	defer cancel()
+	resp, err := c.GetEtcdClient().GetEtcdClient().Get(
+		ctx, tidbTopologyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	addrs := make(map[string]struct{})
+	for _, kv := range resp.Kvs {
+		key := strings.TrimPrefix(string(kv.Key), tidbTopologyPrefix)
+		if idx := strings.Index(key, "/"); idx > 0 {
+			addrs[key[:idx]] = struct{}{}
+		}
+	}
+	if len(addrs) == 0 {
+		return cerror.ErrUnauthenticated.GenWithStackByArgs(
+			"no TiDB server is found to verify the user")
+	}
+
+	var lastErr error
+	for addr := range addrs {
+		lastErr = loginTiDB(ctx, addr, user, password)
+		if lastErr == nil {
+			return nil
+		}
+		if mysqlErr, ok := errors.Cause(lastErr).(*dmysql.MySQLError); ok &&
+			mysqlErr.Number == tmysql.ErrAccessDenied {
+			return cerror.ErrUnauthenticated.GenWithStackByArgs(
+				"user " + user + " is not verified by TiDB")
+		}
+		log.Warn("failed to verify the user by TiDB",
+			zap.String("addr", addr), zap.String("user", user), zap.Error(lastErr))
+	}
+	return cerror.WrapError(cerror.ErrUnauthenticated, lastErr,
+		"failed to verify the user by TiDB")
+}
+
+func loginTiDB(ctx context.Context, addr, user, password string) error {
+	dsn := dmysql.NewConfig()
+	dsn.User = user
+	dsn.Passwd = password
+	dsn.Net = "tcp"
+	dsn.Addr = addr
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer db.Close()
+	return errors.Trace(db.PingContext(ctx))
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthTestRouter(credential *security.Credential) *gin.Engine {
+	verify := func(ctx context.Context, user, password string) error {
+		if password != "pass" {
+			return cerror.ErrUnauthenticated.GenWithStackByArgs("wrong password")
+		}
+		return nil
+	}
+	router := gin.New()
+	v2 := router.Group("/api/v2")
+	v2.Use(authenticate(credential, verify))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v2.GET("/changefeeds/:changefeed_id", handler)
+	v2.POST("/changefeeds", handler)
+	v2.POST("/tso", handler)
+	v2.POST("/dry_run_changefeed", handler)
+	v2.GET("/unsafe/metadata", handler)
+	return router
+}
+
+func serveAuthTestRequest(
+	t *testing.T, router *gin.Engine, method, path string, prepare func(*http.Request),
+) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(context.Background(), method, path, nil)
+	require.Nil(t, err)
+	if prepare != nil {
+		prepare(req)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticateDisabled(t *testing.T) {
+	t.Parallel()
+
+	router := newAuthTestRouter(&security.Credential{})
+	w := serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	t.Parallel()
+
+	credential := &security.Credential{
+		ClientUserRequired: true,
+		ClientAllowedUser:  []string{"root", "reader"},
+		ClientRoles:        map[string]security.Role{"reader": security.RoleReadOnly},
+	}
+	router := newAuthTestRouter(credential)
+	basicAuth := func(user, password string) func(*http.Request) {
+		return func(req *http.Request) { req.SetBasicAuth(user, password) }
+	}
+
+	w := serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test", nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test",
+		basicAuth("root", "wrong"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test",
+		basicAuth("writer", "pass"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// The user without role is granted the read-only role by default.
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata",
+		basicAuth("root", "pass"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	credential.ClientRoles["root"] = security.RoleUnsafe
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata",
+		basicAuth("root", "pass"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test",
+		basicAuth("reader", "pass"))
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthTestRequest(t, router, "POST", "/api/v2/tso",
+		basicAuth("reader", "pass"))
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthTestRequest(t, router, "POST", "/api/v2/dry_run_changefeed",
+		basicAuth("reader", "pass"))
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthTestRequest(t, router, "POST", "/api/v2/changefeeds",
+		basicAuth("reader", "pass"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "CDC:ErrUnauthorized")
+}
+
+func TestAuthenticateCertificate(t *testing.T) {
+	t.Parallel()
+
+	credential := &security.Credential{
+		CAPath:            "ca.pem",
+		CertPath:          "server.pem",
+		KeyPath:           "server-key.pem",
+		CertAllowedCN:     []string{"client", "server"},
+		ClientRoles:       map[string]security.Role{"client": security.RoleOperator},
+		ClientDefaultRole: security.RoleReadOnly,
+	}
+	router := newAuthTestRouter(credential)
+	withCN := func(cn string) func(*http.Request) {
+		return func(req *http.Request) {
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{
+					{Subject: pkix.Name{CommonName: cn}},
+				},
+			}
+		}
+	}
+
+	w := serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test", nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Empty(t, w.Header().Get("WWW-Authenticate"))
+
+	w = serveAuthTestRequest(t, router, "POST", "/api/v2/changefeeds", withCN("client"))
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata", withCN("client"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Requests forwarded by other captures are granted the default role if
+	// the common name of the captures has no role.
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/changefeeds/test", withCN("server"))
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata", withCN("server"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	credential.ClientRoles["server"] = security.RoleUnsafe
+	w = serveAuthTestRequest(t, router, "GET", "/api/v2/unsafe/metadata", withCN("server"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAuthTestRequest(t, router, "POST", "/api/v2/changefeeds", withCN("other"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUserCache(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	verify := func(ctx context.Context, user, password string) error {
+		calls++
+		if password != "pass" {
+			return cerror.ErrUnauthenticated.GenWithStackByArgs("wrong password")
+		}
+		return nil
+	}
+	ctx := context.Background()
+	cache := newUserCache(time.Hour)
+	cached := cache.wrap(verify)
+
+	// Verified users are cached.
+	require.Nil(t, cached(ctx, "root", "pass"))
+	require.Nil(t, cached(ctx, "root", "pass"))
+	require.Equal(t, 1, calls)
+
+	// Failed verifications are not cached, and another password of the
+	// same user is verified again.
+	require.NotNil(t, cached(ctx, "root", "wrong"))
+	require.NotNil(t, cached(ctx, "root", "wrong"))
+	require.Equal(t, 3, calls)
+
+	// Expired users are verified again.
+	cache = newUserCache(0)
+	cached = cache.wrap(verify)
+	require.Nil(t, cached(ctx, "root", "pass"))
+	require.Nil(t, cached(ctx, "root", "pass"))
+	require.Equal(t, 5, calls)
+	require.Len(t, cache.verified, 1)
+}
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/etcd/client/v3/concurrency"
@@ -43,6 +44,24 @@ func TestHTTPStatus(t *testing.T) {
 	testHandleChangefeedQuery(t, addr)
 }
 
+func TestOwnerAPIAuthenticated(t *testing.T) {
+	original := config.GetGlobalServerConfig()
+	defer config.StoreGlobalServerConfig(original)
+	cfg := original.Clone()
+	cfg.Security.ClientUserRequired = true
+	config.StoreGlobalServerConfig(cfg)
+
+	router := gin.New()
+	RegisterOwnerAPIRoutes(router, nil)
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(context.Background(), "POST",
+		"/capture/owner/admin", nil)
+	require.Nil(t, err)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "CDC:ErrUnauthenticated")
+}
+
 func testReisgnOwner(t *testing.T, addr string) {
 	uri := fmt.Sprintf("%s/capture/owner/resign", addr)
 	testRequestNonOwnerFailed(t, uri)
@@ -40,7 +40,8 @@ type statusAPI struct {
 	capture capture.Capture
 }
 
-// RegisterStatusAPIRoutes registers routes for status.
+// RegisterStatusAPIRoutes registers routes for status. They are not
+// authenticated, since they are used by health checks and diagnosis tools.
 func RegisterStatusAPIRoutes(router *gin.Engine, capture capture.Capture) {
 	statusAPI := statusAPI{capture: capture}
 	router.GET("/status", gin.WrapF(statusAPI.handleStatus))
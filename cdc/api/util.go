@@ -150,6 +150,46 @@ func HandleOwnerScheduleTable(
 	}
 }
 
+// HandleOwnerUpdateTableSet changes the tables replicated by the changefeed
+func HandleOwnerUpdateTableSet(
+	ctx context.Context, capture capture.Capture,
+	changefeedID model.ChangeFeedID, change *model.TableSetChange,
+) error {
+	// Use buffered channel to prevent blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.UpdateTableSet(changefeedID, change, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
+// HandleOwnerApproveDDL approves or rejects the DDL held by the changefeed
+func HandleOwnerApproveDDL(
+	ctx context.Context, capture capture.Capture,
+	changefeedID model.ChangeFeedID, commitTs uint64, approved bool,
+) error {
+	// Use buffered channel to prevent blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.ApproveDDL(changefeedID, commitTs, approved, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
 // ForwardToOwner forwards an request to the owner
 func ForwardToOwner(c *gin.Context, p capture.Capture) {
 	ctx := c.Request.Context()
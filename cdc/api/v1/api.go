@@ -76,6 +76,7 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api OpenAPI) {
 	v1.Use(middleware.CheckServerReadyMiddleware(api.capture))
 	v1.Use(middleware.LogMiddleware())
 	v1.Use(middleware.ErrorHandleMiddleware())
+	v1.Use(middleware.AuthenticateMiddleware(api.capture))
 
 	// common API
 	v1.GET("/status", api.ServerStatus)
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
@@ -146,6 +147,9 @@ func verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, err
 	}
+	if _, err := filter.NewDDLPolicy(replicaConfig); err != nil {
+		return nil, err
+	}
 	tableInfos, ineligibleTables, _, err := entry.VerifyTables(f,
 		up.KVStorage, changefeedConfig.StartTS)
 	if err != nil {
@@ -155,6 +159,18 @@ func verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, err
 	}
+	computer, err := entry.NewColumnComputer(replicaConfig, "")
+	if err != nil {
+		return nil, err
+	}
+	err = computer.Verify(tableInfos)
+	if err != nil {
+		return nil, err
+	}
+	err = dispatcher.VerifyTables(replicaConfig, tableInfos)
+	if err != nil {
+		return nil, err
+	}
 	if !replicaConfig.ForceReplicate && !changefeedConfig.IgnoreIneligibleTable {
 		if len(ineligibleTables) != 0 {
 			return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
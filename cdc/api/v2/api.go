@@ -42,6 +42,7 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	v2.Use(middleware.CheckServerReadyMiddleware(api.capture))
 	v2.Use(middleware.LogMiddleware())
 	v2.Use(middleware.ErrorHandleMiddleware())
+	v2.Use(middleware.AuthenticateMiddleware(api.capture))
 
 	// changefeed apis
 	changefeedGroup := v2.Group("/changefeeds")
@@ -50,6 +51,30 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.PUT("/:changefeed_id", api.updateChangefeed)
 	changefeedGroup.GET("/:changefeed_id/meta_info", api.getChangeFeedMetaInfo)
 	changefeedGroup.POST("/:changefeed_id/resume", api.resumeChangefeed)
+	changefeedGroup.POST("/:changefeed_id/clone", api.cloneChangefeed)
+	changefeedGroup.GET("/:changefeed_id/diagnosis", api.getChangefeedDiagnosis)
+	changefeedGroup.POST("/:changefeed_id/tables", api.updateChangefeedTables)
+	changefeedGroup.POST("/:changefeed_id/approve_ddl", api.approveChangefeedDDL)
+	changefeedGroup.GET("/:changefeed_id/schema", api.getChangefeedTableSchema)
+	changefeedGroup.GET("/:changefeed_id/events", api.getChangefeedEvents)
+
+	// multi-upstream changefeed apis
+	fanInGroup := v2.Group("/fan_in_changefeeds")
+	fanInGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	fanInGroup.POST("", api.createFanInChangefeed)
+	fanInGroup.GET("/:changefeed_id", api.getFanInChangefeed)
+	fanInGroup.POST("/:changefeed_id/pause", api.pauseFanInChangefeed)
+	fanInGroup.POST("/:changefeed_id/resume", api.resumeFanInChangefeed)
+	fanInGroup.DELETE("/:changefeed_id", api.deleteFanInChangefeed)
+
+	// processor apis, they are served by the capture itself and not forwarded
+	// to the owner.
+	processorGroup := v2.Group("/processors")
+	processorGroup.GET("/:changefeed_id/diagnosis", api.getProcessorDiagnosis)
+
+	dryRunGroup := v2.Group("/dry_run_changefeed")
+	dryRunGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	dryRunGroup.POST("", api.dryRunChangefeed)
 
 	verifyTableGroup := v2.Group("/verify_table")
 	verifyTableGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
@@ -64,4 +89,7 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 
 	// common APIs
 	v2.POST("/tso", api.QueryTso)
+	// The server config of the capture itself is reloaded, it's not
+	// forwarded to the owner.
+	v2.POST("/config/reload", api.reloadServerConfig)
 }
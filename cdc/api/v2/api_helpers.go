@@ -28,11 +28,13 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/r3labs/diff"
 	"github.com/tikv/client-go/v2/oracle"
@@ -100,6 +102,15 @@ type APIV2Helpers interface {
 		storage tidbkv.Storage, startTs uint64) (ineligibleTables,
 		eligibleTables []model.TableName, err error,
 	)
+
+	// getTableInfos wraps entry.VerifyTables to return the infos of the
+	// tables which are not ignored by the filter
+	getTableInfos(replicaConfig *config.ReplicaConfig,
+		storage tidbkv.Storage, startTs uint64) ([]*model.TableInfo, error)
+
+	// getTableInfoAt wraps entry.TableInfoAt to increase testability
+	getTableInfoAt(storage tidbkv.Storage, ts uint64,
+		schemaName, tableName string) (*model.TableInfo, error)
 }
 
 // APIV2HelpersImpl is an implementation of AVIV2Helpers interface
@@ -219,6 +230,9 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
+	if _, err := filter.NewDDLPolicy(replicaCfg); err != nil {
+		return nil, errors.Cause(err)
+	}
 	tableInfos, ineligibleTables, _, err := entry.VerifyTables(f, kvStorage, cfg.StartTs)
 	if err != nil {
 		return nil, errors.Cause(err)
@@ -227,6 +241,18 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
+	computer, err := entry.NewColumnComputer(replicaCfg, "")
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	err = computer.Verify(tableInfos)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	err = dispatcher.VerifyTables(replicaCfg, tableInfos)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
 	if !replicaCfg.ForceReplicate && !cfg.ReplicaConfig.IgnoreIneligibleTable {
 		if err != nil {
 			return nil, err
@@ -284,6 +310,23 @@ func (h APIV2HelpersImpl) verifyUpstream(ctx context.Context,
 	return nil
 }
 
+// restoreHeartbeatUpstreamURI keeps the old heartbeat upstream URI if the new
+// one is the masked form returned by the API, so that a config read from the
+// API can be sent back without losing the upstream password.
+func restoreHeartbeatUpstreamURI(oldCfg, newCfg *config.ReplicaConfig) {
+	if oldCfg == nil || oldCfg.Heartbeat == nil ||
+		newCfg == nil || newCfg.Heartbeat == nil {
+		return
+	}
+	masked, err := util.MaskSinkURI(oldCfg.Heartbeat.UpstreamURI)
+	if err != nil {
+		return
+	}
+	if newCfg.Heartbeat.UpstreamURI == masked {
+		newCfg.Heartbeat.UpstreamURI = oldCfg.Heartbeat.UpstreamURI
+	}
+}
+
 // verifyUpdateChangefeedConfig verifies config to update
 // a changefeed and returns a changefeedInfo
 func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
@@ -312,6 +355,7 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 	// verify replica config
 	if cfg.ReplicaConfig != nil {
 		newInfo.Config = cfg.ReplicaConfig.ToInternalReplicaConfig()
+		restoreHeartbeatUpstreamURI(oldInfo.Config, newInfo.Config)
 		err = newInfo.Config.ValidateAndAdjust(nil)
 		if err != nil {
 			return nil, nil, err
@@ -323,6 +367,10 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		return nil, nil, cerror.ErrChangefeedUpdateRefused.
 			GenWithStackByArgs(errors.Cause(err).Error())
 	}
+	if _, err := filter.NewDDLPolicy(newInfo.Config); err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs(errors.Cause(err).Error())
+	}
 
 	tableInfos, _, _, err := entry.VerifyTables(f, kvStorage, checkpointTs)
 	if err != nil {
@@ -334,6 +382,21 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		return nil, nil, cerror.ErrChangefeedUpdateRefused.
 			GenWithStackByArgs(errors.Cause(err).Error())
 	}
+	computer, err := entry.NewColumnComputer(newInfo.Config, "")
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs(errors.Cause(err).Error())
+	}
+	err = computer.Verify(tableInfos)
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs(errors.Cause(err).Error())
+	}
+	err = dispatcher.VerifyTables(newInfo.Config, tableInfos)
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs(errors.Cause(err).Error())
+	}
 
 	// verify SinkURI
 	if cfg.SinkURI != "" {
@@ -452,3 +515,20 @@ func (h APIV2HelpersImpl) getVerfiedTables(replicaConfig *config.ReplicaConfig,
 		VerifyTables(f, storage, startTs)
 	return
 }
+
+func (h APIV2HelpersImpl) getTableInfos(replicaConfig *config.ReplicaConfig,
+	storage tidbkv.Storage, startTs uint64,
+) ([]*model.TableInfo, error) {
+	f, err := filter.NewFilter(replicaConfig, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tableInfos, _, _, err := entry.VerifyTables(f, storage, startTs)
+	return tableInfos, errors.Trace(err)
+}
+
+func (h APIV2HelpersImpl) getTableInfoAt(storage tidbkv.Storage, ts uint64,
+	schemaName, tableName string,
+) (*model.TableInfo, error) {
+	return entry.TableInfoAt(storage, ts, schemaName, tableName)
+}
@@ -70,6 +70,36 @@ func (mr *MockAPIV2HelpersMockRecorder) getPDClient(ctx, pdAddrs, credential int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getPDClient", reflect.TypeOf((*MockAPIV2Helpers)(nil).getPDClient), ctx, pdAddrs, credential)
 }
 
+// getTableInfoAt mocks base method.
+func (m *MockAPIV2Helpers) getTableInfoAt(storage kv.Storage, ts uint64, schemaName, tableName string) (*model.TableInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getTableInfoAt", storage, ts, schemaName, tableName)
+	ret0, _ := ret[0].(*model.TableInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getTableInfoAt indicates an expected call of getTableInfoAt.
+func (mr *MockAPIV2HelpersMockRecorder) getTableInfoAt(storage, ts, schemaName, tableName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getTableInfoAt", reflect.TypeOf((*MockAPIV2Helpers)(nil).getTableInfoAt), storage, ts, schemaName, tableName)
+}
+
+// getTableInfos mocks base method.
+func (m *MockAPIV2Helpers) getTableInfos(replicaConfig *config.ReplicaConfig, storage kv.Storage, startTs uint64) ([]*model.TableInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getTableInfos", replicaConfig, storage, startTs)
+	ret0, _ := ret[0].([]*model.TableInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getTableInfos indicates an expected call of getTableInfos.
+func (mr *MockAPIV2HelpersMockRecorder) getTableInfos(replicaConfig, storage, startTs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getTableInfos", reflect.TypeOf((*MockAPIV2Helpers)(nil).getTableInfos), replicaConfig, storage, startTs)
+}
+
 // getVerfiedTables mocks base method.
 func (m *MockAPIV2Helpers) getVerfiedTables(replicaConfig *config.ReplicaConfig, storage kv.Storage, startTs uint64) ([]model.TableName, []model.TableName, error) {
 	m.ctrl.T.Helper()
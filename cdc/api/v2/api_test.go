@@ -62,9 +62,26 @@ func (c *mockPDClient) Close() {}
 
 type mockStatusProvider struct {
 	owner.StatusProvider
-	changefeedStatus *model.ChangeFeedStatus
-	changefeedInfo   *model.ChangeFeedInfo
-	err              error
+	changefeedStatus   *model.ChangeFeedStatus
+	changefeedInfo     *model.ChangeFeedInfo
+	changefeedStatuses map[model.ChangeFeedID]*model.ChangeFeedStatus
+	changefeedInfos    map[model.ChangeFeedID]*model.ChangeFeedInfo
+	captures           []*model.CaptureInfo
+	err                error
+}
+
+// GetAllChangeFeedStatuses returns the mock changefeeds' runtime status.
+func (m *mockStatusProvider) GetAllChangeFeedStatuses(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedStatus, error,
+) {
+	return m.changefeedStatuses, m.err
+}
+
+// GetAllChangeFeedInfo returns the mock changefeeds' info.
+func (m *mockStatusProvider) GetAllChangeFeedInfo(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedInfo, error,
+) {
+	return m.changefeedInfos, m.err
 }
 
 // GetChangeFeedStatus returns a changefeeds' runtime status.
@@ -80,3 +97,8 @@ func (m *mockStatusProvider) GetChangeFeedInfo(ctx context.Context,
 ) (*model.ChangeFeedInfo, error) {
 	return m.changefeedInfo, m.err
 }
+
+// GetCaptures returns the mock captures.
+func (m *mockStatusProvider) GetCaptures(ctx context.Context) ([]*model.CaptureInfo, error) {
+	return m.captures, m.err
+}
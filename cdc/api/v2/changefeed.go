@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
+	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
 )
 
@@ -45,90 +46,145 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
 		return
 	}
+	info, err := h.createChangefeedWithConfig(ctx, cfg, "")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, toAPIModel(info, true))
+}
+
+// createChangefeedWithConfig verifies the config and creates the changefeed,
+// fanInGroup is the multi-upstream changefeed it belongs to if it's not empty.
+func (h *OpenAPIV2) createChangefeedWithConfig(
+	ctx context.Context, cfg *ChangefeedConfig, fanInGroup string,
+) (*model.ChangeFeedInfo, error) {
+	pending, err := h.verifyChangefeedConfig(ctx, cfg, fanInGroup)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.savePendingChangefeed(ctx, pending); err != nil {
+		pending.release(ctx, true)
+		return nil, err
+	}
+	pending.release(ctx, false)
+	return pending.info, nil
+}
+
+// pendingChangefeed is a changefeed verified but not created yet.
+type pendingChangefeed struct {
+	info         *model.ChangeFeedInfo
+	upstreamInfo *model.UpstreamInfo
+	pdClient     pd.Client
+	gcServiceID  string
+	// cancel cancels the context the PD client is created with.
+	cancel context.CancelFunc
+}
+
+// release closes the PD client of the changefeed, the service GC safepoint
+// set by the verification is removed if the changefeed is not created.
+func (p *pendingChangefeed) release(ctx context.Context, removeGCSafePoint bool) {
+	defer p.cancel()
+	defer p.pdClient.Close()
+	if !removeGCSafePoint {
+		return
+	}
+	err := gc.UndoEnsureChangefeedStartTsSafety(
+		ctx,
+		p.pdClient,
+		p.gcServiceID,
+		model.DefaultChangeFeedID(p.info.ID),
+	)
+	if err != nil {
+		log.Warn("failed to remove the service gc safepoint of changefeed",
+			zap.String("changefeed", p.info.ID), zap.Error(err))
+	}
+}
+
+// verifyChangefeedConfig verifies the config of a changefeed to be created,
+// and ensures its start ts is not garbage collected. The returned changefeed
+// must be released.
+func (h *OpenAPIV2) verifyChangefeedConfig(
+	ctx context.Context, cfg *ChangefeedConfig, fanInGroup string,
+) (*pendingChangefeed, error) {
 	if len(cfg.PDAddrs) == 0 {
 		up, err := getCaptureDefaultUpstream(h.capture)
 		if err != nil {
-			_ = c.Error(err)
-			return
+			return nil, err
 		}
 		cfg.PDConfig = getUpstreamPDConfig(up)
 	}
 	credential := cfg.PDConfig.toCredential()
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
 	pdClient, err := h.helpers.getPDClient(timeoutCtx, cfg.PDAddrs, credential)
 	if err != nil {
-		_ = c.Error(cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err))
-		return
+		cancel()
+		return nil, cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err)
 	}
-	defer pdClient.Close()
 
 	// verify tables todo: del kvstore
 	kvStorage, err := h.helpers.createTiStore(cfg.PDAddrs, credential)
 	if err != nil {
-		_ = c.Error(cerror.WrapError(cerror.ErrNewStore, err))
-		return
+		pdClient.Close()
+		cancel()
+		return nil, cerror.WrapError(cerror.ErrNewStore, err)
 	}
 	// We should not close kvStorage since all kvStorage in cdc is the same one.
 	// defer kvStorage.Close()
 	// TODO: We should get a kvStorage from upstream instead of creating a new one
+	gcServiceID := h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating)
 	info, err := h.helpers.verifyCreateChangefeedConfig(
 		ctx,
 		cfg,
 		pdClient,
 		h.capture.StatusProvider(),
-		h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
+		gcServiceID,
 		kvStorage)
 	if err != nil {
-		_ = c.Error(err)
-		return
-	}
-	needRemoveGCSafePoint := false
-	defer func() {
-		if !needRemoveGCSafePoint {
-			return
-		}
-		err := gc.UndoEnsureChangefeedStartTsSafety(
-			ctx,
-			pdClient,
-			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
-			model.DefaultChangeFeedID(cfg.ID),
-		)
-		if err != nil {
-			_ = c.Error(err)
-			return
-		}
-	}()
-	upstreamInfo := &model.UpstreamInfo{
-		ID:            info.UpstreamID,
-		PDEndpoints:   strings.Join(cfg.PDAddrs, ","),
-		KeyPath:       cfg.KeyPath,
-		CertPath:      cfg.CertPath,
-		CAPath:        cfg.CAPath,
-		CertAllowedCN: cfg.CertAllowedCN,
-	}
+		pdClient.Close()
+		cancel()
+		return nil, err
+	}
+	info.FanInGroup = fanInGroup
+	return &pendingChangefeed{
+		info: info,
+		upstreamInfo: &model.UpstreamInfo{
+			ID:            info.UpstreamID,
+			PDEndpoints:   strings.Join(cfg.PDAddrs, ","),
+			KeyPath:       cfg.KeyPath,
+			CertPath:      cfg.CertPath,
+			CAPath:        cfg.CAPath,
+			CertAllowedCN: cfg.CertAllowedCN,
+		},
+		pdClient:    pdClient,
+		gcServiceID: gcServiceID,
+		cancel:      cancel,
+	}, nil
+}
+
+// savePendingChangefeed saves the verified changefeed to etcd.
+func (h *OpenAPIV2) savePendingChangefeed(
+	ctx context.Context, pending *pendingChangefeed,
+) error {
+	info := pending.info
 	infoStr, err := info.Marshal()
 	if err != nil {
-		needRemoveGCSafePoint = true
-		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
-		return
+		return cerror.WrapError(cerror.ErrAPIInvalidParam, err)
 	}
 
 	err = h.capture.GetEtcdClient().CreateChangefeedInfo(ctx,
-		upstreamInfo,
+		pending.upstreamInfo,
 		info,
 		model.DefaultChangeFeedID(info.ID))
 	if err != nil {
-		needRemoveGCSafePoint = true
-		_ = c.Error(err)
-		return
+		return err
 	}
 
 	log.Info("Create changefeed successfully!",
 		zap.String("id", info.ID),
 		zap.String("changefeed", infoStr))
-	c.JSON(http.StatusCreated, toAPIModel(info, true))
+	return nil
 }
 
 // verifyTable verify table, return ineligibleTables and EligibleTables.
@@ -408,6 +464,21 @@ func toAPIModel(info *model.ChangeFeedInfo, maskSinkURI bool) *ChangeFeedInfo {
 		State:          info.State,
 		Error:          runningError,
 		CreatorVersion: info.CreatorVersion,
+		FanInGroup:     info.FanInGroup,
+	}
+	if info.TableSetChange != nil {
+		apiInfoModel.TableSetChange = toAPITableSetChange(info.TableSetChange)
+	}
+	if info.DDLApproval != nil {
+		apiInfoModel.DDLApproval = toAPIDDLApproval(info.DDLApproval)
+	}
+	// The heartbeat upstream URI carries the credentials of the upstream
+	// TiDB, so it is masked in every response regardless of maskSinkURI.
+	if hb := apiInfoModel.Config.Heartbeat; hb != nil {
+		hb.UpstreamURI, err = util.MaskSinkURI(hb.UpstreamURI)
+		if err != nil {
+			log.Error("failed to mask heartbeat upstream URI", zap.Error(err))
+		}
 	}
 	return apiInfoModel
 }
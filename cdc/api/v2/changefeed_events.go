@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const apiOpVarEventLimit = "limit"
+
+// getChangefeedEvents returns the event log of a changefeed, events are in
+// the order they happened. Only the latest events are returned if the limit
+// is specified.
+func (h *OpenAPIV2) getChangefeedEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	limit := 0
+	if limitStr := c.Query(apiOpVarEventLimit); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid limit: %s", limitStr))
+			return
+		}
+	}
+
+	if _, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	eventLog, err := h.capture.GetEtcdClient().GetChangefeedEvents(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	events := eventLog.Events
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	c.JSON(http.StatusOK, toAPIChangefeedEvents(events))
+}
+
+func toAPIChangefeedEvents(events []*model.ChangefeedEvent) []ChangefeedEvent {
+	res := make([]ChangefeedEvent, 0, len(events))
+	for _, event := range events {
+		e := ChangefeedEvent{
+			Time:    event.Time,
+			Type:    event.Type,
+			Message: event.Message,
+		}
+		if event.Error != nil {
+			e.Error = &RunningError{
+				Addr:    event.Error.Addr,
+				Code:    event.Error.Code,
+				Message: event.Error.Message,
+			}
+		}
+		res = append(res, e)
+	}
+	return res
+}
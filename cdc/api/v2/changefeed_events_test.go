@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChangefeedEvents(t *testing.T) {
+	t.Parallel()
+
+	get := testCase{url: "/api/v2/changefeeds/%s/events%s", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t)))
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{ID: "abc"},
+	}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+
+	request := func(id, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), get.method,
+			fmt.Sprintf(get.url, id, query), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: invalid limit
+	w := request("abc", "?limit=-1")
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: get all events
+	failed := model.NewChangefeedEvent(model.ChangefeedEventFailed, "the changefeed is failed")
+	failed.Error = &model.RunningError{Code: "CDC:ErrGCTTLExceeded", Message: "gc ttl exceeded"}
+	eventLog := &model.ChangefeedEventLog{Events: []*model.ChangefeedEvent{
+		model.NewChangefeedEvent(model.ChangefeedEventCreated, ""), failed,
+	}}
+	etcdClient.EXPECT().GetChangefeedEvents(gomock.Any(), model.DefaultChangeFeedID("abc")).
+		Return(eventLog, nil).Times(2)
+	w = request("abc", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var events []ChangefeedEvent
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&events))
+	require.Len(t, events, 2)
+	require.Equal(t, model.ChangefeedEventCreated, events[0].Type)
+
+	// case 3: get the latest events
+	w = request("abc", "?limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	events = nil
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&events))
+	require.Len(t, events, 1)
+	require.Equal(t, model.ChangefeedEventFailed, events[0].Type)
+	require.Equal(t, "CDC:ErrGCTTLExceeded", events[0].Error.Code)
+
+	// case 4: the changefeed doesn't exist
+	statusProvider.err = cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc")
+	w = request("abc", "")
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// changefeedConfig returns the config to create the clone of the source
+// changefeed, checkpointTs is the checkpoint of the source changefeed.
+func (c *CloneChangefeedConfig) changefeedConfig(
+	source *model.ChangeFeedInfo, checkpointTs uint64, up *model.UpstreamInfo,
+) *ChangefeedConfig {
+	cfg := &ChangefeedConfig{
+		Namespace:     source.Namespace,
+		ID:            c.ID,
+		StartTs:       c.StartTs,
+		TargetTs:      c.TargetTs,
+		SinkURI:       c.SinkURI,
+		Engine:        source.Engine,
+		ReplicaConfig: ToAPIReplicaConfig(source.Config),
+	}
+	if cfg.StartTs == 0 {
+		cfg.StartTs = checkpointTs
+	}
+	if cfg.TargetTs == 0 {
+		cfg.TargetTs = source.TargetTs
+	}
+	if up != nil {
+		cfg.PDConfig = PDConfig{
+			PDAddrs:       strings.Split(up.PDEndpoints, ","),
+			CAPath:        up.CAPath,
+			CertPath:      up.CertPath,
+			KeyPath:       up.KeyPath,
+			CertAllowedCN: up.CertAllowedCN,
+		}
+	}
+	return cfg
+}
+
+// cloneChangefeed handles the request to clone a changefeed. The clone is
+// created like a new changefeed, so the service GC safepoint is set at its
+// start ts before it's created, and the creation is refused if the start ts
+// is already garbage collected.
+func (h *OpenAPIV2) cloneChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	sourceID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(sourceID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			sourceID.ID))
+		return
+	}
+	cfg := new(CloneChangefeedConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+
+	source, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, sourceID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// The checkpoint of a changefeed which is not initialized yet is its
+	// start ts.
+	checkpointTs := source.StartTs
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, sourceID)
+	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
+		_ = c.Error(err)
+		return
+	}
+	if status != nil && status.CheckpointTs != 0 {
+		checkpointTs = status.CheckpointTs
+	}
+	up, err := h.capture.GetEtcdClient().
+		GetUpstreamInfo(ctx, source.UpstreamID, source.Namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	info, err := h.createChangefeedWithConfig(ctx,
+		cfg.changefeedConfig(source, checkpointTs, up), "")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("Clone changefeed successfully!",
+		zap.String("source", sourceID.ID),
+		zap.String("changefeed", info.ID),
+		zap.Uint64("startTs", info.StartTs))
+	c.JSON(http.StatusCreated, toAPIModel(info, true))
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tidbkv "github.com/pingcap/tidb/kv"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+	pd "github.com/tikv/pd/client"
+)
+
+func TestCloneChangefeed(t *testing.T) {
+	t.Parallel()
+
+	clone := testCase{url: "/api/v2/changefeeds/%s/clone", method: "POST"}
+	pdClient := &mockPDClient{}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Filter.Rules = []string{"test.*"}
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{
+			UpstreamID: 1,
+			Namespace:  model.DefaultNamespace,
+			ID:         "source",
+			SinkURI:    "mysql://127.0.0.1:3306/",
+			StartTs:    10,
+			TargetTs:   1000,
+			Config:     replicaConfig,
+		},
+		changefeedStatus: &model.ChangeFeedStatus{CheckpointTs: 100},
+	}
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	etcdClient.EXPECT().
+		GetUpstreamInfo(gomock.Any(), uint64(1), model.DefaultNamespace).
+		Return(&model.UpstreamInfo{ID: 1, PDEndpoints: "http://127.0.0.1:2379"}, nil).
+		AnyTimes()
+	etcdClient.EXPECT().
+		CreateChangefeedInfo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	helpers.EXPECT().
+		getPDClient(gomock.Any(), []string{"http://127.0.0.1:2379"}, gomock.Any()).
+		Return(pdClient, nil).AnyTimes()
+	helpers.EXPECT().
+		createTiStore(gomock.Any(), gomock.Any()).
+		Return(nil, nil).AnyTimes()
+
+	var verified *ChangefeedConfig
+	var verifyErr error
+	helpers.EXPECT().
+		verifyCreateChangefeedConfig(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context,
+			cfg *ChangefeedConfig,
+			pdClient pd.Client,
+			statusProvider owner.StatusProvider,
+			ensureGCServiceID string,
+			kvStorage tidbkv.Storage,
+		) (*model.ChangeFeedInfo, error) {
+			verified = cfg
+			if verifyErr != nil {
+				return nil, verifyErr
+			}
+			return &model.ChangeFeedInfo{
+				UpstreamID: 1,
+				ID:         cfg.ID,
+				SinkURI:    cfg.SinkURI,
+				StartTs:    cfg.StartTs,
+				TargetTs:   cfg.TargetTs,
+				Config:     cfg.ReplicaConfig.ToInternalReplicaConfig(),
+			}, nil
+		}).AnyTimes()
+
+	post := func(source string, cfg *CloneChangefeedConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), clone.method,
+			fmt.Sprintf(clone.url, source), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: start from the checkpoint of the source changefeed
+	w := post("source", &CloneChangefeedConfig{ID: "fork", SinkURI: blackholeSink})
+	require.Equal(t, http.StatusCreated, w.Code)
+	resp := ChangeFeedInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "fork", resp.ID)
+	require.Equal(t, uint64(100), resp.StartTs)
+	require.Equal(t, uint64(1000), resp.TargetTs)
+	require.Equal(t, []string{"test.*"}, resp.Config.Filter.Rules)
+	require.Equal(t, blackholeSink, verified.SinkURI)
+	require.Equal(t, []string{"http://127.0.0.1:2379"}, verified.PDAddrs)
+
+	// case 2: start from the given ts
+	w = post("source", &CloneChangefeedConfig{
+		ID: "fork", SinkURI: blackholeSink, StartTs: 50, TargetTs: 500,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, uint64(50), verified.StartTs)
+	require.Equal(t, uint64(500), verified.TargetTs)
+
+	// case 3: the start ts is garbage collected
+	verifyErr = cerror.ErrStartTsBeforeGC.GenWithStackByArgs(50, 60)
+	w = post("source", &CloneChangefeedConfig{
+		ID: "fork", SinkURI: blackholeSink, StartTs: 50,
+	})
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrStartTsBeforeGC")
+
+	// case 4: the source changefeed does not exist
+	statusProvider.err = cerror.ErrChangeFeedNotExists.GenWithStackByArgs("source")
+	w = post("source", &CloneChangefeedConfig{ID: "fork", SinkURI: blackholeSink})
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// approveChangefeedDDL handles the request to approve or reject the DDL
+// held by the DDL policies of a changefeed, the changefeed is blocked at the
+// DDL until it's decided.
+func (h *OpenAPIV2) approveChangefeedDDL(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	cfg := new(ApproveDDLConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.CommitTs == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"the commit ts of the DDL is required"))
+		return
+	}
+
+	err := api.HandleOwnerApproveDDL(ctx, h.capture, changefeedID,
+		cfg.CommitTs, !cfg.Reject)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("DDL of changefeed is decided",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Uint64("commitTs", cfg.CommitTs),
+		zap.Bool("reject", cfg.Reject))
+	c.Status(http.StatusOK)
+}
+
+func toAPIDDLApproval(approval *model.DDLApproval) *DDLApproval {
+	return &DDLApproval{
+		CommitTs: approval.CommitTs,
+		Queries:  approval.Queries,
+		State:    string(approval.State),
+	}
+}
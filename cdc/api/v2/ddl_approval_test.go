@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveChangefeedDDL(t *testing.T) {
+	t.Parallel()
+
+	approve := testCase{url: "/api/v2/changefeeds/%s/approve_ddl", method: "POST"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+
+	post := func(cfg *ApproveDDLConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), approve.method,
+			fmt.Sprintf(approve.url, "abc"), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: commit ts is missing
+	w := post(&ApproveDDLConfig{})
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: no DDL is waiting for approval
+	owner.EXPECT().ApproveDDL(model.DefaultChangeFeedID("abc"), uint64(100), true, gomock.Any()).
+		Do(func(_ model.ChangeFeedID, _ uint64, _ bool, done chan<- error) {
+			done <- cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("no DDL")
+			close(done)
+		})
+	w = post(&ApproveDDLConfig{CommitTs: 100})
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrChangefeedUpdateRefused")
+
+	// case 3: success
+	owner.EXPECT().ApproveDDL(model.DefaultChangeFeedID("abc"), uint64(100), false, gomock.Any()).
+		Do(func(_ model.ChangeFeedID, _ uint64, _ bool, done chan<- error) {
+			close(done)
+		})
+	w = post(&ApproveDDLConfig{CommitTs: 100, Reject: true})
+	require.Equal(t, http.StatusOK, w.Code)
+}
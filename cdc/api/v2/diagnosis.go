@@ -0,0 +1,203 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const apiOpVarRegionLimit = "limit"
+
+// parseRegionLimit parses the number of the slowest regions reported for
+// each table from the query string.
+func parseRegionLimit(c *gin.Context) (int, error) {
+	limitStr := c.Query(apiOpVarRegionLimit)
+	if limitStr == "" {
+		return model.DefaultDiagnosisRegionLimit, nil
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		return 0, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid limit: %s", limitStr)
+	}
+	return limit, nil
+}
+
+// getProcessorDiagnosis returns the replication progress of tables of the
+// changefeed replicated by this capture.
+func (h *OpenAPIV2) getProcessorDiagnosis(c *gin.Context) {
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	limit, err := parseRegionLimit(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tables, err := h.capture.Diagnose(c.Request.Context(), changefeedID, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if tables == nil {
+		tables = []model.TableDiagnosis{}
+	}
+	c.JSON(http.StatusOK, tables)
+}
+
+// getChangefeedDiagnosis collects the replication progress of all tables of
+// the changefeed from all captures, tables are sorted by checkpoint ts so the
+// most lagging tables come first.
+func (h *OpenAPIV2) getChangefeedDiagnosis(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	limit, err := parseRegionLimit(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if _, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	self, err := h.capture.Info()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captures, err := h.capture.StatusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The credentials of the client are forwarded to other captures, which
+	// authenticate the requests as well.
+	authorization := c.GetHeader("Authorization")
+	results := make([][]model.TableDiagnosis, len(captures))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, capture := range captures {
+		i, capture := i, capture
+		g.Go(func() error {
+			var tables []model.TableDiagnosis
+			var err error
+			if capture.ID == self.ID {
+				tables, err = h.capture.Diagnose(gCtx, changefeedID, limit)
+			} else {
+				tables, err = queryProcessorDiagnosis(
+					gCtx, capture.AdvertiseAddr, changefeedID, limit, authorization)
+			}
+			if err != nil {
+				log.Warn("query processor diagnosis failed",
+					zap.String("captureID", capture.ID),
+					zap.String("namespace", changefeedID.Namespace),
+					zap.String("changefeed", changefeedID.ID),
+					zap.Error(err))
+				return errors.Trace(err)
+			}
+			results[i] = tables
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	tables := make([]model.TableDiagnosis, 0)
+	for _, r := range results {
+		tables = append(tables, r...)
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].CheckpointTs < tables[j].CheckpointTs
+	})
+	c.JSON(http.StatusOK, tables)
+}
+
+// queryProcessorDiagnosis queries the diagnosis of a changefeed from the
+// capture with the given address, the request is sent with the authorization
+// header of the client if it's not empty.
+func queryProcessorDiagnosis(
+	ctx context.Context, addr string, changefeedID model.ChangeFeedID, limit int,
+	authorization string,
+) ([]model.TableDiagnosis, error) {
+	security := config.GetGlobalServerConfig().Security
+	scheme := "http"
+	// we should check tls config instead of security here because
+	// security will never be nil
+	if tls, _ := security.ToTLSConfigWithVerify(); tls != nil {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     addr,
+		Path:     fmt.Sprintf("/api/v2/processors/%s/diagnosis", changefeedID.ID),
+		RawQuery: fmt.Sprintf("%s=%d", apiOpVarRegionLimit, limit),
+	}
+	cli, err := httputil.NewClient(security)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, cerror.ErrInternalServerError.GenWithStack(
+			"query diagnosis from %s failed, status: %d, body: %s",
+			addr, resp.StatusCode, string(body))
+	}
+	var tables []model.TableDiagnosis
+	if err := json.Unmarshal(body, &tables); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tables, nil
+}
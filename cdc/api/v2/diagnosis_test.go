@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProcessorDiagnosis(t *testing.T) {
+	t.Parallel()
+
+	diagnosis := testCase{url: "/api/v2/processors/%s/diagnosis", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	// case 1: invalid limit
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), diagnosis.method,
+		fmt.Sprintf(diagnosis.url, "abc")+"?limit=-1", nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: no table of the changefeed in this capture
+	cp.EXPECT().Diagnose(gomock.Any(), model.DefaultChangeFeedID("abc"),
+		model.DefaultDiagnosisRegionLimit).Return(nil, nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), diagnosis.method,
+		fmt.Sprintf(diagnosis.url, "abc"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "[]", w.Body.String())
+
+	// case 3: success
+	cp.EXPECT().Diagnose(gomock.Any(), model.DefaultChangeFeedID("abc"), 2).
+		Return([]model.TableDiagnosis{{TableID: 1}}, nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), diagnosis.method,
+		fmt.Sprintf(diagnosis.url, "abc")+"?limit=2", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var tables []model.TableDiagnosis
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&tables))
+	require.Len(t, tables, 1)
+	require.Equal(t, model.TableID(1), tables[0].TableID)
+}
+
+func TestGetChangefeedDiagnosis(t *testing.T) {
+	t.Parallel()
+
+	// The other capture serves the processor api.
+	remote := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v2/processors/abc/diagnosis", r.URL.Path)
+			require.Equal(t, "3", r.URL.Query().Get("limit"))
+			_ = json.NewEncoder(w).Encode([]model.TableDiagnosis{
+				{CaptureID: "remote", TableID: 2, CheckpointTs: 100},
+			})
+		}))
+	defer remote.Close()
+
+	diagnosis := testCase{url: "/api/v2/changefeeds/%s/diagnosis", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{ID: "abc"},
+		captures: []*model.CaptureInfo{
+			{ID: "owner"},
+			{ID: "remote", AdvertiseAddr: strings.TrimPrefix(remote.URL, "http://")},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: "owner"}, nil).AnyTimes()
+	cp.EXPECT().Diagnose(gomock.Any(), model.DefaultChangeFeedID("abc"), 3).
+		Return([]model.TableDiagnosis{
+			{CaptureID: "owner", TableID: 1, CheckpointTs: 200},
+		}, nil)
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), diagnosis.method,
+		fmt.Sprintf(diagnosis.url, "abc")+"?limit=3", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var tables []model.TableDiagnosis
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&tables))
+	// The most lagging table comes first.
+	require.Len(t, tables, 2)
+	require.Equal(t, "remote", tables[0].CaptureID)
+	require.Equal(t, "owner", tables[1].CaptureID)
+}
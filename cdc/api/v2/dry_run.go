@@ -0,0 +1,212 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mq/columnselector"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const ineligibleReason = "no primary key or not null unique key"
+
+// dryRunChangefeed handles the request to simulate creating a changefeed.
+// It reports which tables the changefeed would replicate at the start ts,
+// and how the filters and dispatchers of the config apply to them. Nothing
+// is written, and no service GC safepoint is set.
+func (h *OpenAPIV2) dryRunChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	cfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.SinkURI == "" {
+		_ = c.Error(cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+			"sink_uri is empty, cannot create a changefeed without sink_uri"))
+		return
+	}
+	sinkURI, err := url.Parse(cfg.SinkURI)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrSinkURIInvalid, err))
+		return
+	}
+	if len(cfg.PDAddrs) == 0 {
+		up, err := getCaptureDefaultUpstream(h.capture)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		cfg.PDConfig = getUpstreamPDConfig(up)
+	}
+	credential := cfg.PDConfig.toCredential()
+
+	if cfg.StartTs == 0 {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		pdClient, err := h.helpers.getPDClient(timeoutCtx, cfg.PDAddrs, credential)
+		if err != nil {
+			_ = c.Error(cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err))
+			return
+		}
+		defer pdClient.Close()
+		ts, logical, err := pdClient.GetTS(ctx)
+		if err != nil {
+			_ = c.Error(cerror.ErrPDEtcdAPIError.GenWithStackByArgs(
+				"fail to get ts from pd client"))
+			return
+		}
+		cfg.StartTs = oracle.ComposeTS(ts, logical)
+	}
+
+	kvStorage, err := h.helpers.createTiStore(cfg.PDAddrs, credential)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrNewStore, err))
+		return
+	}
+	defer closeTiStore(h.capture, kvStorage)
+	replicaCfg := cfg.ReplicaConfig.ToInternalReplicaConfig()
+	if err := replicaCfg.ValidateAndAdjust(sinkURI); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tableInfos, err := h.helpers.getTableInfos(replicaCfg, kvStorage, cfg.StartTs)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	report, err := newDryRunReport(replicaCfg, sinkURI, cfg.StartTs, tableInfos)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// closeTiStore closes the storage created for the dry run. The storages of
+// the same cluster are the same one, so it's left open if an upstream of the
+// capture shares it.
+func closeTiStore(cp capture.Capture, storage tidbkv.Storage) {
+	if storage == nil {
+		return
+	}
+	shared := false
+	if upManager, err := cp.GetUpstreamManager(); err == nil {
+		_ = upManager.Visit(func(up *upstream.Upstream) error {
+			if up.KVStorage != nil && up.KVStorage.UUID() == storage.UUID() {
+				shared = true
+			}
+			return nil
+		})
+	}
+	if shared {
+		return
+	}
+	if err := storage.Close(); err != nil {
+		log.Warn("failed to close the kv storage of dry run", zap.Error(err))
+	}
+}
+
+// newDryRunReport builds the report of replicating the tables with the
+// config and the sink.
+func newDryRunReport(
+	replicaCfg *config.ReplicaConfig, sinkURI *url.URL,
+	startTs uint64, tableInfos []*model.TableInfo,
+) (*DryRunReport, error) {
+	var (
+		eventRouter  *dispatcher.EventRouter
+		partitionNum int32
+	)
+	if sink.IsMQScheme(strings.ToLower(sinkURI.Scheme)) {
+		defaultTopic := strings.Trim(sinkURI.Path, "/")
+		var err error
+		eventRouter, err = dispatcher.NewEventRouter(replicaCfg, defaultTopic)
+		if err != nil {
+			return nil, err
+		}
+		if s := sinkURI.Query().Get("partition-num"); s != "" {
+			n, err := strconv.ParseInt(s, 10, 32)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+			}
+			partitionNum = int32(n)
+		}
+	}
+	columnSelectors, err := columnselector.New(replicaCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DryRunReport{StartTs: startTs}
+	for _, ti := range tableInfos {
+		schema, table := ti.TableName.Schema, ti.TableName.Table
+		tbl := DryRunTable{
+			Schema:  schema,
+			Table:   table,
+			TableID: ti.TableName.TableID,
+		}
+		if !ti.IsEligible(false /* forceReplicate */) {
+			tbl.Reason = ineligibleReason
+			if !replicaCfg.ForceReplicate {
+				report.IneligibleTables = append(report.IneligibleTables, tbl)
+				continue
+			}
+		}
+		if eventRouter != nil {
+			tbl.Topic = eventRouter.GetTopicForRowChange(
+				&model.RowChangedEvent{Table: &ti.TableName})
+			rule, partition := eventRouter.GetPartitionRuleForTable(ti, partitionNum)
+			tbl.PartitionRule = rule
+			if partition >= 0 {
+				tbl.Partition = &partition
+			}
+		}
+		for _, col := range ti.Columns {
+			if !model.IsColCDCVisible(col) {
+				continue
+			}
+			if columnSelectors.SelectColumn(schema, table, col.Name.O) {
+				tbl.Columns = append(tbl.Columns, col.Name.O)
+			}
+		}
+		eventTypes, sqls, err := filter.IgnoredEvents(replicaCfg.Filter, schema, table)
+		if err != nil {
+			return nil, err
+		}
+		for _, et := range eventTypes {
+			tbl.IgnoredEvents = append(tbl.IgnoredEvents, string(et))
+		}
+		tbl.IgnoredSQLs = sqls
+		report.Tables = append(report.Tables, tbl)
+	}
+	return report, nil
+}
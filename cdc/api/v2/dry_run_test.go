@@ -0,0 +1,150 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+)
+
+func newDryRunTableInfo(id int64, table string, withPK bool) *model.TableInfo {
+	ft := types.NewFieldType(mysql.TypeLong)
+	if withPK {
+		ft.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	}
+	return model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		ID:         id,
+		Name:       timodel.NewCIStr(table),
+		PKIsHandle: withPK,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *ft, State: timodel.StatePublic},
+			{
+				ID: 2, Name: timodel.NewCIStr("a"),
+				FieldType: *types.NewFieldType(mysql.TypeLong), State: timodel.StatePublic,
+			},
+		},
+	})
+}
+
+func TestDryRunChangefeed(t *testing.T) {
+	t.Parallel()
+
+	dryRun := &testCase{url: "/api/v2/dry_run_changefeed", method: "POST"}
+
+	pdClient := &mockPDClient{}
+	upManager := upstream.NewManager4Test(pdClient)
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().GetUpstreamManager().Return(upManager, nil).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	// case 1: the sink uri is empty
+	cfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig(), StartTs: 10}
+	body, err := json.Marshal(cfg)
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		dryRun.method, dryRun.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrSinkURIInvalid")
+
+	// case 2: getTableInfos failed
+	cfg.SinkURI = "kafka://127.0.0.1:9092/topic?protocol=open-protocol&partition-num=4"
+	cfg.ReplicaConfig.Sink.DispatchRules = []*DispatchRule{
+		{Matcher: []string{"test.t2"}, PartitionRule: "ts", TopicRule: "{schema}_{table}"},
+	}
+	cfg.ReplicaConfig.Sink.ColumnSelectors = []*ColumnSelector{
+		{Matcher: []string{"test.t1"}, Columns: []string{"id"}},
+	}
+	cfg.ReplicaConfig.Filter.EventFilters = []EventFilterRule{
+		{Matcher: []string{"test.t1"}, IgnoreEvent: []string{"drop table"}, IgnoreSQL: []string{"^ALTER"}},
+	}
+	body, err = json.Marshal(cfg)
+	require.Nil(t, err)
+	helpers.EXPECT().
+		createTiStore(gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	helpers.EXPECT().getTableInfos(gomock.Any(), gomock.Any(), uint64(10)).
+		Return(nil, cerrors.ErrSchemaStorageTableMiss).
+		Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		dryRun.method, dryRun.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrSchemaStorageTableMiss")
+
+	// case 3: success
+	helpers.EXPECT().getTableInfos(gomock.Any(), gomock.Any(), uint64(10)).
+		Return([]*model.TableInfo{
+			newDryRunTableInfo(101, "t1", true),
+			newDryRunTableInfo(102, "t2", true),
+			newDryRunTableInfo(103, "t3", false),
+		}, nil).
+		Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		dryRun.method, dryRun.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	report := &DryRunReport{}
+	err = json.NewDecoder(w.Body).Decode(report)
+	require.Nil(t, err)
+	require.Equal(t, uint64(10), report.StartTs)
+	require.Len(t, report.Tables, 2)
+	require.Len(t, report.IneligibleTables, 1)
+
+	t1 := report.Tables[0]
+	require.Equal(t, "t1", t1.Table)
+	require.Equal(t, "topic", t1.Topic)
+	require.Equal(t, "default", t1.PartitionRule)
+	require.NotNil(t, t1.Partition)
+	require.Equal(t, []string{"id"}, t1.Columns)
+	require.Equal(t, []string{"drop table"}, t1.IgnoredEvents)
+	require.Equal(t, []string{"^ALTER"}, t1.IgnoredSQLs)
+
+	t2 := report.Tables[1]
+	require.Equal(t, "test_t2", t2.Topic)
+	require.Equal(t, "ts", t2.PartitionRule)
+	require.Nil(t, t2.Partition)
+	require.Equal(t, []string{"id", "a"}, t2.Columns)
+	require.Empty(t, t2.IgnoredEvents)
+
+	require.Equal(t, "t3", report.IneligibleTables[0].Table)
+	require.Equal(t, ineligibleReason, report.IneligibleTables[0].Reason)
+}
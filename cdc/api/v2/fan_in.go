@@ -0,0 +1,433 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// minFanInUpstreams is the minimum number of upstreams of a multi-upstream
+// changefeed.
+const minFanInUpstreams = 2
+
+// fanInMemberID returns the ID of the member changefeed replicating the i-th
+// upstream of the multi-upstream changefeed.
+func fanInMemberID(id string, i int) string {
+	return fmt.Sprintf("%s-%d", id, i+1)
+}
+
+// memberConfig returns the config of the member changefeed replicating the
+// i-th upstream.
+func (c *FanInChangefeedConfig) memberConfig(i int) *ChangefeedConfig {
+	upstream := c.Upstreams[i]
+	replicaConfig := *c.ReplicaConfig
+	// Routing rules of the upstream take precedence over the common ones.
+	replicaConfig.RoutingRules = make([]RoutingRule, 0,
+		len(upstream.RoutingRules)+len(c.ReplicaConfig.RoutingRules))
+	replicaConfig.RoutingRules = append(replicaConfig.RoutingRules, upstream.RoutingRules...)
+	replicaConfig.RoutingRules = append(replicaConfig.RoutingRules, c.ReplicaConfig.RoutingRules...)
+	return &ChangefeedConfig{
+		Namespace:     c.Namespace,
+		ID:            fanInMemberID(c.ID, i),
+		StartTs:       upstream.StartTs,
+		TargetTs:      c.TargetTs,
+		SinkURI:       c.SinkURI,
+		Engine:        c.Engine,
+		ReplicaConfig: &replicaConfig,
+		PDConfig:      upstream.PDConfig,
+	}
+}
+
+// createFanInChangefeed handles the request to create a multi-upstream
+// changefeed. A member changefeed is created for each upstream, all members
+// write to the same sink with their own checkpoints. Tables of different
+// upstreams can be routed to the same downstream table, e.g. to merge sharded
+// tables, only if they have the same columns and primary key. No barrier is
+// shared by the members, so DDLs of such tables are executed by each member,
+// DDLs which have been executed by another member are ignored by the MySQL
+// sinks if they fail with errors like duplicate columns.
+//
+// All members are verified before any of them is created, so a
+// multi-upstream changefeed is either created with all its upstreams or not
+// created at all.
+func (h *OpenAPIV2) createFanInChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	cfg := &FanInChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.ReplicaConfig == nil {
+		cfg.ReplicaConfig = GetDefaultReplicaConfig()
+	}
+	if err := model.ValidateChangefeedID(cfg.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			cfg.ID))
+		return
+	}
+	if len(cfg.Upstreams) < minFanInUpstreams {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"a multi-upstream changefeed needs at least %d upstreams", minFanInUpstreams))
+		return
+	}
+	members, err := h.getFanInMembers(ctx, cfg.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if len(members) != 0 {
+		_ = c.Error(cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(cfg.ID))
+		return
+	}
+
+	pendings, err := h.verifyFanInMembers(ctx, cfg)
+	defer func() {
+		for _, pending := range pendings {
+			pending.release(ctx, !pending.created)
+		}
+	}()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos := make([]*model.ChangeFeedInfo, 0, len(pendings))
+	for _, pending := range pendings {
+		if err := h.savePendingChangefeed(ctx, pending.pendingChangefeed); err != nil {
+			// Remove the created members, so a multi-upstream changefeed is
+			// either created with all its upstreams or not created at all.
+			if rollbackErr := h.removeFanInMembers(ctx, infos); rollbackErr != nil {
+				err = errors.Annotatef(err,
+					"failed to remove the created members of %s: %s",
+					cfg.ID, rollbackErr.Error())
+			}
+			_ = c.Error(err)
+			return
+		}
+		pending.created = true
+		infos = append(infos, pending.info)
+	}
+	log.Info("Create multi-upstream changefeed successfully!",
+		zap.String("id", cfg.ID),
+		zap.Int("upstreams", len(infos)))
+	c.JSON(http.StatusCreated, h.toAPIFanInModel(cfg.ID, infos, nil))
+}
+
+// pendingFanInMember is a verified member changefeed of a multi-upstream
+// changefeed.
+type pendingFanInMember struct {
+	*pendingChangefeed
+	created bool
+}
+
+// verifyFanInMembers verifies the configs of all member changefeeds, the
+// returned members must be released even if an error is returned.
+func (h *OpenAPIV2) verifyFanInMembers(
+	ctx context.Context, cfg *FanInChangefeedConfig,
+) ([]*pendingFanInMember, error) {
+	pendings := make([]*pendingFanInMember, 0, len(cfg.Upstreams))
+	upstreamIDs := make(map[uint64]struct{}, len(cfg.Upstreams))
+	// targetTables maps the downstream tables to the upstream tables
+	// written to them.
+	targetTables := make(map[string]*fanInSourceTable)
+	for i := range cfg.Upstreams {
+		memberCfg := cfg.memberConfig(i)
+		pending, err := h.verifyChangefeedConfig(ctx, memberCfg, cfg.ID)
+		if err != nil {
+			return pendings, err
+		}
+		pendings = append(pendings, &pendingFanInMember{pendingChangefeed: pending})
+		info := pending.info
+		if _, ok := upstreamIDs[info.UpstreamID]; ok {
+			return pendings, cerror.ErrAPIInvalidParam.GenWithStack(
+				"upstream %d is specified more than once", info.UpstreamID)
+		}
+		upstreamIDs[info.UpstreamID] = struct{}{}
+		if err := h.checkFanInTargetTables(memberCfg, info, targetTables); err != nil {
+			return pendings, err
+		}
+	}
+	return pendings, nil
+}
+
+// fanInSourceTable is an upstream table written to a downstream table by a
+// member changefeed.
+type fanInSourceTable struct {
+	upstreamID uint64
+	name       string
+	// shape describes the columns and the primary key of the table.
+	shape string
+}
+
+// fanInTableShape returns the columns and the primary key of the table, tables
+// of different upstreams can be written to the same downstream table only if
+// they have the same shape.
+func fanInTableShape(tableInfo *model.TableInfo) string {
+	var columns, primaryKey []string
+	for _, col := range tableInfo.Columns {
+		if !model.IsColCDCVisible(col) {
+			continue
+		}
+		columns = append(columns, col.Name.L+" "+col.FieldType.String())
+		if mysql.HasPriKeyFlag(col.GetFlag()) {
+			primaryKey = append(primaryKey, col.Name.L)
+		}
+	}
+	return fmt.Sprintf("(%s, PRIMARY KEY (%s))",
+		strings.Join(columns, ", "), strings.Join(primaryKey, ", "))
+}
+
+// checkFanInTargetTables checks that the upstream tables written to the same
+// downstream table have the same columns and primary key, targetTables maps
+// the downstream tables of the checked members to their upstream tables.
+func (h *OpenAPIV2) checkFanInTargetTables(
+	cfg *ChangefeedConfig, info *model.ChangeFeedInfo,
+	targetTables map[string]*fanInSourceTable,
+) error {
+	kvStorage, err := h.helpers.createTiStore(cfg.PDAddrs, cfg.PDConfig.toCredential())
+	if err != nil {
+		return cerror.WrapError(cerror.ErrNewStore, err)
+	}
+	tableInfos, err := h.helpers.getTableInfos(info.Config, kvStorage, info.StartTs)
+	if err != nil {
+		return err
+	}
+	router, err := sqlmodel.NewRouter(info.Config.CaseSensitive, info.Config.RoutingRules)
+	if err != nil {
+		return err
+	}
+	for _, tableInfo := range tableInfos {
+		if !tableInfo.IsEligible(info.Config.ForceReplicate) {
+			continue
+		}
+		target := router.RouteTableName(tableInfo.TableName).QuoteString()
+		source := &fanInSourceTable{
+			upstreamID: info.UpstreamID,
+			name:       tableInfo.TableName.QuoteString(),
+			shape:      fanInTableShape(tableInfo),
+		}
+		other, ok := targetTables[target]
+		if !ok {
+			targetTables[target] = source
+			continue
+		}
+		if other.shape != source.shape {
+			return cerror.ErrAPIInvalidParam.GenWithStack(
+				"table %s of upstream %d and table %s of upstream %d are both "+
+					"written to table %s, but their columns or primary keys "+
+					"are different: %s and %s",
+				other.name, other.upstreamID, source.name, source.upstreamID,
+				target, other.shape, source.shape)
+		}
+	}
+	return nil
+}
+
+// pauseFanInChangefeed pauses all member changefeeds of a multi-upstream
+// changefeed. Members are paused one by one, and the request can be retried
+// if it fails halfway.
+func (h *OpenAPIV2) pauseFanInChangefeed(c *gin.Context) {
+	h.handleFanInAdminJob(c, model.AdminStop)
+}
+
+// resumeFanInChangefeed resumes all member changefeeds of a multi-upstream
+// changefeed from their own checkpoints.
+func (h *OpenAPIV2) resumeFanInChangefeed(c *gin.Context) {
+	h.handleFanInAdminJob(c, model.AdminResume)
+}
+
+// deleteFanInChangefeed removes all member changefeeds of a multi-upstream
+// changefeed.
+func (h *OpenAPIV2) deleteFanInChangefeed(c *gin.Context) {
+	h.handleFanInAdminJob(c, model.AdminRemove)
+}
+
+// handleFanInAdminJob applies the admin job to all member changefeeds of the
+// multi-upstream changefeed.
+func (h *OpenAPIV2) handleFanInAdminJob(c *gin.Context, jobType model.AdminJobType) {
+	ctx := c.Request.Context()
+	id := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(id); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", id))
+		return
+	}
+	members, err := h.getFanInMembers(ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if len(members) == 0 {
+		_ = c.Error(cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id))
+		return
+	}
+	for _, info := range members {
+		job := model.AdminJob{
+			CfID: model.DefaultChangeFeedID(info.ID),
+			Type: jobType,
+		}
+		if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	log.Info("Handle admin job of multi-upstream changefeed successfully!",
+		zap.String("id", id),
+		zap.Stringer("job", jobType),
+		zap.Int("upstreams", len(members)))
+	c.Status(http.StatusOK)
+}
+
+// getFanInChangefeed returns the replication progress of each upstream of a
+// multi-upstream changefeed.
+func (h *OpenAPIV2) getFanInChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(id); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", id))
+		return
+	}
+	members, err := h.getFanInMembers(ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if len(members) == 0 {
+		_ = c.Error(cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id))
+		return
+	}
+	statuses, err := h.capture.StatusProvider().GetAllChangeFeedStatuses(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, h.toAPIFanInModel(id, members, statuses))
+}
+
+// getFanInMembers returns the member changefeeds of the multi-upstream
+// changefeed sorted by their IDs.
+func (h *OpenAPIV2) getFanInMembers(
+	ctx context.Context, id string,
+) ([]*model.ChangeFeedInfo, error) {
+	infos, err := h.capture.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var members []*model.ChangeFeedInfo
+	for _, info := range infos {
+		if info.FanInGroup == id {
+			members = append(members, info)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+	return members, nil
+}
+
+// removeFanInMembers removes the member changefeeds and waits until they
+// are removed by the owner, it's called to roll back a failed creation.
+func (h *OpenAPIV2) removeFanInMembers(ctx context.Context, members []*model.ChangeFeedInfo) error {
+	for _, info := range members {
+		changefeedID := model.DefaultChangeFeedID(info.ID)
+		job := model.AdminJob{
+			CfID: changefeedID,
+			Type: model.AdminRemove,
+		}
+		if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+			return errors.Trace(err)
+		}
+		// Owner needs at least two ticks to remove a changefeed.
+		err := retry.Do(ctx, func() error {
+			_, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+			if cerror.ErrChangeFeedNotExists.Equal(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return cerror.ErrChangeFeedDeletionUnfinished.GenWithStackByArgs(changefeedID)
+		},
+			retry.WithMaxTries(100),         // max retry duration is 1 minute
+			retry.WithBackoffBaseDelay(600), // default owner tick interval is 200ms
+			retry.WithIsRetryableErr(cerror.IsRetryableError))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Info("member changefeed of multi-upstream changefeed is removed",
+			zap.String("changefeed", info.ID),
+			zap.String("fanInGroup", info.FanInGroup))
+	}
+	return nil
+}
+
+// toAPIFanInModel converts the member changefeeds to the api model, the lag
+// of each member is calculated by the current time of its own upstream.
+func (h *OpenAPIV2) toAPIFanInModel(
+	id string, members []*model.ChangeFeedInfo,
+	statuses map[model.ChangeFeedID]*model.ChangeFeedStatus,
+) *FanInChangefeedInfo {
+	res := &FanInChangefeedInfo{
+		ID:        id,
+		Upstreams: make([]FanInUpstreamStatus, 0, len(members)),
+	}
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		log.Warn("failed to get upstream manager", zap.Error(err))
+	}
+	for _, info := range members {
+		upstreamStatus := FanInUpstreamStatus{
+			ChangefeedID: info.ID,
+			UpstreamID:   info.UpstreamID,
+			State:        info.State,
+		}
+		if info.Error != nil {
+			upstreamStatus.Error = &RunningError{
+				Addr:    info.Error.Addr,
+				Code:    info.Error.Code,
+				Message: info.Error.Message,
+			}
+		}
+		checkpointTs := info.StartTs
+		if status, ok := statuses[model.DefaultChangeFeedID(info.ID)]; ok {
+			checkpointTs = status.CheckpointTs
+		}
+		upstreamStatus.CheckpointTs = checkpointTs
+		upstreamStatus.CheckpointTime = model.JSONTime(oracle.GetTimeFromTS(checkpointTs))
+		if upManager != nil {
+			if up, ok := upManager.Get(info.UpstreamID); ok {
+				if now, err := up.PDClock.CurrentTime(); err == nil {
+					upstreamStatus.CheckpointLag = float64(
+						oracle.GetPhysical(now)-oracle.ExtractPhysical(checkpointTs)) / 1e3
+				}
+			}
+		}
+		res.Upstreams = append(res.Upstreams, upstreamStatus)
+	}
+	return res
+}
@@ -0,0 +1,404 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tidbkv "github.com/pingcap/tidb/kv"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/owner"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+	pd "github.com/tikv/pd/client"
+)
+
+func TestFanInMemberConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &FanInChangefeedConfig{
+		ID:            "abc",
+		SinkURI:       blackholeSink,
+		ReplicaConfig: GetDefaultReplicaConfig(),
+		Upstreams: []*FanInUpstreamConfig{
+			{StartTs: 1},
+			{
+				StartTs: 2,
+				RoutingRules: []RoutingRule{
+					{Matcher: []string{"test.*"}, TargetSchema: "test_2"},
+				},
+			},
+		},
+	}
+	cfg.ReplicaConfig.RoutingRules = []RoutingRule{
+		{Matcher: []string{"*.*"}, TargetSchema: "merged"},
+	}
+
+	member := cfg.memberConfig(1)
+	require.Equal(t, "abc-2", member.ID)
+	require.Equal(t, uint64(2), member.StartTs)
+	require.Equal(t, blackholeSink, member.SinkURI)
+	require.Equal(t, []RoutingRule{
+		{Matcher: []string{"test.*"}, TargetSchema: "test_2"},
+		{Matcher: []string{"*.*"}, TargetSchema: "merged"},
+	}, member.ReplicaConfig.RoutingRules)
+	// The common config is not changed.
+	require.Len(t, cfg.ReplicaConfig.RoutingRules, 1)
+}
+
+// fanInStatusProvider returns ErrChangeFeedNotExists for the changefeeds
+// which are not in changefeedInfos.
+type fanInStatusProvider struct {
+	mockStatusProvider
+}
+
+func (m *fanInStatusProvider) GetChangeFeedInfo(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (*model.ChangeFeedInfo, error) {
+	info, ok := m.changefeedInfos[changefeedID]
+	if !ok {
+		return nil, cerrors.ErrChangeFeedNotExists.GenWithStackByArgs(changefeedID)
+	}
+	return info, nil
+}
+
+func newFanInTableInfo(tp byte) *model.TableInfo {
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	return model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		ID:         1,
+		Name:       timodel.NewCIStr("t"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *ft, State: timodel.StatePublic},
+			{
+				ID: 2, Name: timodel.NewCIStr("a"),
+				FieldType: *types.NewFieldType(tp), State: timodel.StatePublic,
+			},
+		},
+	})
+}
+
+func TestCreateFanInChangefeed(t *testing.T) {
+	t.Parallel()
+
+	create := testCase{url: "/api/v2/fan_in_changefeeds", method: "POST"}
+	pdClient := &mockPDClient{}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	mockOwner := mock_owner.NewMockOwner(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	statusProvider := &fanInStatusProvider{}
+	var saveErrs map[string]error
+	created := make(map[string]struct{})
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	etcdClient.EXPECT().
+		CreateChangefeedInfo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, upstreamInfo *model.UpstreamInfo,
+			info *model.ChangeFeedInfo, changefeedID model.ChangeFeedID,
+		) error {
+			if err := saveErrs[changefeedID.ID]; err != nil {
+				return err
+			}
+			created[changefeedID.ID] = struct{}{}
+			return nil
+		}).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(pdClient), nil).AnyTimes()
+	cp.EXPECT().GetOwner().Return(mockOwner, nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	helpers.EXPECT().
+		getPDClient(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pdClient, nil).AnyTimes()
+	helpers.EXPECT().
+		createTiStore(gomock.Any(), gomock.Any()).
+		Return(nil, nil).AnyTimes()
+
+	post := func(cfg *FanInChangefeedConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), create.method,
+			create.url, bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	upstreamIDs := []uint64{1, 2}
+	verified := 0
+	helpers.EXPECT().
+		verifyCreateChangefeedConfig(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context,
+			cfg *ChangefeedConfig,
+			pdClient pd.Client,
+			statusProvider owner.StatusProvider,
+			ensureGCServiceID string,
+			kvStorage tidbkv.Storage,
+		) (*model.ChangeFeedInfo, error) {
+			info := &model.ChangeFeedInfo{
+				UpstreamID: upstreamIDs[verified%len(upstreamIDs)],
+				ID:         cfg.ID,
+				SinkURI:    cfg.SinkURI,
+				StartTs:    cfg.StartTs,
+				Config:     cfg.ReplicaConfig.ToInternalReplicaConfig(),
+			}
+			verified++
+			return info, nil
+		}).AnyTimes()
+	// Each upstream has a table test.t, the type of its column a is
+	// specified by columnTypes.
+	columnTypes := []byte{mysql.TypeLong, mysql.TypeLong}
+	listed := 0
+	helpers.EXPECT().
+		getTableInfos(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(replicaConfig *config.ReplicaConfig,
+			storage tidbkv.Storage, startTs uint64,
+		) ([]*model.TableInfo, error) {
+			tableInfo := newFanInTableInfo(columnTypes[listed%len(columnTypes)])
+			listed++
+			return []*model.TableInfo{tableInfo}, nil
+		}).AnyTimes()
+
+	// case 1: only one upstream
+	cfg := &FanInChangefeedConfig{
+		ID:      "abc",
+		SinkURI: blackholeSink,
+		Upstreams: []*FanInUpstreamConfig{
+			{PDConfig: PDConfig{PDAddrs: []string{"http://127.0.0.1:2379"}}},
+		},
+	}
+	w := post(cfg)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: tables of both upstreams are written to test.t but their
+	// columns are different, nothing is created.
+	cfg.Upstreams = append(cfg.Upstreams, &FanInUpstreamConfig{
+		StartTs:  100,
+		PDConfig: PDConfig{PDAddrs: []string{"http://127.0.0.2:2379"}},
+	})
+	columnTypes = []byte{mysql.TypeLong, mysql.TypeVarchar}
+	w = post(cfg)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Contains(t, respErr.Error, "`test`.`t`")
+	require.Empty(t, created)
+
+	// case 3: success, tables of both upstreams are merged into test.t
+	columnTypes = []byte{mysql.TypeLong, mysql.TypeLong}
+	w = post(cfg)
+	require.Equal(t, http.StatusCreated, w.Code)
+	resp := FanInChangefeedInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "abc", resp.ID)
+	require.Len(t, resp.Upstreams, 2)
+	require.Equal(t, "abc-1", resp.Upstreams[0].ChangefeedID)
+	require.Equal(t, uint64(1), resp.Upstreams[0].UpstreamID)
+	require.Equal(t, "abc-2", resp.Upstreams[1].ChangefeedID)
+	require.Equal(t, uint64(2), resp.Upstreams[1].UpstreamID)
+	require.Equal(t, uint64(100), resp.Upstreams[1].CheckpointTs)
+	require.Equal(t, map[string]struct{}{"abc-1": {}, "abc-2": {}}, created)
+
+	// case 4: the multi-upstream changefeed already exists
+	statusProvider.changefeedInfos = map[model.ChangeFeedID]*model.ChangeFeedInfo{
+		model.DefaultChangeFeedID("abc-1"): {ID: "abc-1", FanInGroup: "abc"},
+	}
+	w = post(cfg)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrChangeFeedAlreadyExists")
+
+	// case 5: the same upstream is specified twice, nothing is created
+	statusProvider.changefeedInfos = nil
+	created = make(map[string]struct{})
+	upstreamIDs = []uint64{1}
+	w = post(cfg)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Empty(t, created)
+
+	// case 6: failed to save the second member, the first one is removed
+	upstreamIDs = []uint64{1, 2}
+	saveErrs = map[string]error{"abc-2": cerrors.ErrPDEtcdAPIError}
+	removed := make(map[string]struct{})
+	mockOwner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(job model.AdminJob, done chan<- error) {
+			require.Equal(t, model.AdminRemove, job.Type)
+			removed[job.CfID.ID] = struct{}{}
+			close(done)
+		}).Times(1)
+	w = post(cfg)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrPDEtcdAPIError")
+	require.Equal(t, map[string]struct{}{"abc-1": {}}, removed)
+
+	// case 7: failed to remove the created member, the error is returned
+	mockOwner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(job model.AdminJob, done chan<- error) {
+			done <- cerrors.ErrOwnerNotFound.GenWithStackByArgs()
+		}).Times(1)
+	w = post(cfg)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrPDEtcdAPIError")
+	require.Contains(t, respErr.Error, "failed to remove the created members of abc")
+}
+
+func TestGetFanInChangefeed(t *testing.T) {
+	t.Parallel()
+
+	get := testCase{url: "/api/v2/fan_in_changefeeds/%s", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	checkpointTs := oracle.GoTimeToTS(time.Now().Add(-time.Minute))
+	statusProvider := &mockStatusProvider{
+		changefeedInfos: map[model.ChangeFeedID]*model.ChangeFeedInfo{
+			model.DefaultChangeFeedID("abc-2"): {
+				ID: "abc-2", UpstreamID: 0, FanInGroup: "abc", State: model.StateNormal,
+			},
+			model.DefaultChangeFeedID("abc-1"): {
+				ID: "abc-1", UpstreamID: 1, FanInGroup: "abc", State: model.StateNormal,
+				StartTs: checkpointTs,
+			},
+			model.DefaultChangeFeedID("other"): {ID: "other"},
+		},
+		changefeedStatuses: map[model.ChangeFeedID]*model.ChangeFeedStatus{
+			model.DefaultChangeFeedID("abc-2"): {CheckpointTs: checkpointTs},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+
+	// case 1: not exists
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), get.method,
+		fmt.Sprintf(get.url, "other"), nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+
+	// case 2: success
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), get.method,
+		fmt.Sprintf(get.url, "abc"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := FanInChangefeedInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Upstreams, 2)
+	require.Equal(t, "abc-1", resp.Upstreams[0].ChangefeedID)
+	require.Equal(t, checkpointTs, resp.Upstreams[0].CheckpointTs)
+	// The lag is unknown since upstream 1 is not found.
+	require.Zero(t, resp.Upstreams[0].CheckpointLag)
+	require.Equal(t, "abc-2", resp.Upstreams[1].ChangefeedID)
+	require.Equal(t, checkpointTs, resp.Upstreams[1].CheckpointTs)
+	require.GreaterOrEqual(t, resp.Upstreams[1].CheckpointLag, float64(60))
+}
+
+func TestFanInChangefeedAdminJobs(t *testing.T) {
+	t.Parallel()
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	mockOwner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{
+		changefeedInfos: map[model.ChangeFeedID]*model.ChangeFeedInfo{
+			model.DefaultChangeFeedID("abc-1"): {ID: "abc-1", FanInGroup: "abc"},
+			model.DefaultChangeFeedID("abc-2"): {ID: "abc-2", FanInGroup: "abc"},
+			model.DefaultChangeFeedID("other"): {ID: "other"},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetOwner().Return(mockOwner, nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+
+	var jobs []model.AdminJob
+	mockOwner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(job model.AdminJob, done chan<- error) {
+			jobs = append(jobs, job)
+			close(done)
+		}).AnyTimes()
+
+	for _, tc := range []struct {
+		testCase
+		jobType model.AdminJobType
+	}{
+		{testCase{url: "/api/v2/fan_in_changefeeds/%s/pause", method: "POST"}, model.AdminStop},
+		{testCase{url: "/api/v2/fan_in_changefeeds/%s/resume", method: "POST"}, model.AdminResume},
+		{testCase{url: "/api/v2/fan_in_changefeeds/%s", method: "DELETE"}, model.AdminRemove},
+	} {
+		// The job is applied to all members.
+		jobs = nil
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), tc.method,
+			fmt.Sprintf(tc.url, "abc"), nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, jobs, 2)
+		for i, job := range jobs {
+			require.Equal(t, tc.jobType, job.Type)
+			require.Equal(t, fmt.Sprintf("abc-%d", i+1), job.CfID.ID)
+		}
+
+		// A changefeed not belonging to a multi-upstream changefeed.
+		jobs = nil
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequestWithContext(context.Background(), tc.method,
+			fmt.Sprintf(tc.url, "other"), nil)
+		router.ServeHTTP(w, req)
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+		require.Empty(t, jobs)
+	}
+}
@@ -32,6 +32,18 @@ type Tso struct {
 	LogicTime int64 `json:"logic_time"`
 }
 
+// ServerConfigReloadResult is the result of reloading the server config
+type ServerConfigReloadResult struct {
+	// Applied are the changed items which have taken effect.
+	Applied []string `json:"applied"`
+	// AppliedToNewTables are the changed items which take effect on the
+	// tables added from now on.
+	AppliedToNewTables []string `json:"applied_to_new_tables"`
+	// RequireRestart are the changed items which take effect after the
+	// server is restarted.
+	RequireRestart []string `json:"require_restart"`
+}
+
 // Tables contains IneligibleTables and EligibleTables
 type Tables struct {
 	IneligibleTables []TableName `json:"ineligible_tables,omitempty"`
@@ -46,6 +58,30 @@ type TableName struct {
 	IsPartition bool   `json:"is_partition"`
 }
 
+// DryRunReport is the result of a dry run of creating a changefeed. It
+// shows how the changefeed would replicate the tables at StartTs.
+type DryRunReport struct {
+	StartTs          uint64        `json:"start_ts"`
+	Tables           []DryRunTable `json:"tables,omitempty"`
+	IneligibleTables []DryRunTable `json:"ineligible_tables,omitempty"`
+}
+
+// DryRunTable is how a table would be replicated by a changefeed.
+// Topic, PartitionRule and Partition are only set for MQ sinks, Partition
+// is set if all rows of the table are dispatched to the same partition.
+type DryRunTable struct {
+	Schema        string   `json:"database_name"`
+	Table         string   `json:"table_name"`
+	TableID       int64    `json:"table_id"`
+	Reason        string   `json:"reason,omitempty"`
+	Topic         string   `json:"topic,omitempty"`
+	PartitionRule string   `json:"partition_rule,omitempty"`
+	Partition     *int32   `json:"partition,omitempty"`
+	Columns       []string `json:"columns,omitempty"`
+	IgnoredEvents []string `json:"ignored_events,omitempty"`
+	IgnoredSQLs   []string `json:"ignored_sqls,omitempty"`
+}
+
 // VerifyTableConfig use to verify tables.
 // Only use by Open API v2.
 type VerifyTableConfig struct {
@@ -66,6 +102,17 @@ type ResumeChangefeedConfig struct {
 	OverwriteCheckpointTs uint64 `json:"overwrite_checkpoint_ts"`
 }
 
+// CloneChangefeedConfig is used by clone changefeed api. The changefeed is
+// created with the replica config and the upstream of the source changefeed,
+// it starts from the checkpoint of the source changefeed if StartTs is 0,
+// and inherits the target ts of the source changefeed if TargetTs is 0.
+type CloneChangefeedConfig struct {
+	ID       string `json:"changefeed_id"`
+	StartTs  uint64 `json:"start_ts"`
+	TargetTs uint64 `json:"target_ts"`
+	SinkURI  string `json:"sink_uri"`
+}
+
 // PDConfig is a configuration used to connect to pd
 type PDConfig struct {
 	PDAddrs       []string `json:"pd_addrs,omitempty"`
@@ -89,17 +136,24 @@ type ChangefeedConfig struct {
 
 // ReplicaConfig is a duplicate of  config.ReplicaConfig
 type ReplicaConfig struct {
-	CaseSensitive         bool              `json:"case_sensitive"`
-	EnableOldValue        bool              `json:"enable_old_value"`
-	ForceReplicate        bool              `json:"force_replicate"`
-	IgnoreIneligibleTable bool              `json:"ignore_ineligible_table"`
-	CheckGCSafePoint      bool              `json:"check_gc_safe_point"`
-	EnableSyncPoint       bool              `json:"enable_sync_point"`
-	SyncPointInterval     time.Duration     `json:"sync_point_interval"`
-	SyncPointRetention    time.Duration     `json:"sync_point_retention"`
-	Filter                *FilterConfig     `json:"filter"`
-	Sink                  *SinkConfig       `json:"sink"`
-	Consistent            *ConsistentConfig `json:"consistent"`
+	CaseSensitive         bool                 `json:"case_sensitive"`
+	EnableOldValue        bool                 `json:"enable_old_value"`
+	ForceReplicate        bool                 `json:"force_replicate"`
+	InitialLoad           bool                 `json:"initial_load"`
+	IgnoreIneligibleTable bool                 `json:"ignore_ineligible_table"`
+	CheckGCSafePoint      bool                 `json:"check_gc_safe_point"`
+	EnableSyncPoint       bool                 `json:"enable_sync_point"`
+	SyncPointInterval     time.Duration        `json:"sync_point_interval"`
+	SyncPointRetention    time.Duration        `json:"sync_point_retention"`
+	Filter                *FilterConfig        `json:"filter"`
+	Sink                  *SinkConfig          `json:"sink"`
+	Consistent            *ConsistentConfig    `json:"consistent"`
+	RoutingRules          []RoutingRule        `json:"routing_rules"`
+	BDR                   *BDRConfig           `json:"bdr,omitempty"`
+	DDLPolicies           []DDLPolicy          `json:"ddl_policies,omitempty"`
+	SchemaHistory         *SchemaHistoryConfig `json:"schema_history,omitempty"`
+	RateLimit             *RateLimitConfig     `json:"rate_limit,omitempty"`
+	Heartbeat             *HeartbeatConfig     `json:"heartbeat,omitempty"`
 }
 
 // ToInternalReplicaConfig coverts *v2.ReplicaConfig into *config.ReplicaConfig
@@ -108,6 +162,7 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 	res.CaseSensitive = c.CaseSensitive
 	res.EnableOldValue = c.EnableOldValue
 	res.ForceReplicate = c.ForceReplicate
+	res.InitialLoad = c.InitialLoad
 	res.CheckGCSafePoint = c.CheckGCSafePoint
 	res.EnableSyncPoint = c.EnableSyncPoint
 	res.SyncPointInterval = c.SyncPointInterval
@@ -160,6 +215,56 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 			Storage:           c.Consistent.Storage,
 		}
 	}
+	for _, rule := range c.RoutingRules {
+		res.RoutingRules = append(res.RoutingRules, &config.RoutingRule{
+			Matcher:      rule.Matcher,
+			TargetSchema: rule.TargetSchema,
+			TargetTable:  rule.TargetTable,
+		})
+	}
+	if c.BDR != nil {
+		res.BDR = &config.BDRConfig{
+			Enable:             c.BDR.Enable,
+			ReplicaID:          c.BDR.ReplicaID,
+			FilterReplicaIDs:   c.BDR.FilterReplicaIDs,
+			ConflictResolution: c.BDR.ConflictResolution,
+			VersionColumn:      c.BDR.VersionColumn,
+			ReplicateDDL:       c.BDR.ReplicateDDL,
+		}
+	}
+	for _, policy := range c.DDLPolicies {
+		res.DDLPolicies = append(res.DDLPolicies, policy.toInternalDDLPolicy())
+	}
+	if c.SchemaHistory != nil {
+		res.SchemaHistory = &config.SchemaHistoryConfig{
+			Storage: c.SchemaHistory.Storage,
+		}
+	}
+	if c.RateLimit != nil {
+		res.RateLimit = &config.RateLimitConfig{
+			RowsPerSecond:  c.RateLimit.RowsPerSecond,
+			BytesPerSecond: c.RateLimit.BytesPerSecond,
+			TimeZone:       c.RateLimit.TimeZone,
+		}
+		for _, s := range c.RateLimit.Schedules {
+			res.RateLimit.Schedules = append(res.RateLimit.Schedules,
+				&config.RateLimitSchedule{
+					Start:          s.Start,
+					End:            s.End,
+					RowsPerSecond:  s.RowsPerSecond,
+					BytesPerSecond: s.BytesPerSecond,
+					Pause:          s.Pause,
+				})
+		}
+	}
+	if c.Heartbeat != nil {
+		res.Heartbeat = &config.HeartbeatConfig{
+			Enable:       c.Heartbeat.Enable,
+			UpstreamURI:  c.Heartbeat.UpstreamURI,
+			Table:        c.Heartbeat.Table,
+			IntervalInMs: c.Heartbeat.IntervalInMs,
+		}
+	}
 	if c.Sink != nil {
 		var dispatchRules []*config.DispatchRule
 		for _, rule := range c.Sink.DispatchRules {
@@ -168,6 +273,8 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 				DispatcherRule: "",
 				PartitionRule:  rule.PartitionRule,
 				TopicRule:      rule.TopicRule,
+				Columns:        rule.Columns,
+				HashFunction:   rule.HashFunction,
 			})
 		}
 		var columnSelectors []*config.ColumnSelector
@@ -177,12 +284,32 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var computedColumns []*config.ComputedColumnRule
+		for _, rule := range c.Sink.ComputedColumns {
+			computed := &config.ComputedColumnRule{Matcher: rule.Matcher}
+			for _, col := range rule.Columns {
+				computed.Columns = append(computed.Columns, &config.ComputedColumn{
+					Name:       col.Name,
+					Expression: col.Expression,
+				})
+			}
+			computedColumns = append(computedColumns, computed)
+		}
+		var largeMessageHandle *config.LargeMessageHandleConfig
+		if c.Sink.LargeMessageHandle != nil {
+			largeMessageHandle = &config.LargeMessageHandleConfig{
+				LargeMessageHandleOption: c.Sink.LargeMessageHandle.LargeMessageHandleOption,
+				ClaimCheckStorageURI:     c.Sink.LargeMessageHandle.ClaimCheckStorageURI,
+			}
+		}
 		res.Sink = &config.SinkConfig{
-			DispatchRules:   dispatchRules,
-			Protocol:        c.Sink.Protocol,
-			TxnAtomicity:    config.AtomicityLevel(c.Sink.TxnAtomicity),
-			ColumnSelectors: columnSelectors,
-			SchemaRegistry:  c.Sink.SchemaRegistry,
+			DispatchRules:      dispatchRules,
+			Protocol:           c.Sink.Protocol,
+			TxnAtomicity:       config.AtomicityLevel(c.Sink.TxnAtomicity),
+			ColumnSelectors:    columnSelectors,
+			SchemaRegistry:     c.Sink.SchemaRegistry,
+			ComputedColumns:    computedColumns,
+			LargeMessageHandle: largeMessageHandle,
 		}
 	}
 	return res
@@ -195,6 +322,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		CaseSensitive:         cloned.CaseSensitive,
 		EnableOldValue:        cloned.EnableOldValue,
 		ForceReplicate:        cloned.ForceReplicate,
+		InitialLoad:           cloned.InitialLoad,
 		IgnoreIneligibleTable: false,
 		CheckGCSafePoint:      cloned.CheckGCSafePoint,
 		EnableSyncPoint:       cloned.EnableSyncPoint,
@@ -250,6 +378,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Matcher:       rule.Matcher,
 				PartitionRule: rule.PartitionRule,
 				TopicRule:     rule.TopicRule,
+				Columns:       rule.Columns,
+				HashFunction:  rule.HashFunction,
 			})
 		}
 		var columnSelectors []*ColumnSelector
@@ -259,12 +389,32 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var computedColumns []*ComputedColumnRule
+		for _, rule := range cloned.Sink.ComputedColumns {
+			computed := &ComputedColumnRule{Matcher: rule.Matcher}
+			for _, col := range rule.Columns {
+				computed.Columns = append(computed.Columns, &ComputedColumn{
+					Name:       col.Name,
+					Expression: col.Expression,
+				})
+			}
+			computedColumns = append(computedColumns, computed)
+		}
+		var largeMessageHandle *LargeMessageHandleConfig
+		if cloned.Sink.LargeMessageHandle != nil {
+			largeMessageHandle = &LargeMessageHandleConfig{
+				LargeMessageHandleOption: cloned.Sink.LargeMessageHandle.LargeMessageHandleOption,
+				ClaimCheckStorageURI:     cloned.Sink.LargeMessageHandle.ClaimCheckStorageURI,
+			}
+		}
 		res.Sink = &SinkConfig{
-			Protocol:        cloned.Sink.Protocol,
-			SchemaRegistry:  cloned.Sink.SchemaRegistry,
-			DispatchRules:   dispatchRules,
-			ColumnSelectors: columnSelectors,
-			TxnAtomicity:    string(cloned.Sink.TxnAtomicity),
+			Protocol:           cloned.Sink.Protocol,
+			SchemaRegistry:     cloned.Sink.SchemaRegistry,
+			DispatchRules:      dispatchRules,
+			ColumnSelectors:    columnSelectors,
+			TxnAtomicity:       string(cloned.Sink.TxnAtomicity),
+			ComputedColumns:    computedColumns,
+			LargeMessageHandle: largeMessageHandle,
 		}
 	}
 	if cloned.Consistent != nil {
@@ -275,6 +425,56 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			Storage:           cloned.Consistent.Storage,
 		}
 	}
+	for _, rule := range cloned.RoutingRules {
+		res.RoutingRules = append(res.RoutingRules, RoutingRule{
+			Matcher:      rule.Matcher,
+			TargetSchema: rule.TargetSchema,
+			TargetTable:  rule.TargetTable,
+		})
+	}
+	if cloned.BDR != nil {
+		res.BDR = &BDRConfig{
+			Enable:             cloned.BDR.Enable,
+			ReplicaID:          cloned.BDR.ReplicaID,
+			FilterReplicaIDs:   cloned.BDR.FilterReplicaIDs,
+			ConflictResolution: cloned.BDR.ConflictResolution,
+			VersionColumn:      cloned.BDR.VersionColumn,
+			ReplicateDDL:       cloned.BDR.ReplicateDDL,
+		}
+	}
+	for _, policy := range cloned.DDLPolicies {
+		res.DDLPolicies = append(res.DDLPolicies, toAPIDDLPolicy(policy))
+	}
+	if cloned.SchemaHistory != nil {
+		res.SchemaHistory = &SchemaHistoryConfig{
+			Storage: cloned.SchemaHistory.Storage,
+		}
+	}
+	if cloned.RateLimit != nil {
+		res.RateLimit = &RateLimitConfig{
+			RowsPerSecond:  cloned.RateLimit.RowsPerSecond,
+			BytesPerSecond: cloned.RateLimit.BytesPerSecond,
+			TimeZone:       cloned.RateLimit.TimeZone,
+		}
+		for _, s := range cloned.RateLimit.Schedules {
+			res.RateLimit.Schedules = append(res.RateLimit.Schedules,
+				RateLimitSchedule{
+					Start:          s.Start,
+					End:            s.End,
+					RowsPerSecond:  s.RowsPerSecond,
+					BytesPerSecond: s.BytesPerSecond,
+					Pause:          s.Pause,
+				})
+		}
+	}
+	if cloned.Heartbeat != nil {
+		res.Heartbeat = &HeartbeatConfig{
+			Enable:       cloned.Heartbeat.Enable,
+			UpstreamURI:  cloned.Heartbeat.UpstreamURI,
+			Table:        cloned.Heartbeat.Table,
+			IntervalInMs: cloned.Heartbeat.IntervalInMs,
+		}
+	}
 	return res
 }
 
@@ -300,6 +500,97 @@ func GetDefaultReplicaConfig() *ReplicaConfig {
 	}
 }
 
+// RoutingRule routes the matched tables to tables of the downstream with
+// other names. This is a duplicate of config.RoutingRule
+type RoutingRule struct {
+	Matcher      []string `json:"matcher"`
+	TargetSchema string   `json:"target_schema"`
+	TargetTable  string   `json:"target_table"`
+}
+
+// BDRConfig represents the bidirectional replication config of a changefeed.
+// This is a duplicate of config.BDRConfig
+type BDRConfig struct {
+	Enable             bool     `json:"enable"`
+	ReplicaID          uint64   `json:"replica_id"`
+	FilterReplicaIDs   []uint64 `json:"filter_replica_ids"`
+	ConflictResolution string   `json:"conflict_resolution"`
+	VersionColumn      string   `json:"version_column"`
+	ReplicateDDL       bool     `json:"replicate_ddl"`
+}
+
+// DDLPolicy decides how the matched DDLs are replicated.
+// This is a duplicate of config.DDLPolicy
+type DDLPolicy struct {
+	Matcher  []string `json:"matcher"`
+	DDLTypes []string `json:"ddl_types"`
+	Action   string   `json:"action"`
+	Rewrites []string `json:"rewrites"`
+}
+
+func (p DDLPolicy) toInternalDDLPolicy() *config.DDLPolicy {
+	res := &config.DDLPolicy{
+		Matcher: p.Matcher,
+		Action:  config.DDLPolicyAction(p.Action),
+	}
+	for _, tp := range p.DDLTypes {
+		res.DDLTypes = append(res.DDLTypes, bf.EventType(tp))
+	}
+	for _, rewrite := range p.Rewrites {
+		res.Rewrites = append(res.Rewrites, config.DDLRewrite(rewrite))
+	}
+	return res
+}
+
+func toAPIDDLPolicy(p *config.DDLPolicy) DDLPolicy {
+	res := DDLPolicy{
+		Matcher: p.Matcher,
+		Action:  string(p.Action),
+	}
+	for _, tp := range p.DDLTypes {
+		res.DDLTypes = append(res.DDLTypes, string(tp))
+	}
+	for _, rewrite := range p.Rewrites {
+		res.Rewrites = append(res.Rewrites, string(rewrite))
+	}
+	return res
+}
+
+// SchemaHistoryConfig is the config of persisting the versions of table
+// definitions. This is a duplicate of config.SchemaHistoryConfig
+type SchemaHistoryConfig struct {
+	Storage string `json:"storage"`
+}
+
+// RateLimitConfig is the throughput limits of a changefeed.
+// This is a duplicate of config.RateLimitConfig
+type RateLimitConfig struct {
+	RowsPerSecond  uint64              `json:"rows_per_second"`
+	BytesPerSecond uint64              `json:"bytes_per_second"`
+	TimeZone       string              `json:"time_zone"`
+	Schedules      []RateLimitSchedule `json:"schedules,omitempty"`
+}
+
+// RateLimitSchedule is the limits in a time window of every day.
+// This is a duplicate of config.RateLimitSchedule
+type RateLimitSchedule struct {
+	Start          string `json:"start"`
+	End            string `json:"end"`
+	RowsPerSecond  uint64 `json:"rows_per_second"`
+	BytesPerSecond uint64 `json:"bytes_per_second"`
+	Pause          bool   `json:"pause"`
+}
+
+// HeartbeatConfig is the config of measuring the end-to-end replication
+// latency by heartbeats.
+// This is a duplicate of config.HeartbeatConfig
+type HeartbeatConfig struct {
+	Enable       bool   `json:"enable"`
+	UpstreamURI  string `json:"upstream_uri"`
+	Table        string `json:"table"`
+	IntervalInMs int64  `json:"interval_in_ms"`
+}
+
 // FilterConfig represents filter config for a changefeed
 // This is a duplicate of config.FilterConfig
 type FilterConfig struct {
@@ -395,6 +686,9 @@ type SinkConfig struct {
 	DispatchRules   []*DispatchRule   `json:"dispatchers,omitempty"`
 	ColumnSelectors []*ColumnSelector `json:"column_selectors"`
 	TxnAtomicity    string            `json:"transaction_atomicity"`
+
+	ComputedColumns    []*ComputedColumnRule     `json:"computed_columns,omitempty"`
+	LargeMessageHandle *LargeMessageHandleConfig `json:"large_message_handle,omitempty"`
 }
 
 // DispatchRule represents partition rule for a table
@@ -403,6 +697,8 @@ type DispatchRule struct {
 	Matcher       []string `json:"matcher,omitempty"`
 	PartitionRule string   `json:"partition"`
 	TopicRule     string   `json:"topic"`
+	Columns       []string `json:"columns,omitempty"`
+	HashFunction  string   `json:"hash_function,omitempty"`
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -412,6 +708,28 @@ type ColumnSelector struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
+// ComputedColumnRule represents the computed columns of tables.
+// This is a duplicate of config.ComputedColumnRule
+type ComputedColumnRule struct {
+	Matcher []string          `json:"matcher,omitempty"`
+	Columns []*ComputedColumn `json:"columns,omitempty"`
+}
+
+// ComputedColumn represents a column derived from an expression.
+// This is a duplicate of config.ComputedColumn
+type ComputedColumn struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// LargeMessageHandleConfig represents how to handle the rows exceeding
+// max-message-bytes of MQ sinks.
+// This is a duplicate of config.LargeMessageHandleConfig
+type LargeMessageHandleConfig struct {
+	LargeMessageHandleOption string `json:"large_message_handle_option"`
+	ClaimCheckStorageURI     string `json:"claim_check_storage_uri"`
+}
+
 // ConsistentConfig represents replication consistency config for a changefeed
 // This is a duplicate of config.ConsistentConfig
 type ConsistentConfig struct {
@@ -452,6 +770,77 @@ type ChangeFeedInfo struct {
 	State          model.FeedState    `json:"state,omitempty"`
 	Error          *RunningError      `json:"error,omitempty"`
 	CreatorVersion string             `json:"creator_version,omitempty"`
+	TableSetChange *TableSetChange    `json:"table_set_change,omitempty"`
+	FanInGroup     string             `json:"fan_in_group,omitempty"`
+	DDLApproval    *DDLApproval       `json:"ddl_approval,omitempty"`
+}
+
+// TableSetChangeConfig is used by the api to change the tables replicated
+// by a running changefeed.
+type TableSetChangeConfig struct {
+	// AddRules are the table filter rules of the tables to be added.
+	AddRules []string `json:"add_rules"`
+	// RemoveRules are the table filter rules of the tables to be removed.
+	RemoveRules []string `json:"remove_rules"`
+	// StartTs is the ts the change takes effect at, it must not be garbage
+	// collected. If the changefeed has passed it, the changefeed is restarted
+	// from it, and events after it are replicated again. A ts a few seconds
+	// later than the current ts is used if it's not specified.
+	StartTs uint64 `json:"start_ts"`
+	// InitialLoad indicates whether the snapshots of the added tables at
+	// StartTs are loaded before their incremental data.
+	InitialLoad bool `json:"initial_load"`
+}
+
+// TableSetChange is a change of tables replicated by a changefeed.
+type TableSetChange struct {
+	Rules       []string `json:"rules"`
+	StartTs     uint64   `json:"start_ts"`
+	InitialLoad bool     `json:"initial_load"`
+	Applied     bool     `json:"applied"`
+}
+
+// ApproveDDLConfig is used by the api to approve or reject the DDL held by
+// the DDL policies of a changefeed.
+type ApproveDDLConfig struct {
+	// CommitTs is the commit ts of the DDL waiting for approval.
+	CommitTs uint64 `json:"commit_ts"`
+	// Reject rejects the DDL, the rejected DDL is skipped.
+	Reject bool `json:"reject"`
+}
+
+// DDLApproval is a DDL held by the DDL policies of a changefeed.
+type DDLApproval struct {
+	CommitTs uint64   `json:"commit_ts"`
+	Queries  []string `json:"queries"`
+	State    string   `json:"state"`
+}
+
+// TableSchema is the definition of a table at a ts.
+type TableSchema struct {
+	Ts      uint64         `json:"ts"`
+	Schema  string         `json:"schema"`
+	Table   string         `json:"table"`
+	TableID int64          `json:"table_id"`
+	Columns []ColumnSchema `json:"columns"`
+	Indexes []IndexSchema  `json:"indexes"`
+}
+
+// ColumnSchema is a column of a table definition.
+type ColumnSchema struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Nullable  bool   `json:"nullable"`
+	Default   any    `json:"default,omitempty"`
+	Generated bool   `json:"generated,omitempty"`
+}
+
+// IndexSchema is an index of a table definition.
+type IndexSchema struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Primary bool     `json:"primary,omitempty"`
+	Unique  bool     `json:"unique,omitempty"`
 }
 
 // RunningError represents some running error from cdc components, such as processor.
@@ -505,3 +894,58 @@ type UpstreamConfig struct {
 	ID uint64 `json:"id"`
 	PDConfig
 }
+
+// FanInChangefeedConfig is used by the api to create a multi-upstream
+// changefeed, which merges tables of several upstreams into one sink. It's
+// made up of one member changefeed for each upstream. Members replicate
+// their upstreams with their own checkpoints and DDLs. Tables of different
+// upstreams can be routed to the same downstream table only if they have the
+// same columns and primary key.
+type FanInChangefeedConfig struct {
+	Namespace     string                 `json:"namespace"`
+	ID            string                 `json:"changefeed_id"`
+	TargetTs      uint64                 `json:"target_ts"`
+	SinkURI       string                 `json:"sink_uri"`
+	Engine        string                 `json:"engine"`
+	ReplicaConfig *ReplicaConfig         `json:"replica_config"`
+	Upstreams     []*FanInUpstreamConfig `json:"upstreams"`
+}
+
+// FanInUpstreamConfig is the config of an upstream of a multi-upstream
+// changefeed.
+type FanInUpstreamConfig struct {
+	StartTs uint64 `json:"start_ts"`
+	// RoutingRules are applied to tables of this upstream before the
+	// routing rules in the replica config.
+	RoutingRules []RoutingRule `json:"routing_rules"`
+	PDConfig
+}
+
+// FanInChangefeedInfo is the replication progress of a multi-upstream
+// changefeed.
+type FanInChangefeedInfo struct {
+	ID        string                `json:"id"`
+	Upstreams []FanInUpstreamStatus `json:"upstreams"`
+}
+
+// FanInUpstreamStatus is the replication progress of an upstream of a
+// multi-upstream changefeed.
+type FanInUpstreamStatus struct {
+	ChangefeedID   string          `json:"changefeed_id"`
+	UpstreamID     uint64          `json:"upstream_id"`
+	State          model.FeedState `json:"state"`
+	CheckpointTs   uint64          `json:"checkpoint_ts"`
+	CheckpointTime model.JSONTime  `json:"checkpoint_time"`
+	// CheckpointLag is the lag of the checkpoint in seconds.
+	CheckpointLag float64       `json:"checkpoint_lag"`
+	Error         *RunningError `json:"error,omitempty"`
+}
+
+// ChangefeedEvent is an event in the lifecycle of a changefeed, such as
+// being paused, meeting an error or applying a DDL.
+type ChangefeedEvent struct {
+	Time    time.Time                 `json:"time"`
+	Type    model.ChangefeedEventType `json:"type"`
+	Message string                    `json:"message,omitempty"`
+	Error   *RunningError             `json:"error,omitempty"`
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/schemahistory"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	apiOpVarTable = "table"
+	apiOpVarTs    = "ts"
+)
+
+// getChangefeedTableSchema returns the definition of a table of the upstream
+// of the changefeed at the given ts, the checkpoint ts of the changefeed is
+// used if the ts is not specified. The ts must not be less than the GC
+// safepoint of the upstream.
+func (h *OpenAPIV2) getChangefeedTableSchema(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	schemaName, tableName, ok := strings.Cut(c.Query(apiOpVarTable), ".")
+	if !ok || schemaName == "" || tableName == "" {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid table: %s, it should be like schema.table", c.Query(apiOpVarTable)))
+		return
+	}
+	var ts uint64
+	if tsStr := c.Query(apiOpVarTs); tsStr != "" {
+		var err error
+		ts, err = strconv.ParseUint(tsStr, 10, 64)
+		if err != nil || ts == 0 {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid ts: %s", tsStr))
+			return
+		}
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if ts == 0 {
+		status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		ts = status.CheckpointTs
+	}
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	up, ok := upManager.Get(info.UpstreamID)
+	if !ok {
+		_ = c.Error(cerror.ErrUpstreamNotFound.GenWithStackByArgs(info.UpstreamID))
+		return
+	}
+
+	tableInfo, err := h.helpers.getTableInfoAt(up.KVStorage, ts, schemaName, tableName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	record := schemahistory.NewRecord(ts, schemaName, tableName, tableInfo, "")
+	c.JSON(http.StatusOK, toAPITableSchema(record))
+}
+
+func toAPITableSchema(r *schemahistory.Record) *TableSchema {
+	res := &TableSchema{
+		Ts:      r.Version,
+		Schema:  r.Schema,
+		Table:   r.Table,
+		TableID: r.TableID,
+		Columns: make([]ColumnSchema, 0, len(r.Columns)),
+		Indexes: make([]IndexSchema, 0, len(r.Indexes)),
+	}
+	for _, col := range r.Columns {
+		res.Columns = append(res.Columns, ColumnSchema{
+			Name:      col.Name,
+			Type:      col.Type,
+			Nullable:  col.Nullable,
+			Default:   col.Default,
+			Generated: col.Generated,
+		})
+	}
+	for _, idx := range r.Indexes {
+		res.Indexes = append(res.Indexes, IndexSchema{
+			Name:    idx.Name,
+			Columns: idx.Columns,
+			Primary: idx.Primary,
+			Unique:  idx.Unique,
+		})
+	}
+	return res
+}
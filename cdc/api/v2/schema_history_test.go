@@ -0,0 +1,106 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChangefeedTableSchema(t *testing.T) {
+	t.Parallel()
+
+	get := testCase{url: "/api/v2/changefeeds/%s/schema?%s", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{
+		changefeedInfo:   &model.ChangeFeedInfo{ID: "abc"},
+		changefeedStatus: &model.ChangeFeedStatus{CheckpointTs: 100},
+	}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+
+	request := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), get.method,
+			fmt.Sprintf(get.url, "abc", query), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: invalid table
+	w := request("table=t1")
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: invalid ts
+	w = request("table=test.t1&ts=abc")
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 3: the table is not found at the ts
+	helpers.EXPECT().getTableInfoAt(gomock.Any(), uint64(50), "test", "t1").
+		Return(nil, cerror.ErrTableNotFoundAtTs.GenWithStackByArgs("test", "t1", 50))
+	w = request("table=test.t1&ts=50")
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrTableNotFoundAtTs")
+
+	// case 4: the checkpoint ts is used by default
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.NotNullFlag)
+	tableInfo := model.WrapTableInfo(1, "test", 100, &timodel.TableInfo{
+		ID:   10,
+		Name: timodel.NewCIStr("t1"),
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("a"),
+			FieldType: *ft,
+			State:     timodel.StatePublic,
+		}},
+	})
+	helpers.EXPECT().getTableInfoAt(gomock.Any(), uint64(100), "test", "t1").
+		Return(tableInfo, nil)
+	w = request("table=test.t1")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := TableSchema{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, uint64(100), resp.Ts)
+	require.Equal(t, int64(10), resp.TableID)
+	require.Len(t, resp.Columns, 1)
+	require.Equal(t, "a", resp.Columns[0].Name)
+	require.False(t, resp.Columns[0].Nullable)
+}
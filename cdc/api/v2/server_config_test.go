@@ -0,0 +1,77 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadServerConfig(t *testing.T) {
+	original := config.GetGlobalServerConfig()
+	defer func() {
+		config.StoreGlobalServerConfig(original)
+		config.SetServerConfigLoader(nil)
+	}()
+
+	reload := testCase{url: "/api/v2/config/reload", method: "POST"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t)))
+	router := newRouter(apiV2)
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(false).AnyTimes()
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			reload.method, reload.url, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: the server config can't be loaded
+	w := request()
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrServerConfigReloadFailed")
+
+	// case 2: success, it's served by a capture which is not the owner
+	config.StoreGlobalServerConfig(config.GetDefaultServerConfig())
+	config.SetServerConfigLoader(func() (*config.ServerConfig, error) {
+		cfg := config.GetDefaultServerConfig()
+		cfg.KVClient.RegionScanLimit = 10
+		cfg.Addr = "127.0.0.1:8301"
+		return cfg, nil
+	})
+	w = request()
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ServerConfigReloadResult{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, ServerConfigReloadResult{
+		Applied:            []string{},
+		AppliedToNewTables: []string{"kv-client.region-scan-limit"},
+		RequireRestart:     []string{"addr"},
+	}, resp)
+	require.Equal(t, 10, config.GetGlobalServerConfig().KVClient.RegionScanLimit)
+}
@@ -0,0 +1,283 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/tikv/client-go/v2/oracle"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultTableSetChangeDelay is how long after the current ts a table set
+	// change takes effect if its start ts is not specified. It leaves time
+	// for processors to learn the change before their ddl pullers pass the
+	// start ts.
+	defaultTableSetChangeDelay = 5 * time.Second
+
+	// The changefeed is paused by the owner in its next tick, it's waited for
+	// at most about 30s before the tables are changed.
+	tableSetChangePauseBackoffBaseDelayInMs = 100
+	tableSetChangePauseBackoffMaxDelayInMs  = 1000
+	tableSetChangePauseMaxTries             = 40
+)
+
+// updateChangefeedTables handles the request to add tables to or remove
+// tables from a running changefeed, tables are changed at the given start ts
+// without recreating the changefeed. The start ts can be any ts not garbage
+// collected, if the changefeed has passed it, the changefeed is restarted
+// from it.
+func (h *OpenAPIV2) updateChangefeedTables(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	cfg := new(TableSetChangeConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if len(cfg.AddRules) == 0 && len(cfg.RemoveRules) == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"no tables to add or remove"))
+		return
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	rules := changeTableRules(info.Config.Filter.Rules, cfg.AddRules, cfg.RemoveRules)
+	if _, err := filter.VerifyTableRules(&config.FilterConfig{Rules: rules}); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	up, ok := upManager.Get(info.UpstreamID)
+	if !ok {
+		_ = c.Error(cerror.ErrUpstreamNotFound.GenWithStackByArgs(info.UpstreamID))
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	startTs := cfg.StartTs
+	if startTs == 0 {
+		physical, logical, err := up.PDClient.GetTS(ctx)
+		if err != nil {
+			_ = c.Error(cerror.WrapError(cerror.ErrInternalServerError, err))
+			return
+		}
+		startTs = oracle.GoTimeToTS(oracle.GetTimeFromTS(
+			oracle.ComposeTS(physical, logical)).Add(defaultTableSetChangeDelay))
+	}
+
+	change := &model.TableSetChange{
+		Rules:       rules,
+		StartTs:     startTs,
+		InitialLoad: cfg.InitialLoad,
+	}
+	if startTs > status.CheckpointTs {
+		err = api.HandleOwnerUpdateTableSet(ctx, h.capture, changefeedID, change)
+	} else if cfg.InitialLoad {
+		// All tables restart from the start ts, they would all load the
+		// snapshot at it.
+		err = cerror.ErrAPIInvalidParam.GenWithStack(
+			"initial load requires the start ts %d to be greater than "+
+				"the checkpoint ts %d", startTs, status.CheckpointTs)
+	} else if info.State != model.StateNormal || info.PendingTableSetChange() != nil {
+		err = cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"can only change tables of a running changefeed without a change in progress")
+	} else {
+		err = h.restartChangefeedWithTables(ctx, up.PDClient, changefeedID, change)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("tables of changefeed will be changed",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Strings("rules", rules),
+		zap.Uint64("startTs", startTs))
+	c.JSON(http.StatusOK, toAPITableSetChange(change))
+}
+
+// restartChangefeedWithTables changes the tables of a changefeed whose
+// checkpoint ts has passed the start ts of the change. The changefeed is
+// paused, its filter rules are replaced, and it's resumed from the start ts,
+// so events after the start ts are replicated again for all tables, as if it
+// is resumed with an overwritten checkpoint ts.
+func (h *OpenAPIV2) restartChangefeedWithTables(
+	ctx context.Context, pdClient pd.Client,
+	changefeedID model.ChangeFeedID, change *model.TableSetChange,
+) error {
+	etcdClient := h.capture.GetEtcdClient()
+	gcServiceID := etcdClient.GetEnsureGCServiceID(gc.EnsureGCServiceResuming)
+	// The start ts must not be garbage collected before the changefeed is
+	// resumed from it.
+	if err := h.helpers.verifyResumeChangefeedConfig(
+		ctx, pdClient, gcServiceID, changefeedID, change.StartTs); err != nil {
+		return errors.Trace(err)
+	}
+	resumed := false
+	defer func() {
+		if resumed {
+			return
+		}
+		if err := gc.UndoEnsureChangefeedStartTsSafety(
+			ctx, pdClient, gcServiceID, changefeedID); err != nil {
+			log.Warn("failed to remove the gc safepoint of the table set change",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.Error(err))
+		}
+	}()
+
+	if err := api.HandleOwnerJob(ctx, h.capture, model.AdminJob{
+		CfID: changefeedID,
+		Type: model.AdminStop,
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	resume := model.AdminJob{
+		CfID:                  changefeedID,
+		Type:                  model.AdminResume,
+		OverwriteCheckpointTs: change.StartTs,
+	}
+	if err := h.replaceChangefeedTables(ctx, changefeedID, change); err != nil {
+		// The changefeed is resumed from its checkpoint with the former rules.
+		resume.OverwriteCheckpointTs = 0
+		if resumeErr := api.HandleOwnerJob(ctx, h.capture, resume); resumeErr != nil {
+			log.Warn("failed to resume the changefeed after changing tables failed",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.Error(resumeErr))
+		}
+		return errors.Trace(err)
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, resume); err != nil {
+		return errors.Trace(err)
+	}
+	resumed = true
+	return nil
+}
+
+// replaceChangefeedTables waits until the changefeed is paused, then saves
+// the filter rules of the change as applied.
+func (h *OpenAPIV2) replaceChangefeedTables(
+	ctx context.Context, changefeedID model.ChangeFeedID, change *model.TableSetChange,
+) error {
+	var info *model.ChangeFeedInfo
+	err := retry.Do(ctx, func() error {
+		var err error
+		info, err = h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if info.State != model.StateStopped {
+			return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+				"the changefeed is not paused yet")
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(tableSetChangePauseBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(tableSetChangePauseBackoffMaxDelayInMs),
+		retry.WithMaxTries(tableSetChangePauseMaxTries),
+		retry.WithIsRetryableErr(cerror.ErrChangefeedUpdateRefused.Equal))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err = info.Clone()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	change.Applied = true
+	info.Config.Filter.Rules = change.Rules
+	info.TableSetChange = change
+
+	etcdClient := h.capture.GetEtcdClient()
+	upInfo, err := etcdClient.GetUpstreamInfo(ctx, info.UpstreamID, info.Namespace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(
+		etcdClient.UpdateChangefeedAndUpstream(ctx, upInfo, info, changefeedID))
+}
+
+// changeTableRules returns the table filter rules after removing and adding
+// the given rules. A removed rule is dropped if it's in the rules, otherwise
+// its negation is appended. An added rule drops its negation and is moved
+// to the end, so it takes precedence over the former rules.
+func changeTableRules(rules, addRules, removeRules []string) []string {
+	res := make([]string, 0, len(rules)+len(addRules)+len(removeRules))
+	res = append(res, rules...)
+	for _, r := range removeRules {
+		removed := removeRule(res, r)
+		if len(removed) == len(res) {
+			removed = append(removed, "!"+r)
+		}
+		res = removed
+	}
+	for _, r := range addRules {
+		res = removeRule(removeRule(res, "!"+r), r)
+		res = append(res, r)
+	}
+	return res
+}
+
+// removeRule returns the rules without the given rule.
+func removeRule(rules []string, rule string) []string {
+	res := make([]string, 0, len(rules))
+	for _, r := range rules {
+		if r != rule {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func toAPITableSetChange(change *model.TableSetChange) *TableSetChange {
+	return &TableSetChange{
+		Rules:       change.Rules,
+		StartTs:     change.StartTs,
+		InitialLoad: change.InitialLoad,
+		Applied:     change.Applied,
+	}
+}
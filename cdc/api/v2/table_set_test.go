@@ -0,0 +1,190 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// gcSafePointPDClient mocks a PD client with the given min service GC safepoint.
+type gcSafePointPDClient struct {
+	*mockPDClient
+	minServiceGCTs uint64
+}
+
+// UpdateServiceGCSafePoint returns the min service GC safepoint.
+func (c *gcSafePointPDClient) UpdateServiceGCSafePoint(ctx context.Context,
+	serviceID string, ttl int64, safePoint uint64,
+) (uint64, error) {
+	return c.minServiceGCTs, nil
+}
+
+func TestChangeTableRules(t *testing.T) {
+	t.Parallel()
+
+	rules := changeTableRules([]string{"test.*", "!test.t3"},
+		[]string{"test.t3", "test2.t1"}, []string{"test.t1", "test.*"})
+	require.Equal(t, []string{"!test.t1", "test.t3", "test2.t1"}, rules)
+
+	rules = changeTableRules([]string{"*.*"}, nil, []string{"test.t1"})
+	require.Equal(t, []string{"*.*", "!test.t1"}, rules)
+}
+
+func TestUpdateChangefeedTables(t *testing.T) {
+	t.Parallel()
+
+	update := testCase{url: "/api/v2/changefeeds/%s/tables", method: "POST"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	currentTs := oracle.ComposeTS(1000, 0)
+	pdClient := &gcSafePointPDClient{
+		mockPDClient:   &mockPDClient{logicTime: 1000},
+		minServiceGCTs: currentTs - 1,
+	}
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{
+			UpstreamID: 1,
+			Namespace:  model.DefaultNamespace,
+			ID:         "abc",
+			State:      model.StateNormal,
+			Config: &config.ReplicaConfig{
+				Filter: &config.FilterConfig{Rules: []string{"test.t1"}},
+			},
+		},
+		changefeedStatus: &model.ChangeFeedStatus{CheckpointTs: currentTs},
+	}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(pdClient), nil).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+
+	post := func(cfg *TableSetChangeConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), update.method,
+			fmt.Sprintf(update.url, "abc"), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: nothing to change
+	w := post(&TableSetChangeConfig{})
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: initial load with a start ts passed by the changefeed
+	w = post(&TableSetChangeConfig{
+		AddRules:    []string{"test.t2"},
+		StartTs:     currentTs,
+		InitialLoad: true,
+	})
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 3: success
+	owner.EXPECT().UpdateTableSet(model.DefaultChangeFeedID("abc"), gomock.Any(), gomock.Any()).
+		Do(func(_ model.ChangeFeedID, change *model.TableSetChange, done chan<- error) {
+			require.Equal(t, []string{"test.t1", "test.t2"}, change.Rules)
+			require.Equal(t, currentTs+1, change.StartTs)
+			require.True(t, change.InitialLoad)
+			close(done)
+		})
+	w = post(&TableSetChangeConfig{
+		AddRules:    []string{"test.t2"},
+		StartTs:     currentTs + 1,
+		InitialLoad: true,
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := TableSetChange{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, currentTs+1, resp.StartTs)
+	require.False(t, resp.Applied)
+
+	// case 4: the start ts is garbage collected
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	w = post(&TableSetChangeConfig{
+		AddRules: []string{"test.t3"},
+		StartTs:  currentTs - 1,
+	})
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrStartTsBeforeGC")
+
+	// case 5: the changefeed is restarted from a start ts it has passed
+	pdClient.minServiceGCTs = currentTs - 2
+	etcdClient.EXPECT().
+		GetUpstreamInfo(gomock.Any(), uint64(1), model.DefaultNamespace).
+		Return(&model.UpstreamInfo{ID: 1}, nil)
+	etcdClient.EXPECT().
+		UpdateChangefeedAndUpstream(gomock.Any(), gomock.Any(), gomock.Any(),
+			model.DefaultChangeFeedID("abc")).
+		Do(func(_ context.Context, _ *model.UpstreamInfo,
+			info *model.ChangeFeedInfo, _ model.ChangeFeedID,
+		) {
+			require.Equal(t, []string{"test.t1", "test.t3"}, info.Config.Filter.Rules)
+			require.True(t, info.TableSetChange.Applied)
+			require.Equal(t, currentTs-1, info.TableSetChange.StartTs)
+		}).Return(nil)
+	var jobs []model.AdminJob
+	owner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(job model.AdminJob, done chan<- error) {
+			jobs = append(jobs, job)
+			if job.Type == model.AdminStop {
+				statusProvider.changefeedInfo.State = model.StateStopped
+			}
+			close(done)
+		}).Times(2)
+	w = post(&TableSetChangeConfig{
+		AddRules: []string{"test.t3"},
+		StartTs:  currentTs - 1,
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = TableSetChange{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, currentTs-1, resp.StartTs)
+	require.True(t, resp.Applied)
+	require.Len(t, jobs, 2)
+	require.Equal(t, model.AdminStop, jobs[0].Type)
+	require.Equal(t, model.AdminResume, jobs[1].Type)
+	require.Equal(t, currentTs-1, jobs[1].OverwriteCheckpointTs)
+}
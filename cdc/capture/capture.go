@@ -61,6 +61,11 @@ type Capture interface {
 	Info() (model.CaptureInfo, error)
 	StatusProvider() owner.StatusProvider
 	WriteDebugInfo(ctx context.Context, w io.Writer)
+	// Diagnose returns the replication progress of tables of the given
+	// changefeed replicated by this capture.
+	Diagnose(
+		ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int,
+	) ([]model.TableDiagnosis, error)
 
 	GetUpstreamManager() (*upstream.Manager, error)
 	GetEtcdClient() etcd.CDCEtcdClient
@@ -643,6 +648,38 @@ func (c *captureImpl) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait(doneM)
 }
 
+// Diagnose returns the replication progress of tables of the given
+// changefeed replicated by this capture.
+func (c *captureImpl) Diagnose(
+	ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int,
+) ([]model.TableDiagnosis, error) {
+	tableCh := make(chan []model.TableDiagnosis, 1)
+	done := make(chan error, 1)
+	c.captureMu.Lock()
+	if c.processorManager == nil {
+		c.captureMu.Unlock()
+		return nil, cerror.ErrCaptureNotInitialized.GenWithStackByArgs()
+	}
+	c.processorManager.QueryDiagnosis(ctx, changefeedID, regionLimit, tableCh, done)
+	// Release the lock before waiting, see WriteDebugInfo.
+	c.captureMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	select {
+	case tables := <-tableCh:
+		return tables, nil
+	default:
+		return nil, nil
+	}
+}
+
 // IsOwner returns whether the capture is an owner
 func (c *captureImpl) IsOwner() bool {
 	c.ownerMu.Lock()
@@ -51,6 +51,21 @@ func (mr *MockCaptureMockRecorder) AsyncClose() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncClose", reflect.TypeOf((*MockCapture)(nil).AsyncClose))
 }
 
+// Diagnose mocks base method.
+func (m *MockCapture) Diagnose(ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int) ([]model.TableDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diagnose", ctx, changefeedID, regionLimit)
+	ret0, _ := ret[0].([]model.TableDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diagnose indicates an expected call of Diagnose.
+func (mr *MockCaptureMockRecorder) Diagnose(ctx, changefeedID, regionLimit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diagnose", reflect.TypeOf((*MockCapture)(nil).Diagnose), ctx, changefeedID, regionLimit)
+}
+
 // Drain mocks base method.
 func (m *MockCapture) Drain() <-chan struct{} {
 	m.ctrl.T.Helper()
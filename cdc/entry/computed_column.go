@@ -0,0 +1,263 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/expression"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type computedColumnRule struct {
+	tableMatcher tfilter.Filter
+	columns      []*config.ComputedColumn
+}
+
+// computedColumn is a computed column of a table, which is evaluated by
+// the same expression engine as the expression filter.
+type computedColumn struct {
+	name    string
+	expr    expression.Expression
+	colInfo *timodel.ColumnInfo
+	flag    model.ColumnFlagType
+}
+
+// computedTable caches the computed columns of a table until its table info
+// is changed.
+type computedTable struct {
+	tableInfoVersion uint64
+	columns          []*computedColumn
+}
+
+// ColumnComputer appends computed columns to the rows of tables matched by
+// the computed column rules of the sink config. Computed columns are only
+// allowed for MQ sinks, see SinkConfig.ComputedColumns.
+type ColumnComputer struct {
+	mu      sync.Mutex
+	sessCtx sessionctx.Context
+	rules   []*computedColumnRule
+	tables  map[string]*computedTable // tableName -> computed columns
+}
+
+// NewColumnComputer creates a ColumnComputer, it returns nil if no computed
+// column is configured. All methods of a nil ColumnComputer are no-ops.
+func NewColumnComputer(cfg *config.ReplicaConfig, tz string) (*ColumnComputer, error) {
+	if cfg.Sink == nil || len(cfg.Sink.ComputedColumns) == 0 {
+		return nil, nil
+	}
+	c := &ColumnComputer{
+		sessCtx: utils.NewSessionCtx(map[string]string{
+			"time_zone": tz,
+		}),
+		tables: make(map[string]*computedTable),
+	}
+	for _, rule := range cfg.Sink.ComputedColumns {
+		tf, err := tfilter.Parse(rule.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, rule.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		c.rules = append(c.rules, &computedColumnRule{
+			tableMatcher: tf,
+			columns:      rule.Columns,
+		})
+	}
+	return c, nil
+}
+
+// Verify checks whether the computed columns can be evaluated on the tables.
+// It should only be called by create changefeed OpenAPI.
+func (c *ColumnComputer) Verify(tableInfos []*model.TableInfo) error {
+	if c == nil {
+		return nil
+	}
+	for _, ti := range tableInfos {
+		if _, err := c.buildColumns(ti); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ColumnComputer) buildColumns(ti *model.TableInfo) ([]*computedColumn, error) {
+	var res []*computedColumn
+	tableName := ti.TableName.String()
+	for _, rule := range c.rules {
+		if !rule.tableMatcher.MatchTable(ti.TableName.Schema, ti.TableName.Table) {
+			continue
+		}
+		for _, col := range rule.columns {
+			if timodel.FindColumnInfo(ti.Columns, strings.ToLower(col.Name)) != nil {
+				return nil, cerror.ErrComputedColumnInvalid.GenWithStackByArgs(
+					col.Name, tableName, "it conflicts with a column of the table")
+			}
+			for _, computed := range res {
+				if strings.EqualFold(computed.name, col.Name) {
+					return nil, cerror.ErrComputedColumnInvalid.GenWithStackByArgs(
+						col.Name, tableName, "it is defined by multiple rules")
+				}
+			}
+			expr, err := expression.ParseSimpleExprWithTableInfo(
+				c.sessCtx, col.Expression, ti.TableInfo)
+			if err != nil {
+				log.Error("failed to parse computed column expression",
+					zap.String("table", tableName),
+					zap.String("column", col.Name),
+					zap.String("expression", col.Expression),
+					zap.Error(err))
+				return nil, cerror.ErrComputedColumnInvalid.GenWithStackByArgs(
+					col.Name, tableName, err.Error())
+			}
+			res = append(res, newComputedColumn(col.Name, expr))
+		}
+	}
+	return res, nil
+}
+
+// newComputedColumn infers the type of the computed column from the
+// expression.
+func newComputedColumn(name string, expr expression.Expression) *computedColumn {
+	ft := expr.GetType()
+	colInfo := &timodel.ColumnInfo{
+		Name:      timodel.NewCIStr(name),
+		FieldType: *ft,
+		State:     timodel.StatePublic,
+	}
+	var flag model.ColumnFlagType
+	if ft.GetCharset() == "binary" {
+		flag.SetIsBinary()
+	}
+	if !mysql.HasNotNullFlag(ft.GetFlag()) {
+		flag.SetIsNullable()
+	}
+	if mysql.HasUnsignedFlag(ft.GetFlag()) {
+		flag.SetIsUnsigned()
+	}
+	return &computedColumn{
+		name:    name,
+		expr:    expr,
+		colInfo: colInfo,
+		flag:    flag,
+	}
+}
+
+// The caller must hold c.mu.Lock() before calling this function.
+func (c *ColumnComputer) getColumns(ti *model.TableInfo) ([]*computedColumn, error) {
+	tableName := ti.TableName.String()
+	if table, ok := c.tables[tableName]; ok &&
+		table.tableInfoVersion == ti.TableInfoVersion {
+		return table.columns, nil
+	}
+	columns, err := c.buildColumns(ti)
+	if err != nil {
+		return nil, err
+	}
+	c.tables[tableName] = &computedTable{
+		tableInfoVersion: ti.TableInfoVersion,
+		columns:          columns,
+	}
+	return columns, nil
+}
+
+// Compute appends the computed columns to the columns and the pre-columns
+// of the row. The row must not be routed yet, since the rules match the
+// upstream tables.
+func (c *ColumnComputer) Compute(
+	row *model.RowChangedEvent,
+	rawRow model.RowChangedDatums,
+	ti *model.TableInfo,
+) error {
+	if c == nil || row == nil || ti == nil {
+		return nil
+	}
+	// Expressions share the session context, so they are evaluated serially
+	// as the ones of the expression filter.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	columns, err := c.getColumns(ti)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	if len(row.Columns) != 0 {
+		row.Columns, err = c.appendColumns(row.Columns, rawRow.RowDatums, columns)
+		if err != nil {
+			return err
+		}
+	}
+	if len(row.PreColumns) != 0 {
+		row.PreColumns, err = c.appendColumns(row.PreColumns, rawRow.PreRowDatums, columns)
+		if err != nil {
+			return err
+		}
+	}
+	// ColInfos is shared by the rows of the table, so it's copied before
+	// being appended.
+	colInfos := make([]rowcodec.ColInfo, len(row.ColInfos), len(row.ColInfos)+len(columns))
+	copy(colInfos, row.ColInfos)
+	for _, col := range columns {
+		colInfos = append(colInfos, rowcodec.ColInfo{Ft: &col.colInfo.FieldType})
+	}
+	row.ColInfos = colInfos
+	return nil
+}
+
+func (c *ColumnComputer) appendColumns(
+	cols []*model.Column, datums []types.Datum, columns []*computedColumn,
+) ([]*model.Column, error) {
+	r := chunk.MutRowFromDatums(datums).ToRow()
+	for _, col := range columns {
+		d, err := col.expr.Eval(r)
+		if err != nil {
+			log.Error("failed to eval computed column",
+				zap.String("column", col.name), zap.Error(err))
+			return nil, errors.Trace(err)
+		}
+		value, size, warn, err := formatColVal(d, col.colInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if warn != "" {
+			log.Warn(warn, zap.String("column", col.name))
+		}
+		cols = append(cols, &model.Column{
+			Name:             col.name,
+			Type:             col.colInfo.GetType(),
+			Charset:          col.colInfo.GetCharset(),
+			Flag:             col.flag,
+			Value:            value,
+			ApproximateBytes: size + sizeOfEmptyColumn,
+		})
+	}
+	return cols, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newComputedTestTableInfo(table string) *model.TableInfo {
+	ftID := types.NewFieldType(mysql.TypeLong)
+	ftID.SetFlag(mysql.NotNullFlag | mysql.PriKeyFlag)
+	ftPhone := types.NewFieldType(mysql.TypeVarchar)
+	ftPhone.SetFlen(20)
+	ftPhone.SetCharset("utf8mb4")
+	ftPhone.SetCollate("utf8mb4_bin")
+	return model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		ID:         100,
+		Name:       timodel.NewCIStr(table),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("id"),
+			Offset:    0,
+			FieldType: *ftID,
+			State:     timodel.StatePublic,
+		}, {
+			ID:        2,
+			Name:      timodel.NewCIStr("phone"),
+			Offset:    1,
+			FieldType: *ftPhone,
+			State:     timodel.StatePublic,
+		}},
+	})
+}
+
+func newComputedTestRow(ti *model.TableInfo) (*model.RowChangedEvent, model.RowChangedDatums) {
+	_, _, colInfos := ti.GetRowColInfos()
+	row := &model.RowChangedEvent{
+		Table:    &model.TableName{Schema: ti.TableName.Schema, Table: ti.TableName.Table},
+		ColInfos: colInfos,
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "phone", Type: mysql.TypeVarchar, Value: []byte("13800001111")},
+		},
+		PreColumns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "phone", Type: mysql.TypeVarchar, Value: []byte("15900001111")},
+		},
+	}
+	rawRow := model.RowChangedDatums{
+		RowDatums:    []types.Datum{types.NewIntDatum(1), types.NewStringDatum("13800001111")},
+		PreRowDatums: []types.Datum{types.NewIntDatum(1), types.NewStringDatum("15900001111")},
+	}
+	return row, rawRow
+}
+
+func TestColumnComputer(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	computer, err := NewColumnComputer(cfg, "UTC")
+	require.Nil(t, err)
+	require.Nil(t, computer)
+	require.Nil(t, computer.Compute(nil, model.RowChangedDatums{}, nil))
+
+	cfg.Sink.ComputedColumns = []*config.ComputedColumnRule{{
+		Matcher: []string{"test.t1"},
+		Columns: []*config.ComputedColumn{
+			{Name: "region", Expression: "substr(phone, 1, 3)"},
+			{Name: "next_id", Expression: "id + 1"},
+		},
+	}}
+	computer, err = NewColumnComputer(cfg, "UTC")
+	require.Nil(t, err)
+	require.Nil(t, computer.Verify([]*model.TableInfo{
+		newComputedTestTableInfo("t1"), newComputedTestTableInfo("t2"),
+	}))
+
+	ti := newComputedTestTableInfo("t1")
+	row, rawRow := newComputedTestRow(ti)
+	colInfosLen := len(row.ColInfos)
+	require.Nil(t, computer.Compute(row, rawRow, ti))
+	require.Len(t, row.Columns, 4)
+	require.Equal(t, "region", row.Columns[2].Name)
+	require.Equal(t, []byte("138"), row.Columns[2].Value)
+	require.True(t, row.Columns[2].Flag.IsNullable())
+	require.Equal(t, "next_id", row.Columns[3].Name)
+	require.Equal(t, mysql.TypeLonglong, row.Columns[3].Type)
+	require.Equal(t, int64(2), row.Columns[3].Value)
+	require.Len(t, row.PreColumns, 4)
+	require.Equal(t, []byte("159"), row.PreColumns[2].Value)
+	require.Len(t, row.ColInfos, colInfosLen+2)
+	// The column infos of the table are not changed.
+	_, _, colInfos := ti.GetRowColInfos()
+	require.Len(t, colInfos, colInfosLen)
+
+	// Tables not matched are not changed.
+	ti = newComputedTestTableInfo("t2")
+	row, rawRow = newComputedTestRow(ti)
+	require.Nil(t, computer.Compute(row, rawRow, ti))
+	require.Len(t, row.Columns, 2)
+	require.Len(t, row.PreColumns, 2)
+}
+
+func TestColumnComputerVerify(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.ComputedColumns = []*config.ComputedColumnRule{{
+		Matcher: []string{"test.*"},
+		Columns: []*config.ComputedColumn{{Name: "Phone", Expression: "id + 1"}},
+	}}
+	computer, err := NewColumnComputer(cfg, "UTC")
+	require.Nil(t, err)
+	err = computer.Verify([]*model.TableInfo{newComputedTestTableInfo("t1")})
+	require.Regexp(t, ".*Phone of table test.t1: it conflicts with a column.*", err)
+
+	cfg.Sink.ComputedColumns[0].Columns[0] = &config.ComputedColumn{
+		Name: "region", Expression: "substr(mobile, 1, 3)",
+	}
+	computer, err = NewColumnComputer(cfg, "UTC")
+	require.Nil(t, err)
+	err = computer.Verify([]*model.TableInfo{newComputedTestTableInfo("t1")})
+	require.Regexp(t, ".*ErrComputedColumnInvalid.*", err)
+
+	cfg.Sink.ComputedColumns = append(cfg.Sink.ComputedColumns, &config.ComputedColumnRule{
+		Matcher: []string{"test.t1"},
+		Columns: []*config.ComputedColumn{{Name: "Region", Expression: "id"}},
+	})
+	cfg.Sink.ComputedColumns[0].Columns[0].Expression = "substr(phone, 1, 3)"
+	computer, err = NewColumnComputer(cfg, "UTC")
+	require.Nil(t, err)
+	err = computer.Verify([]*model.TableInfo{newComputedTestTableInfo("t1")})
+	require.Regexp(t, ".*Region of table test.t1: it is defined by multiple rules.*", err)
+}
@@ -31,8 +31,10 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/bdr"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pfilter "github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -73,6 +75,8 @@ type mounterImpl struct {
 	enableOldValue               bool
 	changefeedID                 model.ChangeFeedID
 	filter                       pfilter.Filter
+	computer                     *ColumnComputer
+	markTracker                  *bdr.MarkTracker
 	metricMountDuration          prometheus.Observer
 	metricTotalRows              prometheus.Gauge
 	metricIgnoredDMLEventCounter prometheus.Counter
@@ -83,6 +87,8 @@ func NewMounter(schemaStorage SchemaStorage,
 	changefeedID model.ChangeFeedID,
 	tz *time.Location,
 	filter pfilter.Filter,
+	computer *ColumnComputer,
+	markTracker *bdr.MarkTracker,
 	enableOldValue bool,
 ) Mounter {
 	return &mounterImpl{
@@ -90,6 +96,8 @@ func NewMounter(schemaStorage SchemaStorage,
 		changefeedID:   changefeedID,
 		enableOldValue: enableOldValue,
 		filter:         filter,
+		computer:       computer,
+		markTracker:    markTracker,
 		metricMountDuration: mountDuration.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricTotalRows: totalRowsCountGauge.
@@ -121,6 +129,7 @@ func (m *mounterImpl) DecodeEvent(ctx context.Context, pEvent *model.Polymorphic
 	pEvent.Row = row
 	pEvent.RawKV.Value = nil
 	pEvent.RawKV.OldValue = nil
+	tracing.RecordStage(m.changefeedID, row.Table.TableID, row.CommitTs, tracing.StageMounter)
 	duration := time.Since(start)
 	if duration > time.Second {
 		m.metricMountDuration.Observe(duration.Seconds())
@@ -190,6 +199,19 @@ func (m *mounterImpl) unmarshalAndMountRowChanged(ctx context.Context, raw *mode
 				m.metricIgnoredDMLEventCounter.Inc()
 				return nil, nil
 			}
+			// Rows written by TiCDC are not replicated back in bdr mode.
+			if m.markTracker != nil {
+				ignore, err := m.markTracker.ShouldIgnoreRow(ctx, row)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if ignore {
+					return nil, nil
+				}
+			}
+			if err := m.computer.Compute(row, rawRow, tableInfo); err != nil {
+				return nil, errors.Trace(err)
+			}
 			return row, nil
 		}
 		return nil, nil
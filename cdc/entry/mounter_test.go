@@ -297,7 +297,7 @@ func testMounterDisableOldValue(t *testing.T, tc struct {
 	require.Nil(t, err)
 	mounter := NewMounter(scheamStorage,
 		model.DefaultChangeFeedID("c1"),
-		time.UTC, filter, false).(*mounterImpl)
+		time.UTC, filter, nil, nil, false).(*mounterImpl)
 	mounter.tz = time.Local
 	ctx := context.Background()
 
@@ -1004,7 +1004,7 @@ func TestDecodeEventIgnoreRow(t *testing.T) {
 
 	ts := schemaStorage.GetLastSnapshot().CurrentTs()
 	schemaStorage.AdvanceResolvedTs(ver.Ver)
-	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, true).(*mounterImpl)
+	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, nil, nil, true).(*mounterImpl)
 
 	type testCase struct {
 		schema  string
@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"github.com/pingcap/errors"
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/cdc/entry/schema"
+	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// TableInfoAt returns the definition of the table at the given ts. It's read
+// from the snapshot meta of the upstream, so the ts must not be less than the
+// GC safepoint of the upstream.
+func TableInfoAt(
+	storage tidbkv.Storage, ts uint64, schemaName, tableName string,
+) (*model.TableInfo, error) {
+	meta, err := kv.GetSnapshotMeta(storage, ts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snap, err := schema.NewSingleSnapshotFromMeta(meta, ts, false /* explicitTables */)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tableInfo, ok := snap.TableByName(schemaName, tableName)
+	if !ok {
+		return nil, cerror.ErrTableNotFoundAtTs.GenWithStackByArgs(
+			schemaName, tableName, ts)
+	}
+	return tableInfo, nil
+}
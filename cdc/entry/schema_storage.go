@@ -41,6 +41,8 @@ type SchemaStorage interface {
 	GetLastSnapshot() *schema.Snapshot
 	// HandleDDLJob creates a new snapshot in storage and handles the ddl job
 	HandleDDLJob(job *timodel.Job) error
+	// ReloadSnapshot creates a new snapshot in storage from the meta at ts
+	ReloadSnapshot(meta *timeta.Meta, ts uint64) error
 	// AdvanceResolvedTs advances the resolved ts
 	AdvanceResolvedTs(ts uint64)
 	// ResolvedTs returns the resolved ts of the schema storage
@@ -57,6 +59,12 @@ type schemaStorageImpl struct {
 	resolvedTs    uint64
 	schemaVersion int64
 
+	// reloadSnaps holds the snapshots built from a reloaded snapshot, they
+	// replace the stale snapshots once they catch up with the resolved ts.
+	// It's nil if no snapshot is being reloaded.
+	reloadSnaps         []*schema.Snapshot
+	reloadSchemaVersion int64
+
 	forceReplicate bool
 
 	id model.ChangeFeedID
@@ -161,26 +169,32 @@ func (s *schemaStorageImpl) GetLastSnapshot() *schema.Snapshot {
 
 // HandleDDLJob creates a new snapshot in storage and handles the ddl job
 func (s *schemaStorageImpl) HandleDDLJob(job *timodel.Job) error {
+	s.snapsMu.Lock()
+	defer s.snapsMu.Unlock()
+	// DDL jobs are handled on the reloaded snapshots until they catch up
+	// with the resolved ts, see ReloadSnapshot.
+	snaps, schemaVersion := &s.snaps, &s.schemaVersion
+	if s.reloadSnaps != nil {
+		snaps, schemaVersion = &s.reloadSnaps, &s.reloadSchemaVersion
+	}
 	if s.skipJob(job) {
-		s.schemaVersion = job.BinlogInfo.SchemaVersion
-		s.AdvanceResolvedTs(job.BinlogInfo.FinishedTS)
+		*schemaVersion = job.BinlogInfo.SchemaVersion
+		s.advanceResolvedTs(job.BinlogInfo.FinishedTS)
 		return nil
 	}
-	s.snapsMu.Lock()
-	defer s.snapsMu.Unlock()
 	var snap *schema.Snapshot
-	if len(s.snaps) > 0 {
-		lastSnap := s.snaps[len(s.snaps)-1]
+	if len(*snaps) > 0 {
+		lastSnap := (*snaps)[len(*snaps)-1]
 		// We use schemaVersion to check if an already-executed DDL job is processed for a second time.
 		// Unexecuted DDL jobs should have largest schemaVersions.
-		if job.BinlogInfo.FinishedTS <= lastSnap.CurrentTs() || job.BinlogInfo.SchemaVersion <= s.schemaVersion {
+		if job.BinlogInfo.FinishedTS <= lastSnap.CurrentTs() || job.BinlogInfo.SchemaVersion <= *schemaVersion {
 			log.Info("ignore foregone DDL",
 				zap.String("namespace", s.id.Namespace),
 				zap.String("changefeed", s.id.ID),
 				zap.String("DDL", job.Query),
 				zap.Int64("jobID", job.ID),
 				zap.Uint64("finishTs", job.BinlogInfo.FinishedTS),
-				zap.Int64("schemaVersion", s.schemaVersion),
+				zap.Int64("schemaVersion", *schemaVersion),
 				zap.Int64("jobSchemaVersion", job.BinlogInfo.SchemaVersion),
 			)
 			return nil
@@ -206,16 +220,75 @@ func (s *schemaStorageImpl) HandleDDLJob(job *timodel.Job) error {
 		zap.Stringer("job", job),
 		zap.Uint64("finishTs", job.BinlogInfo.FinishedTS))
 
-	s.snaps = append(s.snaps, snap)
-	s.schemaVersion = job.BinlogInfo.SchemaVersion
-	s.AdvanceResolvedTs(job.BinlogInfo.FinishedTS)
+	*snaps = append(*snaps, snap)
+	*schemaVersion = job.BinlogInfo.SchemaVersion
+	s.advanceResolvedTs(job.BinlogInfo.FinishedTS)
+	return nil
+}
+
+// ReloadSnapshot creates a new snapshot in storage from the meta at ts.
+// It's used when the schema of some tables in the snapshots is stale,
+// e.g. tables which were filtered out are added to the changefeed.
+//
+// If the storage has resolved beyond ts, the snapshots are not changed and
+// the resolved ts doesn't fall back. Instead, the reloaded snapshot is kept
+// separately, DDL jobs after ts are handled again on it by the caller, and
+// it replaces the snapshots after ts once the resolved ts is reached again.
+func (s *schemaStorageImpl) ReloadSnapshot(meta *timeta.Meta, ts uint64) error {
+	snap, err := schema.NewSnapshotFromMeta(meta, ts, s.forceReplicate)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	version, err := schema.GetSchemaVersion(meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.snapsMu.Lock()
+	defer s.snapsMu.Unlock()
+	s.reloadSnaps = []*schema.Snapshot{snap}
+	s.reloadSchemaVersion = version
+	log.Info("reload schema snapshot",
+		zap.String("namespace", s.id.Namespace),
+		zap.String("changefeed", s.id.ID),
+		zap.Uint64("ts", ts),
+		zap.Uint64("resolvedTs", s.ResolvedTs()),
+		zap.Int64("schemaVersion", version))
+	s.advanceResolvedTs(ts)
 	return nil
 }
 
 // AdvanceResolvedTs advances the resolved. Not thread safe.
 // NOTE: SHOULD NOT call it concurrently
 func (s *schemaStorageImpl) AdvanceResolvedTs(ts uint64) {
-	if ts > s.ResolvedTs() {
+	s.snapsMu.Lock()
+	defer s.snapsMu.Unlock()
+	s.advanceResolvedTs(ts)
+}
+
+// advanceResolvedTs advances the resolved ts, and replaces the snapshots
+// after the reload ts with the reloaded ones if they have caught up with the
+// resolved ts. The caller must hold snapsMu.
+func (s *schemaStorageImpl) advanceResolvedTs(ts uint64) {
+	resolvedTs := s.ResolvedTs()
+	if s.reloadSnaps != nil && ts >= resolvedTs {
+		reloadTs := s.reloadSnaps[0].CurrentTs()
+		i := sort.Search(len(s.snaps), func(i int) bool {
+			return s.snaps[i].CurrentTs() >= reloadTs
+		})
+		snaps := make([]*schema.Snapshot, 0, i+len(s.reloadSnaps))
+		snaps = append(snaps, s.snaps[:i]...)
+		s.snaps = append(snaps, s.reloadSnaps...)
+		s.schemaVersion = s.reloadSchemaVersion
+		s.reloadSnaps = nil
+		log.Info("reloaded schema snapshots replace the stale ones",
+			zap.String("namespace", s.id.Namespace),
+			zap.String("changefeed", s.id.ID),
+			zap.Uint64("reloadTs", reloadTs),
+			zap.Uint64("resolvedTs", ts),
+			zap.Int64("schemaVersion", s.schemaVersion))
+	}
+	if ts > resolvedTs {
 		atomic.StoreUint64(&s.resolvedTs, ts)
 	}
 }
@@ -697,6 +697,72 @@ func TestCreateSnapFromMeta(t *testing.T) {
 	require.Equal(t, dbInfo.Name.O, "test2")
 }
 
+func TestReloadSnapshot(t *testing.T) {
+	helper := NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("create table test.simple_test1 (id bigint primary key)")
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	startTs := ver.Ver
+	meta, err := kv.GetSnapshotMeta(helper.Storage(), startTs)
+	require.Nil(t, err)
+	storage, err := NewSchemaStorage(meta, startTs, false, model.DefaultChangeFeedID("test"))
+	require.Nil(t, err)
+
+	// The DDL is not handled by the storage, e.g. it's discarded by the filter.
+	helper.Tk().MustExec("alter table test.simple_test1 add column c1 int")
+	ver, err = helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	reloadTs := ver.Ver
+	meta, err = kv.GetSnapshotMeta(helper.Storage(), reloadTs)
+	require.Nil(t, err)
+	require.Nil(t, storage.ReloadSnapshot(meta, reloadTs))
+	require.Equal(t, reloadTs, storage.ResolvedTs())
+
+	snap, err := storage.GetSnapshot(context.Background(), startTs)
+	require.Nil(t, err)
+	tableInfo, ok := snap.TableByName("test", "simple_test1")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 1)
+	snap, err = storage.GetSnapshot(context.Background(), reloadTs)
+	require.Nil(t, err)
+	tableInfo, ok = snap.TableByName("test", "simple_test1")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 2)
+
+	// DDLs after the reload are handled on the reloaded snapshot.
+	job := helper.DDL2Job("alter table test.simple_test1 add column c2 int")
+	require.Nil(t, storage.HandleDDLJob(job))
+	tableInfo, ok = storage.GetLastSnapshot().TableByName("test", "simple_test1")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 3)
+
+	// If the snapshot is reloaded at a handled ts, the resolved ts doesn't
+	// fall back and the snapshots are kept until the DDLs after ts are
+	// handled again on the reloaded snapshot.
+	resolvedTs := storage.ResolvedTs()
+	require.Equal(t, job.BinlogInfo.FinishedTS, resolvedTs)
+	lastSnap := storage.GetLastSnapshot()
+	require.Nil(t, storage.ReloadSnapshot(meta, reloadTs))
+	require.Equal(t, resolvedTs, storage.ResolvedTs())
+	require.Same(t, lastSnap, storage.GetLastSnapshot())
+	snap, err = storage.GetSnapshot(context.Background(), resolvedTs)
+	require.Nil(t, err)
+	require.Same(t, lastSnap, snap)
+	require.Nil(t, storage.HandleDDLJob(job))
+	require.Equal(t, resolvedTs, storage.ResolvedTs())
+	require.NotSame(t, lastSnap, storage.GetLastSnapshot())
+	tableInfo, ok = storage.GetLastSnapshot().TableByName("test", "simple_test1")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 3)
+	snap, err = storage.GetSnapshot(context.Background(), reloadTs)
+	require.Nil(t, err)
+	tableInfo, ok = snap.TableByName("test", "simple_test1")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 2)
+}
+
 func TestExplicitTables(t *testing.T) {
 	store, err := mockstore.NewMockStore()
 	require.Nil(t, err)
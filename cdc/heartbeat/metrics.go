@@ -0,0 +1,43 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	replicationLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "heartbeat",
+			Name:      "replication_latency",
+			Help: "The end-to-end replication latency in seconds, from heartbeats " +
+				"committed in the upstream to them visible in the downstream.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 18), // 10ms~21min
+		}, []string{"namespace", "changefeed"})
+	writeErrorCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "heartbeat",
+			Name:      "write_error_count",
+			Help:      "The total count of heartbeats failed to be written into the upstream.",
+		}, []string{"namespace", "changefeed"})
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(replicationLatencyHistogram)
+	registry.MustRegister(writeErrorCounter)
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// Observer recognises heartbeats replicated by the sink of a changefeed and
+// observes the end-to-end replication latency when they become visible in
+// the downstream. A nil Observer recognises nothing, so sinks can use it
+// whether heartbeats are enabled or not.
+type Observer struct {
+	changefeedID  model.ChangeFeedID
+	schema        string
+	table         string
+	caseSensitive bool
+
+	metricLatency prometheus.Observer
+}
+
+// NewObserver creates an Observer of the changefeed, it returns nil if
+// heartbeats are not enabled.
+func NewObserver(changefeedID model.ChangeFeedID, cfg *config.ReplicaConfig) *Observer {
+	if cfg.Heartbeat == nil || !cfg.Heartbeat.Enable {
+		return nil
+	}
+	// Rows keep the upstream table names until they are written by sinks,
+	// so the heartbeats are recognised by the upstream table name.
+	schema, table := cfg.Heartbeat.TableName()
+	return &Observer{
+		changefeedID:  changefeedID,
+		schema:        schema,
+		table:         table,
+		caseSensitive: cfg.CaseSensitive,
+		metricLatency: replicationLatencyHistogram.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// IsHeartbeat returns true if rows of the table are heartbeats.
+func (o *Observer) IsHeartbeat(table *model.TableName) bool {
+	if o == nil || table == nil {
+		return false
+	}
+	if o.caseSensitive {
+		return table.Schema == o.schema && table.Table == o.table
+	}
+	return strings.EqualFold(table.Schema, o.schema) && strings.EqualFold(table.Table, o.table)
+}
+
+// Observe observes the latency of a heartbeat committed at commitTs in the
+// upstream, which becomes visible in the downstream at visibleAt.
+func (o *Observer) Observe(commitTs uint64, visibleAt time.Time) {
+	if o == nil {
+		return
+	}
+	latency := visibleAt.Sub(oracle.GetTimeFromTS(commitTs))
+	if latency < 0 {
+		latency = 0
+	}
+	o.metricLatency.Observe(latency.Seconds())
+}
+
+// ObserveRows observes the latencies of the heartbeats in the rows, which
+// become visible in the downstream at visibleAt.
+func (o *Observer) ObserveRows(rows []*model.RowChangedEvent, visibleAt time.Time) {
+	if o == nil {
+		return
+	}
+	for _, row := range rows {
+		if o.IsHeartbeat(row.Table) {
+			o.Observe(row.CommitTs, visibleAt)
+		}
+	}
+}
+
+// Close cleans up the metrics of the changefeed.
+func (o *Observer) Close() {
+	if o == nil {
+		return
+	}
+	replicationLatencyHistogram.DeleteLabelValues(o.changefeedID.Namespace, o.changefeedID.ID)
+}
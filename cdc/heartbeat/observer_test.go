@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func getLatencyHistogram(t *testing.T, changefeedID model.ChangeFeedID) *dto.Histogram {
+	observer, err := replicationLatencyHistogram.GetMetricWithLabelValues(
+		changefeedID.Namespace, changefeedID.ID)
+	require.Nil(t, err)
+	metric := &dto.Metric{}
+	require.Nil(t, observer.(prometheus.Metric).Write(metric))
+	return metric.Histogram
+}
+
+func TestObserverDisabled(t *testing.T) {
+	t.Parallel()
+
+	o := NewObserver(model.DefaultChangeFeedID("test"), config.GetDefaultReplicaConfig())
+	require.Nil(t, o)
+	// A nil observer recognises nothing.
+	require.False(t, o.IsHeartbeat(&model.TableName{Schema: "tidb_cdc", Table: "heartbeat"}))
+	o.ObserveRows([]*model.RowChangedEvent{{
+		Table: &model.TableName{Schema: "tidb_cdc", Table: "heartbeat"},
+	}}, time.Now())
+	o.Close()
+}
+
+func TestObserverObserveRows(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test-observe-rows")
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = false
+	cfg.Heartbeat = &config.HeartbeatConfig{
+		Enable:      true,
+		UpstreamURI: "mysql://root@127.0.0.1:4000/",
+	}
+	require.Nil(t, cfg.ValidateAndAdjust(nil))
+	o := NewObserver(changefeedID, cfg)
+	defer o.Close()
+
+	require.True(t, o.IsHeartbeat(&model.TableName{Schema: "TiDB_CDC", Table: "Heartbeat"}))
+	require.False(t, o.IsHeartbeat(&model.TableName{Schema: "tidb_cdc", Table: "syncpoint_v1"}))
+
+	now := time.Now()
+	commitTs := oracle.GoTimeToTS(now.Add(-2 * time.Second))
+	o.ObserveRows([]*model.RowChangedEvent{
+		{Table: &model.TableName{Schema: "test", Table: "t"}, CommitTs: commitTs},
+		{Table: &model.TableName{Schema: "tidb_cdc", Table: "heartbeat"}, CommitTs: commitTs},
+	}, now)
+	// The latency is never negative even if clocks are skewed.
+	o.Observe(oracle.GoTimeToTS(now.Add(time.Second)), now)
+
+	histogram := getLatencyHistogram(t, changefeedID)
+	require.EqualValues(t, 2, histogram.GetSampleCount())
+	require.InDelta(t, 2, histogram.GetSampleSum(), 0.01)
+}
+
+func TestObserverRoutedTable(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Heartbeat = &config.HeartbeatConfig{
+		Enable:      true,
+		UpstreamURI: "mysql://root@127.0.0.1:4000/",
+	}
+	cfg.RoutingRules = []*config.RoutingRule{{
+		Matcher:      []string{"tidb_cdc.*"},
+		TargetSchema: "{schema}_replica",
+	}}
+	require.Nil(t, cfg.ValidateAndAdjust(nil))
+	o := NewObserver(model.DefaultChangeFeedID("test-routed"), cfg)
+	defer o.Close()
+
+	// Rows keep the upstream table names until they are written by sinks.
+	require.True(t, o.IsHeartbeat(&model.TableName{Schema: "tidb_cdc", Table: "heartbeat"}))
+	require.False(t, o.IsHeartbeat(&model.TableName{Schema: "tidb_cdc_replica", Table: "heartbeat"}))
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUpstreamPort = "4000"
+	dialTimeout         = 5 * time.Second
+	readWriteTimeout    = 10 * time.Second
+)
+
+// TSOClient gets timestamps from PD, it's implemented by pd.Client.
+type TSOClient interface {
+	GetTS(ctx context.Context) (int64, int64, error)
+}
+
+// Writer writes the current TSO into the heartbeat table of the upstream
+// periodically on behalf of a changefeed. Heartbeats are best-effort, the
+// changefeed is not affected if they fail to be written.
+type Writer struct {
+	changefeedID model.ChangeFeedID
+	db           *sql.DB
+	tso          TSOClient
+	schema       string
+	table        string
+	interval     time.Duration
+
+	tableCreated bool
+
+	metricWriteErrors prometheus.Counter
+}
+
+// NewWriter creates a Writer of the changefeed, the heartbeat table must be
+// replicated by the changefeed, otherwise the latency can't be observed.
+func NewWriter(
+	changefeedID model.ChangeFeedID, cfg *config.ReplicaConfig, tso TSOClient,
+) (*Writer, error) {
+	schema, table := cfg.Heartbeat.TableName()
+	f, err := filter.NewFilter(cfg, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if f.ShouldIgnoreTable(schema, table) {
+		return nil, cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the heartbeat table %s is not replicated by the changefeed",
+				cfg.Heartbeat.Table))
+	}
+	dsn, err := upstreamDSN(cfg.Heartbeat.UpstreamURI)
+	if err != nil {
+		return nil, err
+	}
+	// The connection is established lazily, so the changefeed can be
+	// initialized even if the upstream TiDB is unavailable.
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+	}
+	return newWriter(changefeedID, cfg.Heartbeat, db, tso), nil
+}
+
+func newWriter(
+	changefeedID model.ChangeFeedID, cfg *config.HeartbeatConfig, db *sql.DB, tso TSOClient,
+) *Writer {
+	schema, table := cfg.TableName()
+	return &Writer{
+		changefeedID: changefeedID,
+		db:           db,
+		tso:          tso,
+		schema:       schema,
+		table:        table,
+		interval:     time.Duration(cfg.IntervalInMs) * time.Millisecond,
+		metricWriteErrors: writeErrorCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// upstreamDSN converts the upstream uri like `mysql://root@127.0.0.1:4000/`
+// to the dsn of the driver.
+func upstreamDSN(upstreamURI string) (string, error) {
+	uri, err := url.Parse(upstreamURI)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+	}
+	dsn := dmysql.NewConfig()
+	dsn.User = uri.User.Username()
+	if dsn.User == "" {
+		dsn.User = "root"
+	}
+	dsn.Passwd, _ = uri.User.Password()
+	port := uri.Port()
+	if port == "" {
+		port = defaultUpstreamPort
+	}
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(uri.Hostname(), port)
+	dsn.Timeout = dialTimeout
+	dsn.ReadTimeout = readWriteTimeout
+	dsn.WriteTimeout = readWriteTimeout
+	return dsn.FormatDSN(), nil
+}
+
+// Run writes heartbeats until the context is done.
+func (w *Writer) Run(ctx context.Context) error {
+	defer func() {
+		if err := w.db.Close(); err != nil {
+			log.Warn("failed to close the heartbeat connection", zap.Error(err))
+		}
+		writeErrorCounter.DeleteLabelValues(w.changefeedID.Namespace, w.changefeedID.ID)
+	}()
+	log.Info("heartbeat writer started",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("table", quotes.QuoteSchema(w.schema, w.table)),
+		zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+		if err := w.write(ctx); err != nil {
+			if ctx.Err() != nil {
+				return errors.Trace(ctx.Err())
+			}
+			w.metricWriteErrors.Inc()
+			log.Warn("failed to write heartbeat",
+				zap.String("namespace", w.changefeedID.Namespace),
+				zap.String("changefeed", w.changefeedID.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+func (w *Writer) write(ctx context.Context) error {
+	if !w.tableCreated {
+		if err := w.createTable(ctx); err != nil {
+			return err
+		}
+		w.tableCreated = true
+	}
+	physical, logical, err := w.tso.GetTS(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ts := oracle.ComposeTS(physical, logical)
+	query := fmt.Sprintf(
+		"REPLACE INTO %s (namespace, changefeed, ts) VALUES (?, ?, ?)",
+		quotes.QuoteSchema(w.schema, w.table))
+	if _, err := w.db.ExecContext(
+		ctx, query, w.changefeedID.Namespace, w.changefeedID.ID, ts); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return nil
+}
+
+// createTable creates the heartbeat table if it doesn't exist. Changefeeds
+// sharing a heartbeat table write their own rows, and every heartbeat
+// replicated by a changefeed is a sample of its latency.
+func (w *Writer) createTable(ctx context.Context) error {
+	queries := []string{
+		"CREATE DATABASE IF NOT EXISTS " + quotes.QuoteName(w.schema),
+		"CREATE TABLE IF NOT EXISTS " + quotes.QuoteSchema(w.schema, w.table) + ` (
+	namespace VARCHAR(128) NOT NULL,
+	changefeed VARCHAR(128) NOT NULL,
+	ts BIGINT UNSIGNED NOT NULL,
+	PRIMARY KEY (namespace, changefeed)
+)`,
+	}
+	for _, query := range queries {
+		if _, err := w.db.ExecContext(ctx, query); err != nil {
+			return cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockTSOClient struct {
+	physical int64
+	err      error
+}
+
+func (c *mockTSOClient) GetTS(_ context.Context) (int64, int64, error) {
+	return atomic.AddInt64(&c.physical, 1), 0, c.err
+}
+
+func TestUpstreamDSN(t *testing.T) {
+	t.Parallel()
+
+	dsn, err := upstreamDSN("mysql://127.0.0.1/")
+	require.Nil(t, err)
+	require.Regexp(t, "^root@tcp\\(127.0.0.1:4000\\)/.*timeout=5s", dsn)
+
+	dsn, err = upstreamDSN("tidb://cdc:p%40ss@[::1]:4001/")
+	require.Nil(t, err)
+	require.Regexp(t, "^cdc:p@ss@tcp\\(\\[::1\\]:4001\\)/", dsn)
+}
+
+func TestNewWriterRequiresReplicatedTable(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Heartbeat = &config.HeartbeatConfig{
+		Enable:      true,
+		UpstreamURI: "mysql://root@127.0.0.1:4000/",
+	}
+	require.Nil(t, cfg.ValidateAndAdjust(nil))
+	cfg.Filter.Rules = []string{"test.*"}
+	_, err := NewWriter(model.DefaultChangeFeedID("test"), cfg, &mockTSOClient{})
+	require.Regexp(t, ".*heartbeat table tidb_cdc.heartbeat is not replicated.*", err)
+
+	cfg.Filter.Rules = []string{"test.*", "tidb_cdc.heartbeat"}
+	w, err := NewWriter(model.DefaultChangeFeedID("test"), cfg, &mockTSOClient{})
+	require.Nil(t, err)
+	require.Nil(t, w.db.Close())
+}
+
+func TestWriterWrite(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	tso := &mockTSOClient{physical: 1000}
+	w := newWriter(model.DefaultChangeFeedID("test"), &config.HeartbeatConfig{
+		Table:        "tidb_cdc.heartbeat",
+		IntervalInMs: 100,
+	}, db, tso)
+	ctx := context.Background()
+
+	// The table is created by the first heartbeat.
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS `tidb_cdc`").
+		WillReturnError(errors.New("connection refused"))
+	require.Regexp(t, ".*connection refused.*", w.write(ctx))
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS `tidb_cdc`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `tidb_cdc`.`heartbeat`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("REPLACE INTO `tidb_cdc`.`heartbeat`").
+		WithArgs("default", "test", oracle.ComposeTS(1001, 0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.Nil(t, w.write(ctx))
+
+	mock.ExpectExec("REPLACE INTO `tidb_cdc`.`heartbeat`").
+		WithArgs("default", "test", oracle.ComposeTS(1002, 0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.Nil(t, w.write(ctx))
+
+	tso.err = errors.New("pd is unavailable")
+	require.Regexp(t, ".*pd is unavailable.*", w.write(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestWriterRun(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	tso := &mockTSOClient{}
+	w := newWriter(model.DefaultChangeFeedID("test"), &config.HeartbeatConfig{
+		Table:        "tidb_cdc.heartbeat",
+		IntervalInMs: 100,
+	}, db, tso)
+
+	// Failed heartbeats don't stop the writer.
+	mock.ExpectExec("CREATE DATABASE").WillReturnError(errors.New("connection refused"))
+	mock.ExpectExec("CREATE DATABASE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("REPLACE INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectClose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	// The ts is got after the previous heartbeat is written.
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&tso.physical) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
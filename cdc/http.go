@@ -19,6 +19,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tiflow/cdc/api/middleware"
 	"github.com/pingcap/tiflow/cdc/api/owner"
 	"github.com/pingcap/tiflow/cdc/api/status"
 	v1 "github.com/pingcap/tiflow/cdc/api/v1"
@@ -54,11 +55,14 @@ func RegisterRoutes(
 	// Status API
 	status.RegisterStatusAPIRoutes(router, capture)
 
+	auth := middleware.AuthenticateMiddleware(capture)
+
 	// Log API
-	router.POST("/admin/log", gin.WrapF(owner.HandleAdminLogLevel))
+	router.POST("/admin/log", auth, gin.WrapF(owner.HandleAdminLogLevel))
 
 	// pprof debug API
 	pprofGroup := router.Group("/debug/pprof/")
+	pprofGroup.Use(auth)
 	pprofGroup.GET("", gin.WrapF(pprof.Index))
 	pprofGroup.GET("/:any", gin.WrapF(pprof.Index))
 	pprofGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
@@ -70,7 +74,7 @@ func RegisterRoutes(
 	// Failpoint API
 	if util.FailpointBuild {
 		// `http.StripPrefix` is needed because `failpoint.HttpHandler` assumes that it handles the prefix `/`.
-		router.Any("/debug/fail/*any", gin.WrapH(http.StripPrefix("/debug/fail", &failpoint.HttpHandler{})))
+		router.Any("/debug/fail/*any", auth, gin.WrapH(http.StripPrefix("/debug/fail", &failpoint.HttpHandler{})))
 	}
 
 	// Promtheus metrics API
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -174,10 +175,14 @@ type regionFeedState struct {
 	stopped   int32
 
 	lock           sync.RWMutex
-	initialized    bool
+	initialized    int32
 	matcher        *matcher
 	startFeedTime  time.Time
-	lastResolvedTs uint64
+	lastResolvedTs uint64 // accessed by getLastResolvedTs and setLastResolvedTs
+
+	// lock resolving statistics, only used by diagnosis
+	resolveLockCount  uint64
+	lastResolveLockTs uint64
 }
 
 func newRegionFeedState(sri singleRegionInfo, requestID uint64) *regionFeedState {
@@ -190,7 +195,7 @@ func newRegionFeedState(sri singleRegionInfo, requestID uint64) *regionFeedState
 
 func (s *regionFeedState) start() {
 	s.startFeedTime = time.Now()
-	s.lastResolvedTs = s.sri.ts
+	s.setLastResolvedTs(s.sri.ts)
 	s.matcher = newMatcher()
 }
 
@@ -202,10 +207,44 @@ func (s *regionFeedState) isStopped() bool {
 	return atomic.LoadInt32(&s.stopped) > 0
 }
 
+func (s *regionFeedState) markInitialized() {
+	atomic.StoreInt32(&s.initialized, 1)
+}
+
+func (s *regionFeedState) isInitialized() bool {
+	return atomic.LoadInt32(&s.initialized) > 0
+}
+
+// getLastResolvedTs returns the last resolved ts of the region, it can be
+// called without holding the state lock.
 func (s *regionFeedState) getLastResolvedTs() uint64 {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-	return s.lastResolvedTs
+	return atomic.LoadUint64(&s.lastResolvedTs)
+}
+
+func (s *regionFeedState) setLastResolvedTs(ts uint64) {
+	atomic.StoreUint64(&s.lastResolvedTs, ts)
+}
+
+func (s *regionFeedState) recordResolveLock(maxVersion uint64) {
+	atomic.AddUint64(&s.resolveLockCount, 1)
+	atomic.StoreUint64(&s.lastResolveLockTs, maxVersion)
+}
+
+// diagnose returns the replication progress of the region without acquiring
+// the state lock, so it never blocks on a busy region worker.
+func (s *regionFeedState) diagnose() model.RegionDiagnosis {
+	d := model.RegionDiagnosis{
+		RegionID:          s.sri.verID.GetID(),
+		StoreID:           getStoreID(s.sri.rpcCtx),
+		ResolvedTs:        s.getLastResolvedTs(),
+		Initialized:       s.isInitialized(),
+		ResolveLockCount:  atomic.LoadUint64(&s.resolveLockCount),
+		LastResolveLockTs: atomic.LoadUint64(&s.lastResolveLockTs),
+	}
+	if s.sri.rpcCtx != nil {
+		d.StoreAddr = s.sri.rpcCtx.Addr
+	}
+	return d
 }
 
 func (s *regionFeedState) getRegionSpan() regionspan.ComparableSpan {
@@ -296,6 +335,10 @@ type CDCKVClient interface {
 		lockResolver txnutil.LockResolver,
 		eventCh chan<- model.RegionFeedEvent,
 	) error
+
+	// SlowestRegions returns at most limit regions with the smallest resolved
+	// ts among all running event feeds.
+	SlowestRegions(limit int) []model.RegionDiagnosis
 }
 
 // NewCDCKVClient is the constructor of CDC KV client
@@ -318,6 +361,10 @@ type CDCClient struct {
 	tableName  string
 
 	regionLimiters *regionEventFeedLimiters
+	scanLimiter    *regionScanLimiter
+
+	sessionsLock sync.Mutex
+	sessions     map[*eventFeedSession]struct{}
 }
 
 // NewCDCClient creates a CDCClient instance
@@ -342,6 +389,8 @@ func NewCDCClient(
 		regionCache:    regionCache,
 		pdClock:        pdClock,
 		regionLimiters: defaultRegionEventFeedLimiters,
+		scanLimiter:    getRegionScanLimiter(),
+		sessions:       make(map[*eventFeedSession]struct{}),
 
 		changefeed: changefeed,
 		tableID:    tableID,
@@ -403,9 +452,35 @@ func (c *CDCClient) EventFeed(
 ) error {
 	s := newEventFeedSession(
 		ctx, c, span, lockResolver, ts, eventCh, c.changefeed, c.tableID, c.tableName)
+	c.sessionsLock.Lock()
+	c.sessions[s] = struct{}{}
+	c.sessionsLock.Unlock()
+	defer func() {
+		c.sessionsLock.Lock()
+		delete(c.sessions, s)
+		c.sessionsLock.Unlock()
+	}()
 	return s.eventFeed(ctx, ts)
 }
 
+// SlowestRegions implements CDCKVClient.SlowestRegions.
+func (c *CDCClient) SlowestRegions(limit int) []model.RegionDiagnosis {
+	c.sessionsLock.Lock()
+	var regions []model.RegionDiagnosis
+	for s := range c.sessions {
+		regions = append(regions, s.regionDiagnoses()...)
+	}
+	c.sessionsLock.Unlock()
+
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].ResolvedTs < regions[j].ResolvedTs
+	})
+	if limit >= 0 && len(regions) > limit {
+		regions = regions[:limit]
+	}
+	return regions
+}
+
 var currentID uint64 = 0
 
 func allocID() uint64 {
@@ -452,6 +527,15 @@ type eventFeedSession struct {
 	streamsLock      sync.RWMutex
 	streamsCanceller map[string]context.CancelFunc
 
+	// scanSlots records the incremental scan slots held by this session in
+	// each store, they are given back to the shared scan limiter on exit.
+	scanSlots     map[string]int
+	scanSlotsLock sync.Mutex
+
+	// workers are the running region workers, one for each gRPC stream.
+	workers     map[*regionWorker]struct{}
+	workersLock sync.Mutex
+
 	changefeed model.ChangeFeedID
 	tableID    model.TableID
 	tableName  string
@@ -494,6 +578,8 @@ func newEventFeedSession(
 		rangeChSizeGauge:  clientChannelSize.WithLabelValues("range"),
 		streams:           make(map[string]*eventFeedStream),
 		streamsCanceller:  make(map[string]context.CancelFunc),
+		scanSlots:         make(map[string]int),
+		workers:           make(map[*regionWorker]struct{}),
 
 		changefeed: changefeed,
 		tableID:    tableID,
@@ -504,6 +590,7 @@ func newEventFeedSession(
 func (s *eventFeedSession) eventFeed(ctx context.Context, ts uint64) error {
 	eventFeedGauge.Inc()
 	defer eventFeedGauge.Dec()
+	defer s.releaseAllScanSlots()
 
 	g, ctx := errgroup.WithContext(ctx)
 
@@ -675,11 +762,79 @@ func (s *eventFeedSession) scheduleRegionRequest(ctx context.Context, sri single
 func (s *eventFeedSession) onRegionFail(ctx context.Context, errorInfo regionErrorInfo, revokeToken bool) {
 	s.rangeLock.UnlockRange(errorInfo.span.Start, errorInfo.span.End, errorInfo.verID.GetID(), errorInfo.verID.GetVer(), errorInfo.ts)
 	if revokeToken {
-		s.regionRouter.Release(errorInfo.rpcCtx.Addr)
+		s.releaseRegionToken(errorInfo.rpcCtx.Addr)
 	}
 	s.enqueueError(ctx, errorInfo)
 }
 
+// releaseRegionToken gives back the region token and the incremental scan slot
+// of a region, it is called when the region finishes incremental scan or fails
+// before that.
+func (s *eventFeedSession) releaseRegionToken(addr string) {
+	s.regionRouter.Release(addr)
+	s.releaseScanSlot(addr)
+}
+
+// waitScanSlot waits for a slot of the capture wide scan limiter in a new
+// goroutine, so regions waiting for their stores don't block others. The
+// region is sent to grantedCh once it's granted, regions lagging behind the
+// most are granted first.
+func (s *eventFeedSession) waitScanSlot(
+	ctx context.Context, g *errgroup.Group,
+	sri singleRegionInfo, grantedCh chan<- singleRegionInfo,
+) {
+	g.Go(func() error {
+		addr := sri.rpcCtx.Addr
+		if err := s.acquireScanSlot(ctx, addr, sri.ts); err != nil {
+			// The context is done, the session is exiting.
+			return nil
+		}
+		select {
+		case grantedCh <- sri:
+		case <-ctx.Done():
+			s.releaseScanSlot(addr)
+		}
+		return nil
+	})
+}
+
+// acquireScanSlot blocks until the shared scan limiter allows a region with
+// the given checkpoint ts to start incremental scan in the store.
+func (s *eventFeedSession) acquireScanSlot(ctx context.Context, addr string, ts uint64) error {
+	if err := s.client.scanLimiter.acquire(ctx, addr, ts); err != nil {
+		return errors.Trace(err)
+	}
+	s.scanSlotsLock.Lock()
+	defer s.scanSlotsLock.Unlock()
+	s.scanSlots[addr]++
+	return nil
+}
+
+func (s *eventFeedSession) releaseScanSlot(addr string) {
+	s.scanSlotsLock.Lock()
+	defer s.scanSlotsLock.Unlock()
+	// The slot could have been released when a pending region is cancelled
+	// before its request is sent, see `requestRegionToStore`.
+	if s.scanSlots[addr] <= 0 {
+		return
+	}
+	s.scanSlots[addr]--
+	s.client.scanLimiter.release(addr)
+}
+
+// releaseAllScanSlots gives back slots of regions that are still in
+// incremental scan when the session exits.
+func (s *eventFeedSession) releaseAllScanSlots() {
+	s.scanSlotsLock.Lock()
+	defer s.scanSlotsLock.Unlock()
+	for addr, n := range s.scanSlots {
+		for i := 0; i < n; i++ {
+			s.client.scanLimiter.release(addr)
+		}
+	}
+	s.scanSlots = make(map[string]int)
+}
+
 // requestRegionToStore gets singleRegionInfo from regionRouter, which is a token
 // based limiter, sends request to TiKV.
 // If the send request to TiKV returns error, fail the region with sendRequestToStoreErr
@@ -694,6 +849,8 @@ func (s *eventFeedSession) requestRegionToStore(
 	// and it will be loaded by the receiver thread when it receives the first response from that region. We need this
 	// to pass the region info to the receiver since the region info cannot be inferred from the response from TiKV.
 	storePendingRegions := make(map[string]*syncRegionFeedStateMap)
+	// Regions which have been granted a slot of the scan limiter.
+	grantedCh := make(chan singleRegionInfo)
 
 	var sri singleRegionInfo
 	for {
@@ -701,6 +858,9 @@ func (s *eventFeedSession) requestRegionToStore(
 		case <-ctx.Done():
 			return errors.Trace(ctx.Err())
 		case sri = <-s.regionRouter.Chan():
+			s.waitScanSlot(ctx, g, sri, grantedCh)
+			continue
+		case sri = <-grantedCh:
 		}
 		requestID := allocID()
 
@@ -769,6 +929,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				}
 				bo := tikv.NewBackoffer(ctx, tikvRequestMaxBackoff)
 				s.client.regionCache.OnSendFail(bo, rpcCtx, regionScheduleReload, err)
+				s.releaseScanSlot(rpcCtx.Addr)
 				errInfo := newRegionErrorInfo(sri, &connectToStoreErr{})
 				s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 				continue
@@ -836,6 +997,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				continue
 			}
 
+			s.releaseScanSlot(rpcCtx.Addr)
 			errInfo := newRegionErrorInfo(sri, &sendRequestToStoreErr{})
 			s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 		} else {
@@ -1136,6 +1298,8 @@ func (s *eventFeedSession) receiveFromStream(
 	// always create a new region worker, because `receiveFromStream` is ensured
 	// to call exactly once from outer code logic
 	worker := newRegionWorker(s.changefeed, s, addr)
+	s.addWorker(worker)
+	defer s.deleteWorker(worker)
 
 	defer worker.evictAllRegions()
 
@@ -1217,7 +1381,8 @@ func (s *eventFeedSession) receiveFromStream(
 				zap.Int("resolvedRegionCount", regionCount))
 		}
 
-		err = s.sendRegionChangeEvents(ctx, cevent.Events, worker, pendingRegions, addr)
+		scanEvents, scanBytes, err := s.sendRegionChangeEvents(
+			ctx, cevent.Events, worker, pendingRegions, addr)
 		if err != nil {
 			return err
 		}
@@ -1228,16 +1393,31 @@ func (s *eventFeedSession) receiveFromStream(
 				return err
 			}
 		}
+		// The incremental scan entries are throttled after other events are
+		// sent, so the bandwidth limit doesn't hold back the resolved ts of
+		// initialized regions. The resolved ts of regions in incremental scan
+		// is ignored by the worker anyway. The back pressure is passed to
+		// TiKV by gRPC flow control.
+		if err := s.client.scanLimiter.waitScanBytes(ctx, scanBytes); err != nil {
+			return err
+		}
+		if err := sendStatefulEvents(ctx, worker, scanEvents); err != nil {
+			return err
+		}
 	}
 }
 
+// sendRegionChangeEvents sends the events to the region worker except the
+// incremental scan entries, which are returned with their size to be sent
+// after the bandwidth limit allows. Once a region has an incremental scan
+// entry, its following events are returned as well to keep them in order.
 func (s *eventFeedSession) sendRegionChangeEvents(
 	ctx context.Context,
 	events []*cdcpb.Event,
 	worker *regionWorker,
 	pendingRegions *syncRegionFeedStateMap,
 	addr string,
-) error {
+) (scanEvents [][]*regionStatefulEvent, scanBytes int, err error) {
 	statefulEvents := make([][]*regionStatefulEvent, worker.inputSlots)
 	for i := 0; i < worker.inputSlots; i++ {
 		// Allocate a buffer with 1.5x length than average to reduce reallocate.
@@ -1245,6 +1425,8 @@ func (s *eventFeedSession) sendRegionChangeEvents(
 		statefulEvents[i] = make([]*regionStatefulEvent, 0, buffLen)
 	}
 
+	// Regions that have incremental scan entries in the events.
+	var scanRegions map[uint64]struct{}
 	for _, event := range events {
 		state, valid := worker.getRegionState(event.RegionId)
 		// Every region's range is locked before sending requests and unlocked after exiting, and the requestID
@@ -1300,12 +1482,37 @@ func (s *eventFeedSession) sendRegionChangeEvents(
 		}
 
 		slot := worker.inputCalcSlot(event.RegionId)
-		statefulEvents[slot] = append(statefulEvents[slot], &regionStatefulEvent{
+		statefulEvent := &regionStatefulEvent{
 			changeEvent: event,
 			regionID:    event.RegionId,
 			state:       state,
-		})
+		}
+		_, inScan := scanRegions[event.RegionId]
+		if _, ok := event.Event.(*cdcpb.Event_Entries_); ok && !state.isInitialized() {
+			scanBytes += event.Size()
+			inScan = true
+		}
+		if inScan {
+			if scanRegions == nil {
+				scanRegions = make(map[uint64]struct{})
+				scanEvents = make([][]*regionStatefulEvent, worker.inputSlots)
+			}
+			scanRegions[event.RegionId] = struct{}{}
+			scanEvents[slot] = append(scanEvents[slot], statefulEvent)
+			continue
+		}
+		statefulEvents[slot] = append(statefulEvents[slot], statefulEvent)
 	}
+	if err := sendStatefulEvents(ctx, worker, statefulEvents); err != nil {
+		return nil, 0, err
+	}
+	return scanEvents, scanBytes, nil
+}
+
+// sendStatefulEvents sends the events of every input slot to the worker.
+func sendStatefulEvents(
+	ctx context.Context, worker *regionWorker, statefulEvents [][]*regionStatefulEvent,
+) error {
 	for _, events := range statefulEvents {
 		if len(events) > 0 {
 			err := worker.sendEvents(ctx, events)
@@ -1388,6 +1595,30 @@ func (s *eventFeedSession) getStream(storeAddr string) (stream *eventFeedStream,
 	return
 }
 
+func (s *eventFeedSession) addWorker(worker *regionWorker) {
+	s.workersLock.Lock()
+	defer s.workersLock.Unlock()
+	s.workers[worker] = struct{}{}
+}
+
+func (s *eventFeedSession) deleteWorker(worker *regionWorker) {
+	s.workersLock.Lock()
+	defer s.workersLock.Unlock()
+	delete(s.workers, worker)
+}
+
+// regionDiagnoses returns the replication progress of all regions
+// maintained by region workers of this session.
+func (s *eventFeedSession) regionDiagnoses() []model.RegionDiagnosis {
+	s.workersLock.Lock()
+	defer s.workersLock.Unlock()
+	var regions []model.RegionDiagnosis
+	for worker := range s.workers {
+		regions = append(regions, worker.regionDiagnoses()...)
+	}
+	return regions
+}
+
 func (s *eventFeedSession) getStreamCancel(storeAddr string) (cancel context.CancelFunc, ok bool) {
 	s.streamsLock.RLock()
 	defer s.streamsLock.RUnlock()
@@ -3540,6 +3540,7 @@ func createFakeEventFeedSession(ctx context.Context) *eventFeedSession {
 	return newEventFeedSession(ctx,
 		&CDCClient{
 			regionLimiters: defaultRegionEventFeedLimiters,
+			scanLimiter:    newRegionScanLimiter(0, 0, 0),
 			config:         config.GetDefaultServerConfig().KVClient,
 		},
 		regionspan.ComparableSpan{Start: []byte("a"), End: []byte("b")},
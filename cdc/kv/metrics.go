@@ -110,6 +110,36 @@ var (
 			Help:      "region events batch size",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 20),
 		})
+
+	scanPendingRegionGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "region_scan_pending",
+			Help:      "regions waiting for an incremental scan slot in kv client",
+		}, []string{"store"})
+	scanRunningRegionGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "region_scan_running",
+			Help:      "regions in incremental scan in kv client",
+		}, []string{"store"})
+	scanWaitDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "region_scan_wait_duration_seconds",
+			Help:      "The time a region waits for an incremental scan slot.",
+			Buckets:   prometheus.ExponentialBuckets(0.001 /* 1 ms */, 2, 20),
+		})
+	scanThrottledDuration = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "region_scan_throttled_seconds",
+			Help:      "The total time incremental scans are throttled by the bandwidth limit.",
+		})
 )
 
 // InitMetrics registers all metrics in the kv package
@@ -126,6 +156,10 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(batchResolvedEventSize)
 	registry.MustRegister(grpcPoolStreamGauge)
 	registry.MustRegister(regionEventsBatchSize)
+	registry.MustRegister(scanPendingRegionGauge)
+	registry.MustRegister(scanRunningRegionGauge)
+	registry.MustRegister(scanWaitDuration)
+	registry.MustRegister(scanThrottledDuration)
 
 	// Register client metrics to registry.
 	registry.MustRegister(grpcMetrics)
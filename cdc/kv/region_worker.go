@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/regionspan"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/workerpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
@@ -232,6 +233,22 @@ func (w *regionWorker) checkRegionStateEmpty() (empty bool) {
 	return
 }
 
+// regionDiagnoses returns the replication progress of regions maintained by
+// the region worker.
+func (w *regionWorker) regionDiagnoses() []model.RegionDiagnosis {
+	var regions []model.RegionDiagnosis
+	for _, states := range w.statesManager.states {
+		states.Range(func(_, value interface{}) bool {
+			state := value.(*regionFeedState)
+			if !state.isStopped() {
+				regions = append(regions, state.diagnose())
+			}
+			return true
+		})
+	}
+	return regions
+}
+
 // checkShouldExit checks whether the region worker should exit, if should exit
 // return an error
 func (w *regionWorker) checkShouldExit() error {
@@ -246,8 +263,8 @@ func (w *regionWorker) checkShouldExit() error {
 }
 
 func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState) error {
-	if state.lastResolvedTs > state.sri.ts {
-		state.sri.ts = state.lastResolvedTs
+	if lastResolvedTs := state.getLastResolvedTs(); lastResolvedTs > state.sri.ts {
+		state.sri.ts = lastResolvedTs
 	}
 	regionID := state.sri.verID.GetID()
 	log.Info("single region event feed disconnected",
@@ -284,7 +301,7 @@ func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState
 		w.cancelStream(time.Second)
 	}
 
-	revokeToken := !state.initialized
+	revokeToken := !state.isInitialized()
 	// since the context used in region worker will be cancelled after region
 	// worker exits, we must use the parent context to prevent regionErrorInfo loss.
 	errInfo := newRegionErrorInfo(state.sri, err)
@@ -385,6 +402,7 @@ func (w *regionWorker) resolveLock(ctx context.Context) error {
 							zap.String("changefeed", w.session.client.changefeed.ID))
 						continue
 					}
+					state.recordResolveLock(maxVersion)
 					rts.ts.penalty = 0
 				}
 				rts.ts.resolvedTs = lastResolvedTs
@@ -674,9 +692,9 @@ func (w *regionWorker) handleEventEntry(
 			}
 			w.metrics.metricPullEventInitializedCounter.Inc()
 
-			state.initialized = true
-			w.session.regionRouter.Release(state.sri.rpcCtx.Addr)
-			cachedEvents := state.matcher.matchCachedRow(state.initialized)
+			state.markInitialized()
+			w.session.releaseRegionToken(state.sri.rpcCtx.Addr)
+			cachedEvents := state.matcher.matchCachedRow(state.isInitialized())
 			for _, cachedEvent := range cachedEvents {
 				revent, err := assembleRowEvent(regionID, cachedEvent)
 				if err != nil {
@@ -685,11 +703,12 @@ func (w *regionWorker) handleEventEntry(
 				select {
 				case w.outputCh <- revent:
 					w.metrics.metricSendEventCommitCounter.Inc()
+					w.traceCommittedRow(revent.Val.CRTs)
 				case <-ctx.Done():
 					return errors.Trace(ctx.Err())
 				}
 			}
-			state.matcher.matchCachedRollbackRow(state.initialized)
+			state.matcher.matchCachedRollbackRow(state.isInitialized())
 		case cdcpb.Event_COMMITTED:
 			w.metrics.metricPullEventCommittedCounter.Inc()
 			revent, err := assembleRowEvent(regionID, entry)
@@ -697,17 +716,18 @@ func (w *regionWorker) handleEventEntry(
 				return errors.Trace(err)
 			}
 
-			if entry.CommitTs <= state.lastResolvedTs {
+			if entry.CommitTs <= state.getLastResolvedTs() {
 				logPanic("The CommitTs must be greater than the resolvedTs",
 					zap.String("EventType", "COMMITTED"),
 					zap.Uint64("CommitTs", entry.CommitTs),
-					zap.Uint64("resolvedTs", state.lastResolvedTs),
+					zap.Uint64("resolvedTs", state.getLastResolvedTs()),
 					zap.Uint64("regionID", regionID))
 				return errUnreachable
 			}
 			select {
 			case w.outputCh <- revent:
 				w.metrics.metricSendEventCommittedCounter.Inc()
+				w.traceCommittedRow(revent.Val.CRTs)
 			case <-ctx.Done():
 				return errors.Trace(ctx.Err())
 			}
@@ -716,17 +736,17 @@ func (w *regionWorker) handleEventEntry(
 			state.matcher.putPrewriteRow(entry)
 		case cdcpb.Event_COMMIT:
 			w.metrics.metricPullEventCommitCounter.Inc()
-			if entry.CommitTs <= state.lastResolvedTs {
+			if entry.CommitTs <= state.getLastResolvedTs() {
 				logPanic("The CommitTs must be greater than the resolvedTs",
 					zap.String("EventType", "COMMIT"),
 					zap.Uint64("CommitTs", entry.CommitTs),
-					zap.Uint64("resolvedTs", state.lastResolvedTs),
+					zap.Uint64("resolvedTs", state.getLastResolvedTs()),
 					zap.Uint64("regionID", regionID))
 				return errUnreachable
 			}
-			ok := state.matcher.matchRow(entry, state.initialized)
+			ok := state.matcher.matchRow(entry, state.isInitialized())
 			if !ok {
-				if !state.initialized {
+				if !state.isInitialized() {
 					state.matcher.cacheCommitRow(entry)
 					continue
 				}
@@ -744,12 +764,13 @@ func (w *regionWorker) handleEventEntry(
 			select {
 			case w.outputCh <- revent:
 				w.metrics.metricSendEventCommitCounter.Inc()
+				w.traceCommittedRow(revent.Val.CRTs)
 			case <-ctx.Done():
 				return errors.Trace(ctx.Err())
 			}
 		case cdcpb.Event_ROLLBACK:
 			w.metrics.metricPullEventRollbackCounter.Inc()
-			if !state.initialized {
+			if !state.isInitialized() {
 				state.matcher.cacheRollbackRow(entry)
 				continue
 			}
@@ -764,7 +785,7 @@ func (w *regionWorker) handleResolvedTs(
 	resolvedTs uint64,
 	state *regionFeedState,
 ) error {
-	if !state.initialized {
+	if !state.isInitialized() {
 		return nil
 	}
 	regionID := state.sri.verID.GetID()
@@ -778,17 +799,17 @@ func (w *regionWorker) handleResolvedTs(
 	default:
 	}
 
-	if resolvedTs < state.lastResolvedTs {
+	if resolvedTs < state.getLastResolvedTs() {
 		log.Debug("The resolvedTs is fallen back in kvclient",
 			zap.String("namespace", w.session.client.changefeed.Namespace),
 			zap.String("changefeed", w.session.client.changefeed.ID),
 			zap.String("EventType", "RESOLVED"),
 			zap.Uint64("resolvedTs", resolvedTs),
-			zap.Uint64("lastResolvedTs", state.lastResolvedTs),
+			zap.Uint64("lastResolvedTs", state.getLastResolvedTs()),
 			zap.Uint64("regionID", regionID))
 		return nil
 	}
-	state.lastResolvedTs = resolvedTs
+	state.setLastResolvedTs(resolvedTs)
 	// emit a checkpointTs
 	revent := model.RegionFeedEvent{
 		RegionID: regionID,
@@ -821,10 +842,10 @@ func (w *regionWorker) evictAllRegions() {
 			}
 			state.markStopped()
 			w.delRegionState(state.sri.verID.GetID())
-			if state.lastResolvedTs > state.sri.ts {
-				state.sri.ts = state.lastResolvedTs
+			if lastResolvedTs := state.getLastResolvedTs(); lastResolvedTs > state.sri.ts {
+				state.sri.ts = lastResolvedTs
 			}
-			revokeToken := !state.initialized
+			revokeToken := !state.isInitialized()
 			state.lock.Unlock()
 			// since the context used in region worker will be cancelled after
 			// region worker exits, we must use the parent context to prevent
@@ -836,6 +857,13 @@ func (w *regionWorker) evictAllRegions() {
 	}
 }
 
+// traceCommittedRow records the kv client stage of a committed row if its
+// transaction is sampled for tracing.
+func (w *regionWorker) traceCommittedRow(commitTs uint64) {
+	tracing.RecordStage(w.session.changefeed, w.session.tableID,
+		commitTs, tracing.StageKVClient)
+}
+
 // sendEvents puts events into inputCh and updates some internal states.
 // Callers must ensure that all items in events can be hashed into one handle slot.
 func (w *regionWorker) sendEvents(ctx context.Context, events []*regionStatefulEvent) error {
@@ -48,7 +48,9 @@ func TestRegionStateManagerThreadSafe(t *testing.T) {
 	for i := 0; i < regionCount; i++ {
 		regionID := uint64(1000 + i)
 		regionIDs[i] = regionID
-		rsm.setState(regionID, &regionFeedState{requestID: uint64(i + 1), lastResolvedTs: uint64(1000)})
+		state := &regionFeedState{requestID: uint64(i + 1)}
+		state.setLastResolvedTs(1000)
+		rsm.setState(regionID, state)
 	}
 
 	var wg sync.WaitGroup
@@ -80,7 +82,7 @@ func TestRegionStateManagerThreadSafe(t *testing.T) {
 				s, ok := rsm.getState(regionID)
 				require.True(t, ok)
 				s.lock.Lock()
-				s.lastResolvedTs += 10
+				s.setLastResolvedTs(s.getLastResolvedTs() + 10)
 				s.lock.Unlock()
 				rsm.setState(regionID, s)
 			}
@@ -92,8 +94,8 @@ func TestRegionStateManagerThreadSafe(t *testing.T) {
 	for _, regionID := range regionIDs {
 		s, ok := rsm.getState(regionID)
 		require.True(t, ok)
-		require.Greater(t, s.lastResolvedTs, uint64(1000))
-		totalResolvedTs += s.lastResolvedTs
+		require.Greater(t, s.getLastResolvedTs(), uint64(1000))
+		totalResolvedTs += s.getLastResolvedTs()
 	}
 	// 100 regions, initial resolved ts 1000;
 	// 2000 * resolved ts forward, increased by 10 each time, routine number is `concurrency`.
@@ -0,0 +1,249 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+var (
+	scanLimiterOnce   sync.Once
+	globalScanLimiter *regionScanLimiter
+)
+
+// getRegionScanLimiter returns the region scan limiter shared by all kv
+// clients in the cdc server, it is initialized once from the global server
+// config. So region-scan-global-limit, region-scan-store-limit and
+// region-scan-bandwidth take effect after the server is restarted.
+func getRegionScanLimiter() *regionScanLimiter {
+	scanLimiterOnce.Do(func() {
+		cfg := config.GetGlobalServerConfig().KVClient
+		globalScanLimiter = newRegionScanLimiter(
+			cfg.RegionScanGlobalLimit, cfg.RegionScanStoreLimit, cfg.RegionScanBandwidth)
+	})
+	return globalScanLimiter
+}
+
+// scanWaiter is a region waiting for an incremental scan slot.
+type scanWaiter struct {
+	// ts is the checkpoint ts of the region, regions with smaller ts lag
+	// more and are granted first.
+	ts    uint64
+	index int
+	ready chan struct{}
+}
+
+type scanWaiterHeap []*scanWaiter
+
+func (h scanWaiterHeap) Len() int { return len(h) }
+
+func (h scanWaiterHeap) Less(i, j int) bool { return h[i].ts < h[j].ts }
+
+func (h scanWaiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scanWaiterHeap) Push(x interface{}) {
+	w := x.(*scanWaiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *scanWaiterHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+	return w
+}
+
+// storeScanQueue keeps the running and waiting incremental scans of a store.
+type storeScanQueue struct {
+	running int
+	waiters scanWaiterHeap
+
+	pendingGauge prometheus.Gauge
+	runningGauge prometheus.Gauge
+}
+
+// regionScanLimiter limits the concurrency and bandwidth of incremental scans
+// across all tables on a capture, on top of the per table limit enforced by
+// sizedRegionRouter. Regions waiting for a scan slot are granted in ascending
+// order of their checkpoint ts, so the regions whose resolved ts lag the most
+// are scanned first.
+type regionScanLimiter struct {
+	mu sync.Mutex
+	// globalLimit and storeLimit are the max number of concurrent incremental
+	// scans on the capture and on a single TiKV store. 0 means no limit.
+	globalLimit int
+	storeLimit  int
+	running     int
+	stores      map[string]*storeScanQueue
+
+	// bandwidth limits bytes per second received from regions that are in
+	// incremental scan, nil means no limit.
+	bandwidth *rate.Limiter
+}
+
+func newRegionScanLimiter(globalLimit, storeLimit, bandwidth int) *regionScanLimiter {
+	l := &regionScanLimiter{
+		globalLimit: globalLimit,
+		storeLimit:  storeLimit,
+		stores:      make(map[string]*storeScanQueue),
+	}
+	if bandwidth > 0 {
+		l.bandwidth = rate.NewLimiter(rate.Limit(bandwidth), bandwidth)
+	}
+	return l
+}
+
+func (l *regionScanLimiter) getStore(store string) *storeScanQueue {
+	q, ok := l.stores[store]
+	if !ok {
+		q = &storeScanQueue{
+			pendingGauge: scanPendingRegionGauge.WithLabelValues(store),
+			runningGauge: scanRunningRegionGauge.WithLabelValues(store),
+		}
+		l.stores[store] = q
+	}
+	return q
+}
+
+func (l *regionScanLimiter) globalAvailable() bool {
+	return l.globalLimit <= 0 || l.running < l.globalLimit
+}
+
+func (l *regionScanLimiter) storeAvailable(q *storeScanQueue) bool {
+	return l.storeLimit <= 0 || q.running < l.storeLimit
+}
+
+func (l *regionScanLimiter) grantLocked(q *storeScanQueue) {
+	q.running++
+	l.running++
+	q.runningGauge.Inc()
+}
+
+// acquire blocks until an incremental scan slot of the given store is
+// granted, or the context is done.
+func (l *regionScanLimiter) acquire(ctx context.Context, store string, ts uint64) error {
+	l.mu.Lock()
+	q := l.getStore(store)
+	// A free global slot means every waiter is blocked by its store limit,
+	// so only waiters of the same store can have a higher priority.
+	if q.waiters.Len() == 0 && l.storeAvailable(q) && l.globalAvailable() {
+		l.grantLocked(q)
+		l.mu.Unlock()
+		return nil
+	}
+	w := &scanWaiter{ts: ts, ready: make(chan struct{})}
+	heap.Push(&q.waiters, w)
+	q.pendingGauge.Inc()
+	l.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-w.ready:
+		scanWaitDuration.Observe(time.Since(start).Seconds())
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.index < 0 {
+			// The slot has been granted concurrently, give it back.
+			l.releaseLocked(q)
+		} else {
+			heap.Remove(&q.waiters, w.index)
+			q.pendingGauge.Dec()
+		}
+		return errors.Trace(ctx.Err())
+	}
+}
+
+// release gives back an incremental scan slot of the given store.
+func (l *regionScanLimiter) release(store string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked(l.getStore(store))
+}
+
+func (l *regionScanLimiter) releaseLocked(q *storeScanQueue) {
+	q.running--
+	l.running--
+	q.runningGauge.Dec()
+	l.dispatchLocked()
+}
+
+// dispatchLocked grants free slots to the waiters with the smallest ts among
+// stores that are not full.
+func (l *regionScanLimiter) dispatchLocked() {
+	for l.globalAvailable() {
+		var best *storeScanQueue
+		for _, q := range l.stores {
+			if q.waiters.Len() == 0 || !l.storeAvailable(q) {
+				continue
+			}
+			if best == nil || q.waiters[0].ts < best.waiters[0].ts {
+				best = q
+			}
+		}
+		if best == nil {
+			return
+		}
+		w := heap.Pop(&best.waiters).(*scanWaiter)
+		best.pendingGauge.Dec()
+		l.grantLocked(best)
+		close(w.ready)
+	}
+}
+
+// waitScanBytes blocks until n bytes of incremental scan data are allowed to
+// be consumed by the bandwidth limit.
+func (l *regionScanLimiter) waitScanBytes(ctx context.Context, n int) error {
+	if l.bandwidth == nil || n <= 0 {
+		return nil
+	}
+	var throttled time.Duration
+	burst := l.bandwidth.Burst()
+	for n > 0 {
+		size := n
+		if size > burst {
+			size = burst
+		}
+		n -= size
+		if l.bandwidth.AllowN(time.Now(), size) {
+			continue
+		}
+		start := time.Now()
+		if err := l.bandwidth.WaitN(ctx, size); err != nil {
+			return errors.Trace(err)
+		}
+		throttled += time.Since(start)
+	}
+	if throttled > 0 {
+		scanThrottledDuration.Add(throttled.Seconds())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionScanLimiterNoLimit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	l := newRegionScanLimiter(0, 0, 0)
+	for i := 0; i < 100; i++ {
+		require.Nil(t, l.acquire(ctx, "store-1", uint64(i)))
+	}
+	require.Equal(t, 100, l.running)
+	for i := 0; i < 100; i++ {
+		l.release("store-1")
+	}
+	require.Equal(t, 0, l.running)
+	require.Nil(t, l.waitScanBytes(ctx, 1024))
+}
+
+func TestRegionScanLimiterPriority(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := newRegionScanLimiter(1, 0, 0)
+	require.Nil(t, l.acquire(ctx, "store-1", 100))
+
+	granted := make(chan uint64, 3)
+	for _, ts := range []uint64{30, 10, 20} {
+		ts := ts
+		go func() {
+			if err := l.acquire(ctx, "store-2", ts); err == nil {
+				granted <- ts
+			}
+		}()
+	}
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.getStore("store-2").waiters.Len() == 3
+	}, time.Second, 10*time.Millisecond)
+
+	// Regions with smaller checkpoint ts are granted first.
+	l.release("store-1")
+	require.Equal(t, uint64(10), <-granted)
+	l.release("store-2")
+	require.Equal(t, uint64(20), <-granted)
+	l.release("store-2")
+	require.Equal(t, uint64(30), <-granted)
+	require.Equal(t, 1, l.running)
+}
+
+func TestRegionScanLimiterStoreLimit(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := newRegionScanLimiter(0, 2, 0)
+	require.Nil(t, l.acquire(ctx, "store-1", 1))
+	require.Nil(t, l.acquire(ctx, "store-1", 2))
+	// A full store does not block other stores.
+	require.Nil(t, l.acquire(ctx, "store-2", 3))
+
+	ctx1, cancel1 := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel1()
+	err := l.acquire(ctx1, "store-1", 4)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 0, l.getStore("store-1").waiters.Len())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.acquire(ctx, "store-1", 5)
+	}()
+	select {
+	case <-done:
+		t.Fatal("acquire should be blocked by the store limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	l.release("store-1")
+	require.Nil(t, <-done)
+	require.Equal(t, 2, l.getStore("store-1").running)
+	require.Equal(t, 3, l.running)
+}
+
+func TestRegionScanLimiterBandwidth(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	l := newRegionScanLimiter(0, 0, 1024)
+	// The first burst is allowed immediately.
+	require.Nil(t, l.waitScanBytes(ctx, 1024))
+	// The following bytes can not be consumed before the context is done.
+	require.Error(t, l.waitScanBytes(ctx, 4096))
+}
@@ -146,6 +146,57 @@ type ChangeFeedInfo struct {
 	Error  *RunningError         `json:"error"`
 
 	CreatorVersion string `json:"creator-version"`
+
+	// TableSetChange is the latest change of the replicated tables made on
+	// the running changefeed.
+	TableSetChange *TableSetChange `json:"table-set-change,omitempty"`
+
+	// FanInGroup is the ID of the multi-upstream changefeed this changefeed
+	// belongs to. Changefeeds of the same group replicate tables of different
+	// upstreams into the same sink.
+	FanInGroup string `json:"fan-in-group,omitempty"`
+
+	// DDLApproval is the latest DDL held by the DDL policies of the
+	// changefeed for approval.
+	DDLApproval *DDLApproval `json:"ddl-approval,omitempty"`
+}
+
+// TableSetChange describes a change of the replicated tables of a running
+// changefeed. The change takes effect at StartTs, the owner blocks the
+// changefeed at StartTs and then switches to the new filter rules, tables
+// newly matched by the rules start replicating from StartTs.
+type TableSetChange struct {
+	// Rules are the filter rules after the change.
+	Rules []string `json:"rules"`
+	// StartTs is the ts at which the change takes effect.
+	StartTs uint64 `json:"start-ts"`
+	// InitialLoad indicates whether added tables load the snapshot at StartTs.
+	InitialLoad bool `json:"initial-load"`
+	// Applied is true if the changefeed has switched to the new rules.
+	Applied bool `json:"applied"`
+}
+
+// DDLApprovalState is the state of a DDL waiting for approval.
+type DDLApprovalState string
+
+const (
+	// DDLApprovalPending means the DDL is waiting for approval.
+	DDLApprovalPending DDLApprovalState = "pending"
+	// DDLApprovalApproved means the DDL is approved and will be replicated.
+	DDLApprovalApproved DDLApprovalState = "approved"
+	// DDLApprovalRejected means the DDL is rejected and will be skipped.
+	DDLApprovalRejected DDLApprovalState = "rejected"
+)
+
+// DDLApproval describes a DDL held by the DDL policies of a changefeed, the
+// owner blocks the changefeed at CommitTs until the DDL is approved or
+// rejected.
+type DDLApproval struct {
+	// CommitTs is the commit ts of the DDL job.
+	CommitTs uint64 `json:"commit-ts"`
+	// Queries are the queries of the DDL events built from the DDL job.
+	Queries []string         `json:"queries"`
+	State   DDLApprovalState `json:"state"`
 }
 
 const changeFeedIDMaxLen = 128
@@ -194,6 +245,13 @@ func (info *ChangeFeedInfo) String() (str string) {
 	if err != nil {
 		log.Error("failed to marshal changefeed info", zap.Error(err))
 	}
+	if clone.Config != nil && clone.Config.Heartbeat != nil {
+		clone.Config.Heartbeat.UpstreamURI, err = util.MaskSinkURI(
+			clone.Config.Heartbeat.UpstreamURI)
+		if err != nil {
+			log.Error("failed to marshal changefeed info", zap.Error(err))
+		}
+	}
 
 	str, err = clone.Marshal()
 	if err != nil {
@@ -228,6 +286,24 @@ func (info *ChangeFeedInfo) GetTargetTs() uint64 {
 	return uint64(math.MaxUint64)
 }
 
+// PendingTableSetChange returns the table set change that has not been
+// applied yet, nil is returned if there is no such change.
+func (info *ChangeFeedInfo) PendingTableSetChange() *TableSetChange {
+	if info.TableSetChange == nil || info.TableSetChange.Applied {
+		return nil
+	}
+	return info.TableSetChange
+}
+
+// PendingDDLApproval returns the DDL waiting for approval, nil is returned
+// if there is no such DDL.
+func (info *ChangeFeedInfo) PendingDDLApproval() *DDLApproval {
+	if info.DDLApproval == nil || info.DDLApproval.State != DDLApprovalPending {
+		return nil
+	}
+	return info.DDLApproval
+}
+
 // Marshal returns the json marshal format of a ChangeFeedInfo
 func (info *ChangeFeedInfo) Marshal() (string, error) {
 	data, err := json.Marshal(info)
@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// MaxChangefeedEvents is the max number of events kept for a changefeed,
+	// the oldest events are removed once it's exceeded.
+	MaxChangefeedEvents = 100
+	// MaxChangefeedEventMessageBytes is the max size of the message and the
+	// error message of an event, longer messages are truncated.
+	MaxChangefeedEventMessageBytes = 1024
+)
+
+// truncatedSuffix is appended to the truncated messages.
+const truncatedSuffix = "..."
+
+// ChangefeedEventType is the type of changefeed events.
+type ChangefeedEventType string
+
+// All changefeed event types
+const (
+	ChangefeedEventCreated       ChangefeedEventType = "created"
+	ChangefeedEventUpdated       ChangefeedEventType = "updated"
+	ChangefeedEventPaused        ChangefeedEventType = "paused"
+	ChangefeedEventResumed       ChangefeedEventType = "resumed"
+	ChangefeedEventRestarted     ChangefeedEventType = "restarted"
+	ChangefeedEventError         ChangefeedEventType = "error"
+	ChangefeedEventFailed        ChangefeedEventType = "failed"
+	ChangefeedEventFinished      ChangefeedEventType = "finished"
+	ChangefeedEventDDLApplied    ChangefeedEventType = "ddl-applied"
+	ChangefeedEventTableMoved    ChangefeedEventType = "table-moved"
+	ChangefeedEventRebalanced    ChangefeedEventType = "rebalanced"
+	ChangefeedEventTablesChanged ChangefeedEventType = "tables-changed"
+)
+
+// ChangefeedEvent is an event in the lifecycle of a changefeed.
+type ChangefeedEvent struct {
+	Time    time.Time           `json:"time"`
+	Type    ChangefeedEventType `json:"type"`
+	Message string              `json:"message,omitempty"`
+	Error   *RunningError       `json:"error,omitempty"`
+}
+
+// NewChangefeedEvent creates a ChangefeedEvent happened now.
+func NewChangefeedEvent(tp ChangefeedEventType, message string) *ChangefeedEvent {
+	return &ChangefeedEvent{
+		Time:    time.Now(),
+		Type:    tp,
+		Message: message,
+	}
+}
+
+// ChangefeedEventLog is the event log of a changefeed, events are in the order
+// they happened.
+type ChangefeedEventLog struct {
+	Events []*ChangefeedEvent `json:"events"`
+}
+
+// Truncate returns the event with its messages truncated to
+// MaxChangefeedEventMessageBytes, the event itself is returned if they are
+// short enough.
+func (e *ChangefeedEvent) Truncate() *ChangefeedEvent {
+	if len(e.Message) <= MaxChangefeedEventMessageBytes &&
+		(e.Error == nil || len(e.Error.Message) <= MaxChangefeedEventMessageBytes) {
+		return e
+	}
+	truncated := *e
+	truncated.Message = truncateMessage(e.Message)
+	if e.Error != nil {
+		runningErr := *e.Error
+		runningErr.Message = truncateMessage(e.Error.Message)
+		truncated.Error = &runningErr
+	}
+	return &truncated
+}
+
+// truncateMessage truncates the message to MaxChangefeedEventMessageBytes at
+// a rune boundary.
+func truncateMessage(message string) string {
+	if len(message) <= MaxChangefeedEventMessageBytes {
+		return message
+	}
+	end := MaxChangefeedEventMessageBytes - len(truncatedSuffix)
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + truncatedSuffix
+}
+
+// Marshal returns the json marshal format of a ChangefeedEvent.
+func (e *ChangefeedEvent) Marshal() (string, error) {
+	data, err := json.Marshal(e)
+	return string(data), cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+// Unmarshal unmarshals into *ChangefeedEvent from json marshal byte slice.
+func (e *ChangefeedEvent) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, e)
+	if err != nil {
+		return errors.Annotatef(
+			cerror.WrapError(cerror.ErrUnmarshalFailed, err), "Unmarshal data: %v", data)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedEventMarshal(t *testing.T) {
+	t.Parallel()
+
+	event := NewChangefeedEvent(ChangefeedEventError, "the changefeed meets an error")
+	event.Error = &RunningError{Addr: "127.0.0.1:8300", Code: "CDC:ErrSinkURIInvalid"}
+	data, err := event.Marshal()
+	require.Nil(t, err)
+	unmarshaled := &ChangefeedEvent{}
+	require.Nil(t, unmarshaled.Unmarshal([]byte(data)))
+	require.Equal(t, event.Type, unmarshaled.Type)
+	require.Equal(t, event.Message, unmarshaled.Message)
+	require.Equal(t, event.Error, unmarshaled.Error)
+	require.True(t, event.Time.Equal(unmarshaled.Time))
+}
+
+func TestChangefeedEventTruncate(t *testing.T) {
+	t.Parallel()
+
+	event := NewChangefeedEvent(ChangefeedEventDDLApplied, "short")
+	require.Same(t, event, event.Truncate())
+
+	message := strings.Repeat("中", MaxChangefeedEventMessageBytes)
+	event = NewChangefeedEvent(ChangefeedEventDDLApplied, message)
+	event.Error = &RunningError{Code: "CDC:ErrSinkURIInvalid", Message: message}
+	truncated := event.Truncate()
+	for _, m := range []string{truncated.Message, truncated.Error.Message} {
+		require.LessOrEqual(t, len(m), MaxChangefeedEventMessageBytes)
+		require.True(t, utf8.ValidString(m))
+		require.True(t, strings.HasSuffix(m, truncatedSuffix))
+	}
+	// The event itself is not modified.
+	require.Equal(t, message, event.Message)
+	require.Equal(t, message, event.Error.Message)
+}
@@ -964,3 +964,41 @@ func TestGetTs(t *testing.T) {
 	status := &ChangeFeedStatus{CheckpointTs: checkpointTs}
 	require.Equal(t, info.GetCheckpointTs(status), checkpointTs)
 }
+
+func TestPendingTableSetChange(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{}
+	require.Nil(t, info.PendingTableSetChange())
+
+	info.TableSetChange = &TableSetChange{Rules: []string{"test.*"}, StartTs: 100}
+	require.Equal(t, info.TableSetChange, info.PendingTableSetChange())
+
+	cloned, err := info.Clone()
+	require.Nil(t, err)
+	require.Equal(t, info.TableSetChange, cloned.TableSetChange)
+
+	info.TableSetChange.Applied = true
+	require.Nil(t, info.PendingTableSetChange())
+}
+
+func TestPendingDDLApproval(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{}
+	require.Nil(t, info.PendingDDLApproval())
+
+	info.DDLApproval = &DDLApproval{
+		CommitTs: 100,
+		Queries:  []string{"DROP TABLE `test`.`t1`"},
+		State:    DDLApprovalPending,
+	}
+	require.Equal(t, info.DDLApproval, info.PendingDDLApproval())
+
+	cloned, err := info.Clone()
+	require.Nil(t, err)
+	require.Equal(t, info.DDLApproval, cloned.DDLApproval)
+
+	info.DDLApproval.State = DDLApprovalRejected
+	require.Nil(t, info.PendingDDLApproval())
+}
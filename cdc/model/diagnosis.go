@@ -0,0 +1,87 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// DiagnosisStage is the stage of a table pipeline that holds back the
+// checkpoint of the table.
+type DiagnosisStage string
+
+const (
+	// DiagnosisStagePuller means the resolved ts of some regions is lagging,
+	// the puller can not advance.
+	DiagnosisStagePuller DiagnosisStage = "puller"
+	// DiagnosisStageSorter means events are pulled but not yet sorted and
+	// delivered to the sink.
+	DiagnosisStageSorter DiagnosisStage = "sorter"
+	// DiagnosisStageSink means the sink is slow to flush events.
+	DiagnosisStageSink DiagnosisStage = "sink"
+	// DiagnosisStageBarrier means the table is blocked by the barrier ts of
+	// the changefeed, e.g. a DDL is being executed.
+	DiagnosisStageBarrier DiagnosisStage = "barrier"
+)
+
+// DefaultDiagnosisRegionLimit is the default number of the slowest regions
+// reported for each table.
+const DefaultDiagnosisRegionLimit = 5
+
+// RegionDiagnosis holds the replication progress of a region in kv client.
+type RegionDiagnosis struct {
+	RegionID uint64 `json:"region_id"`
+	// The leader store of the region which the kv client subscribes to.
+	StoreID   uint64 `json:"store_id"`
+	StoreAddr string `json:"store_addr"`
+	// ResolvedTs is the last resolved ts received from the region.
+	ResolvedTs uint64 `json:"resolved_ts"`
+	// Initialized is false if the region is still in incremental scan.
+	Initialized bool `json:"initialized"`
+	// ResolveLockCount is the number of times the kv client resolves locks
+	// for the region because its resolved ts is stuck.
+	ResolveLockCount uint64 `json:"resolve_lock_count"`
+	// LastResolveLockTs is the max version used by the last lock resolving.
+	LastResolveLockTs uint64 `json:"last_resolve_lock_ts"`
+}
+
+// TableDiagnosis holds the replication progress of each stage of a table.
+type TableDiagnosis struct {
+	CaptureID string  `json:"capture_id"`
+	TableID   TableID `json:"table_id"`
+	TableName string  `json:"table_name"`
+
+	PullerResolvedTs uint64 `json:"puller_resolved_ts"`
+	SorterResolvedTs uint64 `json:"sorter_resolved_ts"`
+	SinkResolvedTs   uint64 `json:"sink_resolved_ts"`
+	BarrierTs        uint64 `json:"barrier_ts"`
+	CheckpointTs     uint64 `json:"checkpoint_ts"`
+	// BlockingStage is the stage that holds back the checkpoint of the table.
+	BlockingStage DiagnosisStage `json:"blocking_stage"`
+	// SlowestRegions are regions with the smallest resolved ts.
+	SlowestRegions []RegionDiagnosis `json:"slowest_regions"`
+}
+
+// DiagnoseStage finds out the stage that holds back the checkpoint ts of a
+// table, given the progress of each stage of the table pipeline.
+func DiagnoseStage(
+	pullerResolvedTs, sinkResolvedTs, barrierTs, checkpointTs uint64,
+) DiagnosisStage {
+	if checkpointTs >= barrierTs {
+		return DiagnosisStageBarrier
+	}
+	if checkpointTs < sinkResolvedTs {
+		return DiagnosisStageSink
+	}
+	if sinkResolvedTs < pullerResolvedTs {
+		return DiagnosisStageSorter
+	}
+	return DiagnosisStagePuller
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseStage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pullerResolvedTs uint64
+		sinkResolvedTs   uint64
+		barrierTs        uint64
+		checkpointTs     uint64
+		expected         DiagnosisStage
+	}{
+		// Everything pulled has been flushed, regions are lagging.
+		{100, 100, 200, 100, DiagnosisStagePuller},
+		// Pulled events are not delivered to the sink yet.
+		{150, 100, 200, 100, DiagnosisStageSorter},
+		// Events are delivered to the sink but not flushed.
+		{150, 150, 200, 100, DiagnosisStageSink},
+		// The table has reached the barrier.
+		{300, 300, 200, 200, DiagnosisStageBarrier},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, DiagnoseStage(
+			tc.pullerResolvedTs, tc.sinkResolvedTs, tc.barrierTs, tc.checkpointTs))
+	}
+}
@@ -74,6 +74,9 @@ type DDLJobEntry struct {
 	OpType OpType
 	CRTs   uint64
 	Err    error
+	// ReloadSchema is true if the schema should be reloaded at CRTs,
+	// it's sent when the table filter changes.
+	ReloadSchema bool
 }
 
 // TaskPosition records the process information of a capture
@@ -289,6 +292,27 @@ type ChangeFeedStatus struct {
 	ResolvedTs   uint64       `json:"resolved-ts"`
 	CheckpointTs uint64       `json:"checkpoint-ts"`
 	AdminJobType AdminJobType `json:"admin-job-type"`
+	// InitialLoad is the snapshot load progress of tables, it is only set if
+	// the changefeed is created with initial load enabled.
+	InitialLoad map[TableID]*TableLoadProgress `json:"initial-load,omitempty"`
+}
+
+// TableLoadState is the state of the snapshot load of a table.
+type TableLoadState string
+
+const (
+	// TableLoadStateLoading means the snapshot of the table is being scanned
+	// or the scanned rows are not yet written to downstream.
+	TableLoadStateLoading TableLoadState = "loading"
+	// TableLoadStateFinished means all rows in the snapshot of the table have
+	// been written to downstream.
+	TableLoadStateFinished TableLoadState = "finished"
+)
+
+// TableLoadProgress is the snapshot load progress of a table.
+type TableLoadProgress struct {
+	State      TableLoadState `json:"state"`
+	LoadedRows uint64         `json:"loaded-rows"`
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
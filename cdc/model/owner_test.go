@@ -85,6 +85,29 @@ func TestChangeFeedStatusMarshal(t *testing.T) {
 	require.Equal(t, status, newStatus)
 }
 
+func TestChangeFeedStatusMarshalInitialLoad(t *testing.T) {
+	t.Parallel()
+
+	status := &ChangeFeedStatus{
+		ResolvedTs:   420875942036766723,
+		CheckpointTs: 420875940070686721,
+		InitialLoad: map[TableID]*TableLoadProgress{
+			1: {State: TableLoadStateFinished, LoadedRows: 100},
+		},
+	}
+	expected := `{"resolved-ts":420875942036766723,"checkpoint-ts":420875940070686721,` +
+		`"admin-job-type":0,"initial-load":{"1":{"state":"finished","loaded-rows":100}}}`
+
+	data, err := status.Marshal()
+	require.Nil(t, err)
+	require.Equal(t, expected, data)
+
+	newStatus := &ChangeFeedStatus{}
+	err = newStatus.Unmarshal([]byte(data))
+	require.Nil(t, err)
+	require.Equal(t, status, newStatus)
+}
+
 func TestTableOperationState(t *testing.T) {
 	t.Parallel()
 
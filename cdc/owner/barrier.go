@@ -29,6 +29,9 @@ const (
 	syncPointBarrier
 	// finishBarrier denotes a barrier for changefeed finished.
 	finishBarrier
+	// tableSetChangeBarrier denotes a barrier for changing the replicated
+	// tables of a running changefeed.
+	tableSetChangeBarrier
 )
 
 // barriers stores some barrierType and barrierTs, and can calculate the min barrierTs
@@ -15,6 +15,7 @@ package owner
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -26,13 +27,16 @@ import (
 	"github.com/pingcap/tidb/parser/format"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
+	"github.com/pingcap/tiflow/cdc/schemahistory"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -80,8 +84,11 @@ type changefeed struct {
 	barriers         *barriers
 	feedStateManager *feedStateManager
 	redoManager      redo.LogManager
+	// schemaHistory is nil if the schema history is not enabled.
+	schemaHistory *schemahistory.Writer
 
 	schema      *schemaWrap4Owner
+	ddlPolicy   *filter.DDLPolicy
 	sink        DDLSink
 	ddlPuller   puller.DDLPuller
 	initialized bool
@@ -99,6 +106,9 @@ type changefeed struct {
 	// ddlEventCache will be set to nil. ddlEventCache contains more than
 	// one event for a rename tables DDL job.
 	ddlEventCache []*model.DDLEvent
+	// ddlApprovalRequired is true if the DDL job of ddlEventCache is held by
+	// the DDL policies until it's approved or rejected.
+	ddlApprovalRequired bool
 	// currentTableNames is the table names that the changefeed is watching.
 	// And it contains only the tables of the ddl that have been processed.
 	// The ones that have not been executed yet do not have.
@@ -297,6 +307,10 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	}
 	c.sink.emitCheckpointTs(checkpointTs, c.currentTableNames)
 
+	if c.handleTableSetChange(ctx) {
+		return nil
+	}
+
 	barrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
 		return errors.Trace(err)
@@ -385,6 +399,11 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 				newCheckpointTs = c.state.Status.CheckpointTs
 			}
 		})
+		// The checkpoint can't pass the table definitions not persisted yet,
+		// otherwise they are lost if the owner switches.
+		if c.schemaHistory != nil && !c.schemaHistory.Flushed(newCheckpointTs) {
+			newCheckpointTs = c.state.Status.CheckpointTs
+		}
 		c.updateStatus(newCheckpointTs, newResolvedTs)
 		c.updateMetrics(currentTs, newCheckpointTs, newResolvedTs)
 	} else if c.state.Status != nil {
@@ -484,6 +503,10 @@ LOOP:
 	if err != nil {
 		return errors.Trace(err)
 	}
+	c.ddlPolicy, err = filter.NewDDLPolicy(c.state.Info.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	cancelCtx, cancel := cdcContext.WithCancel(ctx)
 	c.cancel = cancel
@@ -502,6 +525,39 @@ LOOP:
 		ctx.Throw(c.ddlPuller.Run(cancelCtx))
 	}()
 
+	if cfg := c.state.Info.Config.SchemaHistory; cfg != nil {
+		storage, err := schemahistory.NewStorage(cancelCtx, cfg.Storage)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.schemaHistory = schemahistory.NewWriter(c.id, storage)
+		c.ddlWg.Add(1)
+		go func() {
+			defer c.ddlWg.Done()
+			ctx.Throw(c.schemaHistory.Run(cancelCtx))
+		}()
+		// All tables are recorded when the changefeed is initialized, so the
+		// definitions at any commit ts after the start ts can be found.
+		records := make([]*schemahistory.Record, 0)
+		for _, info := range c.schema.AllTables() {
+			records = append(records, schemahistory.NewRecord(ddlStartTs,
+				info.TableName.Schema, info.TableName.Table, info, ""))
+		}
+		c.schemaHistory.Emit(records...)
+	}
+
+	if cfg := c.state.Info.Config.Heartbeat; cfg != nil && cfg.Enable {
+		writer, err := heartbeat.NewWriter(c.id, c.state.Info.Config, c.upstream.PDClient)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.ddlWg.Add(1)
+		go func() {
+			defer c.ddlWg.Done()
+			ctx.Throw(writer.Run(cancelCtx))
+		}()
+	}
+
 	stdCtx := contextutil.PutChangefeedIDInCtx(cancelCtx, c.id)
 	redoManagerOpts := redo.NewOwnerManagerOptions(c.errCh)
 	mgr, err := redo.NewManager(stdCtx, c.state.Info.Config.Consistent, redoManagerOpts)
@@ -594,6 +650,7 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 
 	c.cleanupMetrics()
 	c.schema = nil
+	c.schemaHistory = nil
 	c.barriers = nil
 	c.initialized = false
 	c.isReleased = true
@@ -761,17 +818,101 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 			c.feedStateManager.MarkFinished()
 		}
 		return barrierTs, nil
+	case tableSetChangeBarrier:
+		// The change is applied in handleTableSetChange.
+		return barrierTs, nil
 	default:
 		log.Panic("Unknown barrier type", zap.Int("barrierType", int(barrierTp)))
 	}
 	return barrierTs, nil
 }
 
+// updateTableSet records a table set change on the changefeed info,
+// the change is applied when the changefeed reaches the start ts of it.
+func (c *changefeed) updateTableSet(change *model.TableSetChange) error {
+	info, status := c.state.Info, c.state.Status
+	if info == nil || status == nil || info.State != model.StateNormal {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"can only change tables of a running changefeed")
+	}
+	if pending := info.PendingTableSetChange(); pending != nil {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			fmt.Sprintf("the table set change at %d is in progress", pending.StartTs))
+	}
+	// The checkpoint ts can not exceed the barrier at the start ts, even if
+	// the resolved ts has passed it. Processors handle the DDLs after the
+	// start ts again for the added tables.
+	if change.StartTs <= status.CheckpointTs || change.StartTs >= info.GetTargetTs() {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			fmt.Sprintf("start ts %d must be greater than the checkpoint ts %d "+
+				"and less than the target ts %d",
+				change.StartTs, status.CheckpointTs, info.GetTargetTs()))
+	}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		info.TableSetChange = change
+		return info, true, nil
+	})
+	// Block the changefeed at the start ts before the change is committed
+	// to etcd, so the resolved ts can not exceed the start ts.
+	if c.barriers != nil {
+		c.barriers.Update(tableSetChangeBarrier, change.StartTs)
+	}
+	log.Info("changefeed table set change is accepted",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Strings("rules", change.Rules),
+		zap.Uint64("startTs", change.StartTs),
+		zap.Bool("initialLoad", change.InitialLoad))
+	return nil
+}
+
+// handleTableSetChange blocks the changefeed at the start ts of the pending
+// table set change. Once the changefeed reaches the start ts, the change is
+// applied and the resources of the changefeed in the owner are released, so
+// the changefeed is initialized with the new filter rules in the next tick,
+// and the scheduler adds or removes tables according to the new rules.
+// Processors keep replicating tables during the re-initialization, as they
+// do when the owner switches. It returns true if the change is applied.
+func (c *changefeed) handleTableSetChange(ctx cdcContext.Context) bool {
+	change := c.state.Info.PendingTableSetChange()
+	if change == nil {
+		return false
+	}
+	c.barriers.Update(tableSetChangeBarrier, change.StartTs)
+	if c.state.Status.CheckpointTs < change.StartTs {
+		return false
+	}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PendingTableSetChange() == nil {
+			return info, false, nil
+		}
+		info.Config.Filter.Rules = info.TableSetChange.Rules
+		info.TableSetChange.Applied = true
+		return info, true, nil
+	})
+	c.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventTablesChanged,
+		fmt.Sprintf("the filter rules are changed to %s at %d",
+			strings.Join(change.Rules, ", "), change.StartTs)))
+	log.Info("changefeed applies table set change",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Strings("rules", change.Rules),
+		zap.Uint64("startTs", change.StartTs))
+	c.releaseResources(ctx)
+	// Table names are refreshed by the new schema after initialization.
+	c.currentTableNames = nil
+	return true
+}
+
 // asyncExecDDLJob execute ddl job asynchronously, it returns true if the jod is done.
-// 0. Build ddl events from job.
+// 0. Build ddl events from job, apply ddl policies to them.
 // 1. Apply ddl job to c.schema.
-// 2. Emit ddl event to redo manager.
-// 3. Emit ddl event to ddl sink.
+// 2. Wait for approval if the job is held by ddl policies.
+// 3. Emit ddl event to redo manager.
+// 4. Emit ddl event to ddl sink.
 func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 	job *timodel.Job,
 ) (bool, error) {
@@ -789,6 +930,13 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 				zap.Any("job", job), zap.Error(err))
 			return false, errors.Trace(err)
 		}
+		// The changed tables are copied before the events are rewritten by
+		// ddl policies.
+		changed, renamed := changedTables(ddlEvents)
+		ddlEvents, c.ddlApprovalRequired, err = c.applyDDLPolicies(ddlEvents)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
 		c.ddlEventCache = ddlEvents
 		// We can't use the latest schema directly,
 		// we need to make sure we receive the ddl before we start or stop broadcasting checkpoint ts.
@@ -802,19 +950,37 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 		if err != nil {
 			return false, errors.Trace(err)
 		}
-		if c.redoManager.Enabled() {
-			for _, ddlEvent := range c.ddlEventCache {
-				// FIXME: seems it's not necessary to emit DDL to redo storage,
-				// because for a given redo meta with range (checkpointTs, resolvedTs],
-				// there must be no pending DDLs not flushed into DDL sink.
-				err = c.redoManager.EmitDDLEvent(ctx, ddlEvent)
-				if err != nil {
-					return false, err
-				}
+		c.emitSchemaHistory(job, changed, renamed)
+		if !c.ddlApprovalRequired {
+			if err := c.emitDDLEventsToRedo(ctx); err != nil {
+				return false, err
 			}
 		}
 	}
 
+	if c.ddlApprovalRequired {
+		decided, approved := c.checkDDLApproval(job.BinlogInfo.FinishedTS)
+		if !decided {
+			return false, nil
+		}
+		c.ddlApprovalRequired = false
+		if !approved {
+			// The rejected job is skipped.
+			c.ddlEventCache = nil
+			c.currentTableNames = nil
+			return true, nil
+		}
+		if err := c.emitDDLEventsToRedo(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// The DDL is executed after the table definitions changed by it are
+	// persisted, so the definitions are found by the rows after the DDL.
+	if c.schemaHistory != nil && !c.schemaHistory.Flushed(job.BinlogInfo.FinishedTS) {
+		return false, nil
+	}
+
 	jobDone := true
 	for _, event := range c.ddlEventCache {
 		eventDone, err := c.asyncExecDDLEvent(ctx, event)
@@ -825,6 +991,8 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 	}
 
 	if jobDone {
+		c.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventDDLApplied,
+			fmt.Sprintf("%s (commit ts %d)", job.Query, job.BinlogInfo.FinishedTS)))
 		c.ddlEventCache = nil
 		// It has expired.
 		// We should use the latest table names now.
@@ -834,6 +1002,165 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 	return jobDone, nil
 }
 
+func (c *changefeed) emitDDLEventsToRedo(ctx cdcContext.Context) error {
+	if !c.redoManager.Enabled() {
+		return nil
+	}
+	for _, ddlEvent := range c.ddlEventCache {
+		// FIXME: seems it's not necessary to emit DDL to redo storage,
+		// because for a given redo meta with range (checkpointTs, resolvedTs],
+		// there must be no pending DDLs not flushed into DDL sink.
+		if err := c.redoManager.EmitDDLEvent(ctx, ddlEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changedTables returns the tables changed by the ddl events and the old
+// tables of the renamed ones.
+func changedTables(
+	ddlEvents []*model.DDLEvent,
+) (changed, renamed []model.SimpleTableInfo) {
+	for _, ddlEvent := range ddlEvents {
+		if ddlEvent.TableInfo == nil || ddlEvent.TableInfo.Table == "" {
+			continue
+		}
+		changed = append(changed, *ddlEvent.TableInfo)
+		pre := ddlEvent.PreTableInfo
+		if pre != nil && pre.Table != "" &&
+			(pre.Schema != ddlEvent.TableInfo.Schema || pre.Table != ddlEvent.TableInfo.Table) {
+			renamed = append(renamed, *pre)
+		}
+	}
+	return
+}
+
+// emitSchemaHistory records the definitions of the tables changed by the ddl
+// job after it is applied to the schema, even if the ddl events are skipped
+// by ddl policies, since the rows of the tables are changed anyway. The job
+// is not executed until the records are written.
+// Dropped tables and the old tables of renamed ones are recorded as dropped.
+func (c *changefeed) emitSchemaHistory(
+	job *timodel.Job, changed, renamed []model.SimpleTableInfo,
+) {
+	if c.schemaHistory == nil {
+		return
+	}
+	version := job.BinlogInfo.FinishedTS
+	records := make([]*schemahistory.Record, 0, len(changed)+len(renamed))
+	for _, t := range renamed {
+		records = append(records, schemahistory.NewDroppedRecord(
+			version, t.Schema, t.Table, t.TableID, job.Query))
+	}
+	for _, t := range changed {
+		info, ok := c.schema.TableByID(t.TableID)
+		if !ok {
+			records = append(records, schemahistory.NewDroppedRecord(
+				version, t.Schema, t.Table, t.TableID, job.Query))
+			continue
+		}
+		records = append(records, schemahistory.NewRecord(
+			version, t.Schema, t.Table, info, job.Query))
+	}
+	c.schemaHistory.Emit(records...)
+}
+
+// applyDDLPolicies applies the ddl policies to the ddl events of a job, it
+// returns the events not skipped, and whether the job requires approval.
+func (c *changefeed) applyDDLPolicies(
+	ddlEvents []*model.DDLEvent,
+) ([]*model.DDLEvent, bool, error) {
+	res := make([]*model.DDLEvent, 0, len(ddlEvents))
+	approvalRequired := false
+	for _, ddlEvent := range ddlEvents {
+		action, err := c.ddlPolicy.Apply(ddlEvent)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		switch action {
+		case config.DDLPolicyActionSkip:
+			log.Info("DDL event is skipped by ddl policy",
+				zap.String("namespace", c.id.Namespace),
+				zap.String("changefeed", c.id.ID), zap.Any("event", ddlEvent))
+			continue
+		case config.DDLPolicyActionRewrite:
+			log.Info("DDL event is rewritten by ddl policy",
+				zap.String("namespace", c.id.Namespace),
+				zap.String("changefeed", c.id.ID), zap.Any("event", ddlEvent))
+		case config.DDLPolicyActionApprove:
+			approvalRequired = true
+		}
+		res = append(res, ddlEvent)
+	}
+	return res, approvalRequired, nil
+}
+
+// checkDDLApproval checks whether the held DDL job committed at commitTs is
+// approved. The job is recorded on the changefeed info for approval if it's
+// not recorded yet. It returns whether the job is decided and approved.
+func (c *changefeed) checkDDLApproval(commitTs uint64) (decided, approved bool) {
+	approval := c.state.Info.DDLApproval
+	if approval == nil || approval.CommitTs != commitTs {
+		queries := make([]string, 0, len(c.ddlEventCache))
+		for _, ddlEvent := range c.ddlEventCache {
+			queries = append(queries, ddlEvent.Query)
+		}
+		c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			if info == nil {
+				return nil, false, nil
+			}
+			info.DDLApproval = &model.DDLApproval{
+				CommitTs: commitTs,
+				Queries:  queries,
+				State:    model.DDLApprovalPending,
+			}
+			return info, true, nil
+		})
+		log.Info("DDL is held by ddl policy for approval",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("commitTs", commitTs),
+			zap.Strings("queries", queries))
+		return false, false
+	}
+	switch approval.State {
+	case model.DDLApprovalApproved:
+		return true, true
+	case model.DDLApprovalRejected:
+		return true, false
+	}
+	return false, false
+}
+
+// approveDDL approves or rejects the DDL waiting for approval.
+func (c *changefeed) approveDDL(commitTs uint64, approved bool) error {
+	info := c.state.Info
+	if info == nil || info.PendingDDLApproval() == nil ||
+		info.DDLApproval.CommitTs != commitTs {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			fmt.Sprintf("no DDL committed at %d is waiting for approval", commitTs))
+	}
+	state := model.DDLApprovalRejected
+	if approved {
+		state = model.DDLApprovalApproved
+	}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PendingDDLApproval() == nil ||
+			info.DDLApproval.CommitTs != commitTs {
+			return info, false, nil
+		}
+		info.DDLApproval.State = state
+		return info, true, nil
+	})
+	log.Info("DDL held by ddl policy is decided",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Uint64("commitTs", commitTs),
+		zap.String("state", string(state)))
+	return nil
+}
+
 func (c *changefeed) asyncExecDDLEvent(ctx cdcContext.Context,
 	ddlEvent *model.DDLEvent,
 ) (done bool, err error) {
@@ -847,6 +1174,13 @@ func (c *changefeed) asyncExecDDLEvent(ctx cdcContext.Context,
 			zap.String("changefeed", c.id.ID), zap.Any("event", ddlEvent))
 		return true, nil
 	}
+	if cfg := c.state.Info.Config; cfg.BDREnabled() && !cfg.BDR.ReplicateDDL {
+		// DDLs are executed in all clusters by users in bdr mode.
+		log.Info("ignore the DDL event in bdr mode",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID), zap.Any("event", ddlEvent))
+		return true, nil
+	}
 	done, err = c.sink.emitDDLEvent(ctx, ddlEvent)
 	if err != nil {
 		return false, err
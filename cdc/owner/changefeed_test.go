@@ -26,6 +26,7 @@ import (
 
 	"github.com/labstack/gommon/log"
 	"github.com/pingcap/errors"
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
@@ -478,6 +480,47 @@ func TestFinished(t *testing.T) {
 	require.Equal(t, cf.state.Info.State, model.StateFinished)
 }
 
+func TestTableSetChange(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	// initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	startTs := cf.state.Status.ResolvedTs + 1000
+	change := &model.TableSetChange{Rules: []string{"test.t1"}, StartTs: startTs}
+	// The start ts must be greater than the checkpoint ts.
+	err := cf.updateTableSet(&model.TableSetChange{StartTs: cf.state.Status.CheckpointTs})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(err))
+	require.Nil(t, cf.updateTableSet(change))
+	tester.MustApplyPatches()
+	require.Equal(t, change, cf.state.Info.PendingTableSetChange())
+	// Only one change can be in progress.
+	err = cf.updateTableSet(&model.TableSetChange{StartTs: startTs + 1})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(err))
+
+	mockDDLPuller := cf.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs += 2000
+	// The changefeed is blocked at the start ts of the change, then the change
+	// is applied and the changefeed is re-initialized with the new rules.
+	for i := 0; i <= 10; i++ {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		require.LessOrEqual(t, cf.state.Status.ResolvedTs, startTs)
+	}
+	require.True(t, cf.initialized)
+	require.Equal(t, startTs, cf.state.Status.CheckpointTs)
+	require.Nil(t, cf.state.Info.PendingTableSetChange())
+	require.True(t, cf.state.Info.TableSetChange.Applied)
+	require.Equal(t, []string{"test.t1"}, cf.state.Info.Config.Filter.Rules)
+}
+
 func TestRemoveChangefeed(t *testing.T) {
 	baseCtx, cancel := context.WithCancel(context.Background())
 	ctx := cdcContext.NewContext4Test(baseCtx, true)
@@ -986,6 +1029,93 @@ func TestExecDropViewsDDL(t *testing.T) {
 	execDropStmt(jobs[1], "DROP VIEW `test1`.`view1`")
 }
 
+func TestExecDDLWithPolicies(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx.ChangefeedVars().Info.Config.DDLPolicies = []*config.DDLPolicy{
+		{
+			Matcher: []string{"test1.skip*"},
+			Action:  config.DDLPolicyActionSkip,
+		},
+		{
+			Matcher:  []string{"test1.*"},
+			DDLTypes: []bf.EventType{bf.DropTable},
+			Action:   config.DDLPolicyActionApprove,
+		},
+	}
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	// initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	mockDDLSink := cf.sink.(*mockDDLSink)
+	execStmt := func(job *timodel.Job, expectedDDL string) {
+		done, err := cf.asyncExecDDLJob(ctx, job)
+		require.Nil(t, err)
+		require.Equal(t, false, done)
+		require.Equal(t, expectedDDL, mockDDLSink.ddlExecuting.Query)
+		mockDDLSink.ddlDone = true
+		done, err = cf.asyncExecDDLJob(ctx, job)
+		require.Nil(t, err)
+		require.Equal(t, true, done)
+	}
+	execStmt(helper.DDL2Job("create database test1"), "CREATE DATABASE `test1`")
+	execStmt(helper.DDL2Job("create table test1.tb1(id int primary key)"),
+		"CREATE TABLE `test1`.`tb1` (`id` INT PRIMARY KEY)")
+
+	// The skipped DDL is done without being sent to the sink.
+	mockDDLSink.ddlExecuting = nil
+	done, err := cf.asyncExecDDLJob(ctx,
+		helper.DDL2Job("create table test1.skip1(id int primary key)"))
+	require.Nil(t, err)
+	require.True(t, done)
+	require.Nil(t, mockDDLSink.ddlExecuting)
+
+	// The held DDL waits for approval.
+	job := helper.DDL2Job("drop table test1.tb1")
+	done, err = cf.asyncExecDDLJob(ctx, job)
+	require.Nil(t, err)
+	require.False(t, done)
+	require.Nil(t, mockDDLSink.ddlExecuting)
+	tester.MustApplyPatches()
+	approval := cf.state.Info.PendingDDLApproval()
+	require.NotNil(t, approval)
+	require.Equal(t, job.BinlogInfo.FinishedTS, approval.CommitTs)
+	require.Len(t, approval.Queries, 1)
+	done, err = cf.asyncExecDDLJob(ctx, job)
+	require.Nil(t, err)
+	require.False(t, done)
+
+	require.Regexp(t, ".*no DDL committed at.*",
+		cf.approveDDL(approval.CommitTs+1, true))
+	require.Nil(t, cf.approveDDL(approval.CommitTs, true))
+	tester.MustApplyPatches()
+	require.Nil(t, cf.state.Info.PendingDDLApproval())
+	execStmt(job, "DROP TABLE `test1`.`tb1`")
+
+	// The rejected DDL is skipped.
+	execStmt(helper.DDL2Job("create table test1.tb2(id int primary key)"),
+		"CREATE TABLE `test1`.`tb2` (`id` INT PRIMARY KEY)")
+	mockDDLSink.ddlExecuting = nil
+	job = helper.DDL2Job("drop table test1.tb2")
+	done, err = cf.asyncExecDDLJob(ctx, job)
+	require.Nil(t, err)
+	require.False(t, done)
+	tester.MustApplyPatches()
+	require.Nil(t, cf.approveDDL(job.BinlogInfo.FinishedTS, false))
+	tester.MustApplyPatches()
+	done, err = cf.asyncExecDDLJob(ctx, job)
+	require.Nil(t, err)
+	require.True(t, done)
+	require.Nil(t, mockDDLSink.ddlExecuting)
+}
+
 func TestBarrierAdvance(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		ctx := cdcContext.NewBackendContext4Test(true)
@@ -1039,3 +1169,36 @@ func TestBarrierAdvance(t *testing.T) {
 		require.Equal(t, mockDDLPuller.resolvedTs, barrier)
 	}
 }
+
+func TestChangedTables(t *testing.T) {
+	t.Parallel()
+
+	ddlEvents := []*model.DDLEvent{
+		{
+			Query:     "CREATE DATABASE test",
+			TableInfo: &model.SimpleTableInfo{Schema: "test"},
+		},
+		{
+			Query:        "ALTER TABLE test.t1 ADD COLUMN c1 INT",
+			TableInfo:    &model.SimpleTableInfo{Schema: "test", Table: "t1", TableID: 1},
+			PreTableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1", TableID: 1},
+		},
+		{
+			Query:        "RENAME TABLE test.t2 TO test.t3",
+			TableInfo:    &model.SimpleTableInfo{Schema: "test", Table: "t3", TableID: 2},
+			PreTableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t2", TableID: 2},
+		},
+	}
+	changed, renamed := changedTables(ddlEvents)
+	require.Equal(t, []model.SimpleTableInfo{
+		{Schema: "test", Table: "t1", TableID: 1},
+		{Schema: "test", Table: "t3", TableID: 2},
+	}, changed)
+	require.Equal(t, []model.SimpleTableInfo{
+		{Schema: "test", Table: "t2", TableID: 2},
+	}, renamed)
+
+	// The tables are copied, so they are not changed by ddl policies.
+	ddlEvents[1].TableInfo.Schema = "test_replica"
+	require.Equal(t, "test", changed[0].Schema)
+}
@@ -14,6 +14,7 @@
 package owner
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -213,6 +214,8 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.shouldBeRunning = false
 		jobsPending = true
 		m.patchState(model.StateStopped)
+		m.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventPaused,
+			"the changefeed is paused"))
 	case model.AdminRemove:
 
 		switch m.state.Info.State {
@@ -238,6 +241,8 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
 			return nil, true, nil
 		})
+		// remove changefeed events
+		m.state.RemoveEvents()
 		checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
 
 		log.Info("the changefeed is removed",
@@ -261,6 +266,12 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.lastErrorTime = time.Unix(0, 0)
 		jobsPending = true
 		m.patchState(model.StateNormal)
+		message := "the changefeed is resumed"
+		if job.OverwriteCheckpointTs > 0 {
+			message = fmt.Sprintf("the changefeed is resumed with checkpoint ts %d",
+				job.OverwriteCheckpointTs)
+		}
+		m.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventResumed, message))
 
 		m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
 			changed := false
@@ -312,6 +323,8 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.shouldBeRunning = false
 		jobsPending = true
 		m.patchState(model.StateFinished)
+		m.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventFinished,
+			"the changefeed reaches the target ts"))
 	default:
 		log.Warn("Unknown admin job", zap.Any("adminJob", job),
 			zap.String("namespace", m.state.ID.Namespace),
@@ -427,6 +440,7 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 			})
 			m.shouldBeRunning = false
 			m.patchState(model.StateFailed)
+			m.recordErrors(model.ChangefeedEventFailed, "the changefeed is failed", err)
 			return
 		}
 	}
@@ -445,6 +459,8 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 			})
 			m.shouldBeRunning = false
 			m.patchState(model.StateError)
+			m.recordErrors(model.ChangefeedEventError,
+				"the changefeed is stopped by an unretryable error", err)
 			return
 		}
 	}
@@ -458,6 +474,7 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 		}
 		return info, len(errs) > 0, nil
 	})
+	m.recordErrors(model.ChangefeedEventError, "the changefeed meets an error", errs...)
 
 	// If we enter into an abnormal state ('error', 'failed') for this changefeed now
 	// but haven't seen abnormal states in a sliding window (512 ticks),
@@ -490,6 +507,8 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 		// ref: https://github.com/cenkalti/backoff/blob/v4/exponential.go#L121-L123
 		m.backoffInterval = m.errBackoff.NextBackOff()
 		m.lastErrorTime = time.Unix(0, 0)
+		m.state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventRestarted,
+			fmt.Sprintf("the changefeed is restarted after backoff %s", oldBackoffInterval)))
 
 		log.Info("changefeed restart backoff interval is changed",
 			zap.String("namespace", m.state.ID.Namespace),
@@ -498,3 +517,19 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 			zap.Duration("newInterval", m.backoffInterval))
 	}
 }
+
+// recordErrors records the errors in the event log of the changefeed.
+func (m *feedStateManager) recordErrors(
+	tp model.ChangefeedEventType, message string, errs ...*model.RunningError,
+) {
+	if len(errs) == 0 {
+		return
+	}
+	events := make([]*model.ChangefeedEvent, 0, len(errs))
+	for _, err := range errs {
+		event := model.NewChangefeedEvent(tp, message)
+		event.Error = err
+		events = append(events, event)
+	}
+	m.state.AppendEvents(events...)
+}
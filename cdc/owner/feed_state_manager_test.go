@@ -15,6 +15,8 @@ package owner
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +29,29 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func getChangefeedEventTypes(
+	t *testing.T, tester *orchestrator.ReactorStateTester, id model.ChangeFeedID,
+) []model.ChangefeedEventType {
+	prefix := etcd.GetEtcdKeyChangefeedEvents(etcd.DefaultCDCClusterID, id)
+	keys := make([]string, 0)
+	for key := range tester.KVEntries() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	types := make([]model.ChangefeedEventType, 0, len(keys))
+	for _, key := range keys {
+		event := &model.ChangefeedEvent{}
+		require.Nil(t, event.Unmarshal([]byte(tester.KVEntries()[key])))
+		types = append(types, event.Type)
+	}
+	return types
+}
+
 func TestHandleJob(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
@@ -77,6 +102,8 @@ func TestHandleJob(t *testing.T) {
 	require.Equal(t, state.Info.State, model.StateStopped)
 	require.Equal(t, state.Info.AdminJobType, model.AdminStop)
 	require.Equal(t, state.Status.AdminJobType, model.AdminStop)
+	require.Equal(t, []model.ChangefeedEventType{model.ChangefeedEventPaused},
+		getChangefeedEventTypes(t, tester, ctx.ChangefeedVars().ID))
 
 	// resume a changefeed
 	manager.PushAdminJob(&model.AdminJob{
@@ -90,6 +117,9 @@ func TestHandleJob(t *testing.T) {
 	require.Equal(t, state.Info.State, model.StateNormal)
 	require.Equal(t, state.Info.AdminJobType, model.AdminNone)
 	require.Equal(t, state.Status.AdminJobType, model.AdminNone)
+	require.Equal(t, []model.ChangefeedEventType{
+		model.ChangefeedEventPaused, model.ChangefeedEventResumed,
+	}, getChangefeedEventTypes(t, tester, ctx.ChangefeedVars().ID))
 
 	// remove a changefeed
 	manager.PushAdminJob(&model.AdminJob{
@@ -102,6 +132,7 @@ func TestHandleJob(t *testing.T) {
 	require.False(t, manager.ShouldRunning())
 	require.True(t, manager.ShouldRemoved())
 	require.False(t, state.Exist())
+	require.Nil(t, getChangefeedEventTypes(t, tester, ctx.ChangefeedVars().ID))
 }
 
 func TestResumeChangefeedWithCheckpointTs(t *testing.T) {
@@ -290,6 +321,13 @@ func TestHandleError(t *testing.T) {
 		manager.Tick(state)
 		tester.MustApplyPatches()
 	}
+	// Every error and restart is recorded.
+	types := getChangefeedEventTypes(t, tester, ctx.ChangefeedVars().ID)
+	require.Len(t, types, 2*len(intervals))
+	for i := range intervals {
+		require.Equal(t, model.ChangefeedEventError, types[2*i])
+		require.Equal(t, model.ChangefeedEventRestarted, types[2*i+1])
+	}
 }
 
 func TestHandleFastFailError(t *testing.T) {
@@ -39,6 +39,18 @@ func (m *MockOwner) EXPECT() *MockOwnerMockRecorder {
 	return m.recorder
 }
 
+// ApproveDDL mocks base method.
+func (m *MockOwner) ApproveDDL(cfID model.ChangeFeedID, commitTs uint64, approved bool, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ApproveDDL", cfID, commitTs, approved, done)
+}
+
+// ApproveDDL indicates an expected call of ApproveDDL.
+func (mr *MockOwnerMockRecorder) ApproveDDL(cfID, commitTs, approved, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDDL", reflect.TypeOf((*MockOwner)(nil).ApproveDDL), cfID, commitTs, approved, done)
+}
+
 // AsyncStop mocks base method.
 func (m *MockOwner) AsyncStop() {
 	m.ctrl.T.Helper()
@@ -126,6 +138,18 @@ func (mr *MockOwnerMockRecorder) Tick(ctx, state interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockOwner)(nil).Tick), ctx, state)
 }
 
+// UpdateTableSet mocks base method.
+func (m *MockOwner) UpdateTableSet(cfID model.ChangeFeedID, change *model.TableSetChange, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateTableSet", cfID, change, done)
+}
+
+// UpdateTableSet indicates an expected call of UpdateTableSet.
+func (mr *MockOwnerMockRecorder) UpdateTableSet(cfID, change, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTableSet", reflect.TypeOf((*MockOwner)(nil).UpdateTableSet), cfID, change, done)
+}
+
 // WriteDebugInfo mocks base method.
 func (m *MockOwner) WriteDebugInfo(w io.Writer, done chan<- error) {
 	m.ctrl.T.Helper()
@@ -15,6 +15,7 @@ package owner
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -44,6 +45,8 @@ const (
 	ownerJobTypeAdminJob
 	ownerJobTypeDebugInfo
 	ownerJobTypeQuery
+	ownerJobTypeUpdateTableSet
+	ownerJobTypeApproveDDL
 )
 
 // versionInconsistentLogRate represents the rate of log output when there are
@@ -72,6 +75,13 @@ type ownerJob struct {
 	// for scheduler related jobs
 	scheduleQuery *scheduler.Query
 
+	// for UpdateTableSet only
+	TableSetChange *model.TableSetChange
+
+	// for ApproveDDL only
+	DDLCommitTs uint64
+	DDLApproved bool
+
 	done chan<- error
 }
 
@@ -89,6 +99,12 @@ type Owner interface {
 	DrainCapture(query *scheduler.Query, done chan<- error)
 	WriteDebugInfo(w io.Writer, done chan<- error)
 	Query(query *Query, done chan<- error)
+	UpdateTableSet(
+		cfID model.ChangeFeedID, change *model.TableSetChange, done chan<- error,
+	)
+	ApproveDDL(
+		cfID model.ChangeFeedID, commitTs uint64, approved bool, done chan<- error,
+	)
 	AsyncStop()
 }
 
@@ -285,6 +301,33 @@ func (o *ownerImpl) Query(query *Query, done chan<- error) {
 	})
 }
 
+// UpdateTableSet changes the replicated tables of a running changefeed
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) UpdateTableSet(
+	cfID model.ChangeFeedID, change *model.TableSetChange, done chan<- error,
+) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:             ownerJobTypeUpdateTableSet,
+		ChangefeedID:   cfID,
+		TableSetChange: change,
+		done:           done,
+	})
+}
+
+// ApproveDDL approves or rejects the DDL held by the DDL policies of a
+// changefeed. `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) ApproveDDL(
+	cfID model.ChangeFeedID, commitTs uint64, approved bool, done chan<- error,
+) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:           ownerJobTypeApproveDDL,
+		ChangefeedID: cfID,
+		DDLCommitTs:  commitTs,
+		DDLApproved:  approved,
+		done:         done,
+	})
+}
+
 // AsyncStop stops the owner asynchronously
 func (o *ownerImpl) AsyncStop() {
 	atomic.StoreInt32(&o.closed, 1)
@@ -305,6 +348,7 @@ func (o *ownerImpl) cleanUpChangefeed(state *orchestrator.ChangefeedReactorState
 			return nil, position != nil, nil
 		})
 	}
+	state.RemoveEvents()
 }
 
 // Bootstrap checks if the state contains incompatible or incorrect information and tries to fix it.
@@ -487,6 +531,10 @@ func (o *ownerImpl) handleJobs(ctx context.Context) {
 			// Scheduler is created lazily, it is nil before initialization.
 			if cfReactor.scheduler != nil {
 				cfReactor.scheduler.MoveTable(job.TableID, job.TargetCaptureID)
+				cfReactor.state.AppendEvents(model.NewChangefeedEvent(
+					model.ChangefeedEventTableMoved,
+					fmt.Sprintf("table %d is requested to move to capture %s",
+						job.TableID, job.TargetCaptureID)))
 			}
 		case ownerJobTypeDrainCapture:
 			o.handleDrainCaptures(ctx, job.scheduleQuery, job.done)
@@ -495,9 +543,15 @@ func (o *ownerImpl) handleJobs(ctx context.Context) {
 			// Scheduler is created lazily, it is nil before initialization.
 			if cfReactor.scheduler != nil {
 				cfReactor.scheduler.Rebalance()
+				cfReactor.state.AppendEvents(model.NewChangefeedEvent(
+					model.ChangefeedEventRebalanced, "tables are requested to rebalance"))
 			}
 		case ownerJobTypeQuery:
 			job.done <- o.handleQueries(job.query)
+		case ownerJobTypeUpdateTableSet:
+			job.done <- cfReactor.updateTableSet(job.TableSetChange)
+		case ownerJobTypeApproveDDL:
+			job.done <- cfReactor.approveDDL(job.DDLCommitTs, job.DDLApproved)
 		case ownerJobTypeDebugInfo:
 			// TODO: implement this function
 		}
@@ -116,6 +116,23 @@ func (s *schemaWrap4Owner) AllTableNames() []model.TableName {
 	return names
 }
 
+// AllTables returns the table infos of all tables that are being replicated.
+func (s *schemaWrap4Owner) AllTables() []*model.TableInfo {
+	tables := make([]*model.TableInfo, 0)
+	s.schemaSnapshot.IterTables(true, func(tblInfo *model.TableInfo) {
+		if !s.shouldIgnoreTable(tblInfo) {
+			tables = append(tables, tblInfo)
+		}
+	})
+	return tables
+}
+
+// TableByID returns the table info of the table, the table is not found if
+// it's dropped.
+func (s *schemaWrap4Owner) TableByID(tableID model.TableID) (*model.TableInfo, bool) {
+	return s.schemaSnapshot.PhysicalTableByID(tableID)
+}
+
 func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 	s.allPhysicalTablesCache = nil
 	err := s.schemaSnapshot.HandleDDL(job)
@@ -40,6 +40,9 @@ const (
 	// Query the number of tables in the manager.
 	// command payload is a buffer channel of int, make(chan int, 1).
 	commandTpQueryTableCount
+	// Query the replication progress of tables of a changefeed.
+	// command payload is a *diagnosisQuery.
+	commandTpQueryDiagnosis
 	processorLogsWarnDuration = 1 * time.Second
 )
 
@@ -49,10 +52,22 @@ type command struct {
 	done    chan<- error
 }
 
+type diagnosisQuery struct {
+	changefeedID model.ChangeFeedID
+	regionLimit  int
+	tableCh      chan []model.TableDiagnosis
+}
+
 // Manager is a manager of processor, which maintains the state and behavior of processors
 type Manager interface {
 	orchestrator.Reactor
 	QueryTableCount(ctx context.Context, tableCh chan int, done chan<- error)
+	// QueryDiagnosis queries the replication progress of tables of the given
+	// changefeed in this capture, the result is sent to tableCh.
+	QueryDiagnosis(
+		ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int,
+		tableCh chan []model.TableDiagnosis, done chan<- error,
+	)
 	WriteDebugInfo(ctx context.Context, w io.Writer, done chan<- error)
 	AsyncClose()
 }
@@ -194,6 +209,22 @@ func (m *managerImpl) QueryTableCount(
 	}
 }
 
+// QueryDiagnosis implements Manager.QueryDiagnosis.
+func (m *managerImpl) QueryDiagnosis(
+	ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int,
+	tableCh chan []model.TableDiagnosis, done chan<- error,
+) {
+	query := &diagnosisQuery{
+		changefeedID: changefeedID,
+		regionLimit:  regionLimit,
+		tableCh:      tableCh,
+	}
+	err := m.sendCommand(ctx, commandTpQueryDiagnosis, query, done)
+	if err != nil {
+		log.Warn("send command commandTpQueryDiagnosis failed", zap.Error(err))
+	}
+}
+
 // WriteDebugInfo write the debug info to Writer
 func (m *managerImpl) WriteDebugInfo(
 	ctx context.Context, w io.Writer, done chan<- error,
@@ -247,6 +278,16 @@ func (m *managerImpl) handleCommand() error {
 		case cmd.payload.(chan int) <- count:
 		default:
 		}
+	case commandTpQueryDiagnosis:
+		query := cmd.payload.(*diagnosisQuery)
+		var tables []model.TableDiagnosis
+		if p, ok := m.processors[query.changefeedID]; ok {
+			tables = p.Diagnose(query.regionLimit)
+		}
+		select {
+		case query.tableCh <- tables:
+		default:
+		}
 	default:
 		log.Warn("Unknown command in processor manager", zap.Any("command", cmd))
 	}
@@ -313,3 +313,41 @@ func TestQueryTableCount(t *testing.T) {
 		require.FailNow(t, "done must be closed")
 	}
 }
+
+func TestQueryDiagnosis(t *testing.T) {
+	liveness := model.LivenessCaptureAlive
+	captureInfo := &model.CaptureInfo{ID: "capture-test"}
+	m := NewManager(captureInfo, nil, &liveness).(*managerImpl)
+	ctx := context.TODO()
+	changefeedID := model.ChangeFeedID{ID: "test"}
+	m.processors[changefeedID] = &processor{
+		captureInfo: captureInfo,
+		tables: map[model.TableID]tablepipeline.TablePipeline{
+			1: &mockTablePipeline{tableID: 1, resolvedTs: 20, checkpointTs: 20, barrierTs: 30},
+			2: &mockTablePipeline{tableID: 2, resolvedTs: 20, checkpointTs: 10, barrierTs: 10},
+		},
+	}
+
+	done := make(chan error, 1)
+	tableCh := make(chan []model.TableDiagnosis, 1)
+	m.QueryDiagnosis(ctx, changefeedID, model.DefaultDiagnosisRegionLimit, tableCh, done)
+	require.Nil(t, m.handleCommand())
+	select {
+	case tables := <-tableCh:
+		require.Len(t, tables, 2)
+		// Tables are sorted by checkpoint ts.
+		require.Equal(t, model.TableID(2), tables[0].TableID)
+		require.Equal(t, model.DiagnosisStageBarrier, tables[0].BlockingStage)
+		require.Equal(t, model.TableID(1), tables[1].TableID)
+		require.Equal(t, model.DiagnosisStagePuller, tables[1].BlockingStage)
+		require.Equal(t, "capture-test", tables[1].CaptureID)
+	case <-time.After(time.Second):
+		require.FailNow(t, "done must be closed")
+	}
+
+	// Query a changefeed that is not running in the capture.
+	done = make(chan error, 1)
+	m.QueryDiagnosis(ctx, model.ChangeFeedID{ID: "unknown"}, 1, tableCh, done)
+	require.Nil(t, m.handleCommand())
+	require.Len(t, <-tableCh, 0)
+}
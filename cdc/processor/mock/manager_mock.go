@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	model "github.com/pingcap/tiflow/cdc/model"
 	orchestrator "github.com/pingcap/tiflow/pkg/orchestrator"
 )
 
@@ -48,6 +49,18 @@ func (mr *MockManagerMockRecorder) AsyncClose() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncClose", reflect.TypeOf((*MockManager)(nil).AsyncClose))
 }
 
+// QueryDiagnosis mocks base method.
+func (m *MockManager) QueryDiagnosis(ctx context.Context, changefeedID model.ChangeFeedID, regionLimit int, tableCh chan []model.TableDiagnosis, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "QueryDiagnosis", ctx, changefeedID, regionLimit, tableCh, done)
+}
+
+// QueryDiagnosis indicates an expected call of QueryDiagnosis.
+func (mr *MockManagerMockRecorder) QueryDiagnosis(ctx, changefeedID, regionLimit, tableCh, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryDiagnosis", reflect.TypeOf((*MockManager)(nil).QueryDiagnosis), ctx, changefeedID, regionLimit, tableCh, done)
+}
+
 // QueryTableCount mocks base method.
 func (m *MockManager) QueryTableCount(ctx context.Context, tableCh chan int, done chan<- error) {
 	m.ctrl.T.Helper()
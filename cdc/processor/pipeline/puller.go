@@ -15,6 +15,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/contextutil"
@@ -31,24 +32,32 @@ import (
 type pullerNode struct {
 	tableName string // quoted schema and table, used in metircs only
 
-	tableID    model.TableID
-	startTs    model.Ts
-	changefeed model.ChangeFeedID
-	cancel     context.CancelFunc
-	wg         *errgroup.Group
+	tableID     model.TableID
+	startTs     model.Ts
+	initialLoad bool
+	changefeed  model.ChangeFeedID
+	cancel      context.CancelFunc
+	wg          *errgroup.Group
+
+	// plr is set when the node is started, and it's read by the processor
+	// for diagnosis, so it's protected by plrMu.
+	plrMu sync.RWMutex
+	plr   puller.Puller
 }
 
 func newPullerNode(
 	tableID model.TableID,
 	startTs model.Ts,
+	initialLoad bool,
 	tableName string,
 	changefeed model.ChangeFeedID,
 ) *pullerNode {
 	return &pullerNode{
-		tableID:    tableID,
-		startTs:    startTs,
-		tableName:  tableName,
-		changefeed: changefeed,
+		tableID:     tableID,
+		startTs:     startTs,
+		initialLoad: initialLoad,
+		tableName:   tableName,
+		changefeed:  changefeed,
 	}
 }
 
@@ -78,12 +87,16 @@ func (n *pullerNode) start(ctx pipeline.NodeContext,
 		up.KVStorage,
 		up.PDClock,
 		n.startTs,
+		n.initialLoad,
 		n.tableSpan(),
 		kvCfg,
 		n.changefeed,
 		n.tableID,
 		n.tableName,
 	)
+	n.plrMu.Lock()
+	n.plr = plr
+	n.plrMu.Unlock()
 	n.wg.Go(func() error {
 		ctx.Throw(errors.Trace(plr.Run(ctxC)))
 		return nil
@@ -105,3 +118,36 @@ func (n *pullerNode) start(ctx pipeline.NodeContext,
 	n.cancel = cancel
 	return nil
 }
+
+// getPuller returns the puller of the node, or nil if the node is not started.
+func (n *pullerNode) getPuller() puller.Puller {
+	n.plrMu.RLock()
+	defer n.plrMu.RUnlock()
+	return n.plr
+}
+
+// resolvedTs returns the resolved ts of the puller, or 0 if the puller is not
+// started.
+func (n *pullerNode) resolvedTs() model.Ts {
+	plr := n.getPuller()
+	if plr == nil {
+		return 0
+	}
+	return plr.GetResolvedTs()
+}
+
+func (n *pullerNode) slowestRegions(limit int) []model.RegionDiagnosis {
+	plr := n.getPuller()
+	if plr == nil {
+		return nil
+	}
+	return plr.SlowestRegions(limit)
+}
+
+func (n *pullerNode) loadProgress() *model.TableLoadProgress {
+	plr := n.getPuller()
+	if plr == nil {
+		return nil
+	}
+	return plr.LoadProgress()
+}
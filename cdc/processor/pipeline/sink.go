@@ -28,6 +28,8 @@ import (
 	sinkv2 "github.com/pingcap/tiflow/cdc/sinkv2/tablesink"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/ratelimit"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -48,10 +50,21 @@ type sinkNode struct {
 	flowController tableFlowController
 	redoManager    redo.LogManager
 
+	// limiter throttles the flushes of the table sink, the sizes of emitted
+	// transactions are kept in pendingTxns until they are admitted.
+	limiter     *ratelimit.Limiter
+	pendingTxns []pendingTxn
+
 	enableOldValue bool
 	splitTxn       bool
 }
 
+type pendingTxn struct {
+	commitTs model.Ts
+	rows     int
+	bytes    int
+}
+
 func newSinkNode(
 	tableID model.TableID,
 	sinkV1 sinkv1.Sink,
@@ -59,6 +72,7 @@ func newSinkNode(
 	startTs model.Ts, targetTs model.Ts,
 	flowController tableFlowController,
 	redoManager redo.LogManager,
+	limiter *ratelimit.Limiter,
 	state *TableState,
 	changefeed model.ChangeFeedID,
 	enableOldValue bool,
@@ -74,6 +88,7 @@ func newSinkNode(
 		changefeed:     changefeed,
 		flowController: flowController,
 		redoManager:    redoManager,
+		limiter:        limiter,
 		enableOldValue: enableOldValue,
 		splitTxn:       splitTxn,
 	}
@@ -85,7 +100,6 @@ func newSinkNode(
 func (n *sinkNode) CheckpointTs() model.Ts { return n.getCheckpointTs().ResolvedMark() }
 
 // BarrierTs returns the latest barrierTs.
-// Only for test.
 func (n *sinkNode) BarrierTs() model.Ts { return atomic.LoadUint64(&n.barrierTs) }
 
 func (n *sinkNode) State() TableState { return n.state.Load() }
@@ -175,6 +189,8 @@ func (n *sinkNode) flushSink(ctx context.Context, resolved model.ResolvedTs) (er
 		}
 	}
 
+	resolved = n.throttle(resolved)
+
 	currentCheckpointTs := n.getCheckpointTs()
 	if currentCheckpointTs.EqualOrGreater(resolved) {
 		return nil
@@ -198,6 +214,7 @@ func (n *sinkNode) flushSink(ctx context.Context, resolved model.ResolvedTs) (er
 	// FlushRowChangedEvents to prevent deadlock cause by checkpointTs
 	// fall back
 	n.flowController.Release(checkpoint)
+	tracing.FinishTxns(n.changefeed, n.tableID, checkpoint.ResolvedMark())
 
 	// the checkpointTs may fall back in some situation such as:
 	//   1. This table is newly added to the processor
@@ -211,6 +228,35 @@ func (n *sinkNode) flushSink(ctx context.Context, resolved model.ResolvedTs) (er
 	return nil
 }
 
+// throttle returns the resolved ts the table sink can be flushed to under the
+// rate limit. Transactions are admitted one by one in the order of commit ts,
+// and the resolved ts is held before the first one not admitted, which is
+// retried on the next flush. So the checkpoint ts still advances in the order
+// of transactions, and tables without pending rows are never held back.
+func (n *sinkNode) throttle(resolved model.ResolvedTs) model.ResolvedTs {
+	for len(n.pendingTxns) > 0 && n.pendingTxns[0].commitTs <= resolved.Ts {
+		txn := n.pendingTxns[0]
+		if !n.limiter.Admit(txn.rows, txn.bytes) {
+			return model.NewResolvedTs(txn.commitTs - 1)
+		}
+		n.pendingTxns = n.pendingTxns[1:]
+	}
+	return resolved
+}
+
+// trackPendingTxns records the sizes of rows not flushed yet.
+func (n *sinkNode) trackPendingTxns(rows ...*model.RowChangedEvent) {
+	for _, row := range rows {
+		last := len(n.pendingTxns) - 1
+		if last < 0 || n.pendingTxns[last].commitTs != row.CommitTs {
+			n.pendingTxns = append(n.pendingTxns, pendingTxn{commitTs: row.CommitTs})
+			last++
+		}
+		n.pendingTxns[last].rows++
+		n.pendingTxns[last].bytes += row.ApproximateBytes()
+	}
+}
+
 // emitRowToSink checks event and emits event.Row to sink.
 func (n *sinkNode) emitRowToSink(ctx context.Context, event *model.PolymorphicEvent) error {
 	failpoint.Inject("ProcessorSyncResolvedPreEmit", func() {
@@ -226,6 +272,9 @@ func (n *sinkNode) emitRowToSink(ctx context.Context, event *model.PolymorphicEv
 				return err
 			}
 		}
+		if n.limiter != nil {
+			n.trackPendingTxns(rows...)
+		}
 		if n.sinkV1 != nil {
 			return n.sinkV1.EmitRowChangedEvents(ctx, rows...)
 		}
@@ -242,6 +291,8 @@ func (n *sinkNode) emitRowToSink(ctx context.Context, event *model.PolymorphicEv
 		return nil
 	}
 
+	tracing.RecordStage(n.changefeed, n.tableID, event.CRTs, tracing.StagePipeline)
+
 	colLen := len(event.Row.Columns)
 	preColLen := len(event.Row.PreColumns)
 	// Some transactions could generate empty row change event, such as
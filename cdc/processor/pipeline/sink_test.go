@@ -23,8 +23,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	mocksink "github.com/pingcap/tiflow/cdc/sink/mock"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/ratelimit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -58,7 +60,7 @@ func TestState(t *testing.T) {
 	// test stop at targetTs
 	targetTs := model.Ts(10)
 	node := newSinkNode(1, mocksink.NewNormalMockSink(), nil,
-		0, targetTs, &mockFlowController{}, redo.NewDisabledManager(),
+		0, targetTs, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test-status"), true, true)
 	require.Equal(t, TableStatePrepared, node.State())
 
@@ -128,7 +130,7 @@ func TestState(t *testing.T) {
 	// test the stop at ts command
 	state = TableStatePrepared
 	node = newSinkNode(1, mocksink.NewNormalMockSink(), nil,
-		0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+		0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test-status"), true, false)
 	require.Equal(t, TableStatePrepared, node.State())
 
@@ -168,7 +170,7 @@ func TestState(t *testing.T) {
 	// test the stop at ts command is after then resolvedTs and checkpointTs is greater than stop ts
 	state = TableStatePrepared
 	node = newSinkNode(1, mocksink.NewNormalMockSink(), nil,
-		0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+		0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test-status"), true, false)
 	require.Equal(t, TableStatePrepared, node.State())
 
@@ -215,7 +217,7 @@ func TestStopStatus(t *testing.T) {
 	node := newSinkNode(1,
 		mocksink.NewMockCloseControlSink(closeCh),
 		nil, 0, 100,
-		&mockFlowController{}, redo.NewDisabledManager(), &state,
+		&mockFlowController{}, redo.NewDisabledManager(), nil, &state,
 		model.DefaultChangeFeedID("changefeed-id-test-state"), true, false)
 	require.Equal(t, TableStatePrepared, node.State())
 
@@ -252,7 +254,7 @@ func TestManyTs(t *testing.T) {
 	defer cancel()
 	state := TableStatePrepared
 	sink := mocksink.NewNormalMockSink()
-	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), true, false)
 	require.Equal(t, TableStatePrepared, node.State())
 
@@ -411,7 +413,7 @@ func TestIgnoreEmptyRowChangeEvent(t *testing.T) {
 	defer cancel()
 	state := TableStatePreparing
 	sink := mocksink.NewNormalMockSink()
-	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), true, false)
 
 	// empty row, no Columns and PreColumns.
@@ -430,7 +432,7 @@ func TestSplitUpdateEventWhenEnableOldValue(t *testing.T) {
 	defer cancel()
 	state := TableStatePreparing
 	sink := mocksink.NewNormalMockSink()
-	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), true, false)
 
 	// nil row.
@@ -485,7 +487,7 @@ func TestSplitUpdateEventWhenDisableOldValue(t *testing.T) {
 	state := TableStatePreparing
 	sink := mocksink.NewNormalMockSink()
 	enableOldValue := false
-	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), enableOldValue, false)
 
 	// nil row.
@@ -617,7 +619,7 @@ func TestFlushSinkReleaseFlowController(t *testing.T) {
 	flowController := &flushFlowController{}
 	sink := mocksink.NewMockFlushSink()
 	// sNode is a sinkNode
-	sNode := newSinkNode(1, sink, nil, 0, 10, flowController, redo.NewDisabledManager(),
+	sNode := newSinkNode(1, sink, nil, 0, 10, flowController, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), true, false)
 	sNode.barrierTs = 10
 
@@ -639,7 +641,7 @@ func TestSplitTxn(t *testing.T) {
 	flowController := &flushFlowController{}
 	sink := mocksink.NewMockFlushSink()
 	// sNode is a sinkNode
-	sNode := newSinkNode(1, sink, nil, 0, 10, flowController, redo.NewDisabledManager(),
+	sNode := newSinkNode(1, sink, nil, 0, 10, flowController, redo.NewDisabledManager(), nil,
 		&state, model.DefaultChangeFeedID("changefeed-id-test"), true, false)
 	msg := pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
 		CRTs:  1,
@@ -678,3 +680,53 @@ func TestSplitTxn(t *testing.T) {
 	_, err = sNode.HandleMessage(ctx, msg)
 	require.Regexp(t, ".*batch mode resolved ts is not supported.*", err)
 }
+
+func TestThrottleFlush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeedID := model.DefaultChangeFeedID("changefeed-id-test")
+	limiter, err := ratelimit.NewLimiter(changefeedID, &config.RateLimitConfig{
+		RowsPerSecond: 1,
+	})
+	require.Nil(t, err)
+	defer limiter.Close()
+
+	state := TableStatePrepared
+	sink := mocksink.NewNormalMockSink()
+	node := newSinkNode(1, sink, nil, 0, 10, &mockFlowController{}, redo.NewDisabledManager(),
+		limiter, &state, changefeedID, true, false)
+	node.barrierTs = 10
+
+	for _, commitTs := range []model.Ts{2, 2, 3} {
+		msg := pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
+			CRTs: commitTs, RawKV: &model.RawKVEntry{OpType: model.OpTypePut},
+			Row: &model.RowChangedEvent{
+				CommitTs: commitTs,
+				Columns:  []*model.Column{{Name: "col1", Value: "col1-value"}},
+			},
+		})
+		ok, err := node.HandleMessage(ctx, msg)
+		require.Nil(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, []pendingTxn{
+		{commitTs: 2, rows: 2, bytes: node.pendingTxns[0].bytes},
+		{commitTs: 3, rows: 1, bytes: node.pendingTxns[1].bytes},
+	}, node.pendingTxns)
+
+	// The first transaction is admitted, and the second one is held back
+	// since the rows of the first one exceed the limit.
+	msg := pmessage.PolymorphicEventMessage(model.NewResolvedPolymorphicEvent(0, 5))
+	ok, err := node.HandleMessage(ctx, msg)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, model.NewResolvedTs(2), node.getCheckpointTs())
+	require.Equal(t, model.NewResolvedTs(5), node.getResolvedTs())
+	require.Len(t, node.pendingTxns, 1)
+
+	// The resolved ts is not held back if there is no pending transaction
+	// before it.
+	require.Equal(t, model.NewResolvedTs(2), node.throttle(model.NewResolvedTs(2)))
+	node.pendingTxns = nil
+	require.Equal(t, model.NewResolvedTs(5), node.throttle(model.NewResolvedTs(5)))
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
@@ -253,6 +254,7 @@ func (n *sorterNode) start(
 
 				if msg.RawKV.OpType != model.OpTypeResolved {
 					atomic.AddInt64(&n.remainEvents, -1)
+					tracing.RecordStage(n.changefeed, n.tableID, msg.CRTs, tracing.StageSorter)
 					ignored, err := n.mounter.DecodeEvent(ctx, msg)
 					if err != nil {
 						log.Error("got an error from mounter, sorter will stop.",
@@ -110,6 +110,14 @@ type TablePipeline interface {
 
 	// RemainEvents return the amount of kv events remain in sorter.
 	RemainEvents() int64
+
+	// Diagnose returns the progress of each stage of this table pipeline and
+	// at most regionLimit regions with the smallest resolved ts.
+	Diagnose(regionLimit int) model.TableDiagnosis
+
+	// LoadProgress returns the snapshot load progress of the table, it
+	// returns nil if the table does not load the snapshot.
+	LoadProgress() *model.TableLoadProgress
 }
 
 // TODO find a better name or avoid using an interface
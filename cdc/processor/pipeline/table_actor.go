@@ -33,6 +33,7 @@ import (
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/ratelimit"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -61,6 +62,7 @@ type tableActor struct {
 	tableSinkV1 sinkv1.Sink
 	tableSinkV2 sinkv2.TableSink
 	redoManager redo.LogManager
+	limiter     *ratelimit.Limiter
 
 	state TableState
 
@@ -108,6 +110,7 @@ func NewTableActor(cdcCtx cdcContext.Context,
 	sinkV1 sinkv1.Sink,
 	sinkV2 sinkv2.TableSink,
 	redoManager redo.LogManager,
+	limiter *ratelimit.Limiter,
 	targetTs model.Ts,
 ) (TablePipeline, error) {
 	config := cdcCtx.ChangefeedVars().Info.Config
@@ -139,6 +142,7 @@ func NewTableActor(cdcCtx cdcContext.Context,
 		tableSinkV1:   sinkV1,
 		tableSinkV2:   sinkV2,
 		redoManager:   redoManager,
+		limiter:       limiter,
 		targetTs:      targetTs,
 		started:       false,
 
@@ -298,7 +302,21 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		return err
 	}
 
-	pullerNode := newPullerNode(t.tableID, t.replicaInfo.StartTs, t.tableName, t.changefeedVars.ID)
+	// Only tables that start replicating from the start ts of the changefeed,
+	// or from the start ts of a table set change, load the snapshot. If the
+	// changefeed restarts before the snapshot is written to downstream, tables
+	// start from the same ts again and reload the snapshot. Loaded rows are
+	// committed at the start ts, which is always before the replicating ts of
+	// the table, so MySQL sinks write them with REPLACE even if safe mode is
+	// disabled. MQ sinks may deliver reloaded rows more than once.
+	initialLoad := t.replicaConfig.InitialLoad &&
+		t.replicaInfo.StartTs == t.changefeedVars.Info.StartTs
+	if change := t.changefeedVars.Info.TableSetChange; change != nil &&
+		change.InitialLoad && t.replicaInfo.StartTs == change.StartTs {
+		initialLoad = true
+	}
+	pullerNode := newPullerNode(t.tableID, t.replicaInfo.StartTs, initialLoad,
+		t.tableName, t.changefeedVars.ID)
 	pullerActorNodeContext := newContext(sdtTableContext,
 		t.tableName,
 		t.globalVars.TableActorSystem.Router(),
@@ -319,7 +337,7 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		t.tableID,
 		t.tableSinkV1,
 		t.tableSinkV2,
-		t.replicaInfo.StartTs, t.targetTs, flowController, t.redoManager,
+		t.replicaInfo.StartTs, t.targetTs, flowController, t.redoManager, t.limiter,
 		&t.state, t.changefeedID, t.replicaConfig.EnableOldValue, splitTxn,
 	)
 	t.sinkNode = actorSinkNode
@@ -492,6 +510,38 @@ func (t *tableActor) RemainEvents() int64 {
 	return t.sortNode.remainEvent()
 }
 
+// Diagnose implements TablePipeline.Diagnose.
+func (t *tableActor) Diagnose(regionLimit int) model.TableDiagnosis {
+	d := model.TableDiagnosis{
+		TableID:          t.tableID,
+		TableName:        t.tableName,
+		PullerResolvedTs: t.pullerNode.resolvedTs(),
+		SorterResolvedTs: t.sortNode.ResolvedTs(),
+		SinkResolvedTs:   t.sinkNode.getResolvedTs().Ts,
+		BarrierTs:        t.sinkNode.BarrierTs(),
+		CheckpointTs:     t.sinkNode.CheckpointTs(),
+		SlowestRegions:   t.pullerNode.slowestRegions(regionLimit),
+	}
+	d.BlockingStage = model.DiagnoseStage(
+		d.PullerResolvedTs, d.SinkResolvedTs, d.BarrierTs, d.CheckpointTs)
+	return d
+}
+
+// LoadProgress implements TablePipeline.LoadProgress.
+func (t *tableActor) LoadProgress() *model.TableLoadProgress {
+	progress := t.pullerNode.loadProgress()
+	if progress == nil {
+		return nil
+	}
+	// Loaded rows are committed at the start ts, they are written to
+	// downstream once the checkpoint ts of the table passes the start ts.
+	if progress.State == model.TableLoadStateFinished &&
+		t.sinkNode.CheckpointTs() <= t.replicaInfo.StartTs {
+		progress.State = model.TableLoadStateLoading
+	}
+	return progress
+}
+
 // for ut
 var startPuller = func(t *tableActor, ctx *actorNodeContext) error {
 	return t.pullerNode.start(ctx, t.upstream, t.wg, t.sortNode)
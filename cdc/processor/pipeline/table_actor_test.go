@@ -56,7 +56,7 @@ func TestAsyncStopFailed(t *testing.T) {
 		upstream:    upstream.NewUpstream4Test(&mockPD{}),
 	}
 	tbl.sinkNode = newSinkNode(1, mocksink.NewNormalMockSink(), nil,
-		0, 0, &mockFlowController{}, tbl.redoManager,
+		0, 0, &mockFlowController{}, tbl.redoManager, nil,
 		&tbl.state, model.DefaultChangeFeedID("changefeed-test"), true, false)
 	require.True(t, tbl.AsyncStop())
 
@@ -398,7 +398,7 @@ func TestNewTableActor(t *testing.T) {
 	tbl, err := NewTableActor(cctx, upstream.NewUpstream4Test(&mockPD{}), nil, 1, "t1",
 		&model.TableReplicaInfo{
 			StartTs: 0,
-		}, mocksink.NewNormalMockSink(), nil, redo.NewDisabledManager(), 10)
+		}, mocksink.NewNormalMockSink(), nil, redo.NewDisabledManager(), nil, 10)
 	require.NotNil(t, tbl)
 	require.Nil(t, err)
 	require.Equal(t, TableStatePreparing, tbl.State())
@@ -414,7 +414,7 @@ func TestNewTableActor(t *testing.T) {
 	tbl, err = NewTableActor(cctx, upstream.NewUpstream4Test(&mockPD{}), nil, 1, "t1",
 		&model.TableReplicaInfo{
 			StartTs: 0,
-		}, mocksink.NewNormalMockSink(), nil, redo.NewDisabledManager(), 10)
+		}, mocksink.NewNormalMockSink(), nil, redo.NewDisabledManager(), nil, 10)
 	require.Nil(t, tbl)
 	require.NotNil(t, err)
 
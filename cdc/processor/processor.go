@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -36,11 +37,14 @@ import (
 	sinkv1 "github.com/pingcap/tiflow/cdc/sink"
 	sinkmetric "github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/factory"
+	"github.com/pingcap/tiflow/pkg/bdr"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/ratelimit"
+	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -52,6 +56,10 @@ import (
 const (
 	backoffBaseDelayInMs = 5
 	maxTries             = 3
+
+	// initialLoadReportInterval is the min interval to report loaded rows of
+	// tables to the changefeed status.
+	initialLoadReportInterval = 10 * time.Second
 )
 
 type processor struct {
@@ -65,12 +73,15 @@ type processor struct {
 
 	schemaStorage entry.SchemaStorage
 	lastSchemaTs  model.Ts
+	ddlJobPuller  puller.DDLJobPuller
 
-	filter        filter.Filter
+	filter        *filter.UnionFilter
+	markTracker   *bdr.MarkTracker
 	mounter       entry.Mounter
 	sinkV1        sinkv1.Sink
 	sinkV2Factory *factory.SinkFactory
 	redoManager   redo.LogManager
+	limiter       *ratelimit.Limiter
 
 	initialized bool
 	errCh       chan error
@@ -86,6 +97,17 @@ type processor struct {
 	checkpointTs model.Ts
 	resolvedTs   model.Ts
 
+	// initialLoadProgress is the snapshot load progress of tables which has
+	// been reported to the changefeed status.
+	initialLoadProgress   map[model.TableID]model.TableLoadProgress
+	lastInitialLoadReport time.Time
+	// tableSetChangeTs is the start ts of the last table set change
+	// handled by the processor.
+	tableSetChangeTs model.Ts
+	// tableSetChangeFilter is the filter of the last table set change, it's
+	// nil after the processor has replaced its filters with it.
+	tableSetChangeFilter filter.Filter
+
 	metricResolvedTsGauge           prometheus.Gauge
 	metricResolvedTsLagGauge        prometheus.Gauge
 	metricMinResolvedTableIDGauge   prometheus.Gauge
@@ -562,12 +584,19 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 	if err := p.lazyInit(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	if err := p.handleTableSetChange(ctx); err != nil {
+		return errors.Trace(err)
+	}
 	// it is no need to check the error here, because we will use
 	// local time when an error return, which is acceptable
 	pdTime, _ := p.upstream.PDClock.CurrentTime()
 
 	p.handlePosition(oracle.GetPhysical(pdTime))
+	// Every processor of the changefeed owns a task position, the rate
+	// limits are shared by them.
+	p.limiter.SetProcessorCount(len(p.changefeed.TaskPositions))
 	p.pushResolvedTs2Table()
+	p.handleInitialLoadProgress()
 
 	p.doGCSchemaStorage()
 
@@ -582,6 +611,54 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 	return nil
 }
 
+// handleInitialLoadProgress reports the snapshot load progress of tables to
+// the changefeed status. A table is reported once its load state changes, and
+// the loaded rows are reported at most once per initialLoadReportInterval.
+func (p *processor) handleInitialLoadProgress() {
+	change := p.changefeed.Info.TableSetChange
+	if !p.changefeed.Info.Config.InitialLoad && (change == nil || !change.InitialLoad) {
+		return
+	}
+	if p.initialLoadProgress == nil {
+		p.initialLoadProgress = make(map[model.TableID]model.TableLoadProgress)
+	}
+	now := time.Now()
+	reportRows := now.Sub(p.lastInitialLoadReport) >= initialLoadReportInterval
+	if reportRows {
+		p.lastInitialLoadReport = now
+	}
+	changed := make(map[model.TableID]*model.TableLoadProgress)
+	for tableID, table := range p.tables {
+		progress := table.LoadProgress()
+		if progress == nil {
+			continue
+		}
+		last, ok := p.initialLoadProgress[tableID]
+		if ok && last.State == progress.State &&
+			(!reportRows || last.LoadedRows == progress.LoadedRows) {
+			continue
+		}
+		changed[tableID] = progress
+		p.initialLoadProgress[tableID] = *progress
+	}
+	if len(changed) == 0 {
+		return
+	}
+	p.changefeed.PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil {
+				return nil, false, nil
+			}
+			if status.InitialLoad == nil {
+				status.InitialLoad = make(map[model.TableID]*model.TableLoadProgress)
+			}
+			for tableID, progress := range changed {
+				status.InitialLoad[tableID] = progress
+			}
+			return status, true, nil
+		})
+}
+
 // checkChangefeedNormal checks if the changefeed is runnable.
 func (p *processor) checkChangefeedNormal() bool {
 	// check the state in this tick, make sure that the admin job type of the changefeed is not stopped
@@ -643,12 +720,12 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 		}
 	}()
 
-	var err error
-	p.filter, err = filter.NewFilter(p.changefeed.Info.Config,
+	f, err := filter.NewFilter(p.changefeed.Info.Config,
 		util.GetTimeZoneName(contextutil.TimezoneFromCtx(ctx)))
 	if err != nil {
 		return errors.Trace(err)
 	}
+	p.filter = filter.NewUnionFilter(f)
 
 	p.schemaStorage, err = p.createAndDriveSchemaStorage(ctx)
 	if err != nil {
@@ -658,10 +735,23 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	stdCtx := contextutil.PutChangefeedIDInCtx(ctx, p.changefeedID)
 	stdCtx = contextutil.PutRoleInCtx(stdCtx, util.RoleProcessor)
 
+	if p.changefeed.Info.Config.BDREnabled() {
+		p.markTracker, err = p.createAndDriveMarkTracker(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	computer, err := entry.NewColumnComputer(p.changefeed.Info.Config,
+		util.GetTimeZoneName(contextutil.TimezoneFromCtx(ctx)))
+	if err != nil {
+		return errors.Trace(err)
+	}
 	p.mounter = entry.NewMounter(p.schemaStorage,
 		p.changefeedID,
 		contextutil.TimezoneFromCtx(ctx),
 		p.filter,
+		computer,
+		p.markTracker,
 		p.changefeed.Info.Config.EnableOldValue,
 	)
 
@@ -704,6 +794,11 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 		zap.String("namespace", p.changefeedID.Namespace),
 		zap.String("changefeed", p.changefeedID.ID))
 
+	p.limiter, err = ratelimit.NewLimiter(p.changefeedID, p.changefeed.Info.Config.RateLimit)
+	if err != nil {
+		return err
+	}
+
 	p.agent, err = p.newAgent(ctx, p.liveness)
 	if err != nil {
 		return err
@@ -791,6 +886,15 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// DDL jobs after the checkpoint ts are kept by the DDL puller, so they
+	// can be handled again if a table set change is received late.
+	ddlPuller.DoGC(checkpointTs)
+	// The pending table set change is handled before the DDL puller runs,
+	// so DDL jobs after the start ts of it are handled only once.
+	p.ddlJobPuller = ddlPuller
+	if err := p.handleTableSetChange(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
@@ -807,6 +911,16 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 			case jobEntry = <-ddlPuller.Output():
 			}
 			failpoint.Inject("processorDDLResolved", nil)
+			if jobEntry.ReloadSchema {
+				meta, err := kv.GetSnapshotMeta(kvStorage, jobEntry.CRTs)
+				if err == nil {
+					err = schemaStorage.ReloadSnapshot(meta, jobEntry.CRTs)
+				}
+				if err != nil {
+					p.sendError(errors.Trace(err))
+					return
+				}
+			}
 			if jobEntry.OpType == model.OpTypeResolved {
 				schemaStorage.AdvanceResolvedTs(jobEntry.CRTs)
 			}
@@ -827,6 +941,128 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 	return schemaStorage, nil
 }
 
+// createAndDriveMarkTracker creates a puller of the mark tables of the filter
+// replica IDs, which feeds the returned tracker. It's only called in bdr mode.
+func (p *processor) createAndDriveMarkTracker(ctx cdcContext.Context) (*bdr.MarkTracker, error) {
+	checkpointTs := p.changefeed.Info.GetCheckpointTs(p.changefeed.Status)
+	snap, err := p.schemaStorage.GetSnapshot(ctx, checkpointTs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	replicaIDs := p.changefeed.Info.Config.BDR.FilterReplicaIDs
+	spans := make([]regionspan.Span, 0, len(replicaIDs))
+	for _, replicaID := range replicaIDs {
+		tableName := bdr.MarkTableName(replicaID)
+		tableID, ok := snap.TableIDByName(bdr.MarkSchema, tableName)
+		if !ok {
+			return nil, cerror.ErrBDRMarkTableNotFound.GenWithStackByArgs(
+				bdr.MarkSchema, tableName)
+		}
+		spans = append(spans, regionspan.GetTableSpan(tableID))
+	}
+
+	kvCfg := config.GetGlobalServerConfig().KVClient
+	stdCtx := contextutil.PutTableInfoInCtx(ctx, -1, bdr.MarkPullerTableName)
+	stdCtx = contextutil.PutChangefeedIDInCtx(stdCtx, p.changefeedID)
+	stdCtx = contextutil.PutRoleInCtx(stdCtx, util.RoleProcessor)
+	markPuller := puller.New(
+		stdCtx,
+		p.upstream.PDClient,
+		p.upstream.GrpcPool,
+		p.upstream.RegionCache,
+		p.upstream.KVStorage,
+		p.upstream.PDClock,
+		checkpointTs,
+		false,
+		spans,
+		kvCfg,
+		p.changefeedID,
+		-1,
+		bdr.MarkPullerTableName,
+	)
+	tracker := bdr.NewMarkTracker(p.changefeedID, checkpointTs)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.sendError(markPuller.Run(stdCtx))
+	}()
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case raw := <-markPuller.Output():
+				tracker.AddEntry(raw)
+			}
+		}
+	}()
+	log.Info("processor creates mark tracker",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Uint64s("filterReplicaIDs", replicaIDs))
+	return tracker, nil
+}
+
+// handleTableSetChange extends the filter of the processor with the rules of
+// the pending table set change. The schema of tables added by the change is
+// reloaded at the start ts of the change, so these tables can be replicated
+// once the owner applies the change. After the change is applied, the filters
+// are replaced with the one of the change, so tables removed by the change
+// are not replicated anymore.
+func (p *processor) handleTableSetChange(ctx cdcContext.Context) error {
+	change := p.changefeed.Info.TableSetChange
+	// ddlJobPuller is nil only in test.
+	if change == nil || p.ddlJobPuller == nil {
+		return nil
+	}
+	if change.Applied {
+		if p.tableSetChangeFilter == nil || change.StartTs != p.tableSetChangeTs {
+			return nil
+		}
+		p.filter.Replace(p.tableSetChangeFilter)
+		p.ddlJobPuller.ResetFilter(p.tableSetChangeFilter)
+		p.tableSetChangeFilter = nil
+		log.Info("processor replaces filter with the applied table set change",
+			zap.String("capture", p.captureInfo.ID),
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.Strings("rules", change.Rules),
+			zap.Uint64("startTs", change.StartTs))
+		return nil
+	}
+	if change.StartTs == p.tableSetChangeTs {
+		return nil
+	}
+	tz := util.GetTimeZoneName(contextutil.TimezoneFromCtx(ctx))
+	// Tables matched by the current rules are still replicated until the
+	// change is applied.
+	current, err := filter.NewFilter(p.changefeed.Info.Config, tz)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg := p.changefeed.Info.Config.Clone()
+	cfg.Filter.Rules = change.Rules
+	f, err := filter.NewFilter(cfg, tz)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.ddlJobPuller.ExtendFilter(change.StartTs, f); err != nil {
+		return errors.Trace(err)
+	}
+	p.filter.Replace(current, f)
+	p.tableSetChangeTs = change.StartTs
+	p.tableSetChangeFilter = f
+	log.Info("processor handles table set change",
+		zap.String("capture", p.captureInfo.ID),
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Strings("rules", change.Rules),
+		zap.Uint64("startTs", change.StartTs))
+	return nil
+}
+
 func (p *processor) sendError(err error) {
 	if err == nil {
 		return
@@ -953,6 +1189,7 @@ func (p *processor) createTablePipelineImpl(
 			s,
 			nil,
 			p.redoManager,
+			p.limiter,
 			p.changefeed.Info.GetTargetTs())
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -970,6 +1207,7 @@ func (p *processor) createTablePipelineImpl(
 			nil,
 			s,
 			p.redoManager,
+			p.limiter,
 			p.changefeed.Info.GetTargetTs())
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -1003,6 +1241,12 @@ func (p *processor) doGCSchemaStorage() {
 	// Please refer to `unmarshalAndMountRowChanged` in cdc/entry/mounter.go
 	// for why we need -1.
 	lastSchemaTs := p.schemaStorage.DoGC(p.changefeed.Status.CheckpointTs - 1)
+	if p.ddlJobPuller != nil {
+		p.ddlJobPuller.DoGC(p.changefeed.Status.CheckpointTs)
+	}
+	if p.markTracker != nil {
+		p.markTracker.DoGC(p.changefeed.Status.CheckpointTs)
+	}
 	if p.lastSchemaTs == lastSchemaTs {
 		return
 	}
@@ -1111,6 +1355,10 @@ func (p *processor) cleanupMetrics() {
 	processorSchemaStorageGcTsGauge.DeleteLabelValues(p.changefeedID.Namespace, p.changefeedID.ID)
 	tableMemoryHistogram.DeleteLabelValues(p.changefeedID.Namespace, p.changefeedID.ID)
 	processorMemoryGauge.DeleteLabelValues(p.changefeedID.Namespace, p.changefeedID.ID)
+	if p.markTracker != nil {
+		p.markTracker.Close()
+	}
+	p.limiter.Close()
 
 	sinkmetric.TableSinkTotalRowsCountCounter.
 		DeleteLabelValues(p.changefeedID.Namespace, p.changefeedID.ID)
@@ -1124,3 +1372,18 @@ func (p *processor) WriteDebugInfo(w io.Writer) {
 			tableID, tablePipeline.Name(), tablePipeline.ResolvedTs(), tablePipeline.CheckpointTs(), tablePipeline.State())
 	}
 }
+
+// Diagnose returns the replication progress of all tables in the processor,
+// sorted by checkpoint ts in ascending order.
+func (p *processor) Diagnose(regionLimit int) []model.TableDiagnosis {
+	tables := make([]model.TableDiagnosis, 0, len(p.tables))
+	for _, table := range p.tables {
+		d := table.Diagnose(regionLimit)
+		d.CaptureID = p.captureInfo.ID
+		tables = append(tables, d)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].CheckpointTs < tables[j].CheckpointTs
+	})
+	return tables
+}
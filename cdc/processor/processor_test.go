@@ -140,6 +140,7 @@ type mockTablePipeline struct {
 	barrierTs    model.Ts
 	state        pipeline.TableState
 	canceled     bool
+	loadProgress *model.TableLoadProgress
 
 	sinkStartTs model.Ts
 }
@@ -176,6 +177,28 @@ func (m *mockTablePipeline) RemainEvents() int64 {
 	return 1
 }
 
+func (m *mockTablePipeline) Diagnose(regionLimit int) model.TableDiagnosis {
+	return model.TableDiagnosis{
+		TableID:          m.tableID,
+		TableName:        m.name,
+		PullerResolvedTs: m.resolvedTs,
+		SorterResolvedTs: m.resolvedTs,
+		SinkResolvedTs:   m.resolvedTs,
+		BarrierTs:        m.barrierTs,
+		CheckpointTs:     m.checkpointTs,
+		BlockingStage: model.DiagnoseStage(
+			m.resolvedTs, m.resolvedTs, m.barrierTs, m.checkpointTs),
+	}
+}
+
+func (m *mockTablePipeline) LoadProgress() *model.TableLoadProgress {
+	if m.loadProgress == nil {
+		return nil
+	}
+	progress := *m.loadProgress
+	return &progress
+}
+
 func (m *mockTablePipeline) State() pipeline.TableState {
 	if m.state == pipeline.TableStateStopped {
 		return m.state
@@ -856,3 +879,54 @@ func TestProcessorLiveness(t *testing.T) {
 	*p.agent.(*mockAgent).liveness = model.LivenessCaptureAlive
 	require.Equal(t, model.LivenessCaptureAlive, p.liveness.Load())
 }
+
+func TestInitialLoadProgress(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	liveness := model.LivenessCaptureAlive
+	p, tester := initProcessor4Test(ctx, t, &liveness)
+	p.changefeed.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		info.Config.InitialLoad = true
+		return info, true, nil
+	})
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 5
+		status.ResolvedTs = 10
+		return status, true, nil
+	})
+	p.schemaStorage.(*mockSchemaStorage).resolvedTs = 10
+	tester.MustApplyPatches()
+	// First tick for creating position.
+	err := p.Tick(ctx)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+
+	done, err := p.AddTable(ctx, model.TableID(1), 5, false)
+	require.True(t, done)
+	require.Nil(t, err)
+	tb := p.tables[model.TableID(1)].(*mockTablePipeline)
+	tb.loadProgress = &model.TableLoadProgress{
+		State: model.TableLoadStateLoading, LoadedRows: 10,
+	}
+	err = p.Tick(ctx)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, map[model.TableID]*model.TableLoadProgress{
+		1: {State: model.TableLoadStateLoading, LoadedRows: 10},
+	}, p.changefeed.Status.InitialLoad)
+
+	// Loaded rows are not reported until the report interval elapses.
+	tb.loadProgress.LoadedRows = 20
+	err = p.Tick(ctx)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(10), p.changefeed.Status.InitialLoad[1].LoadedRows)
+
+	// State changes are reported immediately.
+	tb.loadProgress.State = model.TableLoadStateFinished
+	err = p.Tick(ctx)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, map[model.TableID]*model.TableLoadProgress{
+		1: {State: model.TableLoadStateFinished, LoadedRows: 20},
+	}, p.changefeed.Status.InitialLoad)
+}
@@ -16,6 +16,7 @@ package puller
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -59,6 +60,25 @@ type DDLJobPuller interface {
 	Run(ctx context.Context) error
 	// Output the DDL job entry, it contains the DDL job and the error.
 	Output() <-chan *model.DDLJobEntry
+	// ExtendFilter extends the filter with f at ts. DDLs of tables matched by
+	// f are handled after ts, and the schema is reloaded at ts. If DDLs after
+	// ts have been handled, they are handled again with the extended filter.
+	ExtendFilter(ts uint64, f filter.Filter) error
+	// ResetFilter replaces the filter with f once the pending filter, if any,
+	// is added. It's called after a table set change is applied, so DDLs of
+	// removed tables are discarded.
+	ResetFilter(f filter.Filter)
+	// DoGC removes the DDL jobs committed before ts from the history, which
+	// is kept to handle DDLs again when the filter is extended. The history
+	// is kept only after DoGC is called, and the filter can't be extended at
+	// a ts less than the gc ts.
+	DoGC(ts uint64)
+}
+
+// pendingFilter is a filter to be added to the DDLJobPuller at ts.
+type pendingFilter struct {
+	ts     uint64
+	filter filter.Filter
 }
 
 // Note: All unexported methods of `ddlJobPullerImpl` should
@@ -71,6 +91,7 @@ type ddlJobPullerImpl struct {
 	resolvedTs     uint64
 	schemaVersion  int64
 	filter         filter.Filter
+	forceReplicate bool
 	// ddlJobsTable is initialized when receive the first concurrent DDL job.
 	// It holds the info of table `tidb_ddl_jobs` of upstream TiDB.
 	ddlJobsTable *model.TableInfo
@@ -78,6 +99,16 @@ type ddlJobPullerImpl struct {
 	jobMetaColumnID           int64
 	outputCh                  chan *model.DDLJobEntry
 	metricDiscardedDDLCounter prometheus.Counter
+
+	pendingFilterMu sync.Mutex
+	pendingFilter   *pendingFilter
+	resetFilter     filter.Filter
+
+	// history holds the raw kv entries of DDL jobs committed after gcTs,
+	// it's not kept if gcTs is 0.
+	historyMu sync.Mutex
+	history   []*model.RawKVEntry
+	gcTs      uint64
 }
 
 // Run starts the DDLJobPuller.
@@ -102,6 +133,10 @@ func (p *ddlJobPullerImpl) Run(ctx context.Context) error {
 					continue
 				}
 
+				if err := p.handlePendingFilter(ctx, ddlRawKV.CRTs); err != nil {
+					return errors.Trace(err)
+				}
+
 				if ddlRawKV.OpType == model.OpTypeResolved {
 					if ddlRawKV.CRTs > p.getResolvedTs() {
 						p.setResolvedTs(ddlRawKV.CRTs)
@@ -114,6 +149,7 @@ func (p *ddlJobPullerImpl) Run(ctx context.Context) error {
 				}
 
 				if job != nil {
+					p.appendHistory(ddlRawKV)
 					skip, err := p.handleJob(job)
 					if err != nil {
 						return errors.Trace(err)
@@ -144,6 +180,182 @@ func (p *ddlJobPullerImpl) Output() <-chan *model.DDLJobEntry {
 	return p.outputCh
 }
 
+// ExtendFilter extends the filter with f at ts.
+func (p *ddlJobPullerImpl) ExtendFilter(ts uint64, f filter.Filter) error {
+	p.historyMu.Lock()
+	gcTs := p.gcTs
+	p.historyMu.Unlock()
+	if ts < gcTs {
+		return cerror.ErrTableSetChangeMissed.GenWithStackByArgs(ts, gcTs)
+	}
+	p.pendingFilterMu.Lock()
+	defer p.pendingFilterMu.Unlock()
+	p.pendingFilter = &pendingFilter{ts: ts, filter: f}
+	return nil
+}
+
+// ResetFilter replaces the filter with f.
+func (p *ddlJobPullerImpl) ResetFilter(f filter.Filter) {
+	p.pendingFilterMu.Lock()
+	defer p.pendingFilterMu.Unlock()
+	p.resetFilter = f
+}
+
+// DoGC removes the DDL jobs committed before ts from the history.
+func (p *ddlJobPullerImpl) DoGC(ts uint64) {
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+	if ts <= p.gcTs {
+		return
+	}
+	p.gcTs = ts
+	i := sort.Search(len(p.history), func(i int) bool {
+		return p.history[i].CRTs >= ts
+	})
+	// copy the part of the slice that is needed instead of re-slicing it,
+	// so the removed entries can be released.
+	history := make([]*model.RawKVEntry, len(p.history)-i)
+	copy(history, p.history[i:])
+	p.history = history
+}
+
+func (p *ddlJobPullerImpl) appendHistory(rawKV *model.RawKVEntry) {
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+	if p.gcTs == 0 || rawKV.CRTs < p.gcTs {
+		return
+	}
+	p.history = append(p.history, rawKV)
+}
+
+// handlePendingFilter adds the pending filter and reloads the schema snapshot
+// once all DDLs committed before the ts of the pending filter are handled.
+// The reloaded schema is sent to the output so the schema storage can be
+// reloaded at the same ts. If the DDL puller has resolved beyond the ts, DDLs
+// committed after the ts are handled again with the extended filter.
+// The filter is replaced once the pending filter is added.
+func (p *ddlJobPullerImpl) handlePendingFilter(ctx context.Context, crts uint64) error {
+	p.pendingFilterMu.Lock()
+	pending := p.pendingFilter
+	if pending != nil && crts <= pending.ts {
+		p.pendingFilterMu.Unlock()
+		return nil
+	}
+	reset := p.resetFilter
+	p.pendingFilter = nil
+	p.resetFilter = nil
+	p.pendingFilterMu.Unlock()
+
+	if pending != nil {
+		if err := p.extendFilter(ctx, pending); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if reset != nil {
+		p.filter = reset
+		log.Info("ddl job puller resets filter",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID))
+	}
+	return nil
+}
+
+func (p *ddlJobPullerImpl) extendFilter(ctx context.Context, pending *pendingFilter) error {
+	resolvedTs := p.getResolvedTs()
+	// The schema of tables which are not matched by the old filter may be
+	// stale, as their DDLs are discarded, so we reload the whole snapshot.
+	// kvStorage is nil only in test.
+	if p.kvStorage != nil {
+		meta, err := kv.GetSnapshotMeta(p.kvStorage, pending.ts)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		snap, err := schema.NewSingleSnapshotFromMeta(meta, pending.ts, p.forceReplicate)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		version, err := schema.GetSchemaVersion(meta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		p.schemaSnapshot = snap
+		p.schemaVersion = version
+	}
+	p.filter = filter.NewUnionFilter(p.filter, pending.filter)
+	p.setResolvedTs(pending.ts)
+	log.Info("ddl job puller extends filter and reloads schema",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Uint64("ts", pending.ts),
+		zap.Uint64("resolvedTs", resolvedTs),
+		zap.Int64("schemaVersion", p.schemaVersion))
+
+	jobEntry := &model.DDLJobEntry{
+		OpType:       model.OpTypeResolved,
+		CRTs:         pending.ts,
+		ReloadSchema: true,
+	}
+	if err := p.output(ctx, jobEntry); err != nil {
+		return errors.Trace(err)
+	}
+	if resolvedTs <= pending.ts {
+		return nil
+	}
+
+	// DDLs committed after the ts have been handled with the old filter,
+	// they're handled again with the extended one.
+	p.historyMu.Lock()
+	history := make([]*model.RawKVEntry, 0)
+	for _, rawKV := range p.history {
+		if rawKV.CRTs > pending.ts {
+			history = append(history, rawKV)
+		}
+	}
+	p.historyMu.Unlock()
+	for _, rawKV := range history {
+		job, err := p.unmarshalDDL(rawKV)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		skip, err := p.handleJob(job)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if skip {
+			continue
+		}
+		if err := p.output(ctx, &model.DDLJobEntry{
+			Job:    job,
+			OpType: rawKV.OpType,
+			CRTs:   rawKV.CRTs,
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if resolvedTs > p.getResolvedTs() {
+		p.setResolvedTs(resolvedTs)
+	}
+	log.Info("ddl job puller handles ddl jobs again with extended filter",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Uint64("ts", pending.ts),
+		zap.Int("jobs", len(history)),
+		zap.Uint64("resolvedTs", resolvedTs))
+	return p.output(ctx, &model.DDLJobEntry{
+		OpType: model.OpTypeResolved,
+		CRTs:   resolvedTs,
+	})
+}
+
+func (p *ddlJobPullerImpl) output(ctx context.Context, jobEntry *model.DDLJobEntry) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case p.outputCh <- jobEntry:
+	}
+	return nil
+}
+
 func (p *ddlJobPullerImpl) getResolvedTs() uint64 {
 	return atomic.LoadUint64(&p.resolvedTs)
 }
@@ -469,6 +681,7 @@ func NewDDLJobPuller(
 	return &ddlJobPullerImpl{
 		changefeedID:   changefeed,
 		filter:         f,
+		forceReplicate: replicaConfig.ForceReplicate,
 		schemaSnapshot: schemaSnap,
 		puller: New(
 			ctx,
@@ -478,6 +691,7 @@ func NewDDLJobPuller(
 			kvStorage,
 			pdClock,
 			checkpointTs,
+			false,
 			regionspan.GetAllDDLSpan(),
 			cfg,
 			changefeed,
@@ -25,6 +25,7 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/kv"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tiflow/cdc/entry"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -85,24 +87,36 @@ func (m *mockPuller) IsInitialized() bool {
 	return true
 }
 
+func (m *mockPuller) SlowestRegions(limit int) []model.RegionDiagnosis {
+	return nil
+}
+
+func (m *mockPuller) LoadProgress() *model.TableLoadProgress {
+	return nil
+}
+
 func (m *mockPuller) append(e *model.RawKVEntry) {
 	m.inCh <- e
 }
 
 func (m *mockPuller) appendDDL(job *timodel.Job) {
+	m.append(newDDLRawKV(m.t, job))
+}
+
+func newDDLRawKV(t *testing.T, job *timodel.Job) *model.RawKVEntry {
 	b, err := json.Marshal(job)
-	require.Nil(m.t, err)
+	require.Nil(t, err)
 	ek := []byte("m")
 	ek = codec.EncodeBytes(ek, []byte("DDLJobList"))
 	ek = codec.EncodeUint(ek, uint64('l'))
 	ek = codec.EncodeInt(ek, 1)
-	m.append(&model.RawKVEntry{
+	return &model.RawKVEntry{
 		OpType:  model.OpTypePut,
 		Key:     ek,
 		Value:   b,
 		StartTs: job.StartTS,
 		CRTs:    job.BinlogInfo.FinishedTS,
-	})
+	}
 }
 
 func (m *mockPuller) appendResolvedTs(ts model.Ts) {
@@ -480,6 +494,139 @@ func TestHandleJob(t *testing.T) {
 	}
 }
 
+func TestExtendFilter(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test1.t1"}
+	f, err := filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+
+	for _, ddl := range []string{
+		"create database test1",
+		"create table test1.t1(id int)",
+		"create table test1.t2(id int)",
+	} {
+		_, err := ddlJobPullerImpl.handleJob(helper.DDL2Job(ddl))
+		require.NoError(t, err)
+	}
+	// DDLs of test1.t2 are discarded, its schema is stale.
+	job := helper.DDL2Job("alter table test1.t2 add column c1 int")
+	skip, err := ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+
+	ver, err := helper.Storage().CurrentVersion(tidbkv.GlobalTxnScope)
+	require.NoError(t, err)
+	ts := ver.Ver
+	cfg.Filter.Rules = []string{"test1.t2"}
+	f, err = filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	require.NoError(t, ddlJobPullerImpl.ExtendFilter(ts, f))
+
+	ctx := context.Background()
+	// Nothing happens before all DDLs committed before ts are handled.
+	require.NoError(t, ddlJobPullerImpl.handlePendingFilter(ctx, ts))
+	require.Len(t, ddlJobPullerImpl.Output(), 0)
+
+	require.NoError(t, ddlJobPullerImpl.handlePendingFilter(ctx, ts+1))
+	jobEntry := <-ddlJobPullerImpl.Output()
+	require.True(t, jobEntry.ReloadSchema)
+	require.Equal(t, ts, jobEntry.CRTs)
+	require.Equal(t, ts, ddlJobPullerImpl.getResolvedTs())
+	tableInfo, ok := ddlJobPullerImpl.schemaSnapshot.TableByName("test1", "t2")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 2)
+
+	// DDLs of both tables are handled after the filter is extended.
+	skip, err = ddlJobPullerImpl.handleJob(
+		helper.DDL2Job("alter table test1.t2 add column c2 int"))
+	require.NoError(t, err)
+	require.False(t, skip)
+	skip, err = ddlJobPullerImpl.handleJob(
+		helper.DDL2Job("alter table test1.t1 add column c1 int"))
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	// The filter is replaced after the table set change is applied.
+	ddlJobPullerImpl.ResetFilter(f)
+	require.NoError(t, ddlJobPullerImpl.handlePendingFilter(ctx, ts+2))
+	skip, err = ddlJobPullerImpl.handleJob(
+		helper.DDL2Job("alter table test1.t1 add column c2 int"))
+	require.NoError(t, err)
+	require.True(t, skip)
+}
+
+func TestExtendFilterAfterResolved(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test1.t1"}
+	f, err := filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+	ddlJobPullerImpl.DoGC(startTs)
+
+	for _, ddl := range []string{
+		"create database test1",
+		"create table test1.t1(id int)",
+		"create table test1.t2(id int)",
+	} {
+		_, err := ddlJobPullerImpl.handleJob(helper.DDL2Job(ddl))
+		require.NoError(t, err)
+	}
+	ver, err := helper.Storage().CurrentVersion(tidbkv.GlobalTxnScope)
+	require.NoError(t, err)
+	ts := ver.Ver
+
+	// The DDL of test1.t2 after ts is discarded before the filter is extended.
+	job := helper.DDL2Job("alter table test1.t2 add column c1 int")
+	ddlJobPullerImpl.appendHistory(newDDLRawKV(t, job))
+	skip, err := ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+	resolvedTs := ddlJobPullerImpl.getResolvedTs()
+	require.Greater(t, resolvedTs, ts)
+
+	cfg.Filter.Rules = []string{"test1.t2"}
+	f, err = filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	require.NoError(t, ddlJobPullerImpl.ExtendFilter(ts, f))
+
+	// The schema is reloaded at ts, and the DDL is handled again.
+	ctx := context.Background()
+	require.NoError(t, ddlJobPullerImpl.handlePendingFilter(ctx, resolvedTs+1))
+	jobEntry := <-ddlJobPullerImpl.Output()
+	require.True(t, jobEntry.ReloadSchema)
+	require.Equal(t, ts, jobEntry.CRTs)
+	jobEntry = <-ddlJobPullerImpl.Output()
+	require.Equal(t, model.OpTypePut, jobEntry.OpType)
+	require.Equal(t, job.ID, jobEntry.Job.ID)
+	jobEntry = <-ddlJobPullerImpl.Output()
+	require.Equal(t, model.OpTypeResolved, jobEntry.OpType)
+	require.Equal(t, resolvedTs, jobEntry.CRTs)
+	require.Equal(t, resolvedTs, ddlJobPullerImpl.getResolvedTs())
+	tableInfo, ok := ddlJobPullerImpl.schemaSnapshot.TableByName("test1", "t2")
+	require.True(t, ok)
+	require.Len(t, tableInfo.Columns, 2)
+
+	// The filter can not be extended at a ts before the gc ts, as DDLs after
+	// it are removed from the history.
+	ddlJobPullerImpl.DoGC(resolvedTs + 1)
+	require.Empty(t, ddlJobPullerImpl.history)
+	require.True(t, cerror.ErrTableSetChangeMissed.Equal(
+		ddlJobPullerImpl.ExtendFilter(ts, f)))
+}
+
 func waitResolvedTs(t *testing.T, p DDLJobPuller, targetTs model.Ts) {
 	err := retry.Do(context.Background(), func() error {
 		if p.(*ddlJobPullerImpl).getResolvedTs() < targetTs {
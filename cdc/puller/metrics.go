@@ -62,6 +62,13 @@ var (
 			Name:      "discarded_ddl_count",
 			Help:      "The total count of ddl job that are discarded in ddl puller.",
 		}, []string{"namespace", "changefeed"})
+	initialLoadRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "puller",
+			Name:      "initial_load_rows_count",
+			Help:      "The total count of rows loaded from the snapshot by puller.",
+		}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -72,4 +79,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(outputChanSizeHistogram)
 	registry.MustRegister(eventChanSizeHistogram)
 	registry.MustRegister(discardedDDLCounter)
+	registry.MustRegister(initialLoadRowsCounter)
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/regionspan"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/txnutil"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
@@ -48,6 +49,12 @@ type Puller interface {
 	GetResolvedTs() uint64
 	Output() <-chan *model.RawKVEntry
 	IsInitialized() bool
+	// SlowestRegions returns at most limit regions with the smallest
+	// resolved ts, it is used to diagnose a lagging table.
+	SlowestRegions(limit int) []model.RegionDiagnosis
+	// LoadProgress returns the snapshot load progress of the puller, it
+	// returns nil if the puller does not load the snapshot.
+	LoadProgress() *model.TableLoadProgress
 }
 
 type pullerImpl struct {
@@ -60,12 +67,23 @@ type pullerImpl struct {
 	resolvedTs   uint64
 	initialized  int64
 
+	// snapshotStorage is used to scan the snapshot of snapshotSpans at
+	// checkpointTs if initialLoad is true. Unlike spans, keys of snapshotSpans
+	// are not encoded.
+	snapshotStorage tidbkv.Storage
+	snapshotSpans   []regionspan.Span
+	initialLoad     bool
+	loadedRows      uint64
+	loaded          int64
+
 	changefeed model.ChangeFeedID
 	tableID    model.TableID
 	tableName  string
 }
 
 // New create a new Puller fetch event start from checkpointTs and put into buf.
+// If initialLoad is true, the puller outputs all rows in the snapshot of spans
+// at checkpointTs before any incremental events.
 func New(ctx context.Context,
 	pdCli pd.Client,
 	grpcPool kv.GrpcPool,
@@ -73,6 +91,7 @@ func New(ctx context.Context,
 	kvStorage tidbkv.Storage,
 	pdClock pdutil.Clock,
 	checkpointTs uint64,
+	initialLoad bool,
 	spans []regionspan.Span,
 	cfg *config.KVClientConfig,
 	changefeed model.ChangeFeedID,
@@ -94,17 +113,20 @@ func New(ctx context.Context,
 	kvCli := kv.NewCDCKVClient(
 		ctx, pdCli, grpcPool, regionCache, pdClock, cfg, changefeed, tableID, tableName)
 	p := &pullerImpl{
-		kvCli:        kvCli,
-		kvStorage:    tikvStorage,
-		checkpointTs: checkpointTs,
-		spans:        comparableSpans,
-		outputCh:     make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
-		tsTracker:    tsTracker,
-		resolvedTs:   checkpointTs,
-		initialized:  0,
-		changefeed:   changefeed,
-		tableID:      tableID,
-		tableName:    tableName,
+		kvCli:           kvCli,
+		kvStorage:       tikvStorage,
+		checkpointTs:    checkpointTs,
+		snapshotStorage: kvStorage,
+		snapshotSpans:   spans,
+		initialLoad:     initialLoad,
+		spans:           comparableSpans,
+		outputCh:        make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
+		tsTracker:       tsTracker,
+		resolvedTs:      checkpointTs,
+		initialized:     0,
+		changefeed:      changefeed,
+		tableID:         tableID,
+		tableName:       tableName,
 	}
 	return p
 }
@@ -143,6 +165,7 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 		pullerResolvedTsGauge.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 		txnCollectCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 		txnCollectCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
+		initialLoadRowsCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 	}()
 
 	lastResolvedTs := p.checkpointTs
@@ -171,9 +194,20 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 				return errors.Trace(ctx.Err())
 			case p.outputCh <- raw:
 			}
+			if raw.OpType != model.OpTypeResolved {
+				tracing.RecordStage(p.changefeed, p.tableID, raw.CRTs, tracing.StagePuller)
+			}
 			return nil
 		}
 
+		if p.initialLoad {
+			// Rows in the snapshot must be output before any resolved ts,
+			// incremental events are buffered by the kv client meanwhile.
+			if err := p.loadSnapshot(ctx); err != nil {
+				return errors.Trace(err)
+			}
+		}
+
 		start := time.Now()
 		initialized := false
 		for {
@@ -246,6 +280,57 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 	return g.Wait()
 }
 
+// loadSnapshot scans all rows in the snapshot of spans at checkpointTs, and
+// outputs them as insert events committed at checkpointTs. Incremental events
+// are committed after checkpointTs, so they are always sorted after the rows.
+func (p *pullerImpl) loadSnapshot(ctx context.Context) error {
+	start := time.Now()
+	metricLoadedRows := initialLoadRowsCounter.
+		WithLabelValues(p.changefeed.Namespace, p.changefeed.ID)
+
+	snap := p.snapshotStorage.GetSnapshot(tidbkv.NewVersion(p.checkpointTs))
+	snap.SetOption(tidbkv.Priority, tidbkv.PriorityLow)
+	snap.SetOption(tidbkv.NotFillCache, true)
+	for _, span := range p.snapshotSpans {
+		iter, err := snap.Iter(span.Start, span.End)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for iter.Valid() {
+			raw := &model.RawKVEntry{
+				OpType:  model.OpTypePut,
+				Key:     iter.Key(),
+				Value:   iter.Value(),
+				StartTs: p.checkpointTs,
+				CRTs:    p.checkpointTs,
+			}
+			select {
+			case <-ctx.Done():
+				iter.Close()
+				return errors.Trace(ctx.Err())
+			case p.outputCh <- raw:
+			}
+			atomic.AddUint64(&p.loadedRows, 1)
+			metricLoadedRows.Inc()
+			if err := iter.Next(); err != nil {
+				iter.Close()
+				return errors.Trace(err)
+			}
+		}
+		iter.Close()
+	}
+	atomic.StoreInt64(&p.loaded, 1)
+	log.Info("puller snapshot is loaded",
+		zap.String("namespace", p.changefeed.Namespace),
+		zap.String("changefeed", p.changefeed.ID),
+		zap.Int64("tableID", p.tableID),
+		zap.String("tableName", p.tableName),
+		zap.Uint64("snapshotTs", p.checkpointTs),
+		zap.Uint64("rows", atomic.LoadUint64(&p.loadedRows)),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
 func (p *pullerImpl) GetResolvedTs() uint64 {
 	return atomic.LoadUint64(&p.resolvedTs)
 }
@@ -257,3 +342,21 @@ func (p *pullerImpl) Output() <-chan *model.RawKVEntry {
 func (p *pullerImpl) IsInitialized() bool {
 	return atomic.LoadInt64(&p.initialized) > 0
 }
+
+func (p *pullerImpl) SlowestRegions(limit int) []model.RegionDiagnosis {
+	return p.kvCli.SlowestRegions(limit)
+}
+
+func (p *pullerImpl) LoadProgress() *model.TableLoadProgress {
+	if !p.initialLoad {
+		return nil
+	}
+	state := model.TableLoadStateLoading
+	if atomic.LoadInt64(&p.loaded) > 0 {
+		state = model.TableLoadStateFinished
+	}
+	return &model.TableLoadProgress{
+		State:      state,
+		LoadedRows: atomic.LoadUint64(&p.loadedRows),
+	}
+}
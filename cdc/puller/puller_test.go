@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	pd "github.com/tikv/pd/client"
 )
@@ -91,6 +92,10 @@ func (mc *mockCDCKVClient) EventFeed(
 	}
 }
 
+func (mc *mockCDCKVClient) SlowestRegions(limit int) []model.RegionDiagnosis {
+	return nil
+}
+
 func (mc *mockCDCKVClient) Close() error {
 	close(mc.expectations)
 	if len(mc.expectations) > 0 {
@@ -112,10 +117,20 @@ func newPullerForTest(
 	spans []regionspan.Span,
 	checkpointTs uint64,
 ) (*mockInjectedPuller, context.CancelFunc, *sync.WaitGroup, tidbkv.Storage) {
-	var wg sync.WaitGroup
-	ctx, cancel := context.WithCancel(context.Background())
 	store, err := mockstore.NewMockStore()
 	require.Nil(t, err)
+	return newPullerWithStoreForTest(t, store, spans, checkpointTs, false)
+}
+
+func newPullerWithStoreForTest(
+	t *testing.T,
+	store tidbkv.Storage,
+	spans []regionspan.Span,
+	checkpointTs uint64,
+	initialLoad bool,
+) (*mockInjectedPuller, context.CancelFunc, *sync.WaitGroup, tidbkv.Storage) {
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(context.Background())
 	backupNewCDCKVClient := kv.NewCDCKVClient
 	kv.NewCDCKVClient = newMockCDCKVClient
 	defer func() {
@@ -128,7 +143,7 @@ func newPullerForTest(
 	defer regionCache.Close()
 	plr := New(
 		ctx, pdCli, grpcPool, regionCache, store, pdutil.NewClock4Test(),
-		checkpointTs, spans, config.GetDefaultServerConfig().KVClient,
+		checkpointTs, initialLoad, spans, config.GetDefaultServerConfig().KVClient,
 		model.DefaultChangeFeedID("changefeed-id-test"), 0, "table-test")
 	wg.Add(1)
 	go func() {
@@ -138,7 +153,6 @@ func newPullerForTest(
 			require.Equal(t, context.Canceled, errors.Cause(err))
 		}
 	}()
-	require.Nil(t, err)
 	mockPlr := &mockInjectedPuller{
 		Puller: plr,
 		cli:    plr.(*pullerImpl).kvCli.(*mockCDCKVClient),
@@ -225,3 +239,56 @@ func TestPullerRawKV(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestPullerInitialLoad(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.Nil(t, err)
+	ctx := context.Background()
+	txn, err := store.Begin()
+	require.Nil(t, err)
+	require.Nil(t, txn.Set([]byte("t_b"), []byte("v1")))
+	require.Nil(t, txn.Set([]byte("t_c"), []byte("v2")))
+	// The key is out of the span.
+	require.Nil(t, txn.Set([]byte("t_f"), []byte("v3")))
+	require.Nil(t, txn.Commit(ctx))
+	ver, err := store.CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	// Changes committed after the checkpoint ts are not loaded.
+	txn, err = store.Begin()
+	require.Nil(t, err)
+	require.Nil(t, txn.Set([]byte("t_d"), []byte("v4")))
+	require.Nil(t, txn.Commit(ctx))
+
+	spans := []regionspan.Span{
+		{Start: []byte("t_a"), End: []byte("t_e")},
+	}
+	checkpointTs := ver.Ver
+	plr, cancel, wg, store := newPullerWithStoreForTest(t, store, spans, checkpointTs, true)
+	plr.cli.Returns(model.RegionFeedEvent{
+		Resolved: &model.ResolvedSpan{
+			Span:       regionspan.ToComparableSpan(spans[0]),
+			ResolvedTs: checkpointTs + 1,
+		},
+	})
+
+	ev := <-plr.Output()
+	require.Equal(t, model.OpTypePut, ev.OpType)
+	require.Equal(t, []byte("t_b"), ev.Key)
+	require.Equal(t, []byte("v1"), ev.Value)
+	require.Equal(t, checkpointTs, ev.CRTs)
+	ev = <-plr.Output()
+	require.Equal(t, []byte("t_c"), ev.Key)
+	require.Equal(t, checkpointTs, ev.CRTs)
+	// Resolved ts is output after all rows in the snapshot.
+	ev = <-plr.Output()
+	require.Equal(t, model.OpTypeResolved, ev.OpType)
+	require.Equal(t, checkpointTs+1, ev.CRTs)
+	require.Equal(t, &model.TableLoadProgress{
+		State:      model.TableLoadStateFinished,
+		LoadedRows: 2,
+	}, plr.LoadProgress())
+
+	store.Close()
+	cancel()
+	wg.Wait()
+}
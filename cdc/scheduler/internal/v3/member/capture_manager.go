@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/schedulepb"
+	"github.com/pingcap/tiflow/pkg/version"
 	"go.uber.org/zap"
 )
 
@@ -61,6 +62,12 @@ type CaptureStatus struct {
 	ID       model.CaptureID
 	Addr     string
 	IsOwner  bool
+	Version  string
+	// Incompatible means the version of the capture is incompatible with
+	// the owner, tables must not be scheduled onto it. It happens in
+	// mixed-version clusters during rolling upgrades, and is updated on
+	// every update of the alive captures.
+	Incompatible bool
 }
 
 func newCaptureStatus(
@@ -75,6 +82,11 @@ func newCaptureStatus(
 	}
 }
 
+// IsSchedulable returns true if tables can be scheduled onto the capture.
+func (c *CaptureStatus) IsSchedulable() bool {
+	return c.State != CaptureStateStopping && !c.Incompatible
+}
+
 func (c *CaptureStatus) handleHeartbeatResponse(
 	resp *schedulepb.HeartbeatResponse, epoch schedulepb.ProcessorEpoch,
 ) {
@@ -216,21 +228,42 @@ func (c *CaptureManager) HandleMessage(
 func (c *CaptureManager) HandleAliveCaptureUpdate(
 	aliveCaptures map[model.CaptureID]*model.CaptureInfo,
 ) []*schedulepb.Message {
+	var ownerVersion string
+	if owner, ok := aliveCaptures[c.ownerID]; ok {
+		ownerVersion = owner.Version
+	}
+	gate := version.NewCreatorVersionGate(ownerVersion)
 	msgs := make([]*schedulepb.Message, 0)
 	for id, info := range aliveCaptures {
-		if _, ok := c.Captures[id]; !ok {
+		status, ok := c.Captures[id]
+		if !ok {
 			// A new capture.
-			c.Captures[id] = newCaptureStatus(
+			status = newCaptureStatus(
 				c.OwnerRev, id, info.AdvertiseAddr, c.ownerID == id)
+			c.Captures[id] = status
 			log.Info("schedulerv3: find a new capture",
 				zap.String("captureAddr", info.AdvertiseAddr),
-				zap.String("capture", id))
+				zap.String("capture", id),
+				zap.String("version", info.Version))
 			msgs = append(msgs, &schedulepb.Message{
 				To:        id,
 				MsgType:   schedulepb.MsgHeartbeat,
 				Heartbeat: &schedulepb.Heartbeat{},
 			})
 		}
+		// The compatibility is checked on every update, since the owner
+		// version may be unknown until the owner capture is seen.
+		incompatible := !gate.CaptureCompatible(info.Version)
+		if incompatible != status.Incompatible {
+			log.Info("schedulerv3: the compatibility of capture changes",
+				zap.String("captureAddr", info.AdvertiseAddr),
+				zap.String("capture", id),
+				zap.String("version", info.Version),
+				zap.String("ownerVersion", ownerVersion),
+				zap.Bool("incompatible", incompatible))
+		}
+		status.Version = info.Version
+		status.Incompatible = incompatible
 	}
 
 	// Find removed captures.
@@ -173,6 +173,46 @@ func TestCaptureManagerHandleMessages(t *testing.T) {
 	require.False(t, cm.CheckAllCaptureInitialized())
 }
 
+func TestCaptureManagerIncompatibleVersion(t *testing.T) {
+	t.Parallel()
+
+	rev := schedulepb.OwnerRevision{}
+	cm := NewCaptureManager("1", model.ChangeFeedID{}, rev, 2)
+	cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{
+		"1": {Version: "v6.4.0"},
+		"2": {Version: "v6.3.0"},
+		"3": {Version: "v6.4.1"},
+		"4": {},
+	})
+	require.False(t, cm.Captures["1"].Incompatible)
+	require.True(t, cm.Captures["2"].Incompatible)
+	require.False(t, cm.Captures["3"].Incompatible)
+	require.False(t, cm.Captures["4"].Incompatible)
+
+	// The compatibility is recomputed on every update.
+	cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{
+		"1": {Version: "v6.3.0"},
+		"2": {Version: "v6.3.0"},
+		"3": {Version: "v6.4.1"},
+		"4": {},
+	})
+	require.False(t, cm.Captures["2"].Incompatible)
+	cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{
+		"1": {Version: "v6.4.0"},
+		"2": {Version: "v6.3.0"},
+		"3": {Version: "v6.4.1"},
+		"4": {},
+	})
+	require.True(t, cm.Captures["2"].Incompatible)
+
+	cm.Captures["1"].State = CaptureStateInitialized
+	cm.Captures["2"].State = CaptureStateInitialized
+	cm.Captures["3"].State = CaptureStateStopping
+	require.True(t, cm.Captures["1"].IsSchedulable())
+	require.False(t, cm.Captures["2"].IsSchedulable())
+	require.False(t, cm.Captures["3"].IsSchedulable())
+}
+
 func TestCaptureManagerTick(t *testing.T) {
 	t.Parallel()
 
@@ -75,12 +75,41 @@ func (b *balanceScheduler) Schedule(
 		}
 	}
 
+	captures, replications = compatibleCaptures(captures, replications)
+	if len(captures) == 0 {
+		return nil
+	}
 	tasks := buildBalanceMoveTables(
 		b.random, currentTables, captures, replications, b.maxTaskConcurrency)
 	b.forceBalance = len(tasks) != 0
 	return tasks
 }
 
+// compatibleCaptures returns the captures whose versions are compatible with
+// the owner, and the replications of the tables on them. The tables on the
+// incompatible captures are left there until the captures are drained.
+func compatibleCaptures(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications map[model.TableID]*replication.ReplicationSet,
+) (map[model.CaptureID]*member.CaptureStatus, map[model.TableID]*replication.ReplicationSet) {
+	compatible := make(map[model.CaptureID]*member.CaptureStatus, len(captures))
+	for id, capture := range captures {
+		if !capture.Incompatible {
+			compatible[id] = capture
+		}
+	}
+	if len(compatible) == len(captures) {
+		return captures, replications
+	}
+	reps := make(map[model.TableID]*replication.ReplicationSet, len(replications))
+	for tableID, rep := range replications {
+		if _, ok := compatible[rep.Primary]; ok {
+			reps[tableID] = rep
+		}
+	}
+	return compatible, reps
+}
+
 func buildBalanceMoveTables(
 	random *rand.Rand,
 	currentTables []model.TableID,
@@ -71,6 +71,41 @@ func TestSchedulerBalanceCaptureOnline(t *testing.T) {
 	require.Len(t, tasks, 0)
 }
 
+func TestSchedulerBalanceIncompatibleCapture(t *testing.T) {
+	t.Parallel()
+
+	sched := newBalanceScheduler(time.Duration(0), 10)
+	sched.random = nil
+
+	// Tables are balanced among the compatible captures only, and the tables
+	// on the incompatible capture are left there.
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {}, "b": {}, "c": {Incompatible: true},
+	}
+	currentTables := []model.TableID{1, 2, 3, 4, 5, 6}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		5: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+		6: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+	}
+	tasks := sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, "b", task.MoveTable.DestCapture)
+		require.Contains(t, []model.TableID{1, 2, 3, 4}, task.MoveTable.TableID)
+	}
+
+	// Nothing is balanced if all captures are incompatible.
+	captures = map[model.CaptureID]*member.CaptureStatus{
+		"c": {Incompatible: true},
+	}
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+}
+
 func TestSchedulerBalanceTaskLimit(t *testing.T) {
 	t.Parallel()
 
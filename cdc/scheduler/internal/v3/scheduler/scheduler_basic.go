@@ -84,8 +84,8 @@ func (b *basicScheduler) Schedule(
 	if len(newTables) > 0 {
 		captureIDs := make([]model.CaptureID, 0, len(captures))
 		for captureID, status := range captures {
-			if status.State == member.CaptureStateStopping {
-				log.Warn("schedulerv3: capture is stopping, "+
+			if !status.IsSchedulable() {
+				log.Warn("schedulerv3: capture is stopping or incompatible, "+
 					"skip the capture when add new table",
 					zap.String("namespace", b.changefeedID.Namespace),
 					zap.String("changefeed", b.changefeedID.ID),
@@ -47,10 +47,18 @@ func TestSchedulerBasic(t *testing.T) {
 	tasks = b.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 0)
 
+	// one capture is incompatible, another one is initialized
 	captures["a"].State = member.CaptureStateInitialized
+	captures["a"].Incompatible = true
 	captures["b"].State = member.CaptureStateInitialized
 	tasks = b.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 1)
+	require.Equal(t, tasks[0].BurstBalance.AddTables[0].CaptureID, "b")
+	require.Equal(t, tasks[0].BurstBalance.AddTables[1].CaptureID, "b")
+
+	captures["a"].Incompatible = false
+	tasks = b.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
 	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
 	require.Equal(t, tasks[0].BurstBalance.AddTables[0].TableID, model.TableID(1))
 	require.Equal(t, tasks[0].BurstBalance.AddTables[1].TableID, model.TableID(2))
@@ -81,11 +81,20 @@ func (d *drainCaptureScheduler) Schedule(
 		// There are two ways to make a capture "stopping",
 		// 1. PUT /api/v1/capture/drain
 		// 2. kill <TiCDC_PID>
+		// Drained captures stay stopping until they leave, so only the
+		// stopping captures with tables are drained.
+		primaries := make(map[model.CaptureID]struct{})
+		for _, rep := range replications {
+			primaries[rep.Primary] = struct{}{}
+		}
 		for id, capture := range captures {
 			if capture.IsOwner {
 				// Skip draining owner.
 				continue
 			}
+			if _, ok := primaries[id]; !ok {
+				continue
+			}
 			if capture.State == member.CaptureStateStopping {
 				d.target = id
 				break
@@ -104,8 +113,10 @@ func (d *drainCaptureScheduler) Schedule(
 
 	// Currently, the workload is the number of tables in a capture.
 	captureWorkload := make(map[model.CaptureID]int)
-	for id := range captures {
-		if id != d.target {
+	for id, capture := range captures {
+		// Tables must not be moved to stopping captures, including the ones
+		// drained before, or captures of incompatible versions.
+		if id != d.target && capture.IsSchedulable() {
 			captureWorkload[id] = 0
 		}
 	}
@@ -144,7 +155,7 @@ func (d *drainCaptureScheduler) Schedule(
 		}
 
 		// only calculate workload of other captures not the drain target.
-		if rep.Primary != d.target {
+		if _, ok := captureWorkload[rep.Primary]; ok {
 			captureWorkload[rep.Primary]++
 		}
 	}
@@ -218,3 +218,52 @@ func TestDrainEvenlyDistributedTables(t *testing.T) {
 	require.Equal(t, 1, taskMap["b"])
 	require.Equal(t, 2, taskMap["c"])
 }
+
+func TestDrainSkipIncompatibleCapture(t *testing.T) {
+	t.Parallel()
+
+	var checkpointTs model.Ts
+	currentTables := make([]model.TableID, 0)
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+		"b": {IsOwner: true, State: member.CaptureStateInitialized},
+		"c": {State: member.CaptureStateInitialized, Incompatible: true},
+	}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+	}
+	scheduler := newDrainCaptureScheduler(10, model.ChangeFeedID{})
+	scheduler.setTarget("a")
+	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.EqualValues(t, "b", task.MoveTable.DestCapture)
+	}
+}
+
+func TestDrainSkipDrainedCapture(t *testing.T) {
+	t.Parallel()
+
+	var checkpointTs model.Ts
+	currentTables := make([]model.TableID, 0)
+	// "c" has been drained and stays stopping until it leaves.
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateStopping},
+		"b": {IsOwner: true, State: member.CaptureStateInitialized},
+		"c": {State: member.CaptureStateStopping},
+	}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	}
+	scheduler := newDrainCaptureScheduler(10, model.ChangeFeedID{})
+	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.EqualValues(t, "a", scheduler.getTarget())
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.EqualValues(t, "b", task.MoveTable.DestCapture)
+	}
+}
@@ -122,6 +122,17 @@ func (m *moveTableScheduler) Schedule(
 			delete(m.tasks, tableID)
 			continue
 		}
+		if status.Incompatible {
+			log.Warn("schedulerv3: move table ignored, "+
+				"the version of target capture is incompatible",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Int64("tableID", tableID),
+				zap.String("captureID", task.MoveTable.DestCapture),
+				zap.String("version", status.Version))
+			delete(m.tasks, tableID)
+			continue
+		}
 
 		rep, ok := replications[tableID]
 		if !ok {
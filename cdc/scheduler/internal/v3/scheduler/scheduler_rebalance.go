@@ -89,6 +89,10 @@ func (r *rebalanceScheduler) Schedule(
 	}
 
 	unlimited := math.MaxInt
+	captures, replications = compatibleCaptures(captures, replications)
+	if len(captures) == 0 {
+		return nil
+	}
 	tasks := newBalanceMoveTables(r.random, captures, replications, unlimited, r.changefeedID)
 	if len(tasks) == 0 {
 		return nil
@@ -0,0 +1,154 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemahistory
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+const recordFileExt = ".json"
+
+// Record is a version of the definition of a table. It takes effect from
+// the commit ts Version until the next version of the table.
+type Record struct {
+	Version uint64 `json:"version"`
+	Schema  string `json:"schema"`
+	Table   string `json:"table"`
+	TableID int64  `json:"table_id"`
+	// Query is the DDL creating the version, it's empty for the versions
+	// recorded when the changefeed is initialized.
+	Query   string   `json:"query,omitempty"`
+	Dropped bool     `json:"dropped,omitempty"`
+	Columns []Column `json:"columns,omitempty"`
+	Indexes []Index  `json:"indexes,omitempty"`
+}
+
+// Column is a column of a table definition.
+type Column struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Nullable  bool   `json:"nullable"`
+	Default   any    `json:"default,omitempty"`
+	Generated bool   `json:"generated,omitempty"`
+}
+
+// Index is an index of a table definition.
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Primary bool     `json:"primary,omitempty"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// NewRecord creates a record of the table definition, the table is recorded
+// with the given schema and table name, which may be routed.
+func NewRecord(
+	version uint64, schema, table string, info *model.TableInfo, query string,
+) *Record {
+	r := &Record{
+		Version: version,
+		Schema:  schema,
+		Table:   table,
+		TableID: info.ID,
+		Query:   query,
+	}
+	for _, col := range info.Columns {
+		if col.State != timodel.StatePublic || col.Hidden {
+			continue
+		}
+		r.Columns = append(r.Columns, Column{
+			Name:      col.Name.O,
+			Type:      col.GetTypeDesc(),
+			Nullable:  !mysql.HasNotNullFlag(col.GetFlag()),
+			Default:   col.GetDefaultValue(),
+			Generated: col.IsGenerated(),
+		})
+	}
+	if info.PKIsHandle {
+		if pk := info.GetPkColInfo(); pk != nil {
+			r.Indexes = append(r.Indexes, Index{
+				Name:    "PRIMARY",
+				Columns: []string{pk.Name.O},
+				Primary: true,
+				Unique:  true,
+			})
+		}
+	}
+	for _, idx := range info.Indices {
+		if idx.State != timodel.StatePublic {
+			continue
+		}
+		index := Index{
+			Name:    idx.Name.O,
+			Columns: make([]string, 0, len(idx.Columns)),
+			Primary: idx.Primary,
+			Unique:  idx.Unique,
+		}
+		for _, col := range idx.Columns {
+			index.Columns = append(index.Columns, col.Name.O)
+		}
+		r.Indexes = append(r.Indexes, index)
+	}
+	return r
+}
+
+// NewDroppedRecord creates a record of a dropped table.
+func NewDroppedRecord(
+	version uint64, schema, table string, tableID int64, query string,
+) *Record {
+	return &Record{
+		Version: version,
+		Schema:  schema,
+		Table:   table,
+		TableID: tableID,
+		Query:   query,
+		Dropped: true,
+	}
+}
+
+// tableDir returns the directory of the records of a table of the
+// changefeed. The layout is `<namespace>/<changefeed>/<schema>/<table>/`,
+// names are escaped, so changefeeds can share the same storage.
+func tableDir(changefeedID model.ChangeFeedID, schema, table string) string {
+	return path.Join(url.PathEscape(changefeedID.Namespace), url.PathEscape(changefeedID.ID),
+		url.PathEscape(schema), url.PathEscape(table)) + "/"
+}
+
+// recordPath returns the path of the record, the layout is
+// `<namespace>/<changefeed>/<schema>/<table>/<version>.json`.
+func recordPath(changefeedID model.ChangeFeedID, r *Record) string {
+	return tableDir(changefeedID, r.Schema, r.Table) +
+		fmt.Sprintf("%d%s", r.Version, recordFileExt)
+}
+
+// parseVersion returns the version of the record at the path.
+func parseVersion(p string) (uint64, bool) {
+	name := path.Base(p)
+	if !strings.HasSuffix(name, recordFileExt) {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(strings.TrimSuffix(name, recordFileExt), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,192 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemahistory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+// NewStorage creates the external storage of schema history from the URI,
+// such as `s3://bucket/prefix` and `file:///path`.
+func NewStorage(ctx context.Context, uri string) (storage.ExternalStorage, error) {
+	backend, err := storage.ParseBackend(uri, &storage.BackendOptions{})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSchemaHistoryStorage, err)
+	}
+	s, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSchemaHistoryStorage, err)
+	}
+	return s, nil
+}
+
+const (
+	writeBackoffBaseDelayInMs = 500
+	writeBackoffMaxDelayInMs  = 5000
+	writeMaxTries             = 10
+)
+
+// Writer persists the versions of table definitions to the external storage
+// in the background, so consumers can reconstruct the column layouts of
+// tables at any commit ts. Records are written in the order they are
+// emitted, the owner waits until the records of a DDL are written before the
+// DDL is executed and the checkpoint passes it.
+type Writer struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+
+	mu sync.Mutex
+	// pending are the records not written yet, a record is removed only
+	// after it's written.
+	pending []*Record
+	notify  chan struct{}
+}
+
+// NewWriter creates a Writer.
+func NewWriter(
+	changefeedID model.ChangeFeedID, storage storage.ExternalStorage,
+) *Writer {
+	return &Writer{
+		changefeedID: changefeedID,
+		storage:      storage,
+		notify:       make(chan struct{}, 1),
+	}
+}
+
+// Emit adds the records to be written, it never blocks. Records must be
+// emitted in the order of their versions.
+func (w *Writer) Emit(records ...*Record) {
+	if len(records) == 0 {
+		return
+	}
+	w.mu.Lock()
+	w.pending = append(w.pending, records...)
+	w.mu.Unlock()
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Flushed returns whether all the emitted records with versions not greater
+// than ts are written.
+func (w *Writer) Flushed(ts uint64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending) == 0 || w.pending[0].Version > ts
+}
+
+// Run writes the emitted records until the context is canceled.
+func (w *Writer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-w.notify:
+		}
+		for {
+			w.mu.Lock()
+			if len(w.pending) == 0 {
+				w.mu.Unlock()
+				break
+			}
+			r := w.pending[0]
+			w.mu.Unlock()
+			if err := w.write(ctx, r); err != nil {
+				return err
+			}
+			w.mu.Lock()
+			w.pending = w.pending[1:]
+			w.mu.Unlock()
+		}
+	}
+}
+
+func (w *Writer) write(ctx context.Context, r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	// Records of the same version are the same, so it's safe to overwrite
+	// the ones written before the owner switches.
+	err = retry.Do(ctx, func() error {
+		return w.storage.WriteFile(ctx, recordPath(w.changefeedID, r), data)
+	}, retry.WithBackoffBaseDelay(writeBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(writeBackoffMaxDelayInMs),
+		retry.WithMaxTries(writeMaxTries),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSchemaHistoryStorage, err)
+	}
+	log.Debug("schema history record written",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("schema", r.Schema),
+		zap.String("table", r.Table),
+		zap.Uint64("version", r.Version),
+		zap.Bool("dropped", r.Dropped))
+	return nil
+}
+
+// Lookup returns the version of the table definition of the changefeed in
+// effect at the commit ts, which is the latest version not greater than the
+// commit ts.
+func Lookup(
+	ctx context.Context, s storage.ExternalStorage, changefeedID model.ChangeFeedID,
+	schema, table string, commitTs uint64,
+) (*Record, error) {
+	found := false
+	var version uint64
+	err := s.WalkDir(ctx, &storage.WalkOption{SubDir: tableDir(changefeedID, schema, table)},
+		func(p string, _ int64) error {
+			v, ok := parseVersion(p)
+			if ok && v <= commitTs && (!found || v > version) {
+				found = true
+				version = v
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSchemaHistoryStorage, err)
+	}
+	if !found {
+		return nil, cerror.ErrTableNotFoundAtTs.GenWithStackByArgs(schema, table, commitTs)
+	}
+	data, err := s.ReadFile(ctx, recordPath(changefeedID, &Record{
+		Schema: schema, Table: table, Version: version,
+	}))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSchemaHistoryStorage, err)
+	}
+	r := new(Record)
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	if r.Dropped {
+		return nil, cerror.ErrTableNotFoundAtTs.GenWithStackByArgs(schema, table, commitTs)
+	}
+	return r, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemahistory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	parser_types "github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTableInfo(columns ...string) *model.TableInfo {
+	ftPK := parser_types.NewFieldType(mysql.TypeLong)
+	ftPK.SetFlag(mysql.NotNullFlag | mysql.PriKeyFlag)
+	tbl := &timodel.TableInfo{
+		ID:         100,
+		Name:       timodel.NewCIStr("t1"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("id"),
+			FieldType: *ftPK,
+			State:     timodel.StatePublic,
+		}},
+	}
+	for i, name := range columns {
+		tbl.Columns = append(tbl.Columns, &timodel.ColumnInfo{
+			ID:        int64(i + 2),
+			Name:      timodel.NewCIStr(name),
+			Offset:    i + 1,
+			FieldType: *parser_types.NewFieldType(mysql.TypeVarchar),
+			State:     timodel.StatePublic,
+		})
+	}
+	return model.WrapTableInfo(1, "test", 0, tbl)
+}
+
+func TestNewRecord(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecord(10, "test", "t1", newTableInfo("a"), "")
+	require.Equal(t, uint64(10), r.Version)
+	require.Equal(t, int64(100), r.TableID)
+	require.Len(t, r.Columns, 2)
+	require.Equal(t, "id", r.Columns[0].Name)
+	require.False(t, r.Columns[0].Nullable)
+	require.Equal(t, "a", r.Columns[1].Name)
+	require.True(t, r.Columns[1].Nullable)
+	require.Equal(t, []Index{{
+		Name: "PRIMARY", Columns: []string{"id"}, Primary: true, Unique: true,
+	}}, r.Indexes)
+
+	changefeedID := model.DefaultChangeFeedID("cf")
+	require.Equal(t, "default/cf/test/t1/10.json", recordPath(changefeedID, r))
+	version, ok := parseVersion(recordPath(changefeedID, r))
+	require.True(t, ok)
+	require.Equal(t, uint64(10), version)
+	_, ok = parseVersion("test/t1/abc.json")
+	require.False(t, ok)
+}
+
+func TestWriteAndLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.Nil(t, err)
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	w := NewWriter(changefeedID, s)
+	w.Emit(
+		NewRecord(10, "test", "t1", newTableInfo("a"), ""),
+		NewRecord(20, "test", "t1", newTableInfo("a", "b"),
+			"ALTER TABLE t1 ADD COLUMN b VARCHAR(10)"),
+	)
+	w.Emit(NewDroppedRecord(30, "test", "t1", 100, "DROP TABLE t1"))
+	// Records are pending until they are written.
+	require.True(t, w.Flushed(9))
+	require.False(t, w.Flushed(10))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return w.Flushed(30)
+	}, 5*time.Second, 10*time.Millisecond)
+	exists, err := s.FileExists(ctx, fmt.Sprintf("default/test/test/t1/%d.json", 30))
+	require.Nil(t, err)
+	require.True(t, exists)
+
+	_, err = Lookup(ctx, s, changefeedID, "test", "t1", 9)
+	require.Regexp(t, ".*not found at ts 9.*", err)
+	r, err := Lookup(ctx, s, changefeedID, "test", "t1", 15)
+	require.Nil(t, err)
+	require.Equal(t, uint64(10), r.Version)
+	require.Len(t, r.Columns, 2)
+	r, err = Lookup(ctx, s, changefeedID, "test", "t1", 20)
+	require.Nil(t, err)
+	require.Equal(t, uint64(20), r.Version)
+	require.Len(t, r.Columns, 3)
+	require.Equal(t, "b", r.Columns[2].Name)
+	_, err = Lookup(ctx, s, changefeedID, "test", "t1", 30)
+	require.Regexp(t, ".*not found at ts 30.*", err)
+	// The records of other changefeeds are not found.
+	_, err = Lookup(ctx, s, model.DefaultChangeFeedID("other"), "test", "t1", 15)
+	require.Regexp(t, ".*not found at ts 15.*", err)
+
+	cancel()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh))
+}
@@ -15,6 +15,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -35,7 +36,6 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"golang.org/x/net/netutil"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
@@ -47,8 +47,10 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/fsutil"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/tcpserver"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	p2pProto "github.com/pingcap/tiflow/proto/p2p"
 )
 
@@ -240,7 +242,9 @@ func (s *server) startStatusHTTP(lis net.Listener) error {
 	// limit will wait in a queue and no new goroutines will be created until
 	// a connection is processed.
 	// We use it here to limit the max concurrent connections of statusServer.
-	lis = netutil.LimitListener(lis, maxHTTPConnection)
+	// The listener also keeps the TLS state of connections, which is used to
+	// authenticate clients by their certificates.
+	lis = httputil.LimitListener(lis, maxHTTPConnection)
 	conf := config.GetGlobalServerConfig()
 
 	// discard gin log output
@@ -258,6 +262,7 @@ func (s *server) startStatusHTTP(lis net.Listener) error {
 		Handler:      router,
 		ReadTimeout:  httpConnectionTimeout,
 		WriteTimeout: httpConnectionTimeout,
+		ConnContext:  httputil.ConnContext,
 	}
 
 	go func() {
@@ -340,6 +345,19 @@ func (s *server) run(ctx context.Context) (err error) {
 		})
 	}
 
+	if conf.Tracing.Enable {
+		var tlsConfig *tls.Config
+		if conf.Security.IsTLSEnabled() {
+			tlsConfig, err = conf.Security.ToTLSConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		wg.Go(func() error {
+			return tracing.Run(cctx, conf.Tracing, tlsConfig)
+		})
+	}
+
 	if conf.Debug.EnableNewScheduler {
 		grpcServer := grpc.NewServer()
 		p2pProto.RegisterCDCPeerToPeerServer(grpcServer, s.grpcService)
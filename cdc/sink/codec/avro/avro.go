@@ -142,10 +142,10 @@ func (a *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 }
 
 // Build Messages
-func (a *BatchEncoder) Build() (messages []*common.Message) {
+func (a *BatchEncoder) Build() ([]*common.Message, error) {
 	old := a.resultBuf
 	a.resultBuf = nil
-	return old
+	return old, nil
 }
 
 const (
@@ -28,17 +28,25 @@ import (
 
 // NewEventBatchEncoderBuilder returns an EncoderBuilder
 func NewEventBatchEncoderBuilder(ctx context.Context, c *common.Config) (codec.EncoderBuilder, error) {
+	builder, err := newEventBatchEncoderBuilder(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return codec.NewCompressionEncoderBuilder(builder, c), nil
+}
+
+func newEventBatchEncoderBuilder(ctx context.Context, c *common.Config) (codec.EncoderBuilder, error) {
 	switch c.Protocol {
 	case config.ProtocolDefault, config.ProtocolOpen:
-		return open.NewBatchEncoderBuilder(c), nil
+		return open.NewBatchEncoderBuilder(ctx, c)
 	case config.ProtocolCanal:
-		return canal.NewBatchEncoderBuilder(), nil
+		return canal.NewBatchEncoderBuilder(c), nil
 	case config.ProtocolAvro:
 		return avro.NewBatchEncoderBuilder(ctx, c)
 	case config.ProtocolMaxwell:
-		return maxwell.NewBatchEncoderBuilder(), nil
+		return maxwell.NewBatchEncoderBuilder(c), nil
 	case config.ProtocolCanalJSON:
-		return canal.NewJSONBatchEncoderBuilder(c), nil
+		return canal.NewJSONBatchEncoderBuilder(ctx, c)
 	case config.ProtocolCraft:
 		return craft.NewBatchEncoderBuilder(c), nil
 	default:
@@ -15,6 +15,7 @@ package canal
 
 import (
 	"context"
+	"encoding/binary"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pingcap/errors"
@@ -28,12 +29,30 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// entryOverhead is the max size of the tag and the length of an entry in
+	// the packet body.
+	entryOverhead = 1 + binary.MaxVarintLen64
+	// packetOverhead is the max size of the fields other than the entries in
+	// a packet.
+	packetOverhead = 32
+)
+
 // BatchEncoder encodes the events into the byte of a batch into.
 type BatchEncoder struct {
 	messages     *canal.Messages
 	callbackBuf  []func()
 	packet       *canal.Packet
 	entryBuilder *canalEntryBuilder
+
+	// messageBuf holds the packets which have reached the batch limits.
+	messageBuf []*common.Message
+	// curBatchBytes is the size of the entries in messages.
+	curBatchBytes int
+
+	// configs, zero means unlimited.
+	MaxMessageBytes int
+	MaxBatchSize    int
 }
 
 // EncodeCheckpointEvent implements the EventBatchEncoder interface
@@ -58,7 +77,11 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	if err != nil {
 		return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
+	if len(d.messages.Messages) != 0 && d.batchFull(len(b)) {
+		d.messageBuf = append(d.messageBuf, d.buildPacket())
+	}
 	d.messages.Messages = append(d.messages.Messages, b)
+	d.curBatchBytes += len(b) + entryOverhead
 	if callback != nil {
 		d.callbackBuf = append(d.callbackBuf, callback)
 	}
@@ -98,13 +121,28 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	return common.NewDDLMsg(config.ProtocolCanal, nil, b, e), nil
 }
 
+// batchFull returns whether the current packet can't hold another entry.
+func (d *BatchEncoder) batchFull(entryBytes int) bool {
+	if d.MaxBatchSize > 0 && len(d.messages.Messages) >= d.MaxBatchSize {
+		return true
+	}
+	length := d.curBatchBytes + entryBytes + entryOverhead + packetOverhead + common.MaxRecordOverhead
+	return d.MaxMessageBytes > 0 && length > d.MaxMessageBytes
+}
+
 // Build implements the EventBatchEncoder interface
-func (d *BatchEncoder) Build() []*common.Message {
-	rowCount := len(d.messages.Messages)
-	if rowCount == 0 {
-		return nil
+func (d *BatchEncoder) Build() ([]*common.Message, error) {
+	if len(d.messages.Messages) != 0 {
+		d.messageBuf = append(d.messageBuf, d.buildPacket())
 	}
+	ret := d.messageBuf
+	d.messageBuf = nil
+	return ret, nil
+}
 
+// buildPacket builds the entries appended since last packet into a message.
+func (d *BatchEncoder) buildPacket() *common.Message {
+	rowCount := len(d.messages.Messages)
 	err := d.refreshPacketBody()
 	if err != nil {
 		log.Panic("Error when generating Canal packet", zap.Error(err))
@@ -118,6 +156,7 @@ func (d *BatchEncoder) Build() []*common.Message {
 	ret.SetRowsCount(rowCount)
 	d.messages.Reset()
 	d.resetPacket()
+	d.curBatchBytes = 0
 
 	if len(d.callbackBuf) != 0 && len(d.callbackBuf) == rowCount {
 		callbacks := d.callbackBuf
@@ -128,7 +167,7 @@ func (d *BatchEncoder) Build() []*common.Message {
 		}
 		d.callbackBuf = make([]func(), 0)
 	}
-	return []*common.Message{ret}
+	return ret
 }
 
 // refreshPacketBody() marshals the messages to the packet body
@@ -167,14 +206,20 @@ func newBatchEncoder() codec.EventBatchEncoder {
 	return encoder
 }
 
-type batchEncoderBuilder struct{}
+type batchEncoderBuilder struct {
+	config *common.Config
+}
 
 // Build a `canalBatchEncoder`
 func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
-	return newBatchEncoder()
+	encoder := newBatchEncoder()
+	encoder.(*BatchEncoder).MaxMessageBytes = b.config.MaxMessageBytes
+	encoder.(*BatchEncoder).MaxBatchSize = b.config.MaxBatchSize
+
+	return encoder
 }
 
 // NewBatchEncoderBuilder creates a canal batchEncoderBuilder.
-func NewBatchEncoderBuilder() codec.EncoderBuilder {
-	return &batchEncoderBuilder{}
+func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
+	return &batchEncoderBuilder{config: config}
 }
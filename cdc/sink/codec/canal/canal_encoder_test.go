@@ -20,6 +20,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
 	canal "github.com/pingcap/tiflow/proto/canal"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +35,8 @@ func TestCanalBatchEncoder(t *testing.T) {
 			err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
 			require.Nil(t, err)
 		}
-		res := encoder.Build()
+		res, err := encoder.Build()
+		require.Nil(t, err)
 
 		if len(cs) == 0 {
 			require.Nil(t, res)
@@ -128,7 +131,8 @@ func TestCanalAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 
 	// Empty build makes sure that the callback build logic not broken.
-	msgs := encoder.Build()
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 0, "no message should be built and no panic")
 
 	// Append the events.
@@ -138,8 +142,60 @@ func TestCanalAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 	require.Equal(t, 0, count, "nothing should be called")
 
-	msgs = encoder.Build()
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 1, "expected one message")
 	msgs[0].Callback()
 	require.Equal(t, 15, count, "expected all callbacks to be called")
 }
+
+func TestCanalBatchLimits(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte("aa"),
+		}},
+	}
+	cfg := common.NewConfig(config.ProtocolCanal)
+	cfg.MaxBatchSize = 4
+	encoder := NewBatchEncoderBuilder(cfg).Build()
+	count := 0
+	for i := 0; i < 10; i++ {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, func() { count++ })
+		require.Nil(t, err)
+	}
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 3)
+	for i, rows := range []int{4, 4, 2} {
+		require.Equal(t, rows, msgs[i].GetRowsCount())
+		packet := &canal.Packet{}
+		require.Nil(t, proto.Unmarshal(msgs[i].Value, packet))
+		messages := &canal.Messages{}
+		require.Nil(t, proto.Unmarshal(packet.GetBody(), messages))
+		require.Len(t, messages.GetMessages(), rows)
+		msgs[i].Callback()
+	}
+	require.Equal(t, 10, count)
+
+	cfg.MaxBatchSize = 1024
+	cfg.MaxMessageBytes = 1024
+	encoder = NewBatchEncoderBuilder(cfg).Build()
+	for i := 0; i < 100; i++ {
+		require.Nil(t, encoder.AppendRowChangedEvent(context.Background(), "", row, nil))
+	}
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Greater(t, len(msgs), 1)
+	rows := 0
+	for _, msg := range msgs {
+		require.LessOrEqual(t, msg.Length(), 1024)
+		rows += msg.GetRowsCount()
+	}
+	require.Equal(t, 100, rows)
+}
@@ -14,50 +14,84 @@
 package canal
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
 
-// batchDecoder decodes the byte into the original message.
+// batchDecoder decodes the byte into the original message. A message may
+// carry multiple rows separated by newlines.
 type batchDecoder struct {
 	data                []byte
+	decoder             *json.Decoder
 	msg                 canalJSONMessageInterface
 	enableTiDBExtension bool
+
+	ctx               context.Context
+	claimCheckStorage storage.ExternalStorage
 }
 
 // NewBatchDecoder return a decoder for canal-json
 func NewBatchDecoder(data []byte, enableTiDBExtension bool) codec.EventBatchDecoder {
+	return NewClaimCheckBatchDecoder(context.Background(), data, enableTiDBExtension, nil)
+}
+
+// NewClaimCheckBatchDecoder return a decoder for canal-json, which reads the
+// full messages of rows from the claim-check storage transparently.
+func NewClaimCheckBatchDecoder(
+	ctx context.Context, data []byte, enableTiDBExtension bool,
+	claimCheckStorage storage.ExternalStorage,
+) codec.EventBatchDecoder {
 	return &batchDecoder{
 		data:                data,
 		msg:                 nil,
 		enableTiDBExtension: enableTiDBExtension,
+		ctx:                 ctx,
+		claimCheckStorage:   claimCheckStorage,
 	}
 }
 
-// HasNext implements the EventBatchDecoder interface
-func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
-	if len(b.data) == 0 {
-		return model.MessageTypeUnknown, false, nil
-	}
-	var msg canalJSONMessageInterface = &canalJSONMessage{}
+func (b *batchDecoder) newMessage() canalJSONMessageInterface {
 	if b.enableTiDBExtension {
-		msg = &canalJSONMessageWithTiDBExtension{
+		return &canalJSONMessageWithTiDBExtension{
 			canalJSONMessage: &canalJSONMessage{},
 			Extensions:       &tidbExtension{},
 		}
 	}
-	if err := json.Unmarshal(b.data, msg); err != nil {
-		log.Error("canal-json decoder unmarshal data failed",
-			zap.Error(err), zap.ByteString("data", b.data))
+	return &canalJSONMessage{}
+}
+
+// HasNext implements the EventBatchDecoder interface
+func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
+	if b.decoder == nil {
+		if len(b.data) == 0 {
+			return model.MessageTypeUnknown, false, nil
+		}
+		data, err := common.DecompressPayload(b.data)
+		if err != nil {
+			return model.MessageTypeUnknown, false, errors.Trace(err)
+		}
+		b.decoder = json.NewDecoder(bytes.NewReader(data))
+		b.data = nil
+	}
+	if !b.decoder.More() {
+		return model.MessageTypeUnknown, false, nil
+	}
+	msg := b.newMessage()
+	if err := b.decoder.Decode(msg); err != nil {
+		log.Error("canal-json decoder unmarshal data failed", zap.Error(err))
 		return model.MessageTypeUnknown, false, err
 	}
 	b.msg = msg
-	b.data = nil
 
 	return b.msg.messageType(), true, nil
 }
@@ -69,7 +103,20 @@ func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 		return nil, cerrors.ErrCanalDecodeFailed.
 			GenWithStack("not found row changed event message")
 	}
-	result, err := canalJSONMessage2RowChange(b.msg)
+	msg := b.msg
+	if withExtension, ok := msg.(*canalJSONMessageWithTiDBExtension); ok &&
+		withExtension.Extensions.ClaimCheckLocation != "" {
+		data, err := common.ReadClaimCheck(
+			b.ctx, b.claimCheckStorage, withExtension.Extensions.ClaimCheckLocation)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		msg = b.newMessage()
+		if err := json.Unmarshal(data, msg); err != nil {
+			return nil, cerrors.WrapError(cerrors.ErrCanalDecodeFailed, err)
+		}
+	}
+	result, err := canalJSONMessage2RowChange(msg)
 	if err != nil {
 		return nil, err
 	}
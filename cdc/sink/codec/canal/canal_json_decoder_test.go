@@ -18,6 +18,10 @@ import (
 	"testing"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,7 +35,8 @@ func TestNewCanalJSONBatchDecoder4RowMessage(t *testing.T) {
 		err := encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, nil)
 		require.Nil(t, err)
 
-		messages := encoder.Build()
+		messages, err := encoder.Build()
+		require.Nil(t, err)
 		require.Equal(t, 1, len(messages))
 		msg := messages[0]
 
@@ -116,3 +121,26 @@ func TestNewCanalJSONBatchDecoder4DDLMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestCanalJSONBatchDecoderCompressedPayload(t *testing.T) {
+	t.Parallel()
+	cfg := common.NewConfig(config.ProtocolCanalJSON)
+	cfg.PayloadCompression = compression.ZSTD
+	builder := codec.NewCompressionEncoderBuilder(&jsonBatchEncoderBuilder{config: cfg}, cfg)
+	encoder := builder.Build()
+	err := encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, nil)
+	require.Nil(t, err)
+	messages, err := encoder.Build()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(messages))
+
+	decoder := NewBatchDecoder(messages[0].Value, false)
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+	consumed, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Equal(t, testCaseInsert.Table, consumed.Table)
+	require.NotEmpty(t, consumed.Columns)
+}
@@ -14,12 +14,14 @@
 package canal
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
@@ -31,31 +33,54 @@ import (
 
 // JSONBatchEncoder encodes Canal json messages in JSON format
 type JSONBatchEncoder struct {
-	builder     *canalEntryBuilder
-	messageBuf  []canalJSONMessageInterface
+	builder *canalEntryBuilder
+	// valueBuf holds the rows of the current batch, separated by newlines.
+	valueBuf    bytes.Buffer
 	callbackBuf []func()
+	batchSize   int
+	// schema, table and commitTs of the first row in the current batch.
+	schema   *string
+	table    *string
+	commitTs uint64
+
+	// messageBuf holds the batches which have reached the batch limits.
+	messageBuf []*common.Message
 	// When it is true, canal-json would generate TiDB extension information
 	// which, at the moment, only includes `tidbWaterMarkType` and `_tidb` fields.
 	enableTiDBExtension bool
+
+	maxMessageBytes    int
+	maxBatchSize       int
+	largeMessageHandle *config.LargeMessageHandleConfig
+	claimCheckStorage  storage.ExternalStorage
 }
 
 // newJSONBatchEncoder creates a new JSONBatchEncoder
 func newJSONBatchEncoder() codec.EventBatchEncoder {
 	return &JSONBatchEncoder{
 		builder:             newCanalEntryBuilder(),
-		messageBuf:          make([]canalJSONMessageInterface, 0),
-		callbackBuf:         make([]func(), 0),
+		messageBuf:          make([]*common.Message, 0),
 		enableTiDBExtension: false,
 	}
 }
 
-func (c *JSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) (canalJSONMessageInterface, error) {
+// newJSONMessageForDML converts the row to the message, only the handle key
+// columns are included if onlyHandleKey is true. Messages containing only
+// the handle key columns always carry the TiDB extension, so that consumers
+// can tell them apart.
+func (c *JSONBatchEncoder) newJSONMessageForDML(
+	e *model.RowChangedEvent, onlyHandleKey bool,
+) (canalJSONMessageInterface, error) {
 	eventType := convertRowEventType(e)
 	header := c.builder.buildHeader(e.CommitTs, e.Table.Schema, e.Table.Table, eventType, 1)
 	rowData, err := c.builder.buildRowData(e)
 	if err != nil {
 		return nil, cerrors.WrapError(cerrors.ErrCanalEncodeFailed, err)
 	}
+	if onlyHandleKey {
+		rowData.BeforeColumns = handleKeyColumns(e.PreColumns, rowData.BeforeColumns)
+		rowData.AfterColumns = handleKeyColumns(e.Columns, rowData.AfterColumns)
+	}
 
 	pkCols := e.PrimaryKeyColumns()
 	pkNames := make([]string, len(pkCols))
@@ -132,16 +157,36 @@ func (c *JSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) (canal
 		log.Panic("unreachable event type", zap.Any("event", e))
 	}
 
-	if !c.enableTiDBExtension {
+	if !c.enableTiDBExtension && !onlyHandleKey {
 		return msg, nil
 	}
 
 	return &canalJSONMessageWithTiDBExtension{
 		canalJSONMessage: msg,
-		Extensions:       &tidbExtension{CommitTs: e.CommitTs},
+		Extensions: &tidbExtension{
+			CommitTs:      e.CommitTs,
+			OnlyHandleKey: onlyHandleKey,
+		},
 	}, nil
 }
 
+// handleKeyColumns returns the canal columns of the handle key columns.
+func handleKeyColumns(cols []*model.Column, canalCols []*canal.Column) []*canal.Column {
+	names := make(map[string]struct{})
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			names[col.Name] = struct{}{}
+		}
+	}
+	res := make([]*canal.Column, 0, len(names))
+	for _, col := range canalCols {
+		if _, ok := names[col.Name]; ok {
+			res = append(res, col)
+		}
+	}
+	return res
+}
+
 func (c *JSONBatchEncoder) newJSONMessageForDDL(e *model.DDLEvent) canalJSONMessageInterface {
 	header := c.builder.buildHeader(e.CommitTs, e.TableInfo.Schema, e.TableInfo.Table, convertDdlEventType(e), 1)
 	msg := &canalJSONMessage{
@@ -195,22 +240,115 @@ func (c *JSONBatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, er
 
 // AppendRowChangedEvent implements the interface EventJSONBatchEncoder
 func (c *JSONBatchEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	message, err := c.newJSONMessageForDML(e)
+	message, err := c.newJSONMessageForDML(e, false)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	c.messageBuf = append(c.messageBuf, message)
+	value, err := json.Marshal(message)
+	if err != nil {
+		return cerrors.WrapError(cerrors.ErrCanalEncodeFailed, err)
+	}
+
+	// for single message that longer than max-message-size, only the handle
+	// key columns are sent if the large message handling is enabled.
+	length := len(value) + common.MaxRecordOverhead
+	if c.largeMessageHandle.Enabled() && length > c.maxMessageBytes {
+		value, err = c.handleLargeMessage(ctx, e, value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Single message too large, only handle key columns are sent",
+			zap.Int("max-message-size", c.maxMessageBytes), zap.Int("length", length),
+			zap.Any("table", e.Table),
+			zap.String("option", c.largeMessageHandle.LargeMessageHandleOption))
+		length = len(value) + common.MaxRecordOverhead
+		if length > c.maxMessageBytes {
+			log.Warn("Single message too large",
+				zap.Int("max-message-size", c.maxMessageBytes), zap.Int("length", length),
+				zap.Any("table", e.Table))
+			return cerrors.ErrCanalEncodeFailed.GenWithStack(
+				"single row too large, length %d, max-message-bytes %d",
+				length, c.maxMessageBytes)
+		}
+	}
+
+	if c.batchSize != 0 && c.batchFull(len(value)) {
+		c.messageBuf = append(c.messageBuf, c.buildMessage())
+	}
+	if c.batchSize == 0 {
+		c.schema, c.table, c.commitTs = message.getSchema(), message.getTable(), e.CommitTs
+	} else {
+		c.valueBuf.WriteByte('\n')
+	}
+	c.valueBuf.Write(value)
+	c.batchSize++
 	if callback != nil {
 		c.callbackBuf = append(c.callbackBuf, callback)
 	}
 	return nil
 }
 
+// batchFull returns whether the current batch can't hold another value.
+func (c *JSONBatchEncoder) batchFull(valueBytes int) bool {
+	if c.maxBatchSize > 0 && c.batchSize >= c.maxBatchSize {
+		return true
+	}
+	length := c.valueBuf.Len() + 1 + valueBytes + common.MaxRecordOverhead
+	return c.maxMessageBytes > 0 && length > c.maxMessageBytes
+}
+
+// buildMessage builds the rows appended since last message into a message.
+func (c *JSONBatchEncoder) buildMessage() *common.Message {
+	// The buffer is reused after reset, so the bytes are copied.
+	value := append([]byte(nil), c.valueBuf.Bytes()...)
+	ret := common.NewMsg(config.ProtocolCanalJSON, nil, value, c.commitTs,
+		model.MessageTypeRow, c.schema, c.table)
+	ret.SetRowsCount(c.batchSize)
+	if len(c.callbackBuf) != 0 {
+		callbacks := c.callbackBuf
+		ret.Callback = func() {
+			for _, cb := range callbacks {
+				cb()
+			}
+		}
+		c.callbackBuf = make([]func(), 0)
+	}
+	c.valueBuf.Reset()
+	c.batchSize = 0
+	c.schema, c.table, c.commitTs = nil, nil, 0
+	return ret
+}
+
+// handleLargeMessage encodes the row exceeding max-message-bytes into a
+// message containing only the handle key columns. If claim-check is enabled,
+// the full value is written to the claim-check storage before, and its
+// location is put into the TiDB extension of the message.
+func (c *JSONBatchEncoder) handleLargeMessage(
+	ctx context.Context, e *model.RowChangedEvent, fullValue []byte,
+) ([]byte, error) {
+	message, err := c.newJSONMessageForDML(e, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.largeMessageHandle.EnableClaimCheck() {
+		location, err := common.WriteClaimCheck(ctx, c.claimCheckStorage, e, fullValue)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		message.(*canalJSONMessageWithTiDBExtension).Extensions.ClaimCheckLocation = location
+	}
+	value, err := json.Marshal(message)
+	if err != nil {
+		return nil, cerrors.WrapError(cerrors.ErrCanalEncodeFailed, err)
+	}
+	return value, nil
+}
+
 // EncodeDDLEvent encodes DDL events
 func (c *JSONBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
 	message := c.newJSONMessageForDDL(e)
@@ -222,44 +360,46 @@ func (c *JSONBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, e
 }
 
 // Build implements the EventJSONBatchEncoder interface
-func (c *JSONBatchEncoder) Build() []*common.Message {
-	if len(c.messageBuf) == 0 {
-		return nil
+func (c *JSONBatchEncoder) Build() ([]*common.Message, error) {
+	if c.batchSize != 0 {
+		c.messageBuf = append(c.messageBuf, c.buildMessage())
 	}
-	ret := make([]*common.Message, len(c.messageBuf))
-	for i, msg := range c.messageBuf {
-		value, err := json.Marshal(msg)
-		if err != nil {
-			log.Panic("JSONBatchEncoder", zap.Error(err))
-			return nil
-		}
-		m := common.NewMsg(config.ProtocolCanalJSON, nil, value, msg.getTikvTs(), model.MessageTypeRow, msg.getSchema(), msg.getTable())
-		m.IncRowsCount()
-		ret[i] = m
-	}
-	c.messageBuf = make([]canalJSONMessageInterface, 0)
-	if len(c.callbackBuf) != 0 && len(c.callbackBuf) == len(ret) {
-		for i, c := range c.callbackBuf {
-			ret[i].Callback = c
-		}
-		c.callbackBuf = make([]func(), 0)
+	if len(c.messageBuf) == 0 {
+		return nil, nil
 	}
-	return ret
+	ret := c.messageBuf
+	c.messageBuf = make([]*common.Message, 0)
+	return ret, nil
 }
 
 type jsonBatchEncoderBuilder struct {
-	config *common.Config
+	config            *common.Config
+	claimCheckStorage storage.ExternalStorage
 }
 
 // NewJSONBatchEncoderBuilder creates a canal-json batchEncoderBuilder.
-func NewJSONBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
-	return &jsonBatchEncoderBuilder{config: config}
+func NewJSONBatchEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.EncoderBuilder, error) {
+	builder := &jsonBatchEncoderBuilder{config: config}
+	if config.LargeMessageHandle.EnableClaimCheck() {
+		s, err := common.NewClaimCheckStorage(ctx, config.LargeMessageHandle.ClaimCheckStorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		builder.claimCheckStorage = s
+	}
+	return builder, nil
 }
 
 // Build a `JSONBatchEncoder`
 func (b *jsonBatchEncoderBuilder) Build() codec.EventBatchEncoder {
 	encoder := newJSONBatchEncoder()
 	encoder.(*JSONBatchEncoder).enableTiDBExtension = b.config.EnableTiDBExtension
+	encoder.(*JSONBatchEncoder).maxMessageBytes = b.config.MaxMessageBytes
+	encoder.(*JSONBatchEncoder).maxBatchSize = b.config.MaxBatchSize
+	encoder.(*JSONBatchEncoder).largeMessageHandle = b.config.LargeMessageHandle
+	encoder.(*JSONBatchEncoder).claimCheckStorage = b.claimCheckStorage
 
 	return encoder
 }
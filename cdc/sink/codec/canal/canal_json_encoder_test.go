@@ -16,8 +16,10 @@ package canal
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
@@ -47,7 +49,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	encoder := &JSONBatchEncoder{builder: newCanalEntryBuilder()}
 	require.NotNil(t, encoder)
 
-	message, err := encoder.newJSONMessageForDML(testCaseInsert)
+	message, err := encoder.newJSONMessageForDML(testCaseInsert, false)
 	require.Nil(t, err)
 	jsonMsg, ok := message.(*canalJSONMessage)
 	require.True(t, ok)
@@ -88,7 +90,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 		require.Equal(t, item.expectedEncodedValue, obtainedValue)
 	}
 
-	message, err = encoder.newJSONMessageForDML(testCaseUpdate)
+	message, err = encoder.newJSONMessageForDML(testCaseUpdate, false)
 	require.Nil(t, err)
 	jsonMsg, ok = message.(*canalJSONMessage)
 	require.True(t, ok)
@@ -96,7 +98,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	require.NotNil(t, jsonMsg.Old)
 	require.Equal(t, "UPDATE", jsonMsg.EventType)
 
-	message, err = encoder.newJSONMessageForDML(testCaseDelete)
+	message, err = encoder.newJSONMessageForDML(testCaseDelete, false)
 	require.Nil(t, err)
 	jsonMsg, ok = message.(*canalJSONMessage)
 	require.True(t, ok)
@@ -106,7 +108,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 
 	encoder = &JSONBatchEncoder{builder: newCanalEntryBuilder(), enableTiDBExtension: true}
 	require.NotNil(t, encoder)
-	message, err = encoder.newJSONMessageForDML(testCaseUpdate)
+	message, err = encoder.newJSONMessageForDML(testCaseUpdate, false)
 	require.Nil(t, err)
 
 	withExtension, ok := message.(*canalJSONMessageWithTiDBExtension)
@@ -149,7 +151,7 @@ func TestNewCanalJSONMessageFromDDL(t *testing.T) {
 
 func TestBatching(t *testing.T) {
 	t.Parallel()
-	encoder := &JSONBatchEncoder{builder: newCanalEntryBuilder()}
+	encoder := &JSONBatchEncoder{builder: newCanalEntryBuilder(), maxBatchSize: 1}
 	require.NotNil(t, encoder)
 
 	updateCase := *testCaseUpdate
@@ -160,7 +162,8 @@ func TestBatching(t *testing.T) {
 		require.Nil(t, err)
 
 		if i%100 == 0 {
-			msgs := encoder.Build()
+			msgs, err := encoder.Build()
+			require.Nil(t, err)
 			require.NotNil(t, msgs)
 			require.Len(t, msgs, 100)
 
@@ -303,7 +306,11 @@ func TestDDLEventWithExtensionValueMarshal(t *testing.T) {
 }
 
 func TestCanalJSONAppendRowChangedEventWithCallback(t *testing.T) {
-	encoder := &JSONBatchEncoder{builder: newCanalEntryBuilder(), enableTiDBExtension: true}
+	encoder := &JSONBatchEncoder{
+		builder:             newCanalEntryBuilder(),
+		enableTiDBExtension: true,
+		maxBatchSize:        2,
+	}
 	require.NotNil(t, encoder)
 
 	count := 0
@@ -355,7 +362,8 @@ func TestCanalJSONAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 
 	// Empty build makes sure that the callback build logic not broken.
-	msgs := encoder.Build()
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 0, "no message should be built and no panic")
 
 	// Append the events.
@@ -365,16 +373,145 @@ func TestCanalJSONAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 	require.Equal(t, 0, count, "nothing should be called")
 
-	msgs = encoder.Build()
-	require.Len(t, msgs, 5, "expected 5 messages")
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 3, "expected 3 messages")
 	msgs[0].Callback()
-	require.Equal(t, 1, count, "expected one callback be called")
+	require.Equal(t, 3, count, "expected 2 callbacks to be called")
 	msgs[1].Callback()
-	require.Equal(t, 3, count, "expected one callback be called")
+	require.Equal(t, 10, count, "expected 2 callbacks to be called")
 	msgs[2].Callback()
-	require.Equal(t, 6, count, "expected one callback be called")
-	msgs[3].Callback()
-	require.Equal(t, 10, count, "expected one callback be called")
-	msgs[4].Callback()
-	require.Equal(t, 15, count, "expected one callback be called")
+	require.Equal(t, 15, count, "expected one callback to be called")
+}
+
+func TestJSONBatchLimits(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte("aa"),
+		}},
+	}
+
+	cfg := common.NewConfig(config.ProtocolCanalJSON)
+	require.Equal(t, 1, cfg.MaxBatchSize)
+	cfg.MaxBatchSize = 4
+	encoder := (&jsonBatchEncoderBuilder{config: cfg}).Build()
+	count := 0
+	for i := 0; i < 10; i++ {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, func() { count++ })
+		require.Nil(t, err)
+	}
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 3)
+	for i, rows := range []int{4, 4, 2} {
+		require.Equal(t, rows, msgs[i].GetRowsCount())
+		decoder := NewBatchDecoder(msgs[i].Value, false)
+		for j := 0; j < rows; j++ {
+			tp, hasNext, err := decoder.HasNext()
+			require.Nil(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeRow, tp)
+			_, err = decoder.NextRowChangedEvent()
+			require.Nil(t, err)
+		}
+		_, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.False(t, hasNext)
+		msgs[i].Callback()
+	}
+	require.Equal(t, 10, count)
+
+	cfg.MaxBatchSize = 100
+	cfg.MaxMessageBytes = 512
+	encoder = (&jsonBatchEncoderBuilder{config: cfg}).Build()
+	for i := 0; i < 100; i++ {
+		require.Nil(t, encoder.AppendRowChangedEvent(context.Background(), "", row, nil))
+	}
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Greater(t, len(msgs), 1)
+	for _, msg := range msgs {
+		require.LessOrEqual(t, msg.Length(), 512)
+	}
+}
+
+func TestJSONLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	largeEvent := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: int64(1),
+		}, {
+			Name:  "payload",
+			Type:  mysql.TypeVarchar,
+			Value: []byte(strings.Repeat("a", 1024)),
+		}},
+	}
+	ctx := context.Background()
+	cfg := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(512)
+	cfg.EnableTiDBExtension = true
+
+	// The message is sent as is without large message handling.
+	encoder := (&jsonBatchEncoderBuilder{config: cfg}).Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.Greater(t, msgs[0].Length(), 512)
+
+	// Only the handle key columns are sent.
+	cfg.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionHandleKeyOnly,
+	}
+	encoder = (&jsonBatchEncoderBuilder{config: cfg}).Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.LessOrEqual(t, msgs[0].Length(), 512)
+	decoder := NewBatchDecoder(msgs[0].Value, true)
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Len(t, row.Columns, 1)
+	require.Equal(t, "id", row.Columns[0].Name)
+
+	// The full message is read from the claim-check storage.
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.Nil(t, err)
+	cfg.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	encoder = (&jsonBatchEncoderBuilder{config: cfg, claimCheckStorage: s}).Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.LessOrEqual(t, msgs[0].Length(), 512)
+
+	decoder = NewBatchDecoder(msgs[0].Value, true)
+	_, _, err = decoder.HasNext()
+	require.Nil(t, err)
+	_, err = decoder.NextRowChangedEvent()
+	require.Regexp(t, ".*claim-check storage is required.*", err)
+
+	decoder = NewClaimCheckBatchDecoder(ctx, msgs[0].Value, true, s)
+	_, _, err = decoder.HasNext()
+	require.Nil(t, err)
+	row, err = decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Len(t, row.Columns, 2)
+	require.Equal(t, largeEvent.CommitTs, row.CommitTs)
 }
@@ -140,6 +140,12 @@ func (c *canalJSONMessage) pkNameSet() map[string]struct{} {
 type tidbExtension struct {
 	CommitTs    uint64 `json:"commitTs,omitempty"`
 	WatermarkTs uint64 `json:"watermarkTs,omitempty"`
+	// OnlyHandleKey is true if only the handle key columns of the row are
+	// in the message, which happens when the row exceeds max-message-bytes.
+	OnlyHandleKey bool `json:"onlyHandleKey,omitempty"`
+	// ClaimCheckLocation is the location of the full message in the
+	// claim-check storage if it's not empty.
+	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
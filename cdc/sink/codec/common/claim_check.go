@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NewClaimCheckStorage creates the external storage of claim-check from the
+// URI, such as `s3://bucket/prefix` and `file:///path`.
+func NewClaimCheckStorage(ctx context.Context, uri string) (storage.ExternalStorage, error) {
+	backend, err := storage.ParseBackend(uri, &storage.BackendOptions{})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckStorage, err)
+	}
+	s, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckStorage, err)
+	}
+	return s, nil
+}
+
+// WriteClaimCheck writes the full message of the row to the claim-check
+// storage, and returns the unique location of it.
+func WriteClaimCheck(
+	ctx context.Context, s storage.ExternalStorage, e *model.RowChangedEvent, value []byte,
+) (string, error) {
+	location := fmt.Sprintf("%s/%s/%d-%s.json",
+		e.Table.Schema, e.Table.Table, e.CommitTs, uuid.NewString())
+	if err := s.WriteFile(ctx, location, value); err != nil {
+		return "", cerror.WrapError(cerror.ErrClaimCheckStorage, err)
+	}
+	return location, nil
+}
+
+// ReadClaimCheck reads the full message at the location of the claim-check
+// storage.
+func ReadClaimCheck(
+	ctx context.Context, s storage.ExternalStorage, location string,
+) ([]byte, error) {
+	if s == nil {
+		return nil, cerror.ErrClaimCheckStorage.GenWithStack(
+			"claim-check storage is required to read %s", location)
+	}
+	data, err := s.ReadFile(ctx, location)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckStorage, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/compression"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// compressedPayloadMagic is the header of the payloads compressed by encoders.
+// It never appears at the beginning of the uncompressed payloads of all the
+// protocols, so decoders can tell the compressed payloads apart from them.
+var compressedPayloadMagic = []byte{0xff, 'C', 'D', 'C'}
+
+// The compression codec flags following compressedPayloadMagic.
+const (
+	compressionFlagSnappy byte = iota + 1
+	compressionFlagLZ4
+	compressionFlagZSTD
+)
+
+// compressedPayloadHeaderLen is the length of the magic and the flag.
+var compressedPayloadHeaderLen = len(compressedPayloadMagic) + 1
+
+func compressionFlag(cc string) (byte, bool) {
+	switch strings.ToLower(cc) {
+	case compression.Snappy:
+		return compressionFlagSnappy, true
+	case compression.LZ4:
+		return compressionFlagLZ4, true
+	case compression.ZSTD:
+		return compressionFlagZSTD, true
+	default:
+		return 0, false
+	}
+}
+
+func compressionCodec(flag byte) (string, bool) {
+	switch flag {
+	case compressionFlagSnappy:
+		return compression.Snappy, true
+	case compressionFlagLZ4:
+		return compression.LZ4, true
+	case compressionFlagZSTD:
+		return compression.ZSTD, true
+	default:
+		return "", false
+	}
+}
+
+// CompressPayload compresses the payload with the compression codec and puts
+// the header flag before it. The payload is returned as is if the codec is
+// none or empty.
+func CompressPayload(cc string, payload []byte) ([]byte, error) {
+	flag, ok := compressionFlag(cc)
+	if !ok || len(payload) == 0 {
+		return payload, nil
+	}
+	compressed, err := compression.Encode(cc, payload)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res := make([]byte, 0, compressedPayloadHeaderLen+len(compressed))
+	res = append(res, compressedPayloadMagic...)
+	res = append(res, flag)
+	return append(res, compressed...), nil
+}
+
+// DecompressPayload decompresses the payload if it has the header flag of
+// compressed payloads, otherwise it's returned as is.
+func DecompressPayload(payload []byte) ([]byte, error) {
+	if len(payload) < compressedPayloadHeaderLen ||
+		!bytes.HasPrefix(payload, compressedPayloadMagic) {
+		return payload, nil
+	}
+	cc, ok := compressionCodec(payload[len(compressedPayloadMagic)])
+	if !ok {
+		return nil, cerror.ErrDecompressionFailed.GenWithStack(
+			"unknown compression flag %d", payload[len(compressedPayloadMagic)])
+	}
+	return compression.Decode(cc, payload[compressedPayloadHeaderLen:])
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressPayload(t *testing.T) {
+	t.Parallel()
+
+	payload := bytes.Repeat([]byte(`{"id":1,"name":"tidb"}`), 100)
+	for _, cc := range []string{compression.Snappy, compression.LZ4, compression.ZSTD} {
+		compressed, err := CompressPayload(cc, payload)
+		require.Nil(t, err)
+		require.True(t, bytes.HasPrefix(compressed, compressedPayloadMagic))
+		require.Less(t, len(compressed), len(payload))
+		decompressed, err := DecompressPayload(compressed)
+		require.Nil(t, err)
+		require.Equal(t, payload, decompressed)
+	}
+
+	// Uncompressed payloads are returned as is.
+	compressed, err := CompressPayload(compression.None, payload)
+	require.Nil(t, err)
+	require.Equal(t, payload, compressed)
+	decompressed, err := DecompressPayload(payload)
+	require.Nil(t, err)
+	require.Equal(t, payload, decompressed)
+
+	_, err = DecompressPayload(append(compressedPayloadMagic, 0xff, 0x01))
+	require.Regexp(t, ".*unknown compression flag 255.*", err)
+}
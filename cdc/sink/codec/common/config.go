@@ -16,8 +16,10 @@ package common
 import (
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -25,14 +27,26 @@ import (
 // defaultMaxBatchSize sets the default value for max-batch-size
 const defaultMaxBatchSize int = 16
 
+// defaultCanalJSONMaxBatchSize sets the default value for max-batch-size of
+// canal-json, consumers expect one row per message unless they opt in.
+const defaultCanalJSONMaxBatchSize int = 1
+
 // Config use to create the encoder
 type Config struct {
 	Protocol config.Protocol
 
-	// control batch behavior, only for `open-protocol` and `craft` at the moment.
+	// control batch behavior, for `open-protocol`, `craft`, `canal`,
+	// `canal-json` and `maxwell`, which can carry multiple rows in a message.
 	MaxMessageBytes int
 	MaxBatchSize    int
 
+	// PayloadCompression compresses the payloads of row messages inside the
+	// messages, it's independent of the compression of the producer. It's
+	// supported by `open-protocol`, `craft` and `canal-json`, whose decoders
+	// recognize the header flag of compressed payloads. avro
+	// and canal are not supported, since their decoders can't decompress.
+	PayloadCompression string
+
 	// canal-json only
 	EnableTiDBExtension bool
 
@@ -40,15 +54,24 @@ type Config struct {
 	AvroSchemaRegistry             string
 	AvroDecimalHandlingMode        string
 	AvroBigintUnsignedHandlingMode string
+
+	// open-protocol and canal-json only
+	LargeMessageHandle *config.LargeMessageHandleConfig
 }
 
 // NewConfig return a Config for codec
 func NewConfig(protocol config.Protocol) *Config {
+	maxBatchSize := defaultMaxBatchSize
+	if protocol == config.ProtocolCanalJSON {
+		maxBatchSize = defaultCanalJSONMaxBatchSize
+	}
 	return &Config{
 		Protocol: protocol,
 
 		MaxMessageBytes: config.DefaultMaxMessageBytes,
-		MaxBatchSize:    defaultMaxBatchSize,
+		MaxBatchSize:    maxBatchSize,
+
+		PayloadCompression: compression.None,
 
 		EnableTiDBExtension:            false,
 		AvroSchemaRegistry:             "",
@@ -64,6 +87,7 @@ const (
 	codecOPTAvroDecimalHandlingMode        = "avro-decimal-handling-mode"
 	codecOPTAvroBigintUnsignedHandlingMode = "avro-bigint-unsigned-handling-mode"
 	codecOPTAvroSchemaRegistry             = "schema-registry"
+	codecOPTPayloadCompression             = "payload-compression"
 )
 
 const (
@@ -104,6 +128,10 @@ func (c *Config) Apply(sinkURI *url.URL, config *config.ReplicaConfig) error {
 		c.MaxMessageBytes = a
 	}
 
+	if s := params.Get(codecOPTPayloadCompression); s != "" {
+		c.PayloadCompression = strings.ToLower(s)
+	}
+
 	if s := params.Get(codecOPTAvroDecimalHandlingMode); s != "" {
 		c.AvroDecimalHandlingMode = s
 	}
@@ -116,6 +144,10 @@ func (c *Config) Apply(sinkURI *url.URL, config *config.ReplicaConfig) error {
 		c.AvroSchemaRegistry = config.Sink.SchemaRegistry
 	}
 
+	if config.Sink != nil && config.Sink.LargeMessageHandle != nil {
+		c.LargeMessageHandle = config.Sink.LargeMessageHandle
+	}
+
 	return nil
 }
 
@@ -134,6 +166,27 @@ func (c *Config) Validate() error {
 		)
 	}
 
+	if c.LargeMessageHandle.Enabled() {
+		switch c.Protocol {
+		case config.ProtocolOpen:
+		case config.ProtocolCanalJSON:
+			// Consumers tell the messages of large rows apart by the TiDB
+			// extension.
+			if !c.EnableTiDBExtension {
+				return cerror.ErrCodecInvalidConfig.GenWithStack(
+					`large-message-handle-option %s requires %s for canal-json protocol`,
+					c.LargeMessageHandle.LargeMessageHandleOption,
+					codecOPTEnableTiDBExtension,
+				)
+			}
+		default:
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`large-message-handle-option %s only supports open-protocol/canal-json protocol`,
+				c.LargeMessageHandle.LargeMessageHandleOption,
+			)
+		}
+	}
+
 	if c.Protocol == config.ProtocolAvro {
 		if c.AvroSchemaRegistry == "" {
 			return cerror.ErrCodecInvalidConfig.GenWithStack(
@@ -163,6 +216,34 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if !compression.Supported(c.PayloadCompression) {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`%s value could only be "%s", "%s", "%s" or "%s"`,
+			codecOPTPayloadCompression,
+			compression.None,
+			compression.Snappy,
+			compression.LZ4,
+			compression.ZSTD,
+		)
+	}
+
+	// The payloads compressed with the header flag can only be decoded by
+	// the decoders of TiCDC, so the protocols consumed by third-party clients
+	// don't support it:
+	// - avro messages are decoded with the schemas in the schema registry.
+	// - canal messages are consumed by canal clients, which reject packets
+	//   with a compression other than none.
+	// - maxwell messages are consumed by maxwell clients.
+	if c.PayloadCompression != compression.None &&
+		(c.Protocol == config.ProtocolAvro ||
+			c.Protocol == config.ProtocolCanal ||
+			c.Protocol == config.ProtocolMaxwell) {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`%s is not supported for %s protocol`,
+			codecOPTPayloadCompression, c.Protocol,
+		)
+	}
+
 	if c.MaxMessageBytes <= 0 {
 		return cerror.ErrCodecInvalidConfig.Wrap(
 			errors.Errorf("invalid max-message-bytes %d", c.MaxMessageBytes),
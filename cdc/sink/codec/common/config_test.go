@@ -32,6 +32,10 @@ func TestNewConfig(t *testing.T) {
 	require.Equal(t, "precise", c.AvroDecimalHandlingMode)
 	require.Equal(t, "long", c.AvroBigintUnsignedHandlingMode)
 	require.Equal(t, "", c.AvroSchemaRegistry)
+	require.Equal(t, "none", c.PayloadCompression)
+
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.Equal(t, defaultCanalJSONMaxBatchSize, c.MaxBatchSize)
 }
 
 func TestConfigApplyValidate(t *testing.T) {
@@ -205,3 +209,68 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.ErrorContains(t, err, "invalid max-batch-size -1")
 }
+
+func TestConfigLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionHandleKeyOnly,
+	}
+
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "requires enable-tidb-extension")
+
+	c = NewConfig(config.ProtocolMaxwell)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "only supports open-protocol/canal-json")
+
+	c = NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+	require.True(t, c.LargeMessageHandle.Enabled())
+
+	sinkURI, err = url.Parse(
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json&enable-tidb-extension=true")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+}
+
+func TestConfigPayloadCompression(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?payload-compression=ZSTD")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.Equal(t, "zstd", c.PayloadCompression)
+	require.NoError(t, c.Validate())
+
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?payload-compression=gzip")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "payload-compression value could only be")
+
+	// avro, canal and maxwell messages are consumed by third-party clients.
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?payload-compression=lz4")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolCanal)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "payload-compression is not supported for canal protocol")
+
+	c = NewConfig(config.ProtocolMaxwell)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "payload-compression is not supported for maxwell protocol")
+
+	replicaConfig.Sink.SchemaRegistry = "some-schema-registry"
+	c = NewConfig(config.ProtocolAvro)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "payload-compression is not supported for avro protocol")
+}
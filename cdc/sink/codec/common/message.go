@@ -40,6 +40,10 @@ type Message struct {
 	Protocol  config.Protocol   // protocol
 	rowsCount int               // rows in one Message
 	Callback  func()            // Callback function will be called when the message is sent to the sink.
+	// OnAck is called with the timestamp of the message when it's acked by
+	// the MQ. The timestamp is assigned by the broker if it's supported,
+	// e.g. a Kafka topic with `LogAppendTime`, otherwise it's the ack time.
+	OnAck func(timestamp time.Time)
 }
 
 // Length returns the expected size of the Kafka message
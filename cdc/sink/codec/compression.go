@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/compression"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// compressionEncoder compresses the payloads of the row messages built by
+// the wrapped encoder. DDL and checkpoint messages are small, so they are
+// left uncompressed.
+type compressionEncoder struct {
+	EventBatchEncoder
+	cc              string
+	maxMessageBytes int
+}
+
+// Build implements the EventBatchEncoder interface
+func (e *compressionEncoder) Build() ([]*common.Message, error) {
+	messages, err := e.EventBatchEncoder.Build()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, msg := range messages {
+		value, err := common.CompressPayload(e.cc, msg.Value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		msg.Value = value
+		// The wrapped encoder limits the uncompressed messages, but the
+		// compressed ones can still be larger if the payload can't be
+		// compressed, because of the header.
+		if msg.Length() > e.maxMessageBytes {
+			log.Warn("Compressed message too large",
+				zap.Int("maxMessageBytes", e.maxMessageBytes),
+				zap.Int("length", msg.Length()),
+				zap.String("compression", e.cc))
+			return nil, cerror.ErrCompressedMessageTooLarge.GenWithStackByArgs(
+				msg.Length(), e.maxMessageBytes)
+		}
+	}
+	return messages, nil
+}
+
+type compressionEncoderBuilder struct {
+	builder         EncoderBuilder
+	cc              string
+	maxMessageBytes int
+}
+
+// Build implements the EncoderBuilder interface
+func (b *compressionEncoderBuilder) Build() EventBatchEncoder {
+	return &compressionEncoder{
+		EventBatchEncoder: b.builder.Build(),
+		cc:                b.cc,
+		maxMessageBytes:   b.maxMessageBytes,
+	}
+}
+
+// NewCompressionEncoderBuilder wraps the builder to compress the payloads of
+// the row messages with the payload compression codec of the config, the
+// compressed messages are checked against the max-message-bytes. The builder
+// is returned as is if the codec is none.
+func NewCompressionEncoderBuilder(builder EncoderBuilder, c *common.Config) EncoderBuilder {
+	cc := strings.ToLower(c.PayloadCompression)
+	if cc == "" || cc == compression.None {
+		return builder
+	}
+	return &compressionEncoderBuilder{
+		builder:         builder,
+		cc:              cc,
+		maxMessageBytes: c.MaxMessageBytes,
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// valueEncoder builds a row message with the value.
+type valueEncoder struct {
+	EventBatchEncoder
+	value []byte
+}
+
+func (e *valueEncoder) Build() ([]*common.Message, error) {
+	return []*common.Message{common.NewMsg(config.ProtocolOpen,
+		nil, e.value, 0, model.MessageTypeRow, nil, nil)}, nil
+}
+
+type valueEncoderBuilder struct {
+	value []byte
+}
+
+func (b *valueEncoderBuilder) Build() EventBatchEncoder {
+	return &valueEncoder{value: b.value}
+}
+
+func TestCompressionEncoderMaxMessageBytes(t *testing.T) {
+	t.Parallel()
+
+	cfg := common.NewConfig(config.ProtocolOpen)
+	cfg.PayloadCompression = compression.LZ4
+	cfg.MaxMessageBytes = 1024 + common.MaxRecordOverhead
+
+	// The compressed message fits in the limit.
+	value := bytes.Repeat([]byte("a"), 1024)
+	messages, err := NewCompressionEncoderBuilder(
+		&valueEncoderBuilder{value: value}, cfg).Build().Build()
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	decompressed, err := common.DecompressPayload(messages[0].Value)
+	require.Nil(t, err)
+	require.Equal(t, value, decompressed)
+
+	// Random bytes can't be compressed, the compressed message is larger
+	// than the limit because of the header.
+	value = make([]byte, 1024)
+	rand.New(rand.NewSource(1)).Read(value)
+	_, err = NewCompressionEncoderBuilder(
+		&valueEncoderBuilder{value: value}, cfg).Build().Build()
+	require.True(t, cerror.ErrCompressedMessageTooLarge.Equal(err))
+
+	// The builder is returned as is without compression.
+	cfg.PayloadCompression = compression.None
+	builder := &valueEncoderBuilder{value: value}
+	require.Same(t, builder, NewCompressionEncoderBuilder(builder, cfg))
+}
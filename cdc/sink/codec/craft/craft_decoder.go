@@ -17,6 +17,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -125,6 +126,10 @@ func newBatchDecoder(bits []byte) (codec.EventBatchDecoder, error) {
 func NewBatchDecoderWithAllocator(
 	bits []byte, allocator *SliceAllocator,
 ) (codec.EventBatchDecoder, error) {
+	bits, err := common.DecompressPayload(bits)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	decoder, err := NewMessageDecoder(bits, allocator)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -66,14 +66,14 @@ func (e *BatchEncoder) EncodeDDLEvent(ev *model.DDLEvent) (*common.Message, erro
 }
 
 // Build implements the EventBatchEncoder interface
-func (e *BatchEncoder) Build() []*common.Message {
+func (e *BatchEncoder) Build() ([]*common.Message, error) {
 	if e.rowChangedBuffer.Size() > 0 {
 		// flush buffered data to message buffer
 		e.flush()
 	}
 	ret := e.messageBuf
 	e.messageBuf = make([]*common.Message, 0, 2)
-	return ret
+	return ret, nil
 }
 
 func (e *BatchEncoder) flush() {
@@ -277,7 +277,8 @@ func (s *BatchTester) TestBatchCodec(
 		}
 
 		if len(cs) > 0 {
-			res := encoder.Build()
+			res, err := encoder.Build()
+			require.Nil(t, err)
 			require.Len(t, res, 1)
 			require.Equal(t, len(cs), res[0].GetRowsCount())
 			decoder, err := newDecoder(res[0].Key, res[0].Value)
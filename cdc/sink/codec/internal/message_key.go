@@ -28,6 +28,12 @@ type MessageKey struct {
 	RowID     int64             `json:"rid,omitempty"`
 	Partition *int64            `json:"ptn,omitempty"`
 	Type      model.MessageType `json:"t"`
+	// OnlyHandleKey is true if only the handle key columns of the row are
+	// in the message, which happens when the row exceeds max-message-bytes.
+	OnlyHandleKey bool `json:"ohk,omitempty"`
+	// ClaimCheckLocation is the location of the full message in the
+	// claim-check storage if it's not empty.
+	ClaimCheckLocation string `json:"ccl,omitempty"`
 }
 
 // Encode encodes the message key to a byte slice.
@@ -31,6 +31,13 @@ type BatchEncoder struct {
 	valueBuf    *bytes.Buffer
 	callbackBuf []func()
 	batchSize   int
+
+	// messageBuf holds the batches which have reached the batch limits.
+	messageBuf []*common.Message
+
+	// configs, zero means unlimited.
+	MaxMessageBytes int
+	MaxBatchSize    int
 }
 
 // EncodeCheckpointEvent implements the EventBatchEncoder interface
@@ -52,6 +59,9 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if d.batchSize != 0 && d.batchFull(len(value)) {
+		d.messageBuf = append(d.messageBuf, d.buildMessage())
+	}
 	d.valueBuf.Write(value)
 	d.batchSize++
 	if callback != nil {
@@ -76,14 +86,32 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	return common.NewDDLMsg(config.ProtocolMaxwell, key, value, e), nil
 }
 
+// batchFull returns whether the current batch can't hold another value.
+func (d *BatchEncoder) batchFull(valueBytes int) bool {
+	if d.MaxBatchSize > 0 && d.batchSize >= d.MaxBatchSize {
+		return true
+	}
+	length := d.keyBuf.Len() + d.valueBuf.Len() + valueBytes + common.MaxRecordOverhead
+	return d.MaxMessageBytes > 0 && length > d.MaxMessageBytes
+}
+
 // Build implements the EventBatchEncoder interface
-func (d *BatchEncoder) Build() []*common.Message {
-	if d.batchSize == 0 {
-		return nil
+func (d *BatchEncoder) Build() ([]*common.Message, error) {
+	if d.batchSize != 0 {
+		d.messageBuf = append(d.messageBuf, d.buildMessage())
 	}
+	ret := d.messageBuf
+	d.messageBuf = nil
+	return ret, nil
+}
 
+// buildMessage builds the values appended since last message into a message.
+func (d *BatchEncoder) buildMessage() *common.Message {
+	// The buffers are reused after reset, so the bytes are copied.
+	key := append([]byte(nil), d.keyBuf.Bytes()...)
+	value := append([]byte(nil), d.valueBuf.Bytes()...)
 	ret := common.NewMsg(config.ProtocolMaxwell,
-		d.keyBuf.Bytes(), d.valueBuf.Bytes(), 0, model.MessageTypeRow, nil, nil)
+		key, value, 0, model.MessageTypeRow, nil, nil)
 	ret.SetRowsCount(d.batchSize)
 	if len(d.callbackBuf) != 0 && len(d.callbackBuf) == d.batchSize {
 		callbacks := d.callbackBuf
@@ -95,7 +123,7 @@ func (d *BatchEncoder) Build() []*common.Message {
 		d.callbackBuf = make([]func(), 0)
 	}
 	d.reset()
-	return []*common.Message{ret}
+	return ret
 }
 
 // reset implements the EventBatchEncoder interface
@@ -119,14 +147,20 @@ func newBatchEncoder() codec.EventBatchEncoder {
 	return batch
 }
 
-type batchEncoderBuilder struct{}
+type batchEncoderBuilder struct {
+	config *common.Config
+}
 
 // NewBatchEncoderBuilder creates a maxwell batchEncoderBuilder.
-func NewBatchEncoderBuilder() codec.EncoderBuilder {
-	return &batchEncoderBuilder{}
+func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
+	return &batchEncoderBuilder{config: config}
 }
 
 // Build a `maxwellBatchEncoder`
 func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
-	return newBatchEncoder()
+	encoder := newBatchEncoder()
+	encoder.(*BatchEncoder).MaxMessageBytes = b.config.MaxMessageBytes
+	encoder.(*BatchEncoder).MaxBatchSize = b.config.MaxBatchSize
+
+	return encoder
 }
@@ -19,6 +19,8 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,7 +39,8 @@ func TestMaxwellBatchCodec(t *testing.T) {
 			err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
 			require.Nil(t, err)
 		}
-		messages := encoder.Build()
+		messages, err := encoder.Build()
+		require.Nil(t, err)
 		if len(cs) == 0 {
 			require.Nil(t, messages)
 			continue
@@ -117,7 +120,8 @@ func TestMaxwellAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 
 	// Empty build makes sure that the callback build logic not broken.
-	msgs := encoder.Build()
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 0, "no message should be built and no panic")
 
 	// Append the events.
@@ -127,8 +131,49 @@ func TestMaxwellAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 	require.Equal(t, 0, count, "nothing should be called")
 
-	msgs = encoder.Build()
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 1, "expected one message")
 	msgs[0].Callback()
 	require.Equal(t, 15, count, "expected all callbacks to be called")
 }
+
+func TestMaxwellBatchLimits(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 3, Value: 10}},
+	}
+	cfg := common.NewConfig(config.ProtocolMaxwell)
+	cfg.MaxBatchSize = 4
+	encoder := NewBatchEncoderBuilder(cfg).Build()
+	for i := 0; i < 10; i++ {
+		require.Nil(t, encoder.AppendRowChangedEvent(context.Background(), "", row, nil))
+	}
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 3)
+	require.Equal(t, 4, msgs[0].GetRowsCount())
+	require.Equal(t, 4, msgs[1].GetRowsCount())
+	require.Equal(t, 2, msgs[2].GetRowsCount())
+	// Messages built in a batch don't share the buffers.
+	require.Equal(t, msgs[0].Value, msgs[1].Value)
+
+	cfg.MaxBatchSize = 1024
+	cfg.MaxMessageBytes = 256
+	encoder = NewBatchEncoderBuilder(cfg).Build()
+	for i := 0; i < 100; i++ {
+		require.Nil(t, encoder.AppendRowChangedEvent(context.Background(), "", row, nil))
+	}
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Greater(t, len(msgs), 1)
+	rows := 0
+	for _, msg := range msgs {
+		require.LessOrEqual(t, msg.Length(), 256)
+		rows += msg.GetRowsCount()
+	}
+	require.Equal(t, 100, rows)
+}
@@ -14,17 +14,46 @@
 package open
 
 import (
+	"context"
 	"encoding/binary"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
+// claimCheckReader resolves the rows offloaded to the claim-check storage.
+type claimCheckReader struct {
+	ctx     context.Context
+	storage storage.ExternalStorage
+}
+
+// decodeRow decodes the row message, the full message is read from the
+// claim-check storage if the key refers to it.
+func (r *claimCheckReader) decodeRow(
+	key *internal.MessageKey, value []byte,
+) (*model.RowChangedEvent, error) {
+	if key.ClaimCheckLocation != "" {
+		data, err := common.ReadClaimCheck(r.ctx, r.storage, key.ClaimCheckLocation)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		value = data
+	}
+	rowMsg := new(messageRow)
+	if err := rowMsg.decode(value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return msgToRowChange(key, rowMsg), nil
+}
+
 // BatchMixedDecoder decodes the byte of a batch into the original messages.
 type BatchMixedDecoder struct {
+	claimCheckReader
 	mixedBytes []byte
 	nextKey    *internal.MessageKey
 	nextKeyLen uint64
@@ -73,11 +102,10 @@ func (b *BatchMixedDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error
 	valueLen := binary.BigEndian.Uint64(b.mixedBytes[:8])
 	value := b.mixedBytes[8 : valueLen+8]
 	b.mixedBytes = b.mixedBytes[valueLen+8:]
-	rowMsg := new(messageRow)
-	if err := rowMsg.decode(value); err != nil {
+	rowEvent, err := b.decodeRow(b.nextKey, value)
+	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	rowEvent := msgToRowChange(b.nextKey, rowMsg)
 	b.nextKey = nil
 	return rowEvent, nil
 }
@@ -125,6 +153,7 @@ func (b *BatchMixedDecoder) decodeNextKey() error {
 
 // BatchDecoder decodes the byte of a batch into the original messages.
 type BatchDecoder struct {
+	claimCheckReader
 	keyBytes   []byte
 	valueBytes []byte
 	nextKey    *internal.MessageKey
@@ -174,11 +203,10 @@ func (b *BatchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 	valueLen := binary.BigEndian.Uint64(b.valueBytes[:8])
 	value := b.valueBytes[8 : valueLen+8]
 	b.valueBytes = b.valueBytes[valueLen+8:]
-	rowMsg := new(messageRow)
-	if err := rowMsg.decode(value); err != nil {
+	rowEvent, err := b.decodeRow(b.nextKey, value)
+	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	rowEvent := msgToRowChange(b.nextKey, rowMsg)
 	b.nextKey = nil
 	return rowEvent, nil
 }
@@ -225,19 +253,34 @@ func (b *BatchDecoder) decodeNextKey() error {
 
 // NewBatchDecoder creates a new BatchDecoder.
 func NewBatchDecoder(key []byte, value []byte) (codec.EventBatchDecoder, error) {
+	return NewClaimCheckBatchDecoder(context.Background(), key, value, nil)
+}
+
+// NewClaimCheckBatchDecoder creates a new BatchDecoder, which reads the full
+// messages of rows from the claim-check storage transparently.
+func NewClaimCheckBatchDecoder(
+	ctx context.Context, key []byte, value []byte, claimCheckStorage storage.ExternalStorage,
+) (codec.EventBatchDecoder, error) {
 	version := binary.BigEndian.Uint64(key[:8])
 	key = key[8:]
 	if version != codec.BatchVersion1 {
 		return nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("unexpected key format version")
 	}
+	value, err := common.DecompressPayload(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reader := claimCheckReader{ctx: ctx, storage: claimCheckStorage}
 	// if only decode one byte slice, we choose MixedDecoder
 	if len(key) > 0 && len(value) == 0 {
 		return &BatchMixedDecoder{
-			mixedBytes: key,
+			claimCheckReader: reader,
+			mixedBytes:       key,
 		}, nil
 	}
 	return &BatchDecoder{
-		keyBytes:   key,
-		valueBytes: value,
+		claimCheckReader: reader,
+		keyBytes:         key,
+		valueBytes:       value,
 	}, nil
 }
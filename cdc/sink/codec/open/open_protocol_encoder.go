@@ -20,10 +20,11 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
-
+	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
@@ -38,39 +39,67 @@ type BatchEncoder struct {
 	// configs
 	MaxMessageBytes int
 	MaxBatchSize    int
+
+	largeMessageHandle *config.LargeMessageHandleConfig
+	claimCheckStorage  storage.ExternalStorage
+}
+
+func encodeRowMsg(keyMsg *internal.MessageKey, valueMsg *messageRow) ([]byte, []byte, error) {
+	key, err := keyMsg.Encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	value, err := valueMsg.encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return key, value, nil
+}
+
+// rowMessageLength returns the length of a batch containing only one row.
+// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
+func rowMessageLength(key, value []byte) int {
+	return len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
 }
 
 // AppendRowChangedEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	keyMsg, valueMsg := rowChangeToMsg(e)
-	key, err := keyMsg.Encode()
+	keyMsg, valueMsg := rowChangeToMsg(e, false)
+	key, value, err := encodeRowMsg(keyMsg, valueMsg)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	value, err := valueMsg.encode()
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	var keyLenByte [8]byte
-	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
-	var valueLenByte [8]byte
-	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
 
-	// for single message that longer than max-message-size, do not send it.
-	// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
-	length := len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
+	// for single message that longer than max-message-size, do not send it
+	// unless the large message handling is enabled.
+	length := rowMessageLength(key, value)
+	if length > d.MaxMessageBytes && d.largeMessageHandle.Enabled() {
+		key, value, err = d.handleLargeMessage(ctx, e, value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Single message too large, only handle key columns are sent",
+			zap.Int("max-message-size", d.MaxMessageBytes), zap.Int("length", length),
+			zap.Any("table", e.Table),
+			zap.String("option", d.largeMessageHandle.LargeMessageHandleOption))
+		length = rowMessageLength(key, value)
+	}
 	if length > d.MaxMessageBytes {
 		log.Warn("Single message too large",
 			zap.Int("max-message-size", d.MaxMessageBytes), zap.Int("length", length), zap.Any("table", e.Table))
 		return cerror.ErrOpenProtocolCodecRowTooLarge.GenWithStackByArgs()
 	}
 
+	var keyLenByte [8]byte
+	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
+	var valueLenByte [8]byte
+	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
+
 	if len(d.messageBuf) == 0 ||
 		d.curBatchSize >= d.MaxBatchSize ||
 		d.messageBuf[len(d.messageBuf)-1].Length()+len(key)+len(value)+16 > d.MaxMessageBytes {
@@ -101,6 +130,24 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	return nil
 }
 
+// handleLargeMessage encodes the row exceeding max-message-bytes into a
+// message containing only the handle key columns. If claim-check is enabled,
+// the full value is written to the claim-check storage before, and its
+// location is put into the key of the message.
+func (d *BatchEncoder) handleLargeMessage(
+	ctx context.Context, e *model.RowChangedEvent, fullValue []byte,
+) ([]byte, []byte, error) {
+	keyMsg, valueMsg := rowChangeToMsg(e, true)
+	if d.largeMessageHandle.EnableClaimCheck() {
+		location, err := common.WriteClaimCheck(ctx, d.claimCheckStorage, e, fullValue)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		keyMsg.ClaimCheckLocation = location
+	}
+	return encodeRowMsg(keyMsg, valueMsg)
+}
+
 // EncodeDDLEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
 	keyMsg, valueMsg := ddlEventToMsg(e)
@@ -161,11 +208,11 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 }
 
 // Build implements the EventBatchEncoder interface
-func (d *BatchEncoder) Build() (messages []*common.Message) {
+func (d *BatchEncoder) Build() ([]*common.Message, error) {
 	d.tryBuildCallback()
 	ret := d.messageBuf
 	d.messageBuf = make([]*common.Message, 0)
-	return ret
+	return ret, nil
 }
 
 // tryBuildCallback will collect all the callbacks into one message's callback.
@@ -183,7 +230,8 @@ func (d *BatchEncoder) tryBuildCallback() {
 }
 
 type batchEncoderBuilder struct {
-	config *common.Config
+	config            *common.Config
+	claimCheckStorage storage.ExternalStorage
 }
 
 // Build a BatchEncoder
@@ -191,13 +239,25 @@ func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
 	encoder := NewBatchEncoder()
 	encoder.(*BatchEncoder).MaxMessageBytes = b.config.MaxMessageBytes
 	encoder.(*BatchEncoder).MaxBatchSize = b.config.MaxBatchSize
+	encoder.(*BatchEncoder).largeMessageHandle = b.config.LargeMessageHandle
+	encoder.(*BatchEncoder).claimCheckStorage = b.claimCheckStorage
 
 	return encoder
 }
 
 // NewBatchEncoderBuilder creates an open-protocol batchEncoderBuilder.
-func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
-	return &batchEncoderBuilder{config: config}
+func NewBatchEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.EncoderBuilder, error) {
+	builder := &batchEncoderBuilder{config: config}
+	if config.LargeMessageHandle.EnableClaimCheck() {
+		s, err := common.NewClaimCheckStorage(ctx, config.LargeMessageHandle.ClaimCheckStorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		builder.claimCheckStorage = s
+	}
+	return builder, nil
 }
 
 // NewBatchEncoder creates a new BatchEncoder.
@@ -15,13 +15,16 @@ package open
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,25 +56,26 @@ func TestMaxMessageBytes(t *testing.T) {
 	// for a single message, the overhead is 36(maxRecordOverhead) + 8(versionHea) = 44, just can hold it.
 	a := 88 + 44
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(a)
-	encoder := NewBatchEncoderBuilder(config).Build()
+	encoder := (&batchEncoderBuilder{config: config}).Build()
 	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.Nil(t, err)
 
 	// cannot hold a single message
 	config = config.WithMaxMessageBytes(a - 1)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = (&batchEncoderBuilder{config: config}).Build()
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 
 	// make sure each batch's `Length` not greater than `max-message-bytes`
 	config = config.WithMaxMessageBytes(256)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = (&batchEncoderBuilder{config: config}).Build()
 	for i := 0; i < 10000; i++ {
 		err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 		require.Nil(t, err)
 	}
 
-	messages := encoder.Build()
+	messages, err := encoder.Build()
+	require.Nil(t, err)
 	for _, msg := range messages {
 		require.LessOrEqual(t, msg.Length(), 256)
 	}
@@ -81,7 +85,7 @@ func TestMaxBatchSize(t *testing.T) {
 	t.Parallel()
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1048576)
 	config.MaxBatchSize = 64
-	encoder := NewBatchEncoderBuilder(config).Build()
+	encoder := (&batchEncoderBuilder{config: config}).Build()
 
 	testEvent := &model.RowChangedEvent{
 		CommitTs: 1,
@@ -98,7 +102,8 @@ func TestMaxBatchSize(t *testing.T) {
 		require.Nil(t, err)
 	}
 
-	messages := encoder.Build()
+	messages, err := encoder.Build()
+	require.Nil(t, err)
 	sum := 0
 	for _, msg := range messages {
 		decoder, err := NewBatchDecoder(msg.Key, msg.Value)
@@ -182,7 +187,8 @@ func TestOpenProtocolAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 
 	// Empty build makes sure that the callback build logic not broken.
-	msgs := encoder.Build()
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 0, "no message should be built and no panic")
 
 	// Append the events.
@@ -192,7 +198,8 @@ func TestOpenProtocolAppendRowChangedEventWithCallback(t *testing.T) {
 	}
 	require.Equal(t, 0, count, "nothing should be called")
 
-	msgs = encoder.Build()
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
 	require.Len(t, msgs, 3, "expected 3 messages")
 	msgs[0].Callback()
 	require.Equal(t, 3, count, "expected 2 callbacks be called")
@@ -206,5 +213,83 @@ func TestOpenProtocolBatchCodec(t *testing.T) {
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(8192)
 	config.MaxBatchSize = 64
 	tester := internal.NewDefaultBatchTester()
-	tester.TestBatchCodec(t, NewBatchEncoderBuilder(config), NewBatchDecoder)
+	tester.TestBatchCodec(t, &batchEncoderBuilder{config: config}, NewBatchDecoder)
+}
+
+func TestLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	largeEvent := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: int64(1),
+		}, {
+			Name:  "payload",
+			Type:  mysql.TypeBlob,
+			Value: []byte(strings.Repeat("a", 1024)),
+		}},
+	}
+	ctx := context.Background()
+	cfg := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(512)
+
+	// The changefeed fails without large message handling.
+	encoder := (&batchEncoderBuilder{config: cfg}).Build()
+	err := encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil)
+	require.True(t, cerror.ErrOpenProtocolCodecRowTooLarge.Equal(err))
+
+	// Only the handle key columns are sent.
+	cfg.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionHandleKeyOnly,
+	}
+	encoder = (&batchEncoderBuilder{config: cfg}).Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	msgs, err := encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.LessOrEqual(t, msgs[0].Length(), 512)
+	decoder, err := NewBatchDecoder(msgs[0].Key, msgs[0].Value)
+	require.Nil(t, err)
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Len(t, row.Columns, 1)
+	require.Equal(t, "id", row.Columns[0].Name)
+
+	// The full message is read from the claim-check storage.
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.Nil(t, err)
+	cfg.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	encoder = (&batchEncoderBuilder{config: cfg, claimCheckStorage: s}).Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	msgs, err = encoder.Build()
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.LessOrEqual(t, msgs[0].Length(), 512)
+
+	decoder, err = NewBatchDecoder(msgs[0].Key, msgs[0].Value)
+	require.Nil(t, err)
+	_, _, err = decoder.HasNext()
+	require.Nil(t, err)
+	_, err = decoder.NextRowChangedEvent()
+	require.Regexp(t, ".*claim-check storage is required.*", err)
+
+	decoder, err = NewClaimCheckBatchDecoder(ctx, msgs[0].Key, msgs[0].Value, s)
+	require.Nil(t, err)
+	_, _, err = decoder.HasNext()
+	require.Nil(t, err)
+	row, err = decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Len(t, row.Columns, 2)
+	for _, col := range row.Columns {
+		if col.Name == "payload" {
+			require.Equal(t, largeEvent.Columns[1].Value, col.Value)
+		}
+	}
 }
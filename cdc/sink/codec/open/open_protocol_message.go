@@ -76,25 +76,30 @@ func newResolvedMessage(ts uint64) *internal.MessageKey {
 	}
 }
 
-func rowChangeToMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow) {
+// rowChangeToMsg converts the row to the message, only the handle key columns
+// are included if onlyHandleKey is true.
+func rowChangeToMsg(
+	e *model.RowChangedEvent, onlyHandleKey bool,
+) (*internal.MessageKey, *messageRow) {
 	var partition *int64
 	if e.Table.IsPartition {
 		partition = &e.Table.TableID
 	}
 	key := &internal.MessageKey{
-		Ts:        e.CommitTs,
-		Schema:    e.Table.Schema,
-		Table:     e.Table.Table,
-		RowID:     e.RowID,
-		Partition: partition,
-		Type:      model.MessageTypeRow,
+		Ts:            e.CommitTs,
+		Schema:        e.Table.Schema,
+		Table:         e.Table.Table,
+		RowID:         e.RowID,
+		Partition:     partition,
+		Type:          model.MessageTypeRow,
+		OnlyHandleKey: onlyHandleKey,
 	}
 	value := &messageRow{}
 	if e.IsDelete() {
-		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns)
+		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns, onlyHandleKey)
 	} else {
-		value.Update = rowChangeColumns2CodecColumns(e.Columns)
-		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns)
+		value.Update = rowChangeColumns2CodecColumns(e.Columns, onlyHandleKey)
+		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns, onlyHandleKey)
 	}
 	return key, value
 }
@@ -123,12 +128,17 @@ func msgToRowChange(key *internal.MessageKey, value *messageRow) *model.RowChang
 	return e
 }
 
-func rowChangeColumns2CodecColumns(cols []*model.Column) map[string]internal.Column {
+func rowChangeColumns2CodecColumns(
+	cols []*model.Column, onlyHandleKey bool,
+) map[string]internal.Column {
 	jsonCols := make(map[string]internal.Column, len(cols))
 	for _, col := range cols {
 		if col == nil {
 			continue
 		}
+		if onlyHandleKey && !col.Flag.IsHandleKey() {
+			continue
+		}
 		c := internal.Column{}
 		c.FromRowChangeColumn(col)
 		jsonCols[col.Name] = c
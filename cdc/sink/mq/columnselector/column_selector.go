@@ -0,0 +1,207 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package columnselector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// columnRule is a pattern of the column names, the column is excluded if
+// the pattern starts with `!`.
+type columnRule struct {
+	pattern string
+	exclude bool
+}
+
+type selector struct {
+	tableFilter tfilter.Filter
+	rules       []columnRule
+}
+
+// selectColumn returns whether the column is selected. The rules are
+// checked in reverse order, and the first matched rule decides it, so the
+// latter rules take precedence like the table filter. The column is not
+// selected if no rule matches it.
+func (s *selector) selectColumn(column string) bool {
+	column = strings.ToLower(column)
+	for i := len(s.rules) - 1; i >= 0; i-- {
+		// The patterns are verified when the selector is created.
+		if ok, _ := path.Match(s.rules[i].pattern, column); ok {
+			return !s.rules[i].exclude
+		}
+	}
+	return false
+}
+
+// ColumnSelectors selects the columns of the rows by the column selectors of
+// the sink config. All columns of the tables which are not matched by any
+// selector are selected.
+type ColumnSelectors struct {
+	selectors []*selector
+}
+
+// New creates a ColumnSelectors.
+func New(cfg *config.ReplicaConfig) (*ColumnSelectors, error) {
+	if cfg.Sink == nil {
+		return &ColumnSelectors{}, nil
+	}
+	selectors := make([]*selector, 0, len(cfg.Sink.ColumnSelectors))
+	for _, selectorConfig := range cfg.Sink.ColumnSelectors {
+		f, err := tfilter.Parse(selectorConfig.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, selectorConfig.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			f = tfilter.CaseInsensitive(f)
+		}
+		rules := make([]columnRule, 0, len(selectorConfig.Columns))
+		for _, column := range selectorConfig.Columns {
+			rule := columnRule{pattern: strings.ToLower(strings.TrimSpace(column))}
+			if strings.HasPrefix(rule.pattern, "!") {
+				rule.exclude = true
+				rule.pattern = rule.pattern[1:]
+			}
+			if _, err := path.Match(rule.pattern, ""); err != nil || rule.pattern == "" {
+				return nil, cerror.ErrColumnSelectorFailed.GenWithStackByArgs(
+					fmt.Sprintf("invalid column pattern %q", column))
+			}
+			rules = append(rules, rule)
+		}
+		selectors = append(selectors, &selector{tableFilter: f, rules: rules})
+	}
+	return &ColumnSelectors{selectors: selectors}, nil
+}
+
+// match returns the first selector matching the table, nil is returned if
+// no selector matches it.
+func (c *ColumnSelectors) match(schema, table string) *selector {
+	for _, s := range c.selectors {
+		if s.tableFilter.MatchTable(schema, table) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SelectColumn returns whether the column of the table is selected.
+func (c *ColumnSelectors) SelectColumn(schema, table, column string) bool {
+	s := c.match(schema, table)
+	return s == nil || s.selectColumn(column)
+}
+
+// Apply returns the row with the columns which are not selected removed.
+// The row may be shared by other consumers such as the redo log, so it's
+// never modified, a copy is returned if any column is removed.
+func (c *ColumnSelectors) Apply(row *model.RowChangedEvent) *model.RowChangedEvent {
+	s := c.match(row.Table.Schema, row.Table.Table)
+	if s == nil {
+		return row
+	}
+	cols := row.Columns
+	if len(cols) == 0 {
+		cols = row.PreColumns
+	}
+	selected := make([]bool, len(cols))
+	// offsets maps the offsets of the columns to their new offsets.
+	offsets := make(map[int]int, len(cols))
+	for i, col := range cols {
+		if col != nil && s.selectColumn(col.Name) {
+			selected[i] = true
+			offsets[i] = len(offsets)
+		}
+	}
+	if len(offsets) == len(cols) {
+		return row
+	}
+
+	// The columns themselves are not modified, so a shallow copy with new
+	// slices is enough.
+	res := *row
+	if len(row.ColInfos) == len(cols) {
+		colInfos := make([]rowcodec.ColInfo, 0, len(offsets))
+		for i, colInfo := range row.ColInfos {
+			if selected[i] {
+				colInfos = append(colInfos, colInfo)
+			}
+		}
+		res.ColInfos = colInfos
+	}
+	res.Columns = selectColumns(row.Columns, selected)
+	res.PreColumns = selectColumns(row.PreColumns, selected)
+
+	indexColumns := make([][]int, 0, len(row.IndexColumns))
+	for _, index := range row.IndexColumns {
+		newIndex := make([]int, 0, len(index))
+		for _, offset := range index {
+			if newOffset, ok := offsets[offset]; ok {
+				newIndex = append(newIndex, newOffset)
+			}
+		}
+		// Drop the indexes with columns removed.
+		if len(newIndex) == len(index) {
+			indexColumns = append(indexColumns, newIndex)
+		}
+	}
+	res.IndexColumns = indexColumns
+	return &res
+}
+
+func selectColumns(cols []*model.Column, selected []bool) []*model.Column {
+	if len(cols) == 0 {
+		return cols
+	}
+	res := make([]*model.Column, 0, len(cols))
+	for i, col := range cols {
+		if selected[i] {
+			res = append(res, col)
+		}
+	}
+	return res
+}
+
+// VerifyTables checks whether the handle key columns of the tables are all
+// selected, otherwise the downstream can't identify the rows.
+func (c *ColumnSelectors) VerifyTables(tableInfos []*model.TableInfo) error {
+	for _, ti := range tableInfos {
+		s := c.match(ti.TableName.Schema, ti.TableName.Table)
+		if s == nil {
+			continue
+		}
+		for _, col := range ti.Columns {
+			if !isHandleKey(ti, col) {
+				continue
+			}
+			if !s.selectColumn(col.Name.O) {
+				return cerror.ErrColumnSelectorFailed.GenWithStackByArgs(fmt.Sprintf(
+					"the handle key column %s of table %s is not selected",
+					col.Name.O, ti.TableName.String()))
+			}
+		}
+	}
+	return nil
+}
+
+func isHandleKey(ti *model.TableInfo, col *timodel.ColumnInfo) bool {
+	flag, ok := ti.ColumnsFlag[col.ID]
+	return ok && flag.IsHandleKey()
+}
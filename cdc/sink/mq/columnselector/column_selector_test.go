@@ -0,0 +1,144 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package columnselector
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplicaConfig(selectors ...*config.ColumnSelector) *config.ReplicaConfig {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.ColumnSelectors = selectors
+	return cfg
+}
+
+func TestSelectColumn(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(newReplicaConfig(
+		&config.ColumnSelector{
+			Matcher: []string{"test.t1"},
+			Columns: []string{"*", "!secret_*", "secret_id"},
+		},
+		&config.ColumnSelector{
+			Matcher: []string{"test.*"},
+			Columns: []string{"id", "name"},
+		},
+	))
+	require.Nil(t, err)
+
+	require.True(t, s.SelectColumn("test", "t1", "a"))
+	require.False(t, s.SelectColumn("test", "t1", "Secret_Key"))
+	require.True(t, s.SelectColumn("test", "t1", "secret_id"))
+	require.True(t, s.SelectColumn("TEST", "t2", "ID"))
+	require.False(t, s.SelectColumn("test", "t2", "a"))
+	// Tables not matched by any selector keep all columns.
+	require.True(t, s.SelectColumn("other", "t1", "a"))
+
+	_, err = New(newReplicaConfig(&config.ColumnSelector{
+		Matcher: []string{"test.*"},
+		Columns: []string{"[a"},
+	}))
+	require.Regexp(t, ".*ErrColumnSelectorFailed.*invalid column pattern.*", err)
+	_, err = New(newReplicaConfig(&config.ColumnSelector{
+		Matcher: []string{"test.t1.*"},
+		Columns: []string{"a"},
+	}))
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(newReplicaConfig(&config.ColumnSelector{
+		Matcher: []string{"test.*"},
+		Columns: []string{"id", "b"},
+	}))
+	require.Nil(t, err)
+
+	newColumns := func() []*model.Column {
+		return []*model.Column{
+			{Name: "a", Value: 1},
+			{Name: "id", Value: 2, Flag: model.HandleKeyFlag},
+			{Name: "b", Value: 3},
+		}
+	}
+	row := &model.RowChangedEvent{
+		Table:        &model.TableName{Schema: "test", Table: "t"},
+		Columns:      newColumns(),
+		PreColumns:   newColumns(),
+		ColInfos:     []rowcodec.ColInfo{{ID: 1}, {ID: 2}, {ID: 3}},
+		IndexColumns: [][]int{{1}, {0, 2}},
+	}
+	selected := s.Apply(row)
+	require.Len(t, selected.Columns, 2)
+	require.Equal(t, "id", selected.Columns[0].Name)
+	require.Equal(t, "b", selected.Columns[1].Name)
+	require.Len(t, selected.PreColumns, 2)
+	require.Equal(t, []rowcodec.ColInfo{{ID: 2}, {ID: 3}}, selected.ColInfos)
+	require.Equal(t, [][]int{{0}}, selected.IndexColumns)
+	// The original row is not modified.
+	require.Len(t, row.Columns, 3)
+	require.Len(t, row.PreColumns, 3)
+	require.Len(t, row.ColInfos, 3)
+	require.Equal(t, [][]int{{1}, {0, 2}}, row.IndexColumns)
+
+	// Rows of the tables not matched are not changed.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "other", Table: "t"},
+		Columns: newColumns(),
+	}
+	require.Same(t, row, s.Apply(row))
+}
+
+func TestVerifyTables(t *testing.T) {
+	t.Parallel()
+
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	tableInfo := model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		Name:       timodel.NewCIStr("t"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *ft, State: timodel.StatePublic},
+			{
+				ID: 2, Name: timodel.NewCIStr("a"),
+				FieldType: *types.NewFieldType(mysql.TypeLong), State: timodel.StatePublic,
+			},
+		},
+	})
+
+	s, err := New(newReplicaConfig(&config.ColumnSelector{
+		Matcher: []string{"test.*"},
+		Columns: []string{"id"},
+	}))
+	require.Nil(t, err)
+	require.Nil(t, s.VerifyTables([]*model.TableInfo{tableInfo}))
+
+	s, err = New(newReplicaConfig(&config.ColumnSelector{
+		Matcher: []string{"test.*"},
+		Columns: []string{"a"},
+	}))
+	require.Nil(t, err)
+	err = s.VerifyTables([]*model.TableInfo{tableInfo})
+	require.Regexp(t, ".*handle key column id of table test.t is not selected.*", err)
+}
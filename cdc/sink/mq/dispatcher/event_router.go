@@ -17,6 +17,8 @@ import (
 	"strings"
 
 	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	filter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/partition"
@@ -44,6 +46,7 @@ const (
 	partitionDispatchRuleTS
 	partitionDispatchRuleTable
 	partitionDispatchRuleIndexValue
+	partitionDispatchRuleColumns
 )
 
 func (r *partitionDispatchRule) fromString(rule string) {
@@ -59,19 +62,39 @@ func (r *partitionDispatchRule) fromString(rule string) {
 		log.Warn("rowid is deprecated, please use index-value instead.")
 	case "index-value":
 		*r = partitionDispatchRuleIndexValue
+	case config.PartitionRuleColumns:
+		*r = partitionDispatchRuleColumns
 	default:
 		*r = partitionDispatchRuleDefault
-		log.Warn("the partition dispatch rule is not default/ts/table/index-value," +
+		log.Warn("the partition dispatch rule is not default/ts/table/index-value/columns," +
 			" use the default rule instead.")
 	}
 }
 
+// String implements fmt.Stringer.
+func (r partitionDispatchRule) String() string {
+	switch r {
+	case partitionDispatchRuleTS:
+		return "ts"
+	case partitionDispatchRuleTable:
+		return "table"
+	case partitionDispatchRuleIndexValue:
+		return "index-value"
+	case partitionDispatchRuleColumns:
+		return config.PartitionRuleColumns
+	default:
+		return "default"
+	}
+}
+
 // EventRouter is a router, it determines which topic and which partition
 // an event should be dispatched to.
 type EventRouter struct {
-	defaultTopic string
-	rules        []struct {
+	defaultTopic   string
+	enableOldValue bool
+	rules          []struct {
 		partitionDispatcher partition.Dispatcher
+		partitionRule       partitionDispatchRule
 		topicDispatcher     topic.Dispatcher
 		filter.Filter
 	}
@@ -89,6 +112,7 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 	})
 	rules := make([]struct {
 		partitionDispatcher partition.Dispatcher
+		partitionRule       partitionDispatchRule
 		topicDispatcher     topic.Dispatcher
 		filter.Filter
 	}, 0, len(ruleConfigs))
@@ -102,21 +126,25 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 			f = filter.CaseInsensitive(f)
 		}
 
-		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		var rule partitionDispatchRule
+		rule.fromString(ruleConfig.PartitionRule)
+		d := getPartitionDispatcher(rule, ruleConfig, cfg.EnableOldValue)
 		t, err := getTopicDispatcher(ruleConfig, defaultTopic, cfg.Sink.Protocol)
 		if err != nil {
 			return nil, err
 		}
 		rules = append(rules, struct {
 			partitionDispatcher partition.Dispatcher
+			partitionRule       partitionDispatchRule
 			topicDispatcher     topic.Dispatcher
 			filter.Filter
-		}{partitionDispatcher: d, topicDispatcher: t, Filter: f})
+		}{partitionDispatcher: d, partitionRule: rule, topicDispatcher: t, Filter: f})
 	}
 
 	return &EventRouter{
-		defaultTopic: defaultTopic,
-		rules:        rules,
+		defaultTopic:   defaultTopic,
+		enableOldValue: cfg.EnableOldValue,
+		rules:          rules,
 	}, nil
 }
 
@@ -161,6 +189,37 @@ func (s *EventRouter) GetPartitionForRowChange(
 	)
 }
 
+// GetPartitionRuleForTable returns the partition rule matching the table.
+// If all rows of the table are dispatched to the same partition, the
+// partition is returned too, otherwise -1 is returned, which is also the
+// case if the partition number is unknown.
+func (s *EventRouter) GetPartitionRuleForTable(
+	tableInfo *model.TableInfo, partitionNum int32,
+) (string, int32) {
+	schema, table := tableInfo.TableName.Schema, tableInfo.TableName.Table
+	for _, rule := range s.rules {
+		if !rule.MatchTable(schema, table) {
+			continue
+		}
+		// The default rule dispatches rows by table if the old value is
+		// enabled or the table has no single unique key, see DefaultDispatcher.
+		byTable := rule.partitionRule == partitionDispatchRuleTable ||
+			(rule.partitionRule == partitionDispatchRuleDefault &&
+				(s.enableOldValue || len(tableInfo.IndexColumnsOffset) != 1))
+		if !byTable || partitionNum <= 0 {
+			return rule.partitionRule.String(), -1
+		}
+		row := &model.RowChangedEvent{
+			Table:        &tableInfo.TableName,
+			IndexColumns: tableInfo.IndexColumnsOffset,
+		}
+		return rule.partitionRule.String(),
+			rule.partitionDispatcher.DispatchRowChangedEvent(row, partitionNum)
+	}
+	log.Panic("the dispatch rule must cover all tables")
+	return "", -1
+}
+
 // GetDLLDispatchRuleByProtocol returns the DDL
 // distribution rule according to the protocol.
 func (s *EventRouter) GetDLLDispatchRuleByProtocol(
@@ -221,15 +280,52 @@ func (s *EventRouter) matchDispatcher(
 	return nil, nil
 }
 
+// VerifyTables checks whether the columns of the `columns` partition rules
+// exist in the tables, and warns if they are nullable since null values are
+// all dispatched to the same partition.
+// It should only be called by create changefeed OpenAPI.
+func VerifyTables(cfg *config.ReplicaConfig, tableInfos []*model.TableInfo) error {
+	if cfg.Sink == nil {
+		return nil
+	}
+	for _, ruleConfig := range cfg.Sink.DispatchRules {
+		if !strings.EqualFold(ruleConfig.PartitionRule, config.PartitionRuleColumns) {
+			continue
+		}
+		f, err := filter.Parse(ruleConfig.Matcher)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, ruleConfig.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		for _, ti := range tableInfos {
+			if !f.MatchTable(ti.TableName.Schema, ti.TableName.Table) {
+				continue
+			}
+			for _, name := range ruleConfig.Columns {
+				col := timodel.FindColumnInfo(ti.Columns, strings.ToLower(name))
+				if col == nil {
+					return cerror.ErrDispatcherColumnNotFound.GenWithStackByArgs(
+						name, ti.TableName.String())
+				}
+				if !mysql.HasNotNullFlag(col.GetFlag()) {
+					log.Warn("the column to dispatch rows by is nullable, "+
+						"rows with null values are dispatched to the same partition",
+						zap.String("table", ti.TableName.String()),
+						zap.String("column", name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
 func getPartitionDispatcher(
-	ruleConfig *config.DispatchRule, enableOldValue bool,
+	rule partitionDispatchRule, ruleConfig *config.DispatchRule, enableOldValue bool,
 ) partition.Dispatcher {
-	var (
-		d    partition.Dispatcher
-		rule partitionDispatchRule
-	)
-	rule.fromString(ruleConfig.PartitionRule)
+	var d partition.Dispatcher
 	switch rule {
 	case partitionDispatchRuleIndexValue:
 		if enableOldValue {
@@ -238,6 +334,8 @@ func getPartitionDispatcher(
 				"switching on the old value, so please use caution!")
 		}
 		d = partition.NewIndexValueDispatcher()
+	case partitionDispatchRuleColumns:
+		d = partition.NewColumnsDispatcher(ruleConfig.Columns, ruleConfig.HashFunction)
 	case partitionDispatchRuleTS:
 		d = partition.NewTsDispatcher()
 	case partitionDispatchRuleTable:
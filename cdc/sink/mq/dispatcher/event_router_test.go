@@ -17,6 +17,8 @@ import (
 	"testing"
 
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/partition"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/topic"
@@ -469,3 +471,87 @@ func TestGetTopicForDDL(t *testing.T) {
 		require.Equal(t, test.expectedTopic, d.GetTopicForDDL(test.ddl))
 	}
 }
+
+func TestColumnsDispatchRule(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.DispatchRules = []*config.DispatchRule{{
+		Matcher:       []string{"test.*"},
+		PartitionRule: "columns",
+		Columns:       []string{"tenant_id"},
+		HashFunction:  config.HashFunctionMurmur2,
+	}}
+	d, err := NewEventRouter(cfg, "test")
+	require.Nil(t, err)
+	_, partitionDispatcher := d.matchDispatcher("test", "t1")
+	require.IsType(t, &partition.ColumnsDispatcher{}, partitionDispatcher)
+
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.NotNullFlag)
+	newTableInfo := func(table string, column string) *model.TableInfo {
+		return model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+			Name: timodel.NewCIStr(table),
+			Columns: []*timodel.ColumnInfo{{
+				ID:        1,
+				Name:      timodel.NewCIStr(column),
+				FieldType: *ft,
+				State:     timodel.StatePublic,
+			}},
+		})
+	}
+	require.Nil(t, VerifyTables(cfg, []*model.TableInfo{newTableInfo("t1", "Tenant_ID")}))
+	err = VerifyTables(cfg, []*model.TableInfo{newTableInfo("t2", "id")})
+	require.Regexp(t, ".*column tenant_id of the columns partition rule "+
+		"is not found in table test.t2.*", err)
+}
+
+func TestGetPartitionRuleForTable(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.EnableOldValue = false
+	cfg.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.t1"}, PartitionRule: "table"},
+		{Matcher: []string{"test.t2"}, PartitionRule: "ts"},
+	}
+	d, err := NewEventRouter(cfg, "test")
+	require.Nil(t, err)
+
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	newTableInfo := func(table string, withPK bool) *model.TableInfo {
+		info := &timodel.TableInfo{
+			Name: timodel.NewCIStr(table),
+			Columns: []*timodel.ColumnInfo{{
+				ID:        1,
+				Name:      timodel.NewCIStr("id"),
+				FieldType: *types.NewFieldType(mysql.TypeLong),
+				State:     timodel.StatePublic,
+			}},
+		}
+		if withPK {
+			info.PKIsHandle = true
+			info.Columns[0].FieldType = *ft
+		}
+		return model.WrapTableInfo(1, "test", 1, info)
+	}
+
+	rule, partition := d.GetPartitionRuleForTable(newTableInfo("t1", true), 4)
+	require.Equal(t, "table", rule)
+	require.True(t, partition >= 0 && partition < 4)
+	// The partition is unknown without the partition number.
+	_, partition = d.GetPartitionRuleForTable(newTableInfo("t1", true), 0)
+	require.Equal(t, int32(-1), partition)
+
+	rule, partition = d.GetPartitionRuleForTable(newTableInfo("t2", true), 4)
+	require.Equal(t, "ts", rule)
+	require.Equal(t, int32(-1), partition)
+
+	// The default rule dispatches rows by the unique key if there is one.
+	rule, partition = d.GetPartitionRuleForTable(newTableInfo("t3", true), 4)
+	require.Equal(t, "default", rule)
+	require.Equal(t, int32(-1), partition)
+	_, partition = d.GetPartitionRuleForTable(newTableInfo("t3", false), 4)
+	require.True(t, partition >= 0 && partition < 4)
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"hash/crc32"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/hash"
+	"go.uber.org/zap"
+)
+
+// columnsKeySeparator separates the values of columns in the partition key.
+const columnsKeySeparator = ","
+
+// ColumnsDispatcher is a partition dispatcher which dispatches events based on
+// the values of the specified columns. The values are joined by commas as the
+// key, so the rows are co-partitioned with the messages produced by Kafka
+// clients with the same key if the murmur2 hash function is used.
+type ColumnsDispatcher struct {
+	columns      []string
+	hashFunction string
+
+	lock sync.Mutex
+	// warnedTables records the tables whose rows lack the columns, so the
+	// warning is only logged once for each table.
+	warnedTables map[string]struct{}
+}
+
+// NewColumnsDispatcher creates a ColumnsDispatcher.
+func NewColumnsDispatcher(columns []string, hashFunction string) *ColumnsDispatcher {
+	return &ColumnsDispatcher{
+		columns:      columns,
+		hashFunction: hashFunction,
+		warnedTables: make(map[string]struct{}),
+	}
+}
+
+// DispatchRowChangedEvent returns the target partition to which
+// a row changed event should be dispatched.
+func (r *ColumnsDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
+	dispatchCols := row.Columns
+	if len(row.Columns) == 0 {
+		dispatchCols = row.PreColumns
+	}
+
+	var key strings.Builder
+	for i, name := range r.columns {
+		if i > 0 {
+			key.WriteString(columnsKeySeparator)
+		}
+		col := findColumn(dispatchCols, name)
+		if col == nil {
+			r.warnMissingColumn(row.Table, name)
+			continue
+		}
+		key.WriteString(model.ColumnValueString(col.Value))
+	}
+
+	if r.hashFunction == config.HashFunctionCRC32 {
+		return int32(crc32.ChecksumIEEE([]byte(key.String())) % uint32(partitionNum))
+	}
+	return hash.Murmur2Partition([]byte(key.String()), partitionNum)
+}
+
+func (r *ColumnsDispatcher) warnMissingColumn(table *model.TableName, column string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.warnedTables[table.String()]; ok {
+		return
+	}
+	r.warnedTables[table.String()] = struct{}{}
+	log.Warn("the column to dispatch rows by is not found, the value is treated as empty",
+		zap.String("table", table.String()),
+		zap.String("column", column))
+}
+
+func findColumn(cols []*model.Column, name string) *model.Column {
+	for _, col := range cols {
+		if col != nil && strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
+}
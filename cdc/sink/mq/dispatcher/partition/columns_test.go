@@ -0,0 +1,62 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/hash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnsDispatcher(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(table string, tenantID any, region any) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: 1, Flag: model.HandleKeyFlag},
+				{Name: "tenant_id", Value: tenantID},
+				{Name: "region", Value: region},
+			},
+		}
+	}
+
+	// The partition is the same as the one of the Java Kafka client with the
+	// value of the column as the key.
+	d := NewColumnsDispatcher([]string{"Tenant_ID"}, config.HashFunctionMurmur2)
+	require.Equal(t, int32(3), d.DispatchRowChangedEvent(newRow("t1", []byte("abc"), nil), 8))
+	require.Equal(t, int32(12), d.DispatchRowChangedEvent(newRow("t2", 21, nil), 16))
+
+	// Rows of deletions are dispatched by the pre-columns.
+	row := newRow("t1", []byte("abc"), nil)
+	row.PreColumns, row.Columns = row.Columns, nil
+	require.Equal(t, int32(3), d.DispatchRowChangedEvent(row, 8))
+
+	d = NewColumnsDispatcher([]string{"tenant_id", "region"}, config.HashFunctionMurmur2)
+	require.Equal(t, hash.Murmur2Partition([]byte("abc,null"), 8),
+		d.DispatchRowChangedEvent(newRow("t1", "abc", nil), 8))
+
+	d = NewColumnsDispatcher([]string{"tenant_id"}, config.HashFunctionCRC32)
+	require.Equal(t, int32(2), d.DispatchRowChangedEvent(newRow("t1", "abc", nil), 8))
+
+	// Missing columns are treated as empty values.
+	d = NewColumnsDispatcher([]string{"tenant_id", "zone"}, config.HashFunctionCRC32)
+	require.Equal(t, d.DispatchRowChangedEvent(newRow("t1", "abc", nil), 8),
+		d.DispatchRowChangedEvent(newRow("t1", "abc", "us"), 8))
+	require.Len(t, d.warnedTables, 1)
+}
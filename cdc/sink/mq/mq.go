@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
@@ -85,9 +86,11 @@ func newMqSink(
 	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
 	role := contextutil.RoleFromCtx(ctx)
 
+	observer := heartbeat.NewObserver(changefeedID, replicaConfig)
+
 	encoder := encoderBuilder.Build()
 	statistics := metrics.NewStatistics(ctx, captureAddr, metrics.SinkTypeMQ)
-	flushWorker := newFlushWorker(encoder, mqProducer, statistics)
+	flushWorker := newFlushWorker(encoder, mqProducer, observer, statistics)
 
 	s := &mqSink{
 		mqProducer:     mqProducer,
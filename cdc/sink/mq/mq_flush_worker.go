@@ -19,8 +19,10 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/mq/producer"
 	"github.com/pingcap/tiflow/pkg/chann"
@@ -62,8 +64,11 @@ type flushWorker struct {
 	// It is also used to notify that the flush has completed.
 	needsFlush chan<- struct{}
 
-	encoder    codec.EventBatchEncoder
-	producer   producer.Producer
+	encoder  codec.EventBatchEncoder
+	producer producer.Producer
+	// heartbeat observes the replication latency when heartbeats are acked
+	// by the broker, it's nil if heartbeats are disabled.
+	heartbeat  *heartbeat.Observer
 	statistics *metrics.Statistics
 }
 
@@ -71,6 +76,7 @@ type flushWorker struct {
 func newFlushWorker(
 	encoder codec.EventBatchEncoder,
 	producer producer.Producer,
+	heartbeat *heartbeat.Observer,
 	statistics *metrics.Statistics,
 ) *flushWorker {
 	w := &flushWorker{
@@ -78,6 +84,7 @@ func newFlushWorker(
 		ticker:     time.NewTicker(FlushInterval),
 		encoder:    encoder,
 		producer:   producer,
+		heartbeat:  heartbeat,
 		statistics: statistics,
 	}
 	return w
@@ -162,16 +169,29 @@ func (w *flushWorker) asyncSend(
 	partitionedRows map[TopicPartitionKey][]*model.RowChangedEvent,
 ) error {
 	for key, events := range partitionedRows {
+		var messages []*common.Message
 		for _, event := range events {
 			err := w.encoder.AppendRowChangedEvent(ctx, key.Topic, event, nil)
 			if err != nil {
 				return err
 			}
+			if w.heartbeat.IsHeartbeat(event.Table) {
+				heartbeatMessages, err := w.buildHeartbeatMessages(event.CommitTs)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				messages = append(messages, heartbeatMessages...)
+			}
+		}
+		builtMessages, err := w.encoder.Build()
+		if err != nil {
+			return errors.Trace(err)
 		}
+		messages = append(messages, builtMessages...)
 
-		err := w.statistics.RecordBatchExecution(func() (int, error) {
+		err = w.statistics.RecordBatchExecution(func() (int, error) {
 			thisBatchSize := 0
-			for _, message := range w.encoder.Build() {
+			for _, message := range messages {
 				err := w.producer.AsyncSendMessage(ctx, key.Topic, key.Partition, message)
 				if err != nil {
 					return 0, err
@@ -197,6 +217,22 @@ func (w *flushWorker) asyncSend(
 	return nil
 }
 
+// buildHeartbeatMessages builds the messages encoded so far. The heartbeat is
+// the last row encoded, so its latency is observed when the last message is
+// acked.
+func (w *flushWorker) buildHeartbeatMessages(commitTs uint64) ([]*common.Message, error) {
+	messages, err := w.encoder.Build()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(messages) > 0 {
+		messages[len(messages)-1].OnAck = func(timestamp time.Time) {
+			w.heartbeat.Observe(commitTs, timestamp)
+		}
+	}
+	return messages, nil
+}
+
 // run starts a loop that keeps collecting, sorting and sending messages
 // until it encounters an error or is interrupted.
 func (w *flushWorker) run(ctx context.Context) (retErr error) {
@@ -263,6 +299,7 @@ func (w *flushWorker) flushAndNotify(ctx context.Context) error {
 }
 
 func (w *flushWorker) close() {
+	w.heartbeat.Close()
 	w.msgChan.Close()
 	// We must finish consuming the data here,
 	// otherwise it will cause the channel to not close properly.
@@ -18,9 +18,11 @@ import (
 	"math"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
@@ -95,7 +97,7 @@ func newTestWorker(ctx context.Context) (*flushWorker, *mockProducer) {
 		panic(err)
 	}
 	producer := NewMockProducer()
-	return newFlushWorker(encoder, producer,
+	return newFlushWorker(encoder, producer, nil,
 		metrics.NewStatistics(ctx, "", metrics.SinkTypeMQ)), producer
 }
 
@@ -391,6 +393,56 @@ func TestAsyncSend(t *testing.T) {
 	require.Len(t, producer.mqEvent[key3], 2)
 }
 
+func TestAsyncSendHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	encoderConfig := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1024 * 1024)
+	builder, err := builder.NewEventBatchEncoderBuilder(ctx, encoderConfig)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Heartbeat = &config.HeartbeatConfig{
+		Enable:      true,
+		UpstreamURI: "mysql://root@127.0.0.1:4000/",
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(nil))
+	observer := heartbeat.NewObserver(model.DefaultChangeFeedID("test"), replicaConfig)
+	producer := NewMockProducer()
+	worker := newFlushWorker(builder.Build(), producer, observer,
+		metrics.NewStatistics(ctx, "", metrics.SinkTypeMQ))
+	defer worker.close()
+
+	key := TopicPartitionKey{Topic: "test", Partition: 1}
+	newRow := func(commitTs uint64, schema, table string) mqEvent {
+		return mqEvent{
+			row: &model.RowChangedEvent{
+				CommitTs: commitTs,
+				Table:    &model.TableName{Schema: schema, Table: table},
+				Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+			},
+			key: key,
+		}
+	}
+	events := []mqEvent{
+		newRow(1, "a", "b"),
+		newRow(2, "tidb_cdc", "heartbeat"),
+		newRow(3, "a", "b"),
+	}
+	err = worker.asyncSend(ctx, worker.group(events))
+	require.NoError(t, err)
+
+	// The message is split after the heartbeat, so its latency is observed
+	// when the message carrying it is acked.
+	messages := producer.mqEvent[key]
+	require.Len(t, messages, 2)
+	require.Equal(t, 2, messages[0].GetRowsCount())
+	require.NotNil(t, messages[0].OnAck)
+	require.Equal(t, 1, messages[1].GetRowsCount())
+	require.Nil(t, messages[1].OnAck)
+	messages[0].OnAck(time.Now())
+}
+
 func TestFlush(t *testing.T) {
 	t.Parallel()
 
@@ -109,6 +109,7 @@ func (k *kafkaSaramaProducer) AsyncSendMessage(
 		Key:       sarama.ByteEncoder(message.Key),
 		Value:     sarama.ByteEncoder(message.Value),
 		Partition: partition,
+		Metadata:  message.OnAck,
 	}
 	k.mu.Lock()
 	k.mu.inflight++
@@ -303,6 +304,14 @@ func (k *kafkaSaramaProducer) run(ctx context.Context) error {
 			return cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, err)
 		}
 		if ack != nil {
+			if onAck, ok := ack.Metadata.(func(time.Time)); ok && onAck != nil {
+				// The timestamp is set only if the broker assigns it.
+				timestamp := ack.Timestamp
+				if timestamp.IsZero() {
+					timestamp = time.Now()
+				}
+				onAck(timestamp)
+			}
 			k.mu.Lock()
 			k.mu.inflight--
 			if k.mu.inflight == 0 && k.mu.flushDone != nil {
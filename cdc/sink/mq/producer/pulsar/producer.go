@@ -17,6 +17,7 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pingcap/failpoint"
@@ -89,12 +90,21 @@ func createProperties(message *common.Message, partition int32) map[string]strin
 func (p *Producer) AsyncSendMessage(
 	ctx context.Context, _ string, partition int32, message *common.Message,
 ) error {
+	callback := p.errors
+	if message.OnAck != nil {
+		callback = func(id pulsar.MessageID, msg *pulsar.ProducerMessage, err error) {
+			if err == nil {
+				message.OnAck(time.Now())
+			}
+			p.errors(id, msg, err)
+		}
+	}
 	p.producer.SendAsync(ctx, &pulsar.ProducerMessage{
 		Payload:    message.Value,
 		Key:        string(message.Key),
 		Properties: createProperties(message, partition),
 		EventTime:  message.PhysicalTime(),
-	}, p.errors)
+	}, callback)
 	return nil
 }
 
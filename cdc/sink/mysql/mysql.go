@@ -37,16 +37,19 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	dmretry "github.com/pingcap/tiflow/dm/pkg/retry"
 	dmutils "github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/bdr"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/errorutil"
 	"github.com/pingcap/tiflow/pkg/notify"
 	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 )
 
 const (
@@ -76,7 +79,15 @@ type mysqlSink struct {
 	metricBucketSizeCounters        []prometheus.Counter
 
 	forceReplicate bool
-	cancel         func()
+	// router routes the rows and DDLs to the downstream tables.
+	router *sqlmodel.Router
+	// bdrResolver is set in bdr mode, it builds conditional DMLs resolving
+	// conflicts and marks transactions written by the sink.
+	bdrResolver *bdr.ConflictResolver
+	// heartbeat observes the replication latency when heartbeats are
+	// written into the downstream, it's nil if heartbeats are disabled.
+	heartbeat *heartbeat.Observer
+	cancel    func()
 
 	// error is set when the sink has encountered an
 	// error and cannot work anymore.
@@ -167,6 +178,20 @@ func NewMySQLSink(
 		return nil, err
 	}
 
+	var bdrResolver *bdr.ConflictResolver
+	if replicaConfig.BDREnabled() {
+		bdrResolver = bdr.NewConflictResolver(params.changefeedID, replicaConfig)
+		if err := bdr.CreateMarkTable(ctx, db, bdrResolver.ReplicaID()); err != nil {
+			return nil, err
+		}
+	}
+
+	router, err := sqlmodel.NewRouter(replicaConfig.CaseSensitive, replicaConfig.RoutingRules)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	observer := heartbeat.NewObserver(changefeedID, replicaConfig)
+
 	log.Info("Start mysql sink")
 
 	db.SetMaxIdleConns(params.workerCount)
@@ -194,6 +219,9 @@ func NewMySQLSink(
 		resolvedCh:                      make(chan struct{}, 1),
 		errCh:                           make(chan error, 1),
 		forceReplicate:                  replicaConfig.ForceReplicate,
+		router:                          router,
+		bdrResolver:                     bdrResolver,
+		heartbeat:                       observer,
 		cancel:                          cancel,
 	}
 
@@ -295,7 +323,11 @@ func (s *mysqlSink) EmitCheckpointTs(_ context.Context, ts uint64, _ []model.Tab
 // Concurrency Note: EmitDDLEvent is thread-safe.
 func (s *mysqlSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	s.statistics.AddDDLCount()
-	err := s.execDDLWithMaxRetries(ctx, ddl)
+	ddl, err := s.router.RouteDDL(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = s.execDDLWithMaxRetries(ctx, ddl)
 	return errors.Trace(err)
 }
 
@@ -540,6 +572,7 @@ func (s *mysqlSink) cleanTableResource(tableID model.TableID) {
 func (s *mysqlSink) Close(ctx context.Context) error {
 	s.execWaitNotifier.Close()
 	err := s.db.Close()
+	s.heartbeat.Close()
 	s.cancel()
 	return cerror.WrapError(cerror.ErrMySQLConnectionError, err)
 }
@@ -663,10 +696,29 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 					start, s.params.changefeedID, "BEGIN", dmls.rowCount, dmls.startTs)
 			}
 
+			if dmls.markSQL != "" {
+				if _, err := tx.ExecContext(ctx, dmls.markSQL, dmls.markArgs...); err != nil {
+					if rbErr := tx.Rollback(); rbErr != nil {
+						if errors.Cause(rbErr) != context.Canceled {
+							log.Warn("failed to rollback txn", zap.Error(rbErr))
+						}
+					}
+					return 0, logDMLTxnErr(
+						cerror.WrapError(cerror.ErrMySQLTxnError, err),
+						start, s.params.changefeedID, dmls.markSQL, dmls.rowCount, dmls.startTs)
+				}
+			}
+
 			for i, query := range dmls.sqls {
 				args := dmls.values[i]
 				log.Debug("exec row", zap.String("sql", query), zap.Any("args", args))
-				if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				var err error
+				if dmls.bdrDMLs != nil {
+					err = s.bdrResolver.ExecDML(ctx, tx, dmls.bdrDMLs[i])
+				} else {
+					_, err = tx.ExecContext(ctx, query, args...)
+				}
+				if err != nil {
 					if rbErr := tx.Rollback(); rbErr != nil {
 						if errors.Cause(rbErr) != context.Canceled {
 							log.Warn("failed to rollback txn", zap.Error(err))
@@ -708,10 +760,20 @@ type preparedDMLs struct {
 	sqls     []string
 	values   [][]interface{}
 	rowCount int
+
+	// markSQL is executed before sqls in bdr mode to mark the transaction.
+	markSQL  string
+	markArgs []interface{}
+	// bdrDMLs are the DMLs of sqls in bdr mode, which must be executed by
+	// the conflict resolver.
+	bdrDMLs []*bdr.DML
 }
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, bucket int) *preparedDMLs {
+	if s.bdrResolver != nil {
+		return s.prepareBDRDMLs(rows, bucket)
+	}
 	startTs := make([]model.Ts, 0, 1)
 	sqls := make([]string, 0, len(rows))
 	values := make([][]interface{}, 0, len(rows))
@@ -742,7 +804,8 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, bucket int) *prep
 	for _, row := range rows {
 		var query string
 		var args []interface{}
-		quoteTable := quotes.QuoteSchema(row.Table.Schema, row.Table.Table)
+		table := s.router.RouteTableName(row.Table)
+		quoteTable := quotes.QuoteSchema(table.Schema, table.Table)
 		if len(startTs) == 0 || // Always add the first row's start ts.
 			startTs[len(startTs)-1] != row.StartTs { // Try to deduplicate starts ts.
 			startTs = append(startTs, row.StartTs)
@@ -816,6 +879,31 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, bucket int) *prep
 	return dmls
 }
 
+// prepareBDRDMLs converts rows to DMLs in bdr mode. Every row is written by
+// a single DML, which is skipped if it conflicts with the downstream row.
+func (s *mysqlSink) prepareBDRDMLs(rows []*model.RowChangedEvent, bucket int) *preparedDMLs {
+	dmls := &preparedDMLs{
+		startTs: make([]model.Ts, 0, 1),
+		sqls:    make([]string, 0, len(rows)),
+		values:  make([][]interface{}, 0, len(rows)),
+		bdrDMLs: make([]*bdr.DML, 0, len(rows)),
+	}
+	dmls.markSQL, dmls.markArgs = s.bdrResolver.MarkSQL(bucket)
+	for _, row := range rows {
+		if len(dmls.startTs) == 0 || dmls.startTs[len(dmls.startTs)-1] != row.StartTs {
+			dmls.startTs = append(dmls.startTs, row.StartTs)
+		}
+		dml := s.bdrResolver.PrepareDML(s.router.RouteTableName(row.Table), row)
+		if dml != nil {
+			dmls.sqls = append(dmls.sqls, dml.SQL)
+			dmls.values = append(dmls.values, dml.Args)
+			dmls.bdrDMLs = append(dmls.bdrDMLs, dml)
+			dmls.rowCount++
+		}
+	}
+	return dmls
+}
+
 func (s *mysqlSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent, bucket int) error {
 	failpoint.Inject("MySQLSinkExecDMLError", func() {
 		// Add a delay to ensure the sink worker with `MySQLSinkHangLongTime`
@@ -832,6 +920,7 @@ func (s *mysqlSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent,
 		}
 		return errors.Trace(err)
 	}
+	s.heartbeat.ObserveRows(rows, time.Now())
 	return nil
 }
 
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 	"go.uber.org/zap"
 )
 
@@ -49,6 +50,8 @@ type mysqlDDLSink struct {
 	id model.ChangeFeedID
 	// db is the database connection.
 	db *sql.DB
+	// router routes the DDLs to the downstream tables.
+	router *sqlmodel.Router
 	// statistics is the statistics of this sink.
 	// We use it to record the DDL count.
 	statistics *metrics.Statistics
@@ -73,6 +76,11 @@ func NewMySQLDDLSink(
 		return nil, err
 	}
 
+	router, err := sqlmodel.NewRouter(replicaConfig.CaseSensitive, replicaConfig.RoutingRules)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	db, err := dbConnFactory(ctx, dsnStr)
 	if err != nil {
 		return nil, err
@@ -81,6 +89,7 @@ func NewMySQLDDLSink(
 	m := &mysqlDDLSink{
 		id:         changefeedID,
 		db:         db,
+		router:     router,
 		statistics: metrics.NewStatistics(ctx, sink.TxnSink),
 	}
 
@@ -91,7 +100,11 @@ func NewMySQLDDLSink(
 }
 
 func (m *mysqlDDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
-	err := m.execDDLWithMaxRetries(ctx, ddl)
+	ddl, err := m.router.RouteDDL(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = m.execDDLWithMaxRetries(ctx, ddl)
 	return errors.Trace(err)
 }
 
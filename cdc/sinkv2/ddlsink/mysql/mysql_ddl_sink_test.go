@@ -93,6 +93,62 @@ func TestWriteDDLEvent(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestWriteRoutedDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() {
+			dbIndex++
+		}()
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec("USE `test_replica`;").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("ALTER TABLE `test_replica`.`t1` ADD COLUMN `a` INT").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	contextutil.PutChangefeedIDInCtx(ctx, model.DefaultChangeFeedID("test-changefeed"))
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000")
+	require.Nil(t, err)
+	rc := config.GetDefaultReplicaConfig()
+	rc.RoutingRules = []*config.RoutingRule{
+		{Matcher: []string{"test.*"}, TargetSchema: "test_replica"},
+	}
+	sink, err := NewMySQLDDLSink(ctx, sinkURI, rc, mockGetDBConn)
+	require.Nil(t, err)
+
+	ddl := &model.DDLEvent{
+		StartTs:  1000,
+		CommitTs: 1010,
+		TableInfo: &model.SimpleTableInfo{
+			Schema: "test",
+			Table:  "t1",
+		},
+		Type:  timodel.ActionAddColumn,
+		Query: "ALTER TABLE test.t1 ADD COLUMN a int",
+	}
+	require.Nil(t, sink.WriteDDLEvent(ctx, ddl))
+	// The event is shared with other sinks, so it's not changed.
+	require.Equal(t, "ALTER TABLE test.t1 ADD COLUMN a int", ddl.Query)
+	require.Equal(t, "test", ddl.TableInfo.Schema)
+
+	require.Nil(t, sink.Close())
+}
+
 func TestNeedSwitchDB(t *testing.T) {
 	t.Parallel()
 
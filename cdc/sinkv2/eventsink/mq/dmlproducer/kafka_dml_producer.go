@@ -35,9 +35,10 @@ import (
 
 var _ DMLProducer = (*kafkaDMLProducer)(nil)
 
-// messageMetaData is used to store the callback function for the message.
+// messageMetaData is used to store the callback functions for the message.
 type messageMetaData struct {
 	callback eventsink.CallbackFunc
+	onAck    func(timestamp time.Time)
 }
 
 // kafkaDMLProducer is used to send messages to kafka.
@@ -161,7 +162,7 @@ func (k *kafkaDMLProducer) AsyncSendMessage(
 		Partition: partition,
 		Key:       sarama.StringEncoder(message.Key),
 		Value:     sarama.ByteEncoder(message.Value),
-		Metadata:  messageMetaData{callback: message.Callback},
+		Metadata:  messageMetaData{callback: message.Callback, onAck: message.OnAck},
 	}
 
 	select {
@@ -255,9 +256,17 @@ func (k *kafkaDMLProducer) run(ctx context.Context) error {
 			return errors.Trace(err)
 		case ack := <-k.asyncProducer.Successes():
 			if ack != nil {
-				callback := ack.Metadata.(messageMetaData).callback
-				if callback != nil {
-					callback()
+				metadata := ack.Metadata.(messageMetaData)
+				if metadata.onAck != nil {
+					// The timestamp is set only if the broker assigns it.
+					timestamp := ack.Timestamp
+					if timestamp.IsZero() {
+						timestamp = time.Now()
+					}
+					metadata.onAck(timestamp)
+				}
+				if metadata.callback != nil {
+					metadata.callback()
 				}
 			}
 		case err := <-k.asyncProducer.Errors():
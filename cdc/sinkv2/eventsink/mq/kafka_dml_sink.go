@@ -20,6 +20,8 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
@@ -111,13 +113,16 @@ func NewKafkaDMLSink(
 		return nil, errors.Trace(err)
 	}
 
+	observer := heartbeat.NewObserver(
+		contextutil.ChangefeedIDFromCtx(ctx), replicaConfig)
+
 	encoderConfig, err := mqutil.GetEncoderConfig(sinkURI, protocol, replicaConfig,
 		saramaConfig.Producer.MaxMessageBytes)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	s, err := newSink(ctx, p, topicManager, eventRouter, encoderConfig, errCh)
+	s, err := newSink(ctx, p, topicManager, eventRouter, observer, encoderConfig, errCh)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
@@ -61,6 +62,7 @@ func newSink(ctx context.Context,
 	producer dmlproducer.DMLProducer,
 	topicManager manager.TopicManager,
 	eventRouter *dispatcher.EventRouter,
+	heartbeat *heartbeat.Observer,
 	encoderConfig *common.Config,
 	errCh chan error,
 ) (*dmlSink, error) {
@@ -73,7 +75,7 @@ func newSink(ctx context.Context,
 	encoder := encoderBuilder.Build()
 
 	statistics := metrics.NewStatistics(ctx, sink.RowSink)
-	w := newWorker(changefeedID, encoder, producer, statistics)
+	w := newWorker(changefeedID, encoder, producer, heartbeat, statistics)
 
 	s := &dmlSink{
 		id:             changefeedID,
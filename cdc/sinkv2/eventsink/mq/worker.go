@@ -19,8 +19,10 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
@@ -50,6 +52,9 @@ type worker struct {
 	encoder codec.EventBatchEncoder
 	// producer is used to send the messages to the Kafka/Pulsar broker.
 	producer dmlproducer.DMLProducer
+	// heartbeat observes the replication latency when heartbeats are acked
+	// by the broker, it's nil if heartbeats are disabled.
+	heartbeat *heartbeat.Observer
 	// statistics is used to record DML metrics.
 	statistics *metrics.Statistics
 }
@@ -59,6 +64,7 @@ func newWorker(
 	id model.ChangeFeedID,
 	encoder codec.EventBatchEncoder,
 	producer dmlproducer.DMLProducer,
+	heartbeat *heartbeat.Observer,
 	statistics *metrics.Statistics,
 ) *worker {
 	w := &worker{
@@ -67,6 +73,7 @@ func newWorker(
 		ticker:       time.NewTicker(mqv1.FlushInterval),
 		encoder:      encoder,
 		producer:     producer,
+		heartbeat:    heartbeat,
 		statistics:   statistics,
 	}
 
@@ -175,6 +182,7 @@ func (w *worker) asyncSend(
 ) error {
 	for key, events := range partitionedRows {
 		rowsCount := 0
+		var messages []*common.Message
 		for _, event := range events {
 			// Skip this event when the table is stopping.
 			if event.GetTableSinkState() == state.TableSinkStopping {
@@ -188,9 +196,21 @@ func (w *worker) asyncSend(
 			}
 			rowsCount++
 			w.statistics.ObserveRows(event.Event)
+			if w.heartbeat.IsHeartbeat(event.Event.Table) {
+				heartbeatMessages, err := w.buildHeartbeatMessages(event.Event.CommitTs)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				messages = append(messages, heartbeatMessages...)
+			}
+		}
+		builtMessages, err := w.encoder.Build()
+		if err != nil {
+			return errors.Trace(err)
 		}
+		messages = append(messages, builtMessages...)
 
-		for _, message := range w.encoder.Build() {
+		for _, message := range messages {
 			err := w.statistics.RecordBatchExecution(func() (int, error) {
 				err := w.producer.AsyncSendMessage(ctx, key.Topic, key.Partition, message)
 				if err != nil {
@@ -207,6 +227,22 @@ func (w *worker) asyncSend(
 	return nil
 }
 
+// buildHeartbeatMessages builds the messages encoded so far. The heartbeat is
+// the last row encoded, so its latency is observed when the last message is
+// acked.
+func (w *worker) buildHeartbeatMessages(commitTs uint64) ([]*common.Message, error) {
+	messages, err := w.encoder.Build()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(messages) > 0 {
+		messages[len(messages)-1].OnAck = func(timestamp time.Time) {
+			w.heartbeat.Observe(commitTs, timestamp)
+		}
+	}
+	return messages, nil
+}
+
 func (w *worker) close() {
 	w.msgChan.Close()
 	// We must finish consuming the data here,
@@ -215,4 +251,5 @@ func (w *worker) close() {
 		// Do nothing. We do not care about the data.
 	}
 	w.producer.Close()
+	w.heartbeat.Close()
 }
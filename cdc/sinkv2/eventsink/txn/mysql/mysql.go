@@ -27,14 +27,17 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/heartbeat"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
+	"github.com/pingcap/tiflow/pkg/bdr"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/retry"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 	"go.uber.org/zap"
 )
 
@@ -50,6 +53,14 @@ type mysqlBackend struct {
 	db          *sql.DB
 	cfg         *pmysql.Config
 	dmlMaxRetry uint64
+	// bucket is the index of the backend, it's used to write the mark row
+	// in bdr mode.
+	bucket      int
+	bdrResolver *bdr.ConflictResolver
+	// router routes the rows to the downstream tables.
+	router *sqlmodel.Router
+	// heartbeat is nil if heartbeats are disabled.
+	heartbeat *heartbeat.Observer
 
 	events []*eventsink.TxnCallbackableEvent
 	rows   int
@@ -86,6 +97,20 @@ func NewMySQLBackends(
 	db.SetMaxIdleConns(cfg.WorkerCount)
 	db.SetMaxOpenConns(cfg.WorkerCount)
 
+	var bdrResolver *bdr.ConflictResolver
+	if replicaConfig.BDREnabled() {
+		bdrResolver = bdr.NewConflictResolver(changefeedID, replicaConfig)
+		if err := bdr.CreateMarkTable(ctx, db, bdrResolver.ReplicaID()); err != nil {
+			return nil, err
+		}
+	}
+
+	router, err := sqlmodel.NewRouter(replicaConfig.CaseSensitive, replicaConfig.RoutingRules)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	observer := heartbeat.NewObserver(changefeedID, replicaConfig)
+
 	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
 		backends = append(backends, &mysqlBackend{
@@ -93,6 +118,10 @@ func NewMySQLBackends(
 			db:          db,
 			cfg:         cfg,
 			dmlMaxRetry: defaultDMLMaxRetry,
+			bucket:      i,
+			bdrResolver: bdrResolver,
+			router:      router,
+			heartbeat:   observer,
 			statistics:  statistics,
 		})
 	}
@@ -139,6 +168,10 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 		}
 		return errors.Trace(err)
 	}
+	visibleAt := time.Now()
+	for _, event := range s.events {
+		s.heartbeat.ObserveRows(event.Event.Rows, visibleAt)
+	}
 
 	// Be friently to GC.
 	for i := 0; i < len(s.events); i++ {
@@ -158,6 +191,7 @@ func (s *mysqlBackend) Close() (err error) {
 		err = s.db.Close()
 		s.db = nil
 	}
+	s.heartbeat.Close()
 	return
 }
 
@@ -172,10 +206,20 @@ type preparedDMLs struct {
 	values    [][]interface{}
 	callbacks []eventsink.CallbackFunc
 	rowCount  int
+
+	// markSQL is executed before sqls in bdr mode to mark the transaction.
+	markSQL  string
+	markArgs []interface{}
+	// bdrDMLs are the DMLs of sqls in bdr mode, which must be executed by
+	// the conflict resolver.
+	bdrDMLs []*bdr.DML
 }
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	if s.bdrResolver != nil {
+		return s.prepareBDRDMLs()
+	}
 	// TODO: use a sync.Pool to reduce allocations.
 	startTs := make([]uint64, 0, s.rows)
 	sqls := make([]string, 0, s.rows)
@@ -220,7 +264,8 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 
 			var query string
 			var args []interface{}
-			quoteTable := quotes.QuoteSchema(row.Table.Schema, row.Table.Table)
+			table := s.router.RouteTableName(row.Table)
+			quoteTable := quotes.QuoteSchema(table.Schema, table.Table)
 
 			// If the old value is enabled, is not in safe mode and is an update event, then translate to UPDATE.
 			// NOTICE: Only update events with the old value feature enabled will have both columns and preColumns.
@@ -295,6 +340,36 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 	}
 }
 
+// prepareBDRDMLs converts rows to DMLs in bdr mode. Every row is written by
+// a single DML, which is skipped if it conflicts with the downstream row.
+func (s *mysqlBackend) prepareBDRDMLs() *preparedDMLs {
+	dmls := &preparedDMLs{
+		startTs: make([]uint64, 0, s.rows),
+		sqls:    make([]string, 0, s.rows),
+		values:  make([][]interface{}, 0, s.rows),
+		bdrDMLs: make([]*bdr.DML, 0, s.rows),
+	}
+	dmls.markSQL, dmls.markArgs = s.bdrResolver.MarkSQL(s.bucket)
+	for _, event := range s.events {
+		if event.Callback != nil {
+			dmls.callbacks = append(dmls.callbacks, event.Callback)
+		}
+		for _, row := range event.Event.Rows {
+			if len(dmls.startTs) == 0 || dmls.startTs[len(dmls.startTs)-1] != row.StartTs {
+				dmls.startTs = append(dmls.startTs, row.StartTs)
+			}
+			dml := s.bdrResolver.PrepareDML(s.router.RouteTableName(row.Table), row)
+			if dml != nil {
+				dmls.sqls = append(dmls.sqls, dml.SQL)
+				dmls.values = append(dmls.values, dml.Args)
+				dmls.bdrDMLs = append(dmls.bdrDMLs, dml)
+				dmls.rowCount++
+			}
+		}
+	}
+	return dmls
+}
+
 func (s *mysqlBackend) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDMLs) error {
 	if len(dmls.sqls) != len(dmls.values) {
 		log.Panic("unexpected number of sqls and values",
@@ -320,10 +395,30 @@ func (s *mysqlBackend) execDMLWithMaxRetries(ctx context.Context, dmls *prepared
 					start, s.changefeed, "BEGIN", dmls.rowCount, dmls.startTs)
 			}
 
+			if dmls.markSQL != "" {
+				if _, err := tx.ExecContext(ctx, dmls.markSQL, dmls.markArgs...); err != nil {
+					err := logDMLTxnErr(
+						cerror.WrapError(cerror.ErrMySQLTxnError, err),
+						start, s.changefeed, dmls.markSQL, dmls.rowCount, dmls.startTs)
+					if rbErr := tx.Rollback(); rbErr != nil {
+						if errors.Cause(rbErr) != context.Canceled {
+							log.Warn("failed to rollback txn", zap.Error(rbErr))
+						}
+					}
+					return 0, err
+				}
+			}
+
 			for i, query := range dmls.sqls {
 				args := dmls.values[i]
 				log.Debug("exec row", zap.String("sql", query), zap.Any("args", args))
-				if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				var err error
+				if dmls.bdrDMLs != nil {
+					err = s.bdrResolver.ExecDML(ctx, tx, dmls.bdrDMLs[i])
+				} else {
+					_, err = tx.ExecContext(ctx, query, args...)
+				}
+				if err != nil {
 					err := logDMLTxnErr(
 						cerror.WrapError(cerror.ErrMySQLTxnError, err),
 						start, s.changefeed, query, dmls.rowCount, dmls.startTs)
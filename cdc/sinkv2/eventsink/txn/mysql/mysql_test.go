@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
@@ -149,6 +150,42 @@ func TestPrepareDML(t *testing.T) {
 	}
 }
 
+func TestPrepareRoutedDML(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	router, err := sqlmodel.NewRouter(true, []*config.RoutingRule{
+		{Matcher: []string{"common_1.*"}, TargetSchema: "{schema}_replica"},
+	})
+	require.Nil(t, err)
+	ms.router = router
+
+	table := &model.TableName{Schema: "common_1", Table: "uk_without_pk"}
+	ms.events = []*eventsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{{
+			StartTs:  418658114257813516,
+			CommitTs: 418658114257813517,
+			Table:    table,
+			Columns: []*model.Column{{
+				Name:  "a1",
+				Type:  mysql.TypeLong,
+				Flag:  model.BinaryFlag | model.MultipleKeyFlag | model.HandleKeyFlag,
+				Value: 2,
+			}},
+			IndexColumns: [][]int{{0}},
+		}}},
+	}}
+	ms.rows = 1
+	dmls := ms.prepareDMLs()
+	require.Equal(t, []string{
+		"REPLACE INTO `common_1_replica`.`uk_without_pk`(`a1`) VALUES (?);",
+	}, dmls.sqls)
+	// Rows are shared with other sinks, so they keep the upstream names.
+	require.Equal(t, "common_1", table.Schema)
+}
+
 func TestAdjustSQLMode(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -33,11 +33,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/canal"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/codec/open"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	cmdUtil "github.com/pingcap/tiflow/pkg/cmd/util"
@@ -377,6 +379,9 @@ type Consumer struct {
 	enableTiDBExtension bool
 
 	eventRouter *dispatcher.EventRouter
+	// claimCheckStorage is used to read the full messages of the rows
+	// exceeding max-message-bytes.
+	claimCheckStorage storage.ExternalStorage
 }
 
 // NewConsumer creates a new cdc kafka consumer
@@ -410,6 +415,13 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 		}
 		c.eventRouter = eventRouter
 
+		if eventRouterReplicaConfig.Sink.LargeMessageHandle.EnableClaimCheck() {
+			c.claimCheckStorage, err = common.NewClaimCheckStorage(ctx,
+				eventRouterReplicaConfig.Sink.LargeMessageHandle.ClaimCheckStorageURI)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	}
 
 	c.sinks = make([]*partitionSink, kafkaPartitionNum)
@@ -506,9 +518,10 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		)
 		switch c.protocol {
 		case config.ProtocolOpen, config.ProtocolDefault:
-			decoder, err = open.NewBatchDecoder(message.Key, message.Value)
+			decoder, err = open.NewClaimCheckBatchDecoder(ctx, message.Key, message.Value, c.claimCheckStorage)
 		case config.ProtocolCanalJSON:
-			decoder = canal.NewBatchDecoder(message.Value, c.enableTiDBExtension)
+			decoder = canal.NewClaimCheckBatchDecoder(ctx, message.Value,
+				c.enableTiDBExtension, c.claimCheckStorage)
 		default:
 			log.Panic("Protocol not supported", zap.Any("Protocol", c.protocol))
 		}
@@ -66,6 +66,11 @@ type CDCRESTClient struct {
 
 	// Client is a wrapped http client.
 	Client *httputil.Client
+
+	// user and password are sent by the basic authentication if user is
+	// not empty.
+	user     string
+	password string
 }
 
 // NewCDCRESTClient creates a new CDCRESTClient.
@@ -33,6 +33,10 @@ type Config struct {
 	Credential *security.Credential
 	// API verion
 	Version string
+	// User and Password are used to authenticate with the cdc server, if
+	// the server requires clients to be authenticated by TiDB users.
+	User     string
+	Password string
 }
 
 // defaultServerURLFromConfig is used to build base URL and api path.
@@ -83,6 +87,8 @@ func CDCRESTClientFromConfig(config *Config) (*CDCRESTClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	restClient.user = config.User
+	restClient.password = config.Password
 
 	return restClient, nil
 }
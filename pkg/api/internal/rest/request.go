@@ -255,6 +255,9 @@ func (r *Request) newHTTPRequest(ctx context.Context) (*http.Request, error) {
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.headers
+	if r.c.user != "" {
+		req.SetBasicAuth(r.c.user, r.c.password)
+	}
 	return req, nil
 }
 
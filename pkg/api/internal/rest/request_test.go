@@ -90,6 +90,27 @@ func TestRequestHeader(t *testing.T) {
 	_ = req.Do(context.Background())
 }
 
+func TestRequestBasicAuth(t *testing.T) {
+	cli := httputil.NewTestClient(clientFunc(func(req *http.Request) (*http.Response, error) {
+		user, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "root", user)
+		require.Equal(t, "pass", password)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}))
+	req := NewRequestWithClient(&url.URL{Path: "/test"}, "", nil).WithMethod(HTTPMethodGet)
+	req.c.Client = cli
+	req.c.user = "root"
+	req.c.password = "pass"
+
+	res := req.Do(context.Background())
+	require.Nil(t, res.Error())
+}
+
 func TestRequestDoContext(t *testing.T) {
 	received := make(chan struct{})
 	blocked := make(chan struct{})
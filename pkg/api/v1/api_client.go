@@ -66,12 +66,16 @@ func (c *APIV1Client) Status() StatusInterface {
 }
 
 // NewAPIClient creates a new APIV1Client.
-func NewAPIClient(ownerAddr string, credential *security.Credential) (*APIV1Client, error) {
+func NewAPIClient(
+	ownerAddr string, credential *security.Credential, user, password string,
+) (*APIV1Client, error) {
 	c := &rest.Config{}
 	c.APIPath = "/api"
 	c.Version = "v1"
 	c.Host = ownerAddr
 	c.Credential = credential
+	c.User = user
+	c.Password = password
 	client, err := rest.CDCRESTClientFromConfig(c)
 	if err != nil {
 		return nil, err
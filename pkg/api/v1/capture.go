@@ -29,6 +29,8 @@ type CapturesGetter interface {
 // We can also mock the capture operations by implement this interface.
 type CaptureInterface interface {
 	List(ctx context.Context) (*[]model.Capture, error)
+	Drain(ctx context.Context, captureID string) (*model.DrainCaptureResp, error)
+	ResignOwner(ctx context.Context) error
 }
 
 // captures implements CaptureInterface
@@ -52,3 +54,24 @@ func (c *captures) List(ctx context.Context) (*[]model.Capture, error) {
 		Into(result)
 	return result, err
 }
+
+// Drain moves all tables away from the capture, and returns the number of
+// tables that are still replicated by the capture.
+func (c *captures) Drain(ctx context.Context,
+	captureID string,
+) (*model.DrainCaptureResp, error) {
+	result := new(model.DrainCaptureResp)
+	err := c.client.Put().
+		WithURI("captures/drain").
+		WithBody(&model.DrainCaptureRequest{CaptureID: captureID}).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// ResignOwner makes the current owner resign
+func (c *captures) ResignOwner(ctx context.Context) error {
+	return c.client.Post().
+		WithURI("owner/resign").
+		Do(ctx).Error()
+}
@@ -34,6 +34,7 @@ type ChangefeedInterface interface {
 	Delete(ctx context.Context, name string) error
 	Pause(ctx context.Context, name string) error
 	Resume(ctx context.Context, name string) error
+	MoveTable(ctx context.Context, name string, tableID int64, captureID string) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -89,6 +90,20 @@ func (c *changefeeds) Resume(ctx context.Context, name string) error {
 		Do(ctx).Error()
 }
 
+// MoveTable moves a table of the changefeed to the capture
+func (c *changefeeds) MoveTable(ctx context.Context,
+	name string, tableID int64, captureID string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/tables/move_table", name)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(&struct {
+			CaptureID string `json:"capture_id"`
+			TableID   int64  `json:"table_id"`
+		}{CaptureID: captureID, TableID: tableID}).
+		Do(ctx).Error()
+}
+
 // Delete delete the changefeed
 func (c *changefeeds) Delete(ctx context.Context, name string) error {
 	u := fmt.Sprintf("changefeeds/%s", name)
@@ -74,6 +74,22 @@ func (m *MockCaptureInterface) EXPECT() *MockCaptureInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Drain mocks base method.
+func (m *MockCaptureInterface) Drain(ctx context.Context, captureID string) (*model.DrainCaptureResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx, captureID)
+	ret0, _ := ret[0].(*model.DrainCaptureResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockCaptureInterfaceMockRecorder) Drain(ctx, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain",
+		reflect.TypeOf((*MockCaptureInterface)(nil).Drain), ctx, captureID)
+}
+
 // List mocks base method.
 func (m *MockCaptureInterface) List(ctx context.Context) (*[]model.Capture, error) {
 	m.ctrl.T.Helper()
@@ -89,3 +105,18 @@ func (mr *MockCaptureInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List",
 		reflect.TypeOf((*MockCaptureInterface)(nil).List), ctx)
 }
+
+// ResignOwner mocks base method.
+func (m *MockCaptureInterface) ResignOwner(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResignOwner", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResignOwner indicates an expected call of ResignOwner.
+func (mr *MockCaptureInterfaceMockRecorder) ResignOwner(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResignOwner",
+		reflect.TypeOf((*MockCaptureInterface)(nil).ResignOwner), ctx)
+}
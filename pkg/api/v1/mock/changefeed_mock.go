@@ -125,6 +125,23 @@ func (mr *MockChangefeedInterfaceMockRecorder) List(ctx, state interface{}) *gom
 		reflect.TypeOf((*MockChangefeedInterface)(nil).List), ctx, state)
 }
 
+// MoveTable mocks base method.
+func (m *MockChangefeedInterface) MoveTable(ctx context.Context,
+	name string, tableID int64, captureID string,
+) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTable", ctx, name, tableID, captureID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveTable indicates an expected call of MoveTable.
+func (mr *MockChangefeedInterfaceMockRecorder) MoveTable(ctx, name, tableID, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTable",
+		reflect.TypeOf((*MockChangefeedInterface)(nil).MoveTable), ctx, name, tableID, captureID)
+}
+
 // Pause mocks base method.
 func (m *MockChangefeedInterface) Pause(ctx context.Context, name string) error {
 	m.ctrl.T.Helper()
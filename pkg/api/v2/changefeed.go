@@ -16,8 +16,10 @@ package v2
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/api/internal/rest"
 )
 
@@ -33,6 +35,9 @@ type ChangefeedInterface interface {
 	Create(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error)
 	// GetInfo gets a changefeed's info
 	GetInfo(ctx context.Context, name string) (*v2.ChangeFeedInfo, error)
+	// DryRun simulates creating a changefeed and reports how it would
+	// replicate the tables
+	DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.DryRunReport, error)
 	// VerifyTable verifies table for a changefeed
 	VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error)
 	// Update updates a changefeed
@@ -40,6 +45,23 @@ type ChangefeedInterface interface {
 		name string) (*v2.ChangeFeedInfo, error)
 	// Resume resumes a changefeed with given config
 	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error
+	// Clone creates a changefeed with the config of the given changefeed
+	Clone(ctx context.Context, cfg *v2.CloneChangefeedConfig,
+		name string) (*v2.ChangeFeedInfo, error)
+	// Diagnose gets the replication progress of all tables of a changefeed
+	Diagnose(ctx context.Context, name string, limit int) ([]model.TableDiagnosis, error)
+	// UpdateTables changes the tables replicated by a running changefeed
+	UpdateTables(ctx context.Context, cfg *v2.TableSetChangeConfig,
+		name string) (*v2.TableSetChange, error)
+	// ApproveDDL approves or rejects the DDL held by a changefeed
+	ApproveDDL(ctx context.Context, cfg *v2.ApproveDDLConfig, name string) error
+	// GetTableSchema gets the definition of a table of a changefeed at a ts,
+	// the checkpoint ts of the changefeed is used if ts is 0
+	GetTableSchema(ctx context.Context, name string,
+		table string, ts uint64) (*v2.TableSchema, error)
+	// GetEvents gets the event log of a changefeed, only the latest events
+	// are returned if limit is greater than 0
+	GetEvents(ctx context.Context, name string, limit int) ([]v2.ChangefeedEvent, error)
 }
 
 // changefeeds implements ChangefeedInterface
@@ -77,6 +99,19 @@ func (c *changefeeds) VerifyTable(ctx context.Context,
 	return result, err
 }
 
+// DryRun simulates creating a changefeed
+func (c *changefeeds) DryRun(ctx context.Context,
+	cfg *v2.ChangefeedConfig,
+) (*v2.DryRunReport, error) {
+	result := &v2.DryRunReport{}
+	err := c.client.Post().
+		WithURI("dry_run_changefeed").
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
 func (c *changefeeds) GetInfo(ctx context.Context,
 	name string,
 ) (*v2.ChangeFeedInfo, error) {
@@ -112,3 +147,86 @@ func (c *changefeeds) Resume(ctx context.Context,
 		WithBody(cfg).
 		Do(ctx).Error()
 }
+
+// Clone creates a changefeed with the config of a changefeed
+func (c *changefeeds) Clone(ctx context.Context,
+	cfg *v2.CloneChangefeedConfig, name string,
+) (*v2.ChangeFeedInfo, error) {
+	result := &v2.ChangeFeedInfo{}
+	u := fmt.Sprintf("changefeeds/%s/clone", name)
+	err := c.client.Post().
+		WithURI(u).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Diagnose gets the replication progress of all tables of a changefeed
+func (c *changefeeds) Diagnose(ctx context.Context,
+	name string, limit int,
+) ([]model.TableDiagnosis, error) {
+	var result []model.TableDiagnosis
+	u := fmt.Sprintf("changefeeds/%s/diagnosis", name)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("limit", strconv.Itoa(limit)).
+		Do(ctx).
+		Into(&result)
+	return result, err
+}
+
+// UpdateTables changes the tables replicated by a running changefeed
+func (c *changefeeds) UpdateTables(ctx context.Context,
+	cfg *v2.TableSetChangeConfig, name string,
+) (*v2.TableSetChange, error) {
+	result := &v2.TableSetChange{}
+	u := fmt.Sprintf("changefeeds/%s/tables", name)
+	err := c.client.Post().
+		WithURI(u).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// ApproveDDL approves or rejects the DDL held by a changefeed
+func (c *changefeeds) ApproveDDL(ctx context.Context,
+	cfg *v2.ApproveDDLConfig, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/approve_ddl", name)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(cfg).
+		Do(ctx).Error()
+}
+
+// GetTableSchema gets the definition of a table of a changefeed at a ts
+func (c *changefeeds) GetTableSchema(ctx context.Context,
+	name string, table string, ts uint64,
+) (*v2.TableSchema, error) {
+	result := &v2.TableSchema{}
+	u := fmt.Sprintf("changefeeds/%s/schema", name)
+	req := c.client.Get().
+		WithURI(u).
+		WithParam("table", table)
+	if ts != 0 {
+		req = req.WithParam("ts", strconv.FormatUint(ts, 10))
+	}
+	err := req.Do(ctx).Into(result)
+	return result, err
+}
+
+// GetEvents gets the event log of a changefeed
+func (c *changefeeds) GetEvents(ctx context.Context,
+	name string, limit int,
+) ([]v2.ChangefeedEvent, error) {
+	var result []v2.ChangefeedEvent
+	u := fmt.Sprintf("changefeeds/%s/events", name)
+	req := c.client.Get().WithURI(u)
+	if limit > 0 {
+		req = req.WithParam("limit", strconv.Itoa(limit))
+	}
+	err := req.Do(ctx).Into(&result)
+	return result, err
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	model "github.com/pingcap/tiflow/cdc/model"
 	v20 "github.com/pingcap/tiflow/pkg/api/v2"
 )
 
@@ -73,6 +74,35 @@ func (m *MockChangefeedInterface) EXPECT() *MockChangefeedInterfaceMockRecorder
 	return m.recorder
 }
 
+// ApproveDDL mocks base method.
+func (m *MockChangefeedInterface) ApproveDDL(ctx context.Context, cfg *v2.ApproveDDLConfig, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDDL", ctx, cfg, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveDDL indicates an expected call of ApproveDDL.
+func (mr *MockChangefeedInterfaceMockRecorder) ApproveDDL(ctx, cfg, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDDL", reflect.TypeOf((*MockChangefeedInterface)(nil).ApproveDDL), ctx, cfg, name)
+}
+
+// Clone mocks base method.
+func (m *MockChangefeedInterface) Clone(ctx context.Context, cfg *v2.CloneChangefeedConfig, name string) (*v2.ChangeFeedInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clone", ctx, cfg, name)
+	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clone indicates an expected call of Clone.
+func (mr *MockChangefeedInterfaceMockRecorder) Clone(ctx, cfg, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockChangefeedInterface)(nil).Clone), ctx, cfg, name)
+}
+
 // Create mocks base method.
 func (m *MockChangefeedInterface) Create(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -88,6 +118,51 @@ func (mr *MockChangefeedInterfaceMockRecorder) Create(ctx, cfg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChangefeedInterface)(nil).Create), ctx, cfg)
 }
 
+// Diagnose mocks base method.
+func (m *MockChangefeedInterface) Diagnose(ctx context.Context, name string, limit int) ([]model.TableDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diagnose", ctx, name, limit)
+	ret0, _ := ret[0].([]model.TableDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diagnose indicates an expected call of Diagnose.
+func (mr *MockChangefeedInterfaceMockRecorder) Diagnose(ctx, name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diagnose", reflect.TypeOf((*MockChangefeedInterface)(nil).Diagnose), ctx, name, limit)
+}
+
+// DryRun mocks base method.
+func (m *MockChangefeedInterface) DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.DryRunReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", ctx, cfg)
+	ret0, _ := ret[0].(*v2.DryRunReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockChangefeedInterfaceMockRecorder) DryRun(ctx, cfg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockChangefeedInterface)(nil).DryRun), ctx, cfg)
+}
+
+// GetEvents mocks base method.
+func (m *MockChangefeedInterface) GetEvents(ctx context.Context, name string, limit int) ([]v2.ChangefeedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, name, limit)
+	ret0, _ := ret[0].([]v2.ChangefeedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockChangefeedInterfaceMockRecorder) GetEvents(ctx, name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockChangefeedInterface)(nil).GetEvents), ctx, name, limit)
+}
+
 // GetInfo mocks base method.
 func (m *MockChangefeedInterface) GetInfo(ctx context.Context, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +178,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) GetInfo(ctx, name interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockChangefeedInterface)(nil).GetInfo), ctx, name)
 }
 
+// GetTableSchema mocks base method.
+func (m *MockChangefeedInterface) GetTableSchema(ctx context.Context, name, table string, ts uint64) (*v2.TableSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTableSchema", ctx, name, table, ts)
+	ret0, _ := ret[0].(*v2.TableSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTableSchema indicates an expected call of GetTableSchema.
+func (mr *MockChangefeedInterfaceMockRecorder) GetTableSchema(ctx, name, table, ts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTableSchema", reflect.TypeOf((*MockChangefeedInterface)(nil).GetTableSchema), ctx, name, table, ts)
+}
+
 // Resume mocks base method.
 func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error {
 	m.ctrl.T.Helper()
@@ -132,6 +222,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, name interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, name)
 }
 
+// UpdateTables mocks base method.
+func (m *MockChangefeedInterface) UpdateTables(ctx context.Context, cfg *v2.TableSetChangeConfig, name string) (*v2.TableSetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTables", ctx, cfg, name)
+	ret0, _ := ret[0].(*v2.TableSetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTables indicates an expected call of UpdateTables.
+func (mr *MockChangefeedInterfaceMockRecorder) UpdateTables(ctx, cfg, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTables", reflect.TypeOf((*MockChangefeedInterface)(nil).UpdateTables), ctx, cfg, name)
+}
+
 // VerifyTable mocks base method.
 func (m *MockChangefeedInterface) VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error) {
 	m.ctrl.T.Helper()
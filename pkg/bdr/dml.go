@@ -0,0 +1,422 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// ConflictResolver builds and executes the DMLs of rows replicated in bdr
+// mode. Before a replicated row is written, the downstream row it may
+// conflict with is selected and locked, a DML losing to the downstream row is
+// skipped and reported as a conflict.
+//
+// With `last-writer-wins`, the version of a replicated row is its commit ts,
+// which is also written into the version column. A replicated row is applied
+// only if its version is greater than the version of the downstream row. With
+// `reject`, a replicated row is applied only if the downstream row is the
+// same as the row before the change in the upstream.
+type ConflictResolver struct {
+	changefeedID   model.ChangeFeedID
+	replicaID      uint64
+	resolution     string
+	versionColumn  string
+	forceReplicate bool
+	// winTies indicates whether a replicated row wins the downstream row
+	// with the same version. The cluster with the greater replica ID wins,
+	// so all clusters keep the same row.
+	winTies bool
+
+	metricConflicts prometheus.Counter
+}
+
+// NewConflictResolver creates a ConflictResolver, the changefeed must be in
+// bdr mode.
+func NewConflictResolver(
+	changefeedID model.ChangeFeedID, cfg *config.ReplicaConfig,
+) *ConflictResolver {
+	winTies := true
+	for _, id := range cfg.BDR.FilterReplicaIDs {
+		if id > cfg.BDR.ReplicaID {
+			winTies = false
+		}
+	}
+	versionColumn := ""
+	if cfg.BDR.ConflictResolution == config.ConflictResolutionLastWriterWins {
+		versionColumn = cfg.BDR.VersionColumn
+	}
+	return &ConflictResolver{
+		changefeedID:   changefeedID,
+		replicaID:      cfg.BDR.ReplicaID,
+		resolution:     cfg.BDR.ConflictResolution,
+		versionColumn:  versionColumn,
+		forceReplicate: cfg.ForceReplicate,
+		winTies:        winTies,
+		metricConflicts: conflictCounter.WithLabelValues(
+			changefeedID.Namespace, changefeedID.ID, cfg.BDR.ConflictResolution),
+	}
+}
+
+// ReplicaID returns the replica ID marking transactions.
+func (r *ConflictResolver) ReplicaID() uint64 {
+	return r.replicaID
+}
+
+// MarkSQL returns the statement marking the transaction as written by TiCDC,
+// it must be executed in every transaction written by the changefeed.
+func (r *ConflictResolver) MarkSQL(bucket int) (string, []interface{}) {
+	return markSQL(r.replicaID), []interface{}{bucket}
+}
+
+// action is what to do with a DML after checking the downstream row.
+type action int
+
+const (
+	actionApply action = iota
+	actionSkip
+	actionConflict
+	// actionCompare applies the DML only if its version wins the version of
+	// the downstream row.
+	actionCompare
+)
+
+// DML writes a replicated row into the downstream in bdr mode, it must be
+// executed by ConflictResolver.ExecDML.
+type DML struct {
+	// SQL writes the row if it doesn't conflict with the downstream row.
+	SQL  string
+	Args []interface{}
+
+	// checkSQL selects and locks the downstream row, it returns the version
+	// of the row with `last-writer-wins`.
+	checkSQL  string
+	checkArgs []interface{}
+	// version is the commit ts of the replicated row.
+	version   uint64
+	ifAbsent  action
+	ifPresent action
+}
+
+// PrepareDML returns the DML writing the row to the table, which is the
+// routed table of the row in the downstream. It returns nil if the
+// downstream row can't be identified.
+func (r *ConflictResolver) PrepareDML(
+	table *model.TableName, row *model.RowChangedEvent,
+) *DML {
+	quoteTable := quotes.QuoteSchema(table.Schema, table.Table)
+	var dml *DML
+	switch {
+	case len(row.PreColumns) != 0 && len(row.Columns) != 0:
+		dml = r.prepareUpdate(quoteTable, row.PreColumns, row.Columns, row.CommitTs)
+	case len(row.PreColumns) != 0:
+		dml = r.prepareDelete(quoteTable, row.PreColumns)
+	default:
+		dml = r.prepareInsert(quoteTable, row.Columns, row.CommitTs)
+	}
+	if dml != nil {
+		dml.version = row.CommitTs
+	}
+	return dml
+}
+
+// ExecDML executes the DML in the transaction. A DML losing to the downstream
+// row is skipped and reported as a conflict.
+func (r *ConflictResolver) ExecDML(ctx context.Context, tx *sql.Tx, dml *DML) error {
+	var version sql.NullInt64
+	act := dml.ifPresent
+	err := tx.QueryRowContext(ctx, dml.checkSQL, dml.checkArgs...).Scan(&version)
+	switch {
+	case err == sql.ErrNoRows:
+		act = dml.ifAbsent
+	case err != nil:
+		return errors.Trace(err)
+	}
+	if act == actionCompare {
+		act = actionConflict
+		// A downstream row without a version is older than any replicated row.
+		if !version.Valid || r.wins(dml.version, uint64(version.Int64)) {
+			act = actionApply
+		}
+	}
+	switch act {
+	case actionApply:
+		_, err := tx.ExecContext(ctx, dml.SQL, dml.Args...)
+		return errors.Trace(err)
+	case actionConflict:
+		r.onConflict(dml, version)
+	}
+	return nil
+}
+
+// wins returns whether the replicated row with the version wins the
+// downstream row with the downstream version.
+func (r *ConflictResolver) wins(version, downstreamVersion uint64) bool {
+	if version == downstreamVersion {
+		return r.winTies
+	}
+	return version > downstreamVersion
+}
+
+// onConflict reports a DML which is not applied to the downstream.
+func (r *ConflictResolver) onConflict(dml *DML, downstreamVersion sql.NullInt64) {
+	r.metricConflicts.Inc()
+	fields := []zap.Field{
+		zap.String("namespace", r.changefeedID.Namespace),
+		zap.String("changefeed", r.changefeedID.ID),
+		zap.String("resolution", r.resolution),
+		zap.String("query", dml.SQL),
+		zap.Any("args", dml.Args),
+	}
+	if r.versionColumn != "" {
+		fields = append(fields, zap.Uint64("version", dml.version))
+		if downstreamVersion.Valid {
+			fields = append(fields, zap.Int64("downstreamVersion", downstreamVersion.Int64))
+		}
+	}
+	log.Warn("replicated row conflicts with the downstream row and is not applied",
+		fields...)
+}
+
+// prepareInsert builds the DML of an inserted row as following
+// check: `SELECT v FROM t WHERE id=? LIMIT 1 FOR UPDATE`
+// sql: `INSERT INTO t (id,a,v) VALUES (?,?,?) ON DUPLICATE KEY UPDATE
+// id=VALUES(id),a=VALUES(a),v=VALUES(v)`
+// With `reject`, the row is inserted only if there is no downstream row.
+func (r *ConflictResolver) prepareInsert(
+	quoteTable string, cols []*model.Column, commitTs uint64,
+) *DML {
+	colNames, args := r.valueSlice(cols, commitTs)
+	keyNames, keyArgs := r.keySlice(cols)
+	if len(args) == 0 || len(keyNames) == 0 {
+		return nil
+	}
+	dml := r.prepareCheck(quoteTable, keyNames, keyArgs)
+	dml.ifAbsent = actionApply
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO " + quoteTable +
+		" (" + buildColumnList(colNames) + ") VALUES (" + placeHolder(len(colNames)) + ")")
+	if r.versionColumn != "" {
+		dml.ifPresent = actionCompare
+		builder.WriteString(" ON DUPLICATE KEY UPDATE ")
+		for i, colName := range colNames {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			name := quotes.QuoteName(colName)
+			builder.WriteString(name + "=VALUES(" + name + ")")
+		}
+	} else {
+		dml.ifPresent = actionConflict
+	}
+	dml.SQL, dml.Args = builder.String(), args
+	return dml
+}
+
+// prepareUpdate builds the DML of an updated row as following
+// check: `SELECT v FROM t WHERE id=? LIMIT 1 FOR UPDATE`
+// sql: `UPDATE t SET id=?,a=?,v=? WHERE id=? LIMIT 1`
+// The row is updated only if the downstream row exists.
+func (r *ConflictResolver) prepareUpdate(
+	quoteTable string, preCols, cols []*model.Column, commitTs uint64,
+) *DML {
+	colNames, args := r.valueSlice(cols, commitTs)
+	whereNames, whereArgs := r.whereSlice(preCols)
+	if len(args) == 0 || len(whereNames) == 0 {
+		return nil
+	}
+	dml := r.prepareCheck(quoteTable, whereNames, whereArgs)
+	dml.ifAbsent = actionConflict
+	dml.ifPresent = actionApply
+	if r.versionColumn != "" {
+		dml.ifPresent = actionCompare
+	}
+	var builder strings.Builder
+	builder.WriteString("UPDATE " + quoteTable + " SET ")
+	for i, colName := range colNames {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(quotes.QuoteName(colName) + "=?")
+	}
+	builder.WriteString(" WHERE ")
+	args = writeWhere(&builder, whereNames, whereArgs, args)
+	builder.WriteString(" LIMIT 1")
+	dml.SQL, dml.Args = builder.String(), args
+	return dml
+}
+
+// prepareDelete builds the DML of a deleted row as following
+// check: `SELECT v FROM t WHERE id=? LIMIT 1 FOR UPDATE`
+// sql: `DELETE FROM t WHERE id=? LIMIT 1`
+// With `last-writer-wins`, a row which has been deleted in the downstream is
+// skipped.
+func (r *ConflictResolver) prepareDelete(
+	quoteTable string, preCols []*model.Column,
+) *DML {
+	whereNames, whereArgs := r.whereSlice(preCols)
+	if len(whereNames) == 0 {
+		return nil
+	}
+	dml := r.prepareCheck(quoteTable, whereNames, whereArgs)
+	dml.ifAbsent = actionConflict
+	dml.ifPresent = actionApply
+	if r.versionColumn != "" {
+		dml.ifAbsent = actionSkip
+		dml.ifPresent = actionCompare
+	}
+	var builder strings.Builder
+	builder.WriteString("DELETE FROM " + quoteTable + " WHERE ")
+	dml.Args = writeWhere(&builder, whereNames, whereArgs, nil)
+	builder.WriteString(" LIMIT 1")
+	dml.SQL = builder.String()
+	return dml
+}
+
+// prepareCheck returns a DML with the statement selecting and locking the
+// downstream row.
+func (r *ConflictResolver) prepareCheck(
+	quoteTable string, whereNames []string, whereArgs []interface{},
+) *DML {
+	selected := "1"
+	if r.versionColumn != "" {
+		selected = quotes.QuoteName(r.versionColumn)
+	}
+	var builder strings.Builder
+	builder.WriteString("SELECT " + selected + " FROM " + quoteTable + " WHERE ")
+	args := writeWhere(&builder, whereNames, whereArgs, nil)
+	builder.WriteString(" LIMIT 1 FOR UPDATE")
+	return &DML{checkSQL: builder.String(), checkArgs: args}
+}
+
+// keySlice returns the handle key columns identifying the downstream row.
+func (r *ConflictResolver) keySlice(cols []*model.Column) ([]string, []interface{}) {
+	var colNames []string
+	var args []interface{}
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() || !col.Flag.IsHandleKey() {
+			continue
+		}
+		colNames = append(colNames, col.Name)
+		args = appendQueryArgs(args, col)
+	}
+	if len(colNames) == 0 && r.forceReplicate {
+		colNames, args = r.valueSlice(cols, 0)
+	}
+	return colNames, args
+}
+
+// valueSlice returns the columns written to the downstream. With
+// `last-writer-wins`, the version column is set to the commit ts.
+func (r *ConflictResolver) valueSlice(
+	cols []*model.Column, commitTs uint64,
+) ([]string, []interface{}) {
+	colNames := make([]string, 0, len(cols)+1)
+	args := make([]interface{}, 0, len(cols)+1)
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() || r.isVersionColumn(col) {
+			continue
+		}
+		colNames = append(colNames, col.Name)
+		args = appendQueryArgs(args, col)
+	}
+	if r.versionColumn != "" && len(colNames) != 0 && commitTs != 0 {
+		colNames = append(colNames, r.versionColumn)
+		args = append(args, commitTs)
+	}
+	return colNames, args
+}
+
+// whereSlice returns the columns identifying the downstream row of the row
+// before a change. With `reject`, all columns are used, so the downstream row
+// must be the same as the row before the change.
+func (r *ConflictResolver) whereSlice(cols []*model.Column) ([]string, []interface{}) {
+	if r.resolution != config.ConflictResolutionReject {
+		return r.keySlice(cols)
+	}
+	var colNames []string
+	var args []interface{}
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() {
+			continue
+		}
+		colNames = append(colNames, col.Name)
+		args = appendQueryArgs(args, col)
+	}
+	return colNames, args
+}
+
+func (r *ConflictResolver) isVersionColumn(col *model.Column) bool {
+	return r.versionColumn != "" && strings.EqualFold(col.Name, r.versionColumn)
+}
+
+func writeWhere(
+	builder *strings.Builder, colNames []string, whereArgs, args []interface{},
+) []interface{} {
+	for i, colName := range colNames {
+		if i > 0 {
+			builder.WriteString(" AND ")
+		}
+		if whereArgs[i] == nil {
+			builder.WriteString(quotes.QuoteName(colName) + " IS NULL")
+		} else {
+			builder.WriteString(quotes.QuoteName(colName) + "=?")
+			args = append(args, whereArgs[i])
+		}
+	}
+	return args
+}
+
+// appendQueryArgs appends the value of the column, see the function with the
+// same name of the mysql sink.
+func appendQueryArgs(args []interface{}, col *model.Column) []interface{} {
+	if col.Charset != "" && col.Charset != charset.CharsetBin {
+		if colValBytes, ok := col.Value.([]byte); ok {
+			return append(args, string(colValBytes))
+		}
+	}
+	return append(args, col.Value)
+}
+
+func buildColumnList(names []string) string {
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(quotes.QuoteName(name))
+	}
+	return b.String()
+}
+
+func placeHolder(n int) string {
+	var builder strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("?")
+	}
+	return builder.String()
+}
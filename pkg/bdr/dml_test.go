@@ -0,0 +1,200 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResolver(resolution string, replicaID uint64) *ConflictResolver {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.BDR = &config.BDRConfig{
+		Enable:             true,
+		ReplicaID:          replicaID,
+		FilterReplicaIDs:   []uint64{2},
+		ConflictResolution: resolution,
+		VersionColumn:      "v",
+	}
+	return NewConflictResolver(model.DefaultChangeFeedID("test"), cfg)
+}
+
+func TestMark(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "bdr_mark_1", MarkTableName(1))
+	require.True(t, IsMarkTable("tidb_cdc", "bdr_mark_1"))
+	require.True(t, IsMarkTable("TIDB_CDC", "BDR_MARK_2"))
+	require.False(t, IsMarkTable("tidb_cdc", "syncpoint_v1"))
+	require.False(t, IsMarkTable("test", "bdr_mark_1"))
+
+	r := newTestResolver(config.ConflictResolutionLastWriterWins, 1)
+	query, args := r.MarkSQL(3)
+	require.Equal(t, "INSERT INTO `tidb_cdc`.`bdr_mark_1` (`bucket`, `val`) VALUES (?, 1) "+
+		"ON DUPLICATE KEY UPDATE `val` = `val` + 1", query)
+	require.Equal(t, []interface{}{3}, args)
+}
+
+func TestPrepareDMLLastWriterWins(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	preCols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "a", Value: "x"},
+		{Name: "v", Value: 10},
+	}
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "a", Value: "y"},
+		{Name: "v", Value: 20},
+	}
+
+	// The version column is set to the commit ts.
+	r := newTestResolver(config.ConflictResolutionLastWriterWins, 1)
+	dml := r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, Columns: cols, CommitTs: 100,
+	})
+	require.Equal(t, "SELECT `v` FROM `test`.`t` WHERE `id`=? LIMIT 1 FOR UPDATE", dml.checkSQL)
+	require.Equal(t, []interface{}{1}, dml.checkArgs)
+	require.Equal(t, "INSERT INTO `test`.`t` (`id`,`a`,`v`) VALUES (?,?,?) "+
+		"ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`a`=VALUES(`a`),`v`=VALUES(`v`)", dml.SQL)
+	require.Equal(t, []interface{}{1, "y", uint64(100)}, dml.Args)
+	require.Equal(t, uint64(100), dml.version)
+	require.Equal(t, actionApply, dml.ifAbsent)
+	require.Equal(t, actionCompare, dml.ifPresent)
+
+	// The version column is written even if the upstream table doesn't have it.
+	dml = r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, PreColumns: preCols[:2], Columns: cols[:2], CommitTs: 100,
+	})
+	require.Equal(t, "UPDATE `test`.`t` SET `id`=?,`a`=?,`v`=? WHERE `id`=? LIMIT 1", dml.SQL)
+	require.Equal(t, []interface{}{1, "y", uint64(100), 1}, dml.Args)
+	require.Equal(t, actionConflict, dml.ifAbsent)
+	require.Equal(t, actionCompare, dml.ifPresent)
+
+	dml = r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, PreColumns: preCols, CommitTs: 100,
+	})
+	require.Equal(t, "SELECT `v` FROM `test`.`t` WHERE `id`=? LIMIT 1 FOR UPDATE", dml.checkSQL)
+	require.Equal(t, "DELETE FROM `test`.`t` WHERE `id`=? LIMIT 1", dml.SQL)
+	require.Equal(t, []interface{}{1}, dml.Args)
+	require.Equal(t, actionSkip, dml.ifAbsent)
+	require.Equal(t, actionCompare, dml.ifPresent)
+
+	// Rows without handle keys can't be identified.
+	require.Nil(t, r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, Columns: cols[1:], CommitTs: 100,
+	}))
+}
+
+func TestPrepareDMLReject(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	preCols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "a", Value: nil},
+		{Name: "g", Value: 2, Flag: model.GeneratedColumnFlag},
+	}
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "a", Value: "y"},
+		{Name: "g", Value: 2, Flag: model.GeneratedColumnFlag},
+	}
+
+	r := newTestResolver(config.ConflictResolutionReject, 1)
+	dml := r.PrepareDML(table, &model.RowChangedEvent{Table: table, Columns: cols})
+	require.Equal(t, "SELECT 1 FROM `test`.`t` WHERE `id`=? LIMIT 1 FOR UPDATE", dml.checkSQL)
+	require.Equal(t, "INSERT INTO `test`.`t` (`id`,`a`) VALUES (?,?)", dml.SQL)
+	require.Equal(t, []interface{}{1, "y"}, dml.Args)
+	require.Equal(t, actionApply, dml.ifAbsent)
+	require.Equal(t, actionConflict, dml.ifPresent)
+
+	dml = r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, PreColumns: preCols, Columns: cols,
+	})
+	require.Equal(t, "SELECT 1 FROM `test`.`t` WHERE `id`=? AND `a` IS NULL "+
+		"LIMIT 1 FOR UPDATE", dml.checkSQL)
+	require.Equal(t, []interface{}{1}, dml.checkArgs)
+	require.Equal(t, "UPDATE `test`.`t` SET `id`=?,`a`=? "+
+		"WHERE `id`=? AND `a` IS NULL LIMIT 1", dml.SQL)
+	require.Equal(t, []interface{}{1, "y", 1}, dml.Args)
+	require.Equal(t, actionConflict, dml.ifAbsent)
+	require.Equal(t, actionApply, dml.ifPresent)
+
+	dml = r.PrepareDML(table, &model.RowChangedEvent{Table: table, PreColumns: preCols})
+	require.Equal(t, "DELETE FROM `test`.`t` WHERE `id`=? AND `a` IS NULL LIMIT 1", dml.SQL)
+	require.Equal(t, []interface{}{1}, dml.Args)
+	require.Equal(t, actionConflict, dml.ifAbsent)
+	require.Equal(t, actionApply, dml.ifPresent)
+}
+
+func TestExecDML(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.Nil(t, err)
+	defer db.Close() //nolint:errcheck
+	ctx := context.Background()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "a", Value: "y"},
+	}
+	checkSQL := "SELECT `v` FROM `test`.`t` WHERE `id`=? LIMIT 1 FOR UPDATE"
+	updateSQL := "UPDATE `test`.`t` SET `id`=?,`a`=?,`v`=? WHERE `id`=? LIMIT 1"
+	// The replica with the smaller ID loses ties.
+	r := newTestResolver(config.ConflictResolutionLastWriterWins, 1)
+	dml := r.PrepareDML(table, &model.RowChangedEvent{
+		Table: table, PreColumns: cols, Columns: cols, CommitTs: 100,
+	})
+
+	mock.ExpectBegin()
+	// The downstream row is older, the row is applied even if no columns
+	// are changed.
+	mock.ExpectQuery(checkSQL).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(99))
+	mock.ExpectExec(updateSQL).WithArgs(1, "y", 100, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// The downstream row has no version.
+	mock.ExpectQuery(checkSQL).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(nil))
+	mock.ExpectExec(updateSQL).WithArgs(1, "y", 100, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The downstream row has the same version, the tie is lost.
+	mock.ExpectQuery(checkSQL).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(100))
+	// The downstream row is newer.
+	mock.ExpectQuery(checkSQL).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(101))
+	// The downstream row is deleted.
+	mock.ExpectQuery(checkSQL).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}))
+	mock.ExpectCommit()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		require.Nil(t, r.ExecDML(ctx, tx, dml))
+	}
+	require.Nil(t, tx.Commit())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
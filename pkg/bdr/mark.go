@@ -0,0 +1,74 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+const (
+	// MarkSchema is the schema of mark tables.
+	MarkSchema = "tidb_cdc"
+	// MarkPullerTableName is the pseudo table name of the mark table puller,
+	// it's used in logs and metrics.
+	MarkPullerTableName = "BDR_MARK_PULLER"
+
+	markTablePrefix = "bdr_mark_"
+)
+
+// MarkTableName returns the name of the mark table of the replica. Every
+// transaction written by a changefeed in bdr mode writes a row of the mark
+// table of its replica ID.
+func MarkTableName(replicaID uint64) string {
+	return fmt.Sprintf("%s%d", markTablePrefix, replicaID)
+}
+
+// IsMarkTable returns whether the table is a mark table, mark tables are
+// never replicated.
+func IsMarkTable(schema, table string) bool {
+	return strings.EqualFold(schema, MarkSchema) &&
+		strings.HasPrefix(strings.ToLower(table), markTablePrefix)
+}
+
+// CreateMarkTable creates the mark table of the replica in the downstream.
+func CreateMarkTable(ctx context.Context, db *sql.DB, replicaID uint64) error {
+	queries := []string{
+		"CREATE DATABASE IF NOT EXISTS " + quotes.QuoteName(MarkSchema),
+		"CREATE TABLE IF NOT EXISTS " +
+			quotes.QuoteSchema(MarkSchema, MarkTableName(replicaID)) + ` (
+	bucket INT NOT NULL,
+	val BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (bucket)
+)`,
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+		}
+	}
+	return nil
+}
+
+// markSQL returns the statement marking a transaction. Transactions are
+// marked by different buckets, so concurrent transactions don't conflict.
+func markSQL(replicaID uint64) string {
+	return "INSERT INTO " + quotes.QuoteSchema(MarkSchema, MarkTableName(replicaID)) +
+		" (`bucket`, `val`) VALUES (?, 1) ON DUPLICATE KEY UPDATE `val` = `val` + 1"
+}
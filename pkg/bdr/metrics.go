@@ -0,0 +1,41 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	filteredRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "bdr",
+			Name:      "filtered_rows_count",
+			Help:      "The total count of rows written by TiCDC which are not replicated back.",
+		}, []string{"namespace", "changefeed"})
+	conflictCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "bdr",
+			Name:      "conflict_count",
+			Help:      "The total count of replicated rows conflicting with rows in the downstream.",
+		}, []string{"namespace", "changefeed", "resolution"})
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(filteredRowsCounter)
+	registry.MustRegister(conflictCounter)
+}
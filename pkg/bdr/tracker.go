@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// MarkTracker tracks the transactions written into the upstream by TiCDC,
+// they are found by the rows of mark tables written in them. It's fed by a
+// puller of the mark tables.
+type MarkTracker struct {
+	changefeedID model.ChangeFeedID
+	resolvedTs   atomic.Uint64
+
+	mu sync.Mutex
+	// marked maps the start ts of marked transactions to their commit ts.
+	marked map[model.Ts]model.Ts
+
+	metricFilteredRows prometheus.Counter
+}
+
+// NewMarkTracker creates a MarkTracker which is resolved at startTs.
+func NewMarkTracker(changefeedID model.ChangeFeedID, startTs model.Ts) *MarkTracker {
+	t := &MarkTracker{
+		changefeedID: changefeedID,
+		marked:       make(map[model.Ts]model.Ts),
+		metricFilteredRows: filteredRowsCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+	t.resolvedTs.Store(startTs)
+	return t
+}
+
+// AddEntry adds an entry of the mark tables to the tracker.
+func (t *MarkTracker) AddEntry(raw *model.RawKVEntry) {
+	switch raw.OpType {
+	case model.OpTypeResolved:
+		if raw.CRTs > t.resolvedTs.Load() {
+			t.resolvedTs.Store(raw.CRTs)
+		}
+	case model.OpTypePut:
+		t.mu.Lock()
+		t.marked[raw.StartTs] = raw.CRTs
+		t.mu.Unlock()
+	}
+}
+
+// ResolvedTs returns the resolved ts of the tracker, all transactions marked
+// before it are tracked.
+func (t *MarkTracker) ResolvedTs() model.Ts {
+	return t.resolvedTs.Load()
+}
+
+// ShouldIgnoreRow returns whether the row is written by TiCDC and should not
+// be replicated. It blocks until the tracker is resolved at the commit ts
+// of the row.
+func (t *MarkTracker) ShouldIgnoreRow(
+	ctx context.Context, row *model.RowChangedEvent,
+) (bool, error) {
+	startTime := time.Now()
+	logTime := startTime
+	err := retry.Do(ctx, func() error {
+		resolvedTs := t.ResolvedTs()
+		if resolvedTs >= row.CommitTs {
+			return nil
+		}
+		now := time.Now()
+		if now.Sub(logTime) >= 30*time.Second {
+			log.Warn("waiting for mark tables is taking too long, mark puller stuck?",
+				zap.Uint64("commitTs", row.CommitTs),
+				zap.Uint64("resolvedTs", resolvedTs),
+				zap.Duration("duration", now.Sub(startTime)),
+				zap.String("namespace", t.changefeedID.Namespace),
+				zap.String("changefeed", t.changefeedID.ID))
+			logTime = now
+		}
+		return cerror.ErrBDRMarkUnresolved.GenWithStackByArgs(row.CommitTs, resolvedTs)
+	}, retry.WithBackoffBaseDelay(10), retry.WithIsRetryableErr(isRetryable))
+	if err != nil {
+		return false, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	commitTs, ok := t.marked[row.StartTs]
+	ignore := ok && commitTs == row.CommitTs
+	if ignore {
+		t.metricFilteredRows.Inc()
+	}
+	return ignore, nil
+}
+
+// DoGC removes the transactions committed before the ts, rows committed
+// before it are not checked anymore.
+func (t *MarkTracker) DoGC(ts model.Ts) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for startTs, commitTs := range t.marked {
+		if commitTs < ts {
+			delete(t.marked, startTs)
+		}
+	}
+}
+
+// Close cleans up the metrics of the tracker.
+func (t *MarkTracker) Close() {
+	filteredRowsCounter.DeleteLabelValues(t.changefeedID.Namespace, t.changefeedID.ID)
+}
+
+func isRetryable(err error) bool {
+	return cerror.ErrBDRMarkUnresolved.Equal(err)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bdr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewMarkTracker(model.DefaultChangeFeedID("test"), 10)
+	defer tracker.Close()
+	ctx := context.Background()
+
+	tracker.AddEntry(&model.RawKVEntry{OpType: model.OpTypePut, StartTs: 11, CRTs: 12})
+	tracker.AddEntry(&model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: 15})
+	require.Equal(t, uint64(15), tracker.ResolvedTs())
+
+	ignore, err := tracker.ShouldIgnoreRow(ctx, &model.RowChangedEvent{StartTs: 11, CommitTs: 12})
+	require.Nil(t, err)
+	require.True(t, ignore)
+	ignore, err = tracker.ShouldIgnoreRow(ctx, &model.RowChangedEvent{StartTs: 13, CommitTs: 14})
+	require.Nil(t, err)
+	require.False(t, ignore)
+
+	// The tracker blocks until it's resolved at the commit ts.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ignore, err := tracker.ShouldIgnoreRow(ctx,
+			&model.RowChangedEvent{StartTs: 16, CommitTs: 18})
+		require.Nil(t, err)
+		require.True(t, ignore)
+	}()
+	select {
+	case <-done:
+		t.Fatal("the tracker is not resolved")
+	case <-time.After(100 * time.Millisecond):
+	}
+	tracker.AddEntry(&model.RawKVEntry{OpType: model.OpTypePut, StartTs: 16, CRTs: 18})
+	tracker.AddEntry(&model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: 20})
+	<-done
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = tracker.ShouldIgnoreRow(cctx, &model.RowChangedEvent{StartTs: 21, CommitTs: 22})
+	require.NotNil(t, err)
+
+	tracker.DoGC(15)
+	require.Len(t, tracker.marked, 1)
+}
@@ -56,6 +56,8 @@ func NewCmdCli() *cobra.Command {
 	cmds.AddCommand(newCmdProcessor(f))
 	cmds.AddCommand(newCmdTso(f))
 	cmds.AddCommand(newCmdUnsafe(f))
+	cmds.AddCommand(newCmdMeta(f))
+	cmds.AddCommand(newCmdTop(f))
 
 	return cmds
 }
@@ -27,7 +27,7 @@ func newCmdCapture(f factory.Factory) *cobra.Command {
 	}
 	cmds.AddCommand(
 		newCmdListCapture(f),
-		// TODO: add resign owner command
+		newCmdDrainCapture(f),
 	)
 
 	return cmds
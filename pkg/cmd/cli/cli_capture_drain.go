@@ -0,0 +1,210 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// drainCaptureOptions defines flags for the `cli capture drain` command.
+type drainCaptureOptions struct {
+	apiV1Client apiv1client.APIV1Interface
+
+	captureID string
+	interval  time.Duration
+	timeout   time.Duration
+}
+
+// newDrainCaptureOptions creates new options for the `cli capture drain` command.
+func newDrainCaptureOptions() *drainCaptureOptions {
+	return &drainCaptureOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *drainCaptureOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().DurationVar(&o.interval, "interval", time.Second, "Interval for checking whether tables are moved away")
+	cmd.PersistentFlags().DurationVar(&o.timeout, "timeout", 10*time.Minute, "Timeout for draining the capture")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *drainCaptureOptions) complete(f factory.Factory, args []string) error {
+	o.captureID = args[0]
+	var err error
+	o.apiV1Client, err = f.APIV1Client()
+	return err
+}
+
+// validate checks that the provided options are valid.
+func (o *drainCaptureOptions) validate() error {
+	if o.interval <= 0 {
+		return errors.New("the interval must be greater than 0")
+	}
+	if o.timeout < o.interval {
+		return errors.New("the timeout must not be less than the interval")
+	}
+	return nil
+}
+
+// getCapture returns the target capture and the owner.
+func (o *drainCaptureOptions) getCapture(
+	ctx context.Context,
+) (target, owner *model.Capture, total int, err error) {
+	captures, err := o.apiV1Client.Captures().List(ctx)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for i := range *captures {
+		c := &(*captures)[i]
+		if c.ID == o.captureID {
+			target = c
+		}
+		if c.IsOwner {
+			owner = c
+		}
+	}
+	return target, owner, len(*captures), nil
+}
+
+// waitUntil calls check every interval until it returns true, or fails
+// if it doesn't return true before the deadline.
+func (o *drainCaptureOptions) waitUntil(
+	ctx context.Context, deadline time.Time, what string,
+	check func() (bool, error),
+) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout when waiting for %s", what)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// resignOwner makes the target capture resign the ownership, and waits
+// until another capture becomes the owner.
+func (o *drainCaptureOptions) resignOwner(
+	ctx context.Context, cmd *cobra.Command, deadline time.Time,
+) error {
+	cmd.Printf("capture %s is the owner, resign it first\n", o.captureID)
+	if err := o.apiV1Client.Captures().ResignOwner(ctx); err != nil {
+		return err
+	}
+	return o.waitUntil(ctx, deadline, "a new owner", func() (bool, error) {
+		_, owner, _, err := o.getCapture(ctx)
+		if err != nil {
+			return false, err
+		}
+		if owner == nil {
+			return false, nil
+		}
+		if owner.ID == o.captureID {
+			// The capture is elected again, resign it again.
+			return false, o.apiV1Client.Captures().ResignOwner(ctx)
+		}
+		cmd.Printf("capture %s becomes the new owner\n", owner.ID)
+		return true, nil
+	})
+}
+
+// run the `cli capture drain` command.
+func (o *drainCaptureOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	deadline := time.Now().Add(o.timeout)
+
+	target, owner, total, err := o.getCapture(ctx)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.Errorf("capture %s not found", o.captureID)
+	}
+	if total <= 1 {
+		return errors.Errorf("cannot drain capture %s, "+
+			"since it's the only capture in the cluster", o.captureID)
+	}
+	if owner != nil && owner.ID == o.captureID {
+		if err := o.resignOwner(ctx, cmd, deadline); err != nil {
+			return err
+		}
+	}
+
+	err = o.waitUntil(ctx, deadline, "tables to be moved away", func() (bool, error) {
+		resp, err := o.apiV1Client.Captures().Drain(ctx, o.captureID)
+		if err != nil {
+			// The owner may be changing or the captures may be initializing,
+			// which are recoverable, so just retry later.
+			cmd.Printf("failed to drain capture %s, retry later: %s\n", o.captureID, err)
+			return false, nil
+		}
+		if resp.CurrentTableCount == 0 {
+			return true, nil
+		}
+		cmd.Printf("%d tables are still replicated by capture %s\n",
+			resp.CurrentTableCount, o.captureID)
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("capture %s has been drained, it is ready to be shut down\n", o.captureID)
+	return nil
+}
+
+// newCmdDrainCapture creates the `cli capture drain` command.
+func newCmdDrainCapture(f factory.Factory) *cobra.Command {
+	o := newDrainCaptureOptions()
+
+	command := &cobra.Command{
+		Use:   "drain <capture-id>",
+		Short: "Drain a capture before shutting it down",
+		Long: "Move all tables away from the capture, and resign the ownership " +
+			"if it's the owner, so the capture can be shut down gracefully, e.g., " +
+			"in rolling upgrades. Tables are not moved to captures whose versions " +
+			"are incompatible with the owner.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmdcontext.GetDefaultContext()
+
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run(ctx, cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureDrainCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	ctx := context.Background()
+
+	newOptions := func(captureID string) *drainCaptureOptions {
+		o := newDrainCaptureOptions()
+		require.Nil(t, o.complete(f, []string{captureID}))
+		o.interval, o.timeout = time.Millisecond, time.Second
+		require.Nil(t, o.validate())
+		return o
+	}
+	listCaptures := func(owner string) *gomock.Call {
+		return f.captures.EXPECT().List(gomock.Any()).Return(&[]model.Capture{
+			{ID: "c1", IsOwner: owner == "c1"},
+			{ID: "c2", IsOwner: owner == "c2"},
+		}, nil)
+	}
+
+	// Drain the owner, which resigns first and is elected again once.
+	gomock.InOrder(
+		listCaptures("c1"),
+		f.captures.EXPECT().ResignOwner(gomock.Any()).Return(nil),
+		f.captures.EXPECT().List(gomock.Any()).Return(&[]model.Capture{{ID: "c1"}, {ID: "c2"}}, nil),
+		listCaptures("c1"),
+		f.captures.EXPECT().ResignOwner(gomock.Any()).Return(nil),
+		listCaptures("c2"),
+		f.captures.EXPECT().Drain(gomock.Any(), "c1").
+			Return(nil, errors.New("not all captures initialized")),
+		f.captures.EXPECT().Drain(gomock.Any(), "c1").
+			Return(&model.DrainCaptureResp{CurrentTableCount: 2}, nil),
+		f.captures.EXPECT().Drain(gomock.Any(), "c1").
+			Return(&model.DrainCaptureResp{CurrentTableCount: 0}, nil),
+	)
+	cmd := newCmdDrainCapture(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, newOptions("c1").run(ctx, cmd))
+	out := b.String()
+	require.Contains(t, out, "capture c1 is the owner, resign it first")
+	require.Contains(t, out, "capture c2 becomes the new owner")
+	require.Contains(t, out, "retry later: not all captures initialized")
+	require.Contains(t, out, "2 tables are still replicated by capture c1")
+	require.Contains(t, out, "capture c1 has been drained, it is ready to be shut down")
+
+	// Timeout if tables are not moved away.
+	listCaptures("c1")
+	f.captures.EXPECT().Drain(gomock.Any(), "c2").
+		Return(&model.DrainCaptureResp{CurrentTableCount: 1}, nil).MinTimes(1)
+	o := newOptions("c2")
+	o.timeout = 10 * time.Millisecond
+	require.Regexp(t, ".*timeout when waiting for tables to be moved away.*", o.run(ctx, cmd))
+
+	// The capture must exist.
+	listCaptures("c1")
+	require.Regexp(t, ".*capture c3 not found.*", newOptions("c3").run(ctx, cmd))
+
+	o = newDrainCaptureOptions()
+	o.interval, o.timeout = time.Second, time.Millisecond
+	require.Regexp(t, ".*timeout must not be less than the interval.*", o.validate())
+}
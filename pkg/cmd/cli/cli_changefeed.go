@@ -27,6 +27,8 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	}
 
 	cmds.AddCommand(newCmdCreateChangefeed(f))
+	cmds.AddCommand(newCmdCloneChangefeed(f))
+	cmds.AddCommand(newCmdApplyChangefeed(f))
 	cmds.AddCommand(newCmdUpdateChangefeed(f))
 	cmds.AddCommand(newCmdStatisticsChangefeed(f))
 	cmds.AddCommand(newCmdListChangefeed(f))
@@ -34,6 +36,11 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	cmds.AddCommand(newCmdQueryChangefeed(f))
 	cmds.AddCommand(newCmdRemoveChangefeed(f))
 	cmds.AddCommand(newCmdResumeChangefeed(f))
+	cmds.AddCommand(newCmdDiagnoseChangefeed(f))
+	cmds.AddCommand(newCmdUpdateTablesChangefeed(f))
+	cmds.AddCommand(newCmdApproveDDLChangefeed(f))
+	cmds.AddCommand(newCmdSchemaChangefeed(f))
+	cmds.AddCommand(newCmdEventsChangefeed(f))
 
 	return cmds
 }
@@ -0,0 +1,478 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/r3labs/diff"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// changefeedSpec is the desired state of a changefeed, it's declared in a
+// TOML or YAML file. The replica config has the same items as the config
+// file of `cli changefeed create`.
+type changefeedSpec struct {
+	ID      string `toml:"changefeed-id" json:"changefeed-id"`
+	SinkURI string `toml:"sink-uri" json:"sink-uri"`
+	// StartTs is only used when the changefeed is created.
+	StartTs  uint64 `toml:"start-ts" json:"start-ts"`
+	TargetTs uint64 `toml:"target-ts" json:"target-ts"`
+	// Paused is whether the changefeed should be stopped.
+	Paused        bool                  `toml:"paused" json:"paused"`
+	ReplicaConfig *config.ReplicaConfig `toml:"replica-config" json:"replica-config"`
+}
+
+// loadChangefeedSpec loads the spec from a TOML or YAML file. Unknown items
+// in a TOML file are rejected, so are unknown top level items in a YAML file.
+func loadChangefeedSpec(path string) (*changefeedSpec, error) {
+	spec := &changefeedSpec{ReplicaConfig: config.GetDefaultReplicaConfig()}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		if err := util.StrictDecodeFile(path, "changefeed spec", spec); err != nil {
+			return nil, err
+		}
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, errors.Annotatef(err, "failed to decode %s", path)
+		}
+		// The items of the YAML file are the same as the TOML file, which
+		// are also the json tags, so it's decoded as json.
+		data, err = json.Marshal(yamlToJSONValue(v))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(spec); err != nil {
+			return nil, errors.Annotatef(err, "failed to decode %s", path)
+		}
+	default:
+		return nil, errors.Errorf("unsupported file %s, only TOML and YAML are supported", path)
+	}
+
+	if spec.ID == "" {
+		return nil, errors.Errorf("changefeed-id is missing in %s", path)
+	}
+	if err := model.ValidateChangefeedID(spec.ID); err != nil {
+		return nil, errors.Annotatef(err, "invalid changefeed-id in %s", path)
+	}
+	if spec.SinkURI == "" {
+		return nil, errors.Errorf("sink-uri is missing in %s", path)
+	}
+	sinkURI, err := url.Parse(spec.SinkURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if _, err := filter.VerifyTableRules(spec.ReplicaConfig.Filter); err != nil {
+		return nil, err
+	}
+	// The config is adjusted like the server does, so it's the same as the
+	// config of the changefeed if nothing is changed.
+	if err := spec.ReplicaConfig.ValidateAndAdjust(sinkURI); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// yamlToJSONValue converts the maps decoded from YAML, whose keys are
+// interface{}, to the maps which can be encoded as json.
+func yamlToJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = yamlToJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = yamlToJSONValue(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// loadChangefeedSpecs loads the specs from a file, or the files in a
+// directory in the lexical order.
+func loadChangefeedSpecs(path string) ([]*changefeedSpec, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files := []string{path}
+	if fi.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		files = files[:0]
+		for _, e := range entries {
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".toml", ".yaml", ".yml":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(path, e.Name()))
+				}
+			}
+		}
+	}
+
+	specs := make([]*changefeedSpec, 0, len(files))
+	seen := make(map[string]string)
+	for _, file := range files {
+		spec, err := loadChangefeedSpec(file)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[spec.ID]; ok {
+			return nil, errors.Errorf("changefeed %s is declared in both %s and %s",
+				spec.ID, other, file)
+		}
+		seen[spec.ID] = file
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+type applyActionKind string
+
+const (
+	applyActionCreate applyActionKind = "create"
+	applyActionUpdate applyActionKind = "update"
+	applyActionPause  applyActionKind = "pause"
+	applyActionResume applyActionKind = "resume"
+	applyActionRemove applyActionKind = "remove"
+)
+
+// applyAction is a step to converge a changefeed to its spec.
+type applyAction struct {
+	kind applyActionKind
+	id   string
+	spec *changefeedSpec
+	// changes are the changes of an update.
+	changes diff.Changelog
+	// pauseFirst is whether the changefeed should be paused before updated,
+	// since only stopped changefeeds can be updated.
+	pauseFirst bool
+	// resumeAfter is whether the changefeed should be resumed after updated.
+	resumeAfter bool
+}
+
+// applyPlan is the actions to converge the cluster to the specs, in the
+// order of creations, updates, pauses, resumptions and removals.
+type applyPlan []*applyAction
+
+func (p applyPlan) count(kind applyActionKind) int {
+	n := 0
+	for _, a := range p {
+		if a.kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// print prints the plan.
+func (p applyPlan) print(cmd *cobra.Command) {
+	cmd.Printf("Plan: %d to create, %d to update, %d to pause, %d to resume, %d to remove.\n",
+		p.count(applyActionCreate), p.count(applyActionUpdate),
+		p.count(applyActionPause), p.count(applyActionResume),
+		p.count(applyActionRemove))
+	for _, a := range p {
+		switch a.kind {
+		case applyActionCreate:
+			paused := ""
+			if a.spec.Paused {
+				paused = " (paused)"
+			}
+			cmd.Printf("  + create %s%s\n", a.id, paused)
+		case applyActionUpdate:
+			var steps []string
+			if a.pauseFirst {
+				steps = append(steps, "pause")
+			}
+			steps = append(steps, "update")
+			if a.resumeAfter {
+				steps = append(steps, "resume")
+			}
+			cmd.Printf("  ~ update %s (%s)\n", a.id, strings.Join(steps, ", "))
+			for _, c := range a.changes {
+				cmd.Printf("      %s: %v -> %v\n", strings.Join(c.Path, "."), c.From, c.To)
+			}
+		case applyActionPause, applyActionResume:
+			cmd.Printf("  ~ %s %s\n", a.kind, a.id)
+		case applyActionRemove:
+			cmd.Printf("  - remove %s\n", a.id)
+		}
+	}
+}
+
+// applyChangefeedOptions defines flags for the `cli changefeed apply` command.
+type applyChangefeedOptions struct {
+	apiV1Client apiv1client.APIV1Interface
+	apiV2Client apiv2client.APIV2Interface
+
+	path      string
+	prune     bool
+	dryRun    bool
+	noConfirm bool
+}
+
+// newApplyChangefeedOptions creates new options for the `cli changefeed apply` command.
+func newApplyChangefeedOptions() *applyChangefeedOptions {
+	return &applyChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *applyChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.path, "filename", "f", "", "A changefeed spec file, or a directory of the spec files")
+	cmd.PersistentFlags().BoolVar(&o.prune, "prune", false, "Remove the changefeeds which are not declared in the specs")
+	cmd.PersistentFlags().BoolVar(&o.dryRun, "dry-run", false, "Only print the plan without applying it")
+	cmd.PersistentFlags().BoolVar(&o.noConfirm, "no-confirm", false, "Don't ask user whether to apply the plan")
+	_ = cmd.MarkPersistentFlagRequired("filename")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *applyChangefeedOptions) complete(f factory.Factory) error {
+	var err error
+	o.apiV1Client, err = f.APIV1Client()
+	if err != nil {
+		return err
+	}
+	o.apiV2Client, err = f.APIV2Client()
+	return err
+}
+
+// plan diffs the specs against the changefeeds of the cluster.
+func (o *applyChangefeedOptions) plan(
+	ctx context.Context, specs []*changefeedSpec,
+) (applyPlan, error) {
+	changefeeds, err := o.apiV1Client.Changefeeds().List(ctx, "all")
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]model.FeedState, len(*changefeeds))
+	for _, cf := range *changefeeds {
+		if cf.FeedState != model.StateRemoved {
+			states[cf.ID] = cf.FeedState
+		}
+	}
+
+	var creates, updates, pauses, resumes, removes applyPlan
+	declared := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		declared[spec.ID] = struct{}{}
+		state, ok := states[spec.ID]
+		if !ok {
+			creates = append(creates, &applyAction{kind: applyActionCreate, id: spec.ID, spec: spec})
+			continue
+		}
+
+		info, err := o.apiV2Client.Changefeeds().GetInfo(ctx, spec.ID)
+		if err != nil {
+			return nil, err
+		}
+		desired, err := info.Clone()
+		if err != nil {
+			return nil, err
+		}
+		desired.SinkURI = spec.SinkURI
+		desired.Config = v2.ToAPIReplicaConfig(spec.ReplicaConfig)
+		if spec.TargetTs != 0 {
+			desired.TargetTs = spec.TargetTs
+		}
+		// Both infos are encoded and decoded, so they are compared in the
+		// same form.
+		current, err := info.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if desired, err = desired.Clone(); err != nil {
+			return nil, err
+		}
+		changes, err := diff.Diff(current, desired)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		running := state == model.StateNormal || state == model.StateError
+		switch {
+		case len(changes) > 0:
+			// Only the running changefeeds are paused before updated. A
+			// finished changefeed can't be resumed, so it's only updated.
+			updates = append(updates, &applyAction{
+				kind: applyActionUpdate, id: spec.ID, spec: spec,
+				changes: changes, pauseFirst: running,
+				resumeAfter: !spec.Paused && state != model.StateFinished,
+			})
+		case spec.Paused && running:
+			pauses = append(pauses, &applyAction{kind: applyActionPause, id: spec.ID, spec: spec})
+		case !spec.Paused && (state == model.StateStopped || state == model.StateFailed):
+			resumes = append(resumes, &applyAction{kind: applyActionResume, id: spec.ID, spec: spec})
+		}
+	}
+	if o.prune {
+		ids := make([]string, 0, len(states))
+		for id := range states {
+			if _, ok := declared[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			removes = append(removes, &applyAction{kind: applyActionRemove, id: id})
+		}
+	}
+
+	var plan applyPlan
+	for _, actions := range []applyPlan{creates, updates, pauses, resumes, removes} {
+		plan = append(plan, actions...)
+	}
+	return plan, nil
+}
+
+// perform performs an action of the plan.
+func (o *applyChangefeedOptions) perform(ctx context.Context, a *applyAction) error {
+	switch a.kind {
+	case applyActionCreate:
+		_, err := o.apiV2Client.Changefeeds().Create(ctx, &v2.ChangefeedConfig{
+			ID:            a.spec.ID,
+			SinkURI:       a.spec.SinkURI,
+			StartTs:       a.spec.StartTs,
+			TargetTs:      a.spec.TargetTs,
+			ReplicaConfig: v2.ToAPIReplicaConfig(a.spec.ReplicaConfig),
+		})
+		if err != nil || !a.spec.Paused {
+			return err
+		}
+		return o.apiV1Client.Changefeeds().Pause(ctx, a.id)
+	case applyActionUpdate:
+		if a.pauseFirst {
+			if err := o.apiV1Client.Changefeeds().Pause(ctx, a.id); err != nil {
+				return err
+			}
+		}
+		_, err := o.apiV2Client.Changefeeds().Update(ctx, &v2.ChangefeedConfig{
+			SinkURI:       a.spec.SinkURI,
+			TargetTs:      a.spec.TargetTs,
+			ReplicaConfig: v2.ToAPIReplicaConfig(a.spec.ReplicaConfig),
+		}, a.id)
+		if err != nil || !a.resumeAfter {
+			return err
+		}
+		return o.apiV2Client.Changefeeds().Resume(ctx, &v2.ResumeChangefeedConfig{}, a.id)
+	case applyActionPause:
+		return o.apiV1Client.Changefeeds().Pause(ctx, a.id)
+	case applyActionResume:
+		return o.apiV2Client.Changefeeds().Resume(ctx, &v2.ResumeChangefeedConfig{}, a.id)
+	case applyActionRemove:
+		return o.apiV1Client.Changefeeds().Delete(ctx, a.id)
+	}
+	return nil
+}
+
+// run the `cli changefeed apply` command.
+func (o *applyChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	specs, err := loadChangefeedSpecs(o.path)
+	if err != nil {
+		return err
+	}
+	plan, err := o.plan(ctx, specs)
+	if err != nil {
+		return err
+	}
+	if len(plan) == 0 {
+		cmd.Printf("All changefeeds are up to date, do nothing\n")
+		return nil
+	}
+	plan.print(cmd)
+	if o.dryRun {
+		return nil
+	}
+
+	if !o.noConfirm {
+		cmd.Printf("Could you agree to apply the plan above [Y/N]\n")
+		var yOrN string
+		_, err = fmt.Scan(&yOrN)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(strings.TrimSpace(yOrN)) != "y" {
+			cmd.Printf("No changefeed is changed.\n")
+			return nil
+		}
+	}
+
+	for i, a := range plan {
+		if err := o.perform(ctx, a); err != nil {
+			cmd.Printf("Failed to %s changefeed %s, %d of %d actions are applied\n",
+				a.kind, a.id, i, len(plan))
+			return err
+		}
+		cmd.Printf("%s changefeed %s successfully\n", a.kind, a.id)
+	}
+	return nil
+}
+
+// newCmdApplyChangefeed creates the `cli changefeed apply` command.
+func newCmdApplyChangefeed(f factory.Factory) *cobra.Command {
+	o := newApplyChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "apply",
+		Short: "Converge the replication tasks (changefeeds) to the specs in files",
+		Long: "Converge the replication tasks (changefeeds) to the specs in TOML or YAML files, " +
+			"the changefeeds are created, updated, paused or resumed as declared, " +
+			"and the changefeeds not declared are removed if --prune is set.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmdcontext.GetDefaultContext()
+
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(ctx, cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,183 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestLoadChangefeedSpecs(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSpecFiles(t, map[string]string{
+		"cf1.toml": `
+changefeed-id = "cf1"
+sink-uri = "blackhole://"
+start-ts = 10
+[replica-config.filter]
+rules = ["db.*"]
+`,
+		"cf2.yaml": `
+changefeed-id: cf2
+sink-uri: "kafka://127.0.0.1:9092/topic?protocol=canal-json"
+paused: true
+replica-config:
+  filter:
+    rules: ["test.*"]
+  sink:
+    dispatchers:
+      - matcher: ["test.*"]
+        partition: table
+`,
+		"README.md": "ignored",
+	})
+	specs, err := loadChangefeedSpecs(dir)
+	require.Nil(t, err)
+	require.Len(t, specs, 2)
+	require.Equal(t, "cf1", specs[0].ID)
+	require.Equal(t, uint64(10), specs[0].StartTs)
+	require.Equal(t, []string{"db.*"}, specs[0].ReplicaConfig.Filter.Rules)
+	require.Equal(t, "cf2", specs[1].ID)
+	require.True(t, specs[1].Paused)
+	require.Equal(t, []string{"test.*"}, specs[1].ReplicaConfig.Filter.Rules)
+	require.Equal(t, "table", specs[1].ReplicaConfig.Sink.DispatchRules[0].PartitionRule)
+	// The protocol is adjusted by the sink uri like the server does.
+	require.Equal(t, "canal-json", specs[1].ReplicaConfig.Sink.Protocol)
+
+	dir = writeSpecFiles(t, map[string]string{
+		"a.toml": "changefeed-id = \"cf1\"\nsink-uri = \"blackhole://\"",
+		"b.yml":  "changefeed-id: cf1\nsink-uri: blackhole://",
+	})
+	_, err = loadChangefeedSpecs(dir)
+	require.Regexp(t, ".*changefeed cf1 is declared in both.*", err)
+
+	dir = writeSpecFiles(t, map[string]string{
+		"a.yaml": "changefeed-id: cf1\nsink-uri: blackhole://\nsink_uri: a",
+	})
+	_, err = loadChangefeedSpecs(dir)
+	require.Regexp(t, ".*unknown field.*sink_uri.*", err)
+
+	dir = writeSpecFiles(t, map[string]string{"a.toml": "sink-uri = \"blackhole://\""})
+	_, err = loadChangefeedSpecs(filepath.Join(dir, "a.toml"))
+	require.Regexp(t, ".*changefeed-id is missing.*", err)
+}
+
+func TestChangefeedApplyCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	dir := writeSpecFiles(t, map[string]string{
+		"cf1.toml": "changefeed-id = \"cf1\"\nsink-uri = \"blackhole://\"\nstart-ts = 10",
+		"cf2.toml": "changefeed-id = \"cf2\"\nsink-uri = \"blackhole://new\"",
+		"cf4.toml": "changefeed-id = \"cf4\"\nsink-uri = \"blackhole://\"\npaused = true",
+		"cf5.toml": "changefeed-id = \"cf5\"\nsink-uri = \"blackhole://\"",
+		"cf7.toml": "changefeed-id = \"cf7\"\nsink-uri = \"blackhole://new\"",
+		"cf8.toml": "changefeed-id = \"cf8\"\nsink-uri = \"blackhole://new\"",
+	})
+	specs, err := loadChangefeedSpecs(dir)
+	require.Nil(t, err)
+	newInfo := func(id, sinkURI string) *v2.ChangeFeedInfo {
+		return &v2.ChangeFeedInfo{
+			ID:      id,
+			SinkURI: sinkURI,
+			Config:  v2.ToAPIReplicaConfig(specs[0].ReplicaConfig),
+		}
+	}
+	expectPlan := func() {
+		f.changefeeds.EXPECT().List(gomock.Any(), "all").Return(&[]model.ChangefeedCommonInfo{
+			{ID: "cf2", FeedState: model.StateStopped},
+			{ID: "cf3", FeedState: model.StateNormal},
+			{ID: "cf4", FeedState: model.StateNormal},
+			{ID: "cf5", FeedState: model.StateNormal},
+			{ID: "cf6", FeedState: model.StateRemoved},
+			{ID: "cf7", FeedState: model.StateFinished},
+			{ID: "cf8", FeedState: model.StateError},
+		}, nil)
+		f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "cf2").
+			Return(newInfo("cf2", "blackhole://old"), nil)
+		f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "cf4").
+			Return(newInfo("cf4", "blackhole://"), nil)
+		f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "cf5").
+			Return(newInfo("cf5", "blackhole://"), nil)
+		f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "cf7").
+			Return(newInfo("cf7", "blackhole://old"), nil)
+		f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "cf8").
+			Return(newInfo("cf8", "blackhole://old"), nil)
+	}
+
+	// Dry run only prints the plan.
+	cmd := newCmdApplyChangefeed(f)
+	os.Args = []string{"apply", "-f", dir, "--prune", "--dry-run"}
+	expectPlan()
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out := b.String()
+	require.Contains(t, out, "Plan: 1 to create, 3 to update, 1 to pause, 0 to resume, 1 to remove.")
+	require.Contains(t, out, "+ create cf1")
+	require.Contains(t, out, "~ update cf2 (update, resume)")
+	// A finished changefeed is neither paused nor resumed.
+	require.Contains(t, out, "~ update cf7 (update)\n")
+	require.Contains(t, out, "~ update cf8 (pause, update, resume)")
+	require.Contains(t, out, "SinkURI: blackhole://old -> blackhole://new")
+	require.Contains(t, out, "~ pause cf4")
+	require.Contains(t, out, "- remove cf3")
+	require.NotContains(t, out, "cf5")
+
+	// Apply the plan.
+	cmd = newCmdApplyChangefeed(f)
+	os.Args = []string{"apply", "-f", dir, "--prune", "--no-confirm"}
+	expectPlan()
+	f.changefeedsv2.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error) {
+			require.Equal(t, "cf1", cfg.ID)
+			require.Equal(t, uint64(10), cfg.StartTs)
+			return &v2.ChangeFeedInfo{}, nil
+		})
+	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), "cf2").
+		DoAndReturn(func(_ interface{}, cfg *v2.ChangefeedConfig, _ string) (*v2.ChangeFeedInfo, error) {
+			require.Equal(t, "blackhole://new", cfg.SinkURI)
+			return &v2.ChangeFeedInfo{}, nil
+		})
+	f.changefeedsv2.EXPECT().Resume(gomock.Any(), gomock.Any(), "cf2").Return(nil)
+	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), "cf7").
+		Return(&v2.ChangeFeedInfo{}, nil)
+	f.changefeeds.EXPECT().Pause(gomock.Any(), "cf8").Return(nil)
+	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), "cf8").
+		Return(&v2.ChangeFeedInfo{}, nil)
+	f.changefeedsv2.EXPECT().Resume(gomock.Any(), gomock.Any(), "cf8").Return(nil)
+	f.changefeeds.EXPECT().Pause(gomock.Any(), "cf4").Return(nil)
+	f.changefeeds.EXPECT().Delete(gomock.Any(), "cf3").Return(nil)
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), "remove changefeed cf3 successfully")
+}
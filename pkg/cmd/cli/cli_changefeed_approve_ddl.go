@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// approveDDLChangefeedOptions defines flags for the `cli changefeed approve-ddl` command.
+type approveDDLChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	commitTs     uint64
+	reject       bool
+}
+
+// newApproveDDLChangefeedOptions creates new options for the `cli changefeed approve-ddl` command.
+func newApproveDDLChangefeedOptions() *approveDDLChangefeedOptions {
+	return &approveDDLChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *approveDDLChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Uint64Var(&o.commitTs, "commit-ts", 0,
+		"The commit ts of the DDL waiting for approval, it's shown by `cli changefeed query`")
+	cmd.PersistentFlags().BoolVar(&o.reject, "reject", false,
+		"Reject the DDL, the rejected DDL is not replicated to the downstream")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("commit-ts")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *approveDDLChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed approve-ddl` command.
+func (o *approveDDLChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	err := o.apiClient.Changefeeds().ApproveDDL(ctx, &v2.ApproveDDLConfig{
+		CommitTs: o.commitTs,
+		Reject:   o.reject,
+	}, o.changefeedID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if o.reject {
+		cmd.Printf("DDL at %d of changefeed %s is rejected\n", o.commitTs, o.changefeedID)
+	} else {
+		cmd.Printf("DDL at %d of changefeed %s is approved\n", o.commitTs, o.changefeedID)
+	}
+	return nil
+}
+
+// newCmdApproveDDLChangefeed creates the `cli changefeed approve-ddl` command.
+func newCmdApproveDDLChangefeed(f factory.Factory) *cobra.Command {
+	o := newApproveDDLChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "approve-ddl",
+		Short: "Approve or reject the DDL held by the ddl policies of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedApproveDDLCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdApproveDDLChangefeed(f)
+	f.changefeedsv2.EXPECT().ApproveDDL(gomock.Any(), &v2.ApproveDDLConfig{
+		CommitTs: 100,
+		Reject:   true,
+	}, "abc").Return(nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{
+		"approve-ddl", "--changefeed-id=abc", "--commit-ts=100", "--reject",
+	}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), "rejected")
+
+	f.changefeedsv2.EXPECT().ApproveDDL(gomock.Any(), gomock.Any(), "abc").
+		Return(errors.New("test"))
+	o := newApproveDDLChangefeedOptions()
+	o.changefeedID = "abc"
+	o.commitTs = 100
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run(cmd))
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// cloneChangefeedOptions defines flags for the `cli changefeed clone` command.
+type cloneChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	sourceID     string
+	changefeedID string
+	startTs      uint64
+	targetTs     uint64
+	sinkURI      string
+}
+
+// newCloneChangefeedOptions creates new options for the `cli changefeed clone` command.
+func newCloneChangefeedOptions() *cloneChangefeedOptions {
+	return &cloneChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *cloneChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.sourceID, "from", "",
+		"ID of the replication task (changefeed) to clone")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "",
+		"ID of the new replication task (changefeed)")
+	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0,
+		"Start ts of the new changefeed, the checkpoint ts of the source changefeed is used if it's 0")
+	cmd.PersistentFlags().Uint64Var(&o.targetTs, "target-ts", 0,
+		"Target ts of the new changefeed, the target ts of the source changefeed is used if it's 0")
+	cmd.PersistentFlags().StringVar(&o.sinkURI, "sink-uri", "", "sink uri of the new changefeed")
+	_ = cmd.MarkPersistentFlagRequired("from")
+	_ = cmd.MarkPersistentFlagRequired("sink-uri")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *cloneChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed clone` command.
+func (o *cloneChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	info, err := o.apiClient.Changefeeds().Clone(ctx, &v2.CloneChangefeedConfig{
+		ID:       o.changefeedID,
+		StartTs:  o.startTs,
+		TargetTs: o.targetTs,
+		SinkURI:  o.sinkURI,
+	}, o.sourceID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	infoStr, err := info.Marshal()
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Clone changefeed %s successfully!\nID: %s\nInfo: %s\n",
+		o.sourceID, info.ID, infoStr)
+	return nil
+}
+
+// newCmdCloneChangefeed creates the `cli changefeed clone` command.
+func newCmdCloneChangefeed(f factory.Factory) *cobra.Command {
+	o := newCloneChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "clone",
+		Short: "Create a replication task (changefeed) with the config of an existing one",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedCloneCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdCloneChangefeed(f)
+	f.changefeedsv2.EXPECT().Clone(gomock.Any(), &v2.CloneChangefeedConfig{
+		ID:      "fork",
+		StartTs: 100,
+		SinkURI: "blackhole://",
+	}, "abc").Return(&v2.ChangeFeedInfo{ID: "fork", StartTs: 100}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{
+		"clone", "--from=abc", "--changefeed-id=fork",
+		"--start-ts=100", "--sink-uri=blackhole://",
+	}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), "Clone changefeed abc successfully!")
+
+	f.changefeedsv2.EXPECT().Clone(gomock.Any(), gomock.Any(), "abc").
+		Return(nil, errors.New("test"))
+	o := newCloneChangefeedOptions()
+	o.sourceID = "abc"
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run(cmd))
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/fatih/color"
 	"github.com/pingcap/errors"
@@ -108,6 +109,8 @@ type createChangefeedOptions struct {
 	disableGCSafePointCheck bool
 	startTs                 uint64
 	timezone                string
+	dryRun                  bool
+	output                  string
 
 	cfg *config.ReplicaConfig
 }
@@ -127,6 +130,8 @@ func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
+	cmd.PersistentFlags().BoolVar(&o.dryRun, "dry-run", false, "Report how the changefeed would replicate the tables without creating it")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "table", "Output format of the dry run report, json or table")
 	// we don't support specify these flags below when cdc version >= 6.2.0
 	_ = cmd.PersistentFlags().MarkHidden("tz")
 }
@@ -243,6 +248,10 @@ func (o *createChangefeedOptions) validate(cmd *cobra.Command) error {
 		o.commonChangefeedOptions.sortEngine = model.SortUnified
 	}
 
+	if o.dryRun && o.output != "json" && o.output != "table" {
+		return errors.Errorf("invalid output format %s, only json and table are supported", o.output)
+	}
+
 	return nil
 }
 
@@ -295,6 +304,10 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 		o.startTs = oracle.ComposeTS(tso.Timestamp, tso.LogicTime)
 	}
 
+	if o.dryRun {
+		return o.runDryRun(ctx, cmd)
+	}
+
 	if !o.commonChangefeedOptions.noConfirm {
 		if err = confirmLargeDataGap(cmd, tso.Timestamp, o.startTs, "create"); err != nil {
 			return err
@@ -373,6 +386,44 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 	return nil
 }
 
+// runDryRun prints how the changefeed would replicate the tables.
+func (o *createChangefeedOptions) runDryRun(ctx context.Context, cmd *cobra.Command) error {
+	report, err := o.apiClient.Changefeeds().DryRun(ctx, o.getChangefeedConfig())
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		return util.JSONPrint(cmd, report)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Start ts: %d\n\n", report.StartTs)
+	fmt.Fprintln(w, "TABLE\tTOPIC\tPARTITION\tCOLUMNS\tIGNORED EVENTS\tIGNORED SQLS")
+	for _, t := range report.Tables {
+		partition := t.PartitionRule
+		if t.Partition != nil {
+			partition = fmt.Sprintf("%s(%d)", t.PartitionRule, *t.Partition)
+		}
+		fmt.Fprintf(w, "%s.%s\t%s\t%s\t%s\t%s\t%s\n", t.Schema, t.Table,
+			orDash(t.Topic), orDash(partition), orDash(strings.Join(t.Columns, ",")),
+			orDash(strings.Join(t.IgnoredEvents, ",")), orDash(strings.Join(t.IgnoredSQLs, ",")))
+	}
+	if len(report.IneligibleTables) != 0 {
+		fmt.Fprintln(w, "\nINELIGIBLE TABLE\tREASON")
+		for _, t := range report.IneligibleTables {
+			fmt.Fprintf(w, "%s.%s\t%s\n", t.Schema, t.Table, t.Reason)
+		}
+	}
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // newCmdCreateChangefeed creates the `cli changefeed create` command.
 func newCmdCreateChangefeed(f factory.Factory) *cobra.Command {
 	commonChangefeedOptions := newChangefeedCommonOptions()
@@ -200,3 +200,67 @@ func TestChangefeedCreateCli(t *testing.T) {
 	require.NoError(t, o.complete(context.Background(), f, cmd))
 	require.Contains(t, o.validate(cmd).Error(), "creating changefeed with `--sort-dir`")
 }
+
+func TestChangefeedCreateDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	partition := int32(1)
+	report := &v2.DryRunReport{
+		StartTs: 10,
+		Tables: []v2.DryRunTable{{
+			Schema: "test", Table: "t1", Topic: "topic",
+			PartitionRule: "table", Partition: &partition,
+			Columns: []string{"id", "a"}, IgnoredEvents: []string{"drop table"},
+		}},
+		IneligibleTables: []v2.DryRunTable{{
+			Schema: "test", Table: "t2", Reason: "no primary key or not null unique key",
+		}},
+	}
+
+	cmd := newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--sink-uri=kafka://127.0.0.1:9092/topic?protocol=open-protocol",
+		"--start-ts=10",
+		"--dry-run",
+	}
+	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&v2.Tso{
+		Timestamp: time.Now().Unix() * 1000,
+	}, nil)
+	// Nothing is verified or created in a dry run.
+	f.changefeedsv2.EXPECT().DryRun(gomock.Any(), gomock.Any()).Return(report, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out := b.String()
+	require.Contains(t, out, "test.t1")
+	require.Contains(t, out, "table(1)")
+	require.Contains(t, out, "id,a")
+	require.Contains(t, out, "drop table")
+	require.Contains(t, out, "no primary key or not null unique key")
+
+	cmd = newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--sink-uri=kafka://127.0.0.1:9092/topic?protocol=open-protocol",
+		"--start-ts=10",
+		"--dry-run",
+		"--output=json",
+	}
+	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&v2.Tso{
+		Timestamp: time.Now().Unix() * 1000,
+	}, nil)
+	f.changefeedsv2.EXPECT().DryRun(gomock.Any(), gomock.Any()).Return(report, nil)
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), `"ineligible_tables"`)
+
+	o := newCreateChangefeedOptions(newChangefeedCommonOptions())
+	o.commonChangefeedOptions.sinkURI = "blackhole://"
+	o.dryRun = true
+	o.output = "yaml"
+	require.Regexp(t, ".*invalid output format yaml.*", o.validate(cmd))
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// diagnoseChangefeedOptions defines flags for the `cli changefeed diagnose` command.
+type diagnoseChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	regionLimit  int
+}
+
+// newDiagnoseChangefeedOptions creates new options for the `cli changefeed diagnose` command.
+func newDiagnoseChangefeedOptions() *diagnoseChangefeedOptions {
+	return &diagnoseChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *diagnoseChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().IntVar(&o.regionLimit, "limit", model.DefaultDiagnosisRegionLimit,
+		"Number of the slowest regions reported for each table")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *diagnoseChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed diagnose` command.
+func (o *diagnoseChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	if o.regionLimit < 0 {
+		return errors.Errorf("invalid limit %d, it must not be negative", o.regionLimit)
+	}
+	tables, err := o.apiClient.Changefeeds().Diagnose(ctx, o.changefeedID, o.regionLimit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return util.JSONPrint(cmd, tables)
+}
+
+// newCmdDiagnoseChangefeed creates the `cli changefeed diagnose` command.
+func newCmdDiagnoseChangefeed(f factory.Factory) *cobra.Command {
+	o := newDiagnoseChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Diagnose the lagging tables and regions of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedDiagnoseCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdDiagnoseChangefeed(f)
+	f.changefeedsv2.EXPECT().Diagnose(gomock.Any(), "abc", 3).
+		Return([]model.TableDiagnosis{{
+			CaptureID:     "capture-1",
+			TableID:       1,
+			BlockingStage: model.DiagnosisStagePuller,
+			SlowestRegions: []model.RegionDiagnosis{{
+				RegionID: 10,
+			}},
+		}}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{"diagnose", "--changefeed-id=abc", "--limit=3"}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), `"blocking_stage": "puller"`)
+	require.Contains(t, b.String(), `"region_id": 10`)
+
+	f.changefeedsv2.EXPECT().Diagnose(gomock.Any(), "abc", 5).
+		Return(nil, errors.New("test"))
+	o := newDiagnoseChangefeedOptions()
+	o.changefeedID = "abc"
+	o.regionLimit = 5
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run(cmd))
+
+	o.regionLimit = -1
+	require.NotNil(t, o.run(cmd))
+}
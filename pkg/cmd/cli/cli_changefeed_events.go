@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// eventsChangefeedOptions defines flags for the `cli changefeed events` command.
+type eventsChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	limit        int
+}
+
+// newEventsChangefeedOptions creates new options for the `cli changefeed events` command.
+func newEventsChangefeedOptions() *eventsChangefeedOptions {
+	return &eventsChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *eventsChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().IntVar(&o.limit, "limit", 0,
+		"Number of the latest events to show, all events are shown if it's 0")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *eventsChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed events` command.
+func (o *eventsChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	if o.limit < 0 {
+		return errors.Errorf("invalid limit %d, it must not be negative", o.limit)
+	}
+	events, err := o.apiClient.Changefeeds().GetEvents(ctx, o.changefeedID, o.limit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return util.JSONPrint(cmd, events)
+}
+
+// newCmdEventsChangefeed creates the `cli changefeed events` command.
+func newCmdEventsChangefeed(f factory.Factory) *cobra.Command {
+	o := newEventsChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "events",
+		Short: "Show the lifecycle events and errors of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedEventsCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdEventsChangefeed(f)
+	f.changefeedsv2.EXPECT().GetEvents(gomock.Any(), "abc", 10).
+		Return([]v2.ChangefeedEvent{{
+			Time:    time.Now(),
+			Type:    model.ChangefeedEventPaused,
+			Message: "the changefeed is paused",
+		}}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{"events", "--changefeed-id=abc", "--limit=10"}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), `"type": "paused"`)
+
+	o := newEventsChangefeedOptions()
+	o.changefeedID = "abc"
+	o.limit = -1
+	require.Nil(t, o.complete(f))
+	require.Regexp(t, "invalid limit", o.run(cmd))
+
+	f.changefeedsv2.EXPECT().GetEvents(gomock.Any(), "abc", 0).
+		Return(nil, errors.New("test"))
+	o.limit = 0
+	require.NotNil(t, o.run(cmd))
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// schemaChangefeedOptions defines flags for the `cli changefeed schema` command.
+type schemaChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	table        string
+	ts           uint64
+}
+
+// newSchemaChangefeedOptions creates new options for the `cli changefeed schema` command.
+func newSchemaChangefeedOptions() *schemaChangefeedOptions {
+	return &schemaChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *schemaChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.table, "table", "", "The table to query, like schema.table")
+	cmd.PersistentFlags().Uint64Var(&o.ts, "ts", 0,
+		"The ts to query the table definition at, the checkpoint ts of the changefeed is used by default")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("table")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *schemaChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed schema` command.
+func (o *schemaChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	schema, err := o.apiClient.Changefeeds().GetTableSchema(ctx, o.changefeedID, o.table, o.ts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return util.JSONPrint(cmd, schema)
+}
+
+// newCmdSchemaChangefeed creates the `cli changefeed schema` command.
+func newCmdSchemaChangefeed(f factory.Factory) *cobra.Command {
+	o := newSchemaChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "schema",
+		Short: "Query the definition of a table of a replication task (changefeed) at a ts",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
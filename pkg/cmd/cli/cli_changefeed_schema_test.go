@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedSchemaCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdSchemaChangefeed(f)
+	f.changefeedsv2.EXPECT().GetTableSchema(gomock.Any(), "abc", "test.t1", uint64(100)).
+		Return(&v2.TableSchema{
+			Ts:      100,
+			Schema:  "test",
+			Table:   "t1",
+			Columns: []v2.ColumnSchema{{Name: "a", Type: "int(11)"}},
+		}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{
+		"schema", "--changefeed-id=abc", "--table=test.t1", "--ts=100",
+	}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), `"name": "a"`)
+
+	f.changefeedsv2.EXPECT().GetTableSchema(gomock.Any(), "abc", "test.t1", uint64(0)).
+		Return(nil, errors.New("test"))
+	o := newSchemaChangefeedOptions()
+	o.changefeedID = "abc"
+	o.table = "test.t1"
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run(cmd))
+}
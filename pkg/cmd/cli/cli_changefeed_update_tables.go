@@ -0,0 +1,104 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// updateTablesChangefeedOptions defines flags for the `cli changefeed update-tables` command.
+type updateTablesChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	addRules     []string
+	removeRules  []string
+	startTs      uint64
+	initialLoad  bool
+}
+
+// newUpdateTablesChangefeedOptions creates new options for the `cli changefeed update-tables` command.
+func newUpdateTablesChangefeedOptions() *updateTablesChangefeedOptions {
+	return &updateTablesChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *updateTablesChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringSliceVar(&o.addRules, "add", nil,
+		"Table filter rules of the tables to be added")
+	cmd.PersistentFlags().StringSliceVar(&o.removeRules, "remove", nil,
+		"Table filter rules of the tables to be removed")
+	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0,
+		"The ts the change takes effect at, a ts a few seconds later than now is used if it's not specified. "+
+			"If the changefeed has passed it, the changefeed is restarted from it")
+	cmd.PersistentFlags().BoolVar(&o.initialLoad, "initial-load", false,
+		"Load the snapshots of the added tables at start-ts before replicating their incremental data")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *updateTablesChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed update-tables` command.
+func (o *updateTablesChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	if len(o.addRules) == 0 && len(o.removeRules) == 0 {
+		return errors.New("at least one of --add and --remove must be specified")
+	}
+	change, err := o.apiClient.Changefeeds().UpdateTables(ctx, &v2.TableSetChangeConfig{
+		AddRules:    o.addRules,
+		RemoveRules: o.removeRules,
+		StartTs:     o.startTs,
+		InitialLoad: o.initialLoad,
+	}, o.changefeedID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return util.JSONPrint(cmd, change)
+}
+
+// newCmdUpdateTablesChangefeed creates the `cli changefeed update-tables` command.
+func newCmdUpdateTablesChangefeed(f factory.Factory) *cobra.Command {
+	o := newUpdateTablesChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "update-tables",
+		Short: "Add tables to or remove tables from a running replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
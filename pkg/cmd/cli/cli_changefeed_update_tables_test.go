@@ -0,0 +1,60 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedUpdateTablesCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdUpdateTablesChangefeed(f)
+	f.changefeedsv2.EXPECT().UpdateTables(gomock.Any(), &v2.TableSetChangeConfig{
+		AddRules:    []string{"test.t2", "test.t3"},
+		RemoveRules: []string{"test.t1"},
+		StartTs:     100,
+		InitialLoad: true,
+	}, "abc").Return(&v2.TableSetChange{
+		Rules:   []string{"test.t2", "test.t3"},
+		StartTs: 100,
+	}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{
+		"update-tables", "--changefeed-id=abc", "--add=test.t2,test.t3",
+		"--remove=test.t1", "--start-ts=100", "--initial-load",
+	}
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, b.String(), `"start_ts": 100`)
+
+	f.changefeedsv2.EXPECT().UpdateTables(gomock.Any(), gomock.Any(), "abc").
+		Return(nil, errors.New("test"))
+	o := newUpdateTablesChangefeedOptions()
+	o.changefeedID = "abc"
+	o.addRules = []string{"test.t2"}
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run(cmd))
+
+	o.addRules = nil
+	require.NotNil(t, o.run(cmd))
+}
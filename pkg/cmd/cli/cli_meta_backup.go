@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/metabackup"
+	"github.com/spf13/cobra"
+)
+
+// metaBackupOptions defines flags for the `cli meta backup` command.
+type metaBackupOptions struct {
+	etcdClient *etcd.CDCEtcdClientImpl
+
+	clusterID     string
+	namespace     string
+	changefeedIDs []string
+	file          string
+}
+
+// newMetaBackupOptions creates new options for the `cli meta backup` command.
+func newMetaBackupOptions() *metaBackupOptions {
+	return &metaBackupOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *metaBackupOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.clusterID, "cluster-id", etcd.DefaultCDCClusterID, "cdc cluster id")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", model.DefaultNamespace,
+		"Namespace of the changefeeds to back up")
+	cmd.Flags().StringSliceVar(&o.changefeedIDs, "changefeed-ids", nil,
+		"Changefeeds to back up, all changefeeds are backed up if it's empty")
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Path of the file to write the snapshot to")
+	_ = cmd.MarkFlagRequired("file")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *metaBackupOptions) complete(f factory.Factory) error {
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	etcdClient.ClusterID = o.clusterID
+	o.etcdClient = etcdClient
+	return nil
+}
+
+// run runs the `cli meta backup` command.
+func (o *metaBackupOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	defer o.etcdClient.Close()
+
+	ids := make([]model.ChangeFeedID, 0, len(o.changefeedIDs))
+	for _, id := range o.changefeedIDs {
+		ids = append(ids, model.ChangeFeedID{Namespace: o.namespace, ID: id})
+	}
+	snap, err := metabackup.Backup(ctx, o.etcdClient, ids)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := snap.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.WriteFile(o.file, data, 0o600); err != nil {
+		return errors.Trace(err)
+	}
+
+	infos, _, err := snap.Changefeeds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmd.Printf("Back up %d changefeeds at revision %d to %s\n",
+		len(infos), snap.Revision, o.file)
+	return nil
+}
+
+// newCmdMetaBackup creates the `cli meta backup` command.
+func newCmdMetaBackup(f factory.Factory) *cobra.Command {
+	o := newMetaBackupOptions()
+
+	command := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the metadata of changefeeds to a file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
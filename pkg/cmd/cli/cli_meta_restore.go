@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/metabackup"
+	"github.com/spf13/cobra"
+	pd "github.com/tikv/pd/client"
+)
+
+// metaRestoreOptions defines flags for the `cli meta restore` command.
+type metaRestoreOptions struct {
+	etcdClient *etcd.CDCEtcdClientImpl
+	pdClient   pd.Client
+	snapshot   *metabackup.Snapshot
+
+	clusterID string
+	file      string
+	gcTTL     int64
+}
+
+// newMetaRestoreOptions creates new options for the `cli meta restore` command.
+func newMetaRestoreOptions() *metaRestoreOptions {
+	return &metaRestoreOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *metaRestoreOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.clusterID, "cluster-id", etcd.DefaultCDCClusterID,
+		"cdc cluster id to restore the metadata to")
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Path of the snapshot file")
+	cmd.Flags().Int64Var(&o.gcTTL, "gc-ttl", config.GetDefaultServerConfig().GcTTL,
+		"TTL of the service GC safepoints set at the checkpoints of the changefeeds, in seconds")
+	_ = cmd.MarkFlagRequired("file")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *metaRestoreOptions) complete(f factory.Factory) (err error) {
+	data, err := os.ReadFile(o.file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.snapshot, err = metabackup.Unmarshal(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	pdClient, err := f.PdClient()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			pdClient.Close()
+		}
+	}()
+	o.pdClient = pdClient
+
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	etcdClient.ClusterID = o.clusterID
+	o.etcdClient = etcdClient
+	return nil
+}
+
+// run runs the `cli meta restore` command.
+func (o *metaRestoreOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	defer o.pdClient.Close()
+	defer o.etcdClient.Close()
+
+	if err := metabackup.Restore(ctx, o.etcdClient, o.pdClient, o.snapshot, o.gcTTL); err != nil {
+		return errors.Trace(err)
+	}
+	infos, _, err := o.snapshot.Changefeeds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmd.Printf("Restore %d changefeeds from %s to cluster %s\n",
+		len(infos), o.file, o.clusterID)
+	return nil
+}
+
+// newCmdMetaRestore creates the `cli meta restore` command.
+func newCmdMetaRestore(f factory.Factory) *cobra.Command {
+	o := newMetaRestoreOptions()
+	commonOptions := newUnsafeCommonOptions()
+
+	command := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the metadata of changefeeds from a snapshot file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(commonOptions.confirmMetaDelete(cmd))
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+	commonOptions.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// topOptions defines flags for the `cli top` command.
+type topOptions struct {
+	apiV1Client apiv1client.APIV1Interface
+	apiV2Client apiv2client.APIV2Interface
+
+	interval     uint
+	slowestLimit int
+}
+
+// newTopOptions creates new options for the `cli top` command.
+func newTopOptions() *topOptions {
+	return &topOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *topOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().UintVarP(&o.interval, "interval", "I", 3, "Interval in seconds for refreshing the status")
+	cmd.PersistentFlags().IntVar(&o.slowestLimit, "slowest-tables", 5, "Number of the slowest tables of the selected changefeed to show")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *topOptions) complete(f factory.Factory) error {
+	var err error
+	o.apiV1Client, err = f.APIV1Client()
+	if err != nil {
+		return err
+	}
+	o.apiV2Client, err = f.APIV2Client()
+	return err
+}
+
+// validate checks that the provided options are valid.
+func (o *topOptions) validate() error {
+	if o.interval == 0 {
+		return errors.New("the interval must be greater than 0")
+	}
+	if o.slowestLimit < 0 {
+		return errors.New("the number of slowest tables must not be negative")
+	}
+	return nil
+}
+
+// perform performs a confirmed action, and returns the message of the result.
+func (o *topOptions) perform(ctx context.Context, a *topAction) string {
+	var err error
+	switch a.kind {
+	case topActionPause:
+		err = o.apiV1Client.Changefeeds().Pause(ctx, a.changefeed)
+	case topActionResume:
+		err = o.apiV2Client.Changefeeds().Resume(ctx,
+			&v2.ResumeChangefeedConfig{}, a.changefeed)
+	case topActionMoveTable:
+		err = o.apiV1Client.Changefeeds().MoveTable(ctx,
+			a.changefeed, a.tableID, a.captureID)
+	}
+	if err != nil {
+		return fmt.Sprintf("failed to %s: %s", a, err)
+	}
+	return fmt.Sprintf("succeeded to %s", a)
+}
+
+// draw clears the terminal and renders the screen. The terminal is in raw
+// mode, so the line feeds are translated to carriage returns and line feeds.
+func (o *topOptions) draw(w io.Writer, snap *topSnapshot, state *topState) {
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	_ = renderTop(&buf, snap, state, time.Duration(o.interval)*time.Second)
+	_, _ = w.Write(bytes.ReplaceAll(buf.Bytes(), []byte("\n"), []byte("\r\n")))
+}
+
+// readKeys reads the keys from the terminal until it fails. It blocks in
+// reading, so it's not stopped when the command quits, which is fine since
+// the process exits then.
+func readKeys(r io.Reader, keys chan<- rune) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// run the `cli top` command.
+func (o *topOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("`cli top` must be run in a terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = term.Restore(fd, oldState) }()
+	out := cmd.OutOrStdout()
+	// Switch to the alternate screen and hide the cursor, so the screen
+	// before running the command is restored after it quits.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan rune, 16)
+	go readKeys(os.Stdin, keys)
+
+	state := &topState{}
+	var snap *topSnapshot
+	refresh := func() {
+		s, err := collectTopSnapshot(ctx, o.apiV1Client, o.apiV2Client,
+			snap, state.selectedChangefeed(snap), o.slowestLimit)
+		if err != nil {
+			state.message = fmt.Sprintf("failed to refresh: %s", err)
+		} else {
+			snap = s
+		}
+		o.draw(out, snap, state)
+	}
+
+	ticker := time.NewTicker(time.Duration(o.interval) * time.Second)
+	defer ticker.Stop()
+	refresh()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			refresh()
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			action, quit := state.handleKey(k, snap)
+			if quit {
+				return nil
+			}
+			if action != nil {
+				state.message = o.perform(ctx, action)
+				refresh()
+				continue
+			}
+			o.draw(out, snap, state)
+		}
+	}
+}
+
+// newCmdTop creates the `cli top` command.
+func newCmdTop(f factory.Factory) *cobra.Command {
+	o := newTopOptions()
+
+	command := &cobra.Command{
+		Use:   "top",
+		Short: "Show the status of changefeeds and captures interactively",
+		Long: "Show the status of changefeeds and captures interactively, " +
+			"including the checkpoint lag, the throughput, the distribution of " +
+			"tables and the slowest tables. Changefeeds can be paused and resumed, " +
+			"and tables can be moved between captures after confirmations.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmdcontext.GetDefaultContext()
+
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run(ctx, cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestTopCollectAndRender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	o := newTopOptions()
+	o.interval, o.slowestLimit = 3, 1
+	require.Nil(t, o.complete(f))
+	ctx := context.Background()
+
+	now := time.Now().UnixMilli()
+	expect := func(count uint64) {
+		f.captures.EXPECT().List(gomock.Any()).Return(&[]model.Capture{
+			{ID: "c2", AdvertiseAddr: "127.0.0.1:8301"},
+			{ID: "c1", AdvertiseAddr: "127.0.0.1:8300", IsOwner: true},
+		}, nil)
+		f.changefeeds.EXPECT().List(gomock.Any(), "").Return(&[]model.ChangefeedCommonInfo{
+			{
+				UpstreamID: 1, ID: "cf1", FeedState: model.StateNormal,
+				CheckpointTSO: oracle.ComposeTS(now-2000, 0),
+			},
+			{
+				UpstreamID: 1, ID: "cf2", FeedState: model.StateFailed,
+				CheckpointTSO: oracle.ComposeTS(now-5000, 0),
+				RunningError:  &model.RunningError{Code: "CDC:ErrSinkURIInvalid", Message: "bad"},
+			},
+		}, nil)
+		f.tso.EXPECT().Query(gomock.Any(), &v2.UpstreamConfig{ID: 1}).
+			Return(&v2.Tso{Timestamp: now}, nil)
+		f.processor.EXPECT().List(gomock.Any()).Return(&[]model.ProcessorCommonInfo{
+			{CfID: "cf1", CaptureID: "c1"},
+			{CfID: "cf1", CaptureID: "c2"},
+		}, nil)
+		f.processor.EXPECT().Get(gomock.Any(), "cf1", "c1").
+			Return(&model.ProcessorDetail{Tables: []int64{1, 2}, Count: count}, nil)
+		f.processor.EXPECT().Get(gomock.Any(), "cf1", "c2").
+			Return(&model.ProcessorDetail{Tables: []int64{3}, Count: count}, nil)
+		f.changefeedsv2.EXPECT().Diagnose(gomock.Any(), "cf1", 0).
+			Return([]model.TableDiagnosis{
+				{TableID: 1, TableName: "test.t1", CheckpointTs: oracle.ComposeTS(now-1000, 0)},
+				{TableID: 3, TableName: "test.t3", CheckpointTs: oracle.ComposeTS(now-3000, 0)},
+			}, nil)
+	}
+
+	expect(100)
+	snap, err := collectTopSnapshot(ctx, o.apiV1Client, o.apiV2Client, nil, "cf1", o.slowestLimit)
+	require.Nil(t, err)
+	require.Equal(t, "c1", snap.captures[0].ID)
+	cf1 := snap.changefeed("cf1")
+	require.Equal(t, 2*time.Second, cf1.Lag)
+	require.Equal(t, uint64(200), cf1.Count)
+	require.Equal(t, map[model.CaptureID]int{"c1": 2, "c2": 1}, cf1.Tables)
+	require.Equal(t, []string{"CDC:ErrSinkURIInvalid: bad"}, snap.changefeed("cf2").Errors)
+	require.Len(t, snap.slowestTables, 1)
+	require.Equal(t, int64(3), snap.slowestTables[0].TableID)
+
+	// The throughput is computed from the previous snapshot.
+	prev := snap
+	prev.time = prev.time.Add(-2 * time.Second)
+	expect(300)
+	snap, err = collectTopSnapshot(ctx, o.apiV1Client, o.apiV2Client, prev, "cf1", o.slowestLimit)
+	require.Nil(t, err)
+	require.InDelta(t, 200, snap.changefeed("cf1").RowsPerSecond, 10)
+
+	var buf bytes.Buffer
+	require.Nil(t, renderTop(&buf, snap, &topState{}, 3*time.Second))
+	out := buf.String()
+	require.Regexp(t, "> cf1 +normal", out)
+	require.Contains(t, out, "CDC:ErrSinkURIInvalid: bad")
+	require.Regexp(t, "c1 +127.0.0.1:8300 +true +2 +2", out)
+	require.Contains(t, out, "SLOWEST TABLES OF cf1")
+	require.Contains(t, out, "test.t3")
+}
+
+func TestTopHandleKey(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []rune{keyUp, keyDown, 'p', keyEnter, keyEsc, keyBackspace, keyInterrupt},
+		parseKeys([]byte("\x1b[A\x1b[Bp\r\x1b\x7f\x03")))
+
+	snap := &topSnapshot{
+		captures: []model.Capture{{ID: "c1"}, {ID: "c2"}},
+		changefeeds: []*topChangefeed{
+			{ChangefeedCommonInfo: model.ChangefeedCommonInfo{ID: "cf1"}},
+			{ChangefeedCommonInfo: model.ChangefeedCommonInfo{ID: "cf2"}},
+		},
+	}
+	s := &topState{}
+	handle := func(keys ...rune) (*topAction, bool) {
+		var (
+			action *topAction
+			quit   bool
+		)
+		for _, k := range keys {
+			action, quit = s.handleKey(k, snap)
+		}
+		return action, quit
+	}
+
+	// Select and pause a changefeed.
+	action, quit := handle(keyDown, keyDown, 'p')
+	require.Nil(t, action)
+	require.False(t, quit)
+	require.Equal(t, topModeConfirm, s.mode)
+	action, _ = handle('y')
+	require.Equal(t, &topAction{kind: topActionPause, changefeed: "cf2"}, action)
+
+	// Actions are not performed unless confirmed.
+	action, _ = handle(keyUp, 'r', 'n')
+	require.Nil(t, action)
+	require.Equal(t, "cancelled", s.message)
+
+	// Move a table.
+	action, _ = handle('m', '4', '2', '2', keyBackspace, ' ', 'c', '2', keyEnter)
+	require.Nil(t, action)
+	var buf bytes.Buffer
+	require.Nil(t, renderTop(&buf, snap, s, time.Second))
+	require.Contains(t, buf.String(), "move table 42 of changefeed cf1 to capture c2? [y/N]")
+	action, _ = handle('y')
+	require.Equal(t, &topAction{
+		kind: topActionMoveTable, changefeed: "cf1", tableID: 42, captureID: "c2",
+	}, action)
+
+	// The capture must exist.
+	action, _ = handle('m', '1', ' ', 'c', '3', keyEnter)
+	require.Nil(t, action)
+	require.Equal(t, topModeBrowse, s.mode)
+	require.Equal(t, "capture c3 not found", s.message)
+
+	_, quit = handle('q')
+	require.True(t, quit)
+	_, quit = handle('m', keyInterrupt)
+	require.True(t, quit)
+}
+
+func TestTopPerform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	o := newTopOptions()
+	require.Nil(t, o.complete(f))
+	ctx := context.Background()
+
+	f.changefeeds.EXPECT().Pause(gomock.Any(), "cf1").Return(nil)
+	require.Equal(t, "succeeded to pause changefeed cf1",
+		o.perform(ctx, &topAction{kind: topActionPause, changefeed: "cf1"}))
+	f.changefeedsv2.EXPECT().Resume(gomock.Any(), gomock.Any(), "cf1").Return(nil)
+	require.Equal(t, "succeeded to resume changefeed cf1",
+		o.perform(ctx, &topAction{kind: topActionResume, changefeed: "cf1"}))
+	f.changefeeds.EXPECT().MoveTable(gomock.Any(), "cf1", int64(42), "c2").
+		Return(context.DeadlineExceeded)
+	require.Equal(t, "failed to move table 42 of changefeed cf1 to capture c2: "+
+		"context deadline exceeded", o.perform(ctx, &topAction{
+		kind: topActionMoveTable, changefeed: "cf1", tableID: 42, captureID: "c2",
+	}))
+
+	o.interval = 0
+	require.Regexp(t, ".*interval must be greater than 0.*", o.validate())
+}
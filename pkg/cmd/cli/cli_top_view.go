@@ -0,0 +1,439 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// topChangefeed is the status of a changefeed shown by `cli top`.
+type topChangefeed struct {
+	model.ChangefeedCommonInfo
+	// Lag is how far the checkpoint falls behind the upstream.
+	Lag time.Duration
+	// Count is the number of rows replicated by all processors.
+	Count uint64
+	// RowsPerSecond is computed from the counts of two adjacent snapshots.
+	RowsPerSecond float64
+	// Tables is the number of tables replicated by each capture.
+	Tables map[model.CaptureID]int
+	Errors []string
+}
+
+// topSnapshot is the status of the cluster at a time.
+type topSnapshot struct {
+	time        time.Time
+	captures    []model.Capture
+	changefeeds []*topChangefeed
+	// selected is the changefeed whose slowest tables are collected.
+	selected      string
+	slowestTables []model.TableDiagnosis
+	// upstreamTime is the physical time of each upstream in milliseconds.
+	upstreamTime map[uint64]int64
+}
+
+// changefeed returns the changefeed of the snapshot with the id.
+func (s *topSnapshot) changefeed(id string) *topChangefeed {
+	if s == nil {
+		return nil
+	}
+	for _, cf := range s.changefeeds {
+		if cf.ID == id {
+			return cf
+		}
+	}
+	return nil
+}
+
+// collectTopSnapshot collects the status of the cluster. prev is the previous
+// snapshot, which is used to compute the throughput of the changefeeds.
+func collectTopSnapshot(
+	ctx context.Context,
+	apiV1Client apiv1client.APIV1Interface,
+	apiV2Client apiv2client.APIV2Interface,
+	prev *topSnapshot, selected string, slowestLimit int,
+) (*topSnapshot, error) {
+	snap := &topSnapshot{
+		time:         time.Now(),
+		selected:     selected,
+		upstreamTime: make(map[uint64]int64),
+	}
+	captures, err := apiV1Client.Captures().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.captures = *captures
+	sort.Slice(snap.captures, func(i, j int) bool {
+		return snap.captures[i].AdvertiseAddr < snap.captures[j].AdvertiseAddr
+	})
+
+	changefeeds, err := apiV1Client.Changefeeds().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range *changefeeds {
+		cf := &topChangefeed{
+			ChangefeedCommonInfo: info,
+			Tables:               make(map[model.CaptureID]int),
+		}
+		if info.RunningError != nil {
+			cf.Errors = append(cf.Errors, fmt.Sprintf("%s: %s",
+				info.RunningError.Code, info.RunningError.Message))
+		}
+		now, ok := snap.upstreamTime[info.UpstreamID]
+		if !ok {
+			tso, err := apiV2Client.Tso().Query(ctx,
+				&v2.UpstreamConfig{ID: info.UpstreamID})
+			if err != nil {
+				return nil, err
+			}
+			now = tso.Timestamp
+			snap.upstreamTime[info.UpstreamID] = now
+		}
+		lag := now - oracle.ExtractPhysical(info.CheckpointTSO)
+		if lag > 0 {
+			cf.Lag = time.Duration(lag) * time.Millisecond
+		}
+		snap.changefeeds = append(snap.changefeeds, cf)
+	}
+
+	processors, err := apiV1Client.Processors().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range *processors {
+		cf := snap.changefeed(p.CfID)
+		if cf == nil {
+			continue
+		}
+		detail, err := apiV1Client.Processors().Get(ctx, p.CfID, p.CaptureID)
+		if err != nil {
+			cf.Errors = append(cf.Errors, fmt.Sprintf("%s: %s", p.CaptureID, err))
+			continue
+		}
+		cf.Count += detail.Count
+		cf.Tables[p.CaptureID] += len(detail.Tables)
+		if detail.Error != nil {
+			cf.Errors = append(cf.Errors, fmt.Sprintf("%s: %s: %s",
+				p.CaptureID, detail.Error.Code, detail.Error.Message))
+		}
+	}
+	if prev != nil {
+		elapsed := snap.time.Sub(prev.time).Seconds()
+		for _, cf := range snap.changefeeds {
+			last := prev.changefeed(cf.ID)
+			if last == nil || cf.Count < last.Count || elapsed <= 0 {
+				continue
+			}
+			cf.RowsPerSecond = float64(cf.Count-last.Count) / elapsed
+		}
+	}
+
+	if cf := snap.changefeed(selected); cf != nil && cf.FeedState == model.StateNormal {
+		tables, err := apiV2Client.Changefeeds().Diagnose(ctx, selected, 0)
+		if err != nil {
+			cf.Errors = append(cf.Errors, err.Error())
+		} else {
+			sort.Slice(tables, func(i, j int) bool {
+				return tables[i].CheckpointTs < tables[j].CheckpointTs
+			})
+			if len(tables) > slowestLimit {
+				tables = tables[:slowestLimit]
+			}
+			snap.slowestTables = tables
+		}
+	}
+	return snap, nil
+}
+
+const (
+	keyUp rune = -(iota + 1)
+	keyDown
+	keyEnter
+	keyBackspace
+	keyEsc
+	keyInterrupt
+)
+
+// parseKeys parses the bytes read from a terminal in raw mode into keys.
+func parseKeys(buf []byte) []rune {
+	var keys []rune
+	for len(buf) > 0 {
+		switch {
+		case len(buf) >= 3 && buf[0] == 0x1b && buf[1] == '[':
+			switch buf[2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			}
+			buf = buf[3:]
+			continue
+		case buf[0] == 0x1b:
+			keys = append(keys, keyEsc)
+		case buf[0] == '\r' || buf[0] == '\n':
+			keys = append(keys, keyEnter)
+		case buf[0] == 0x7f || buf[0] == 0x08:
+			keys = append(keys, keyBackspace)
+		case buf[0] == 0x03:
+			keys = append(keys, keyInterrupt)
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, r)
+			buf = buf[size:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+type topActionKind int
+
+const (
+	topActionPause topActionKind = iota
+	topActionResume
+	topActionMoveTable
+)
+
+// topAction is an operation on a changefeed, it's performed after confirmed.
+type topAction struct {
+	kind       topActionKind
+	changefeed string
+	tableID    int64
+	captureID  string
+}
+
+func (a *topAction) String() string {
+	switch a.kind {
+	case topActionPause:
+		return fmt.Sprintf("pause changefeed %s", a.changefeed)
+	case topActionResume:
+		return fmt.Sprintf("resume changefeed %s", a.changefeed)
+	default:
+		return fmt.Sprintf("move table %d of changefeed %s to capture %s",
+			a.tableID, a.changefeed, a.captureID)
+	}
+}
+
+type topMode int
+
+const (
+	topModeBrowse topMode = iota
+	// topModeInput is typing the table and the capture to move the table to.
+	topModeInput
+	topModeConfirm
+)
+
+// topState is the state of the interactions of `cli top`.
+type topState struct {
+	selected int
+	mode     topMode
+	input    string
+	pending  *topAction
+	message  string
+}
+
+// selectedChangefeed returns the id of the selected changefeed, or an empty
+// string if there is no changefeed.
+func (s *topState) selectedChangefeed(snap *topSnapshot) string {
+	if snap == nil || len(snap.changefeeds) == 0 {
+		return ""
+	}
+	if s.selected >= len(snap.changefeeds) {
+		s.selected = len(snap.changefeeds) - 1
+	}
+	return snap.changefeeds[s.selected].ID
+}
+
+// handleKey updates the state by the key. It returns the action to perform
+// if an action is confirmed, and whether to quit.
+func (s *topState) handleKey(k rune, snap *topSnapshot) (*topAction, bool) {
+	if k == keyInterrupt {
+		return nil, true
+	}
+	switch s.mode {
+	case topModeInput:
+		switch k {
+		case keyEsc:
+			s.mode, s.input, s.message = topModeBrowse, "", "cancelled"
+		case keyBackspace:
+			if len(s.input) > 0 {
+				_, size := utf8.DecodeLastRuneInString(s.input)
+				s.input = s.input[:len(s.input)-size]
+			}
+		case keyEnter:
+			action, err := s.parseMoveTable(snap)
+			s.mode, s.input = topModeBrowse, ""
+			if err != nil {
+				s.message = err.Error()
+				return nil, false
+			}
+			s.mode, s.pending = topModeConfirm, action
+		default:
+			if k > 0 {
+				s.input += string(k)
+			}
+		}
+	case topModeConfirm:
+		action := s.pending
+		s.mode, s.pending = topModeBrowse, nil
+		if k == 'y' || k == 'Y' {
+			return action, false
+		}
+		s.message = "cancelled"
+	default:
+		s.message = ""
+		changefeed := s.selectedChangefeed(snap)
+		switch k {
+		case 'q', 'Q', keyEsc:
+			return nil, true
+		case keyUp, 'k':
+			if s.selected > 0 {
+				s.selected--
+			}
+		case keyDown, 'j':
+			if snap != nil && s.selected < len(snap.changefeeds)-1 {
+				s.selected++
+			}
+		case 'p':
+			if changefeed != "" {
+				s.mode = topModeConfirm
+				s.pending = &topAction{kind: topActionPause, changefeed: changefeed}
+			}
+		case 'r':
+			if changefeed != "" {
+				s.mode = topModeConfirm
+				s.pending = &topAction{kind: topActionResume, changefeed: changefeed}
+			}
+		case 'm':
+			if changefeed != "" {
+				s.mode = topModeInput
+			}
+		}
+	}
+	return nil, false
+}
+
+// parseMoveTable parses the input in the form of `<table-id> <capture-id>`.
+func (s *topState) parseMoveTable(snap *topSnapshot) (*topAction, error) {
+	fields := strings.Fields(s.input)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid input %q, expect <table-id> <capture-id>", s.input)
+	}
+	tableID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table id %q", fields[0])
+	}
+	found := false
+	for _, c := range snap.captures {
+		found = found || c.ID == fields[1]
+	}
+	if !found {
+		return nil, fmt.Errorf("capture %s not found", fields[1])
+	}
+	return &topAction{
+		kind:       topActionMoveTable,
+		changefeed: s.selectedChangefeed(snap),
+		tableID:    tableID,
+		captureID:  fields[1],
+	}, nil
+}
+
+// renderTop writes the screen of `cli top`.
+func renderTop(w io.Writer, snap *topSnapshot, state *topState, interval time.Duration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "cdc top - %s, refresh every %s\n\n",
+		time.Now().Format("2006-01-02 15:04:05"), interval)
+	if snap != nil {
+		selected := state.selectedChangefeed(snap)
+		fmt.Fprintln(tw, "  CHANGEFEED\tSTATE\tCHECKPOINT\tLAG\tROWS/S\tTABLES\tERROR")
+		for _, cf := range snap.changefeeds {
+			marker := " "
+			if cf.ID == selected {
+				marker = ">"
+			}
+			tables := 0
+			for _, n := range cf.Tables {
+				tables += n
+			}
+			errMsg := "-"
+			if len(cf.Errors) > 0 {
+				errMsg = strings.Join(cf.Errors, "; ")
+			}
+			fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\t%.1f\t%d\t%s\n", marker, cf.ID,
+				cf.FeedState, time.Time(cf.CheckpointTime).Format("2006-01-02 15:04:05"), cf.Lag.Round(time.Millisecond),
+				cf.RowsPerSecond, tables, errMsg)
+		}
+
+		fmt.Fprintf(tw, "\nCAPTURE\tADDRESS\tOWNER\tTABLES OF %s\tALL TABLES\n", orDash(selected))
+		for _, c := range snap.captures {
+			all := 0
+			for _, cf := range snap.changefeeds {
+				all += cf.Tables[c.ID]
+			}
+			ofSelected := 0
+			if cf := snap.changefeed(selected); cf != nil {
+				ofSelected = cf.Tables[c.ID]
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%t\t%d\t%d\n",
+				c.ID, c.AdvertiseAddr, c.IsOwner, ofSelected, all)
+		}
+
+		if len(snap.slowestTables) > 0 && snap.selected == selected {
+			now := int64(0)
+			if cf := snap.changefeed(selected); cf != nil {
+				now = snap.upstreamTime[cf.UpstreamID]
+			}
+			fmt.Fprintf(tw, "\nSLOWEST TABLES OF %s\tID\tCAPTURE\tLAG\tBLOCKING STAGE\n", selected)
+			for _, t := range snap.slowestTables {
+				lag := time.Duration(now-oracle.ExtractPhysical(t.CheckpointTs)) * time.Millisecond
+				fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", t.TableName, t.TableID,
+					t.CaptureID, lag, t.BlockingStage)
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	switch state.mode {
+	case topModeInput:
+		fmt.Fprintf(w, "Move a table of %s, input <table-id> <capture-id>: %s\n",
+			state.selectedChangefeed(snap), state.input)
+	case topModeConfirm:
+		fmt.Fprintf(w, "Are you sure to %s? [y/N]\n", state.pending)
+	default:
+		if state.message != "" {
+			fmt.Fprintln(w, state.message)
+		}
+		fmt.Fprintln(w, "up/k, down/j: select  p: pause  r: resume  m: move a table  q: quit")
+	}
+	return nil
+}
@@ -83,6 +83,16 @@ func (f *factoryImpl) GetLogLevel() string {
 	return f.clientGetter.GetLogLevel()
 }
 
+// GetUser returns the user to authenticate with CDC server.
+func (f *factoryImpl) GetUser() string {
+	return f.clientGetter.GetUser()
+}
+
+// GetPassword returns the password of the user.
+func (f *factoryImpl) GetPassword() string {
+	return f.clientGetter.GetPassword()
+}
+
 // GetCredential returns security credentials.
 func (f *factoryImpl) GetCredential() *security.Credential {
 	return f.clientGetter.GetCredential()
@@ -208,7 +218,8 @@ func (f *factoryImpl) APIV1Client() (apiv1client.APIV1Interface, error) {
 		return nil, errors.Trace(err)
 	}
 	log.Info(serverAddr)
-	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential(),
+		f.clientGetter.GetUser(), f.clientGetter.GetPassword())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -222,14 +233,16 @@ func (f *factoryImpl) APIV2Client() (apiv2client.APIV2Interface, error) {
 		return nil, errors.Trace(err)
 	}
 	log.Info(serverAddr)
-	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential(),
+		f.clientGetter.GetUser(), f.clientGetter.GetPassword())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	if err := checkCDCVersion(client); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return apiv2client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	return apiv2client.NewAPIClient(serverAddr, f.clientGetter.GetCredential(),
+		f.clientGetter.GetUser(), f.clientGetter.GetPassword())
 }
 
 // findServerAddr find the cdc server address by the following logic
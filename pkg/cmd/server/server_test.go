@@ -214,6 +214,11 @@ func TestParseCfg(t *testing.T) {
 			},
 			EnableNewSink: true,
 		},
+		Tracing: &config.TracingConfig{
+			Enable:       false,
+			OTLPEndpoint: "127.0.0.1:4317",
+			SampleRate:   0.0001,
+		},
 		ClusterID: "default",
 	}, o.serverConfig)
 }
@@ -283,6 +288,11 @@ server-worker-pool-size = 16
 heartbeat-tick = 3
 max-task-concurrency = 11
 check-balance-interval = "10s"
+
+[tracing]
+enable = true
+otlp-endpoint = "127.0.0.1:14317"
+sample-rate = 0.5
 `, dataDir)
 	err := os.WriteFile(configPath, []byte(configContent), 0o644)
 	require.Nil(t, err)
@@ -370,6 +380,11 @@ check-balance-interval = "10s"
 			},
 			EnableNewSink: true,
 		},
+		Tracing: &config.TracingConfig{
+			Enable:       true,
+			OTLPEndpoint: "127.0.0.1:14317",
+			SampleRate:   0.5,
+		},
 		ClusterID: "default",
 	}, o.serverConfig)
 }
@@ -518,6 +533,11 @@ cert-allowed-cn = ["dd","ee"]
 			},
 			EnableNewSink: true,
 		},
+		Tracing: &config.TracingConfig{
+			Enable:       false,
+			OTLPEndpoint: "127.0.0.1:4317",
+			SampleRate:   0.0001,
+		},
 		ClusterID: "default",
 	}, o.serverConfig)
 }
@@ -584,3 +604,53 @@ unknown3 = 3
 		EnableNewSink: true,
 	}, o.serverConfig.Debug)
 }
+
+func TestReloadCfg(t *testing.T) {
+	original := config.GetGlobalServerConfig()
+	defer func() {
+		config.StoreGlobalServerConfig(original)
+		config.SetServerConfigLoader(nil)
+	}()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ticdc.toml")
+	err := os.WriteFile(configPath, []byte(`
+per-table-memory-quota = 1024
+gc-ttl = 500
+`), 0o644)
+	require.Nil(t, err)
+
+	cmd := new(cobra.Command)
+	o := newOptions()
+	o.addFlags(cmd)
+	require.Nil(t, cmd.ParseFlags([]string{
+		"--config", configPath, "--sorter-max-memory-percentage", "20",
+	}))
+	require.Nil(t, o.complete(cmd))
+	config.StoreGlobalServerConfig(o.serverConfig)
+
+	// Flags take precedence over the config file after reloading.
+	err = os.WriteFile(configPath, []byte(`
+per-table-memory-quota = 2048
+gc-ttl = 600
+[sorter]
+max-memory-percentage = 40
+`), 0o644)
+	require.Nil(t, err)
+	result, err := config.ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Equal(t, []string{"per-table-memory-quota"}, result.AppliedToNewTables)
+	require.Equal(t, []string{"gc-ttl"}, result.RequireRestart)
+	cfg := config.GetGlobalServerConfig()
+	require.Equal(t, uint64(2048), cfg.PerTableMemoryQuota)
+	require.Equal(t, int64(500), cfg.GcTTL)
+	require.Equal(t, 20, cfg.Sorter.MaxMemoryPercentage)
+
+	// The global config is not changed if the config file is invalid.
+	err = os.WriteFile(configPath, []byte(`per-table-memory-quota = "invalid"`), 0o644)
+	require.Nil(t, err)
+	_, err = config.ReloadGlobalServerConfig()
+	require.Regexp(t, ".*ErrServerConfigReloadFailed.*", err)
+	require.Equal(t, cfg, config.GetGlobalServerConfig())
+}
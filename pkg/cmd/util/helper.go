@@ -63,16 +63,37 @@ type shutdownNotify func() <-chan struct{}
 // InitSignalHandling initializes signal handling.
 // It must be called after InitCmd.
 func InitSignalHandling(shutdown shutdownNotify, cancel context.CancelFunc) {
+	InitSignalHandlingWithReload(shutdown, cancel, nil)
+}
+
+// InitSignalHandlingWithReload initializes signal handling like
+// InitSignalHandling, except that SIGHUP calls reload instead of shutting
+// down if reload is not nil.
+// It must be called after InitCmd.
+func InitSignalHandlingWithReload(
+	shutdown shutdownNotify, cancel context.CancelFunc, reload func(),
+) {
 	// systemd and k8s send signals twice. The first is for graceful shutdown,
 	// and the second is for force shutdown.
 	// We use 2 for channel length to ease testing.
 	signalChanLen := 2
 	sc := make(chan os.Signal, signalChanLen)
 	signal.Notify(sc,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
+	if reload == nil {
+		signal.Notify(sc, syscall.SIGHUP)
+	} else {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				log.Info("got signal, reload", zap.Stringer("signal", syscall.SIGHUP))
+				reload()
+			}
+		}()
+	}
 
 	go func() {
 		sig := <-sc
@@ -328,3 +328,26 @@ func TestInitSignalHandlingForceShutdown(t *testing.T) {
 		require.Fail(t, "timeout")
 	}
 }
+
+func TestInitSignalHandlingWithReload(t *testing.T) {
+	shutdown := func() <-chan struct{} {
+		require.Fail(t, "unexpected shutdown")
+		return nil
+	}
+	cancel := func() { require.Fail(t, "unexpected cancel") }
+	reloadCh := make(chan struct{}, 1)
+	reload := func() { reloadCh <- struct{}{} }
+	InitSignalHandlingWithReload(shutdown, cancel, reload)
+	self, err := os.FindProcess(os.Getpid())
+	require.Nil(t, err)
+	// SIGHUP reloads instead of shutting down, and it can be sent many times.
+	for i := 0; i < 2; i++ {
+		err = self.Signal(syscall.SIGHUP)
+		require.Nil(t, err)
+		select {
+		case <-reloadCh:
+		case <-time.After(1 * time.Second):
+			require.Fail(t, "timeout")
+		}
+	}
+}
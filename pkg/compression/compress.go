@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// None means no compression.
+	None string = "none"
+	// Snappy is the snappy block format.
+	Snappy string = "snappy"
+	// LZ4 is the lz4 frame format.
+	LZ4 string = "lz4"
+	// ZSTD is the zstd frame format.
+	ZSTD string = "zstd"
+)
+
+// The encoder and decoder of zstd are safe for concurrent use of EncodeAll
+// and DecodeAll, so they are shared.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// Supported returns whether the compression codec is supported.
+func Supported(cc string) bool {
+	switch strings.ToLower(cc) {
+	case "", None, Snappy, LZ4, ZSTD:
+		return true
+	default:
+		return false
+	}
+}
+
+// Encode compresses the data with the compression codec.
+func Encode(cc string, data []byte) ([]byte, error) {
+	switch strings.ToLower(cc) {
+	case "", None:
+		return data, nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	case LZ4:
+		var buf bytes.Buffer
+		writer := lz4.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		return buf.Bytes(), nil
+	case ZSTD:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, cerror.ErrCompressionFailed.GenWithStack(
+			"unsupported compression codec %s", cc)
+	}
+}
+
+// Decode decompresses the data with the compression codec.
+func Decode(cc string, data []byte) ([]byte, error) {
+	switch strings.ToLower(cc) {
+	case "", None:
+		return data, nil
+	case Snappy:
+		res, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecompressionFailed, err)
+		}
+		return res, nil
+	case LZ4:
+		res, err := io.ReadAll(lz4.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecompressionFailed, err)
+		}
+		return res, nil
+	case ZSTD:
+		res, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecompressionFailed, err)
+		}
+		return res, nil
+	default:
+		return nil, cerror.ErrDecompressionFailed.GenWithStack(
+			"unsupported compression codec %s", cc)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte(`{"id":1,"name":"tidb"}`), 100)
+	for _, cc := range []string{"", None, Snappy, LZ4, ZSTD, "ZSTD"} {
+		require.True(t, Supported(cc))
+		compressed, err := Encode(cc, data)
+		require.Nil(t, err)
+		if cc != "" && cc != None {
+			require.Less(t, len(compressed), len(data), cc)
+		}
+		decompressed, err := Decode(cc, compressed)
+		require.Nil(t, err)
+		require.Equal(t, data, decompressed, cc)
+	}
+
+	require.False(t, Supported("gzip"))
+	_, err := Encode("gzip", data)
+	require.Regexp(t, ".*unsupported compression codec gzip.*", err)
+	_, err = Decode(Snappy, data)
+	require.Regexp(t, ".*ErrDecompressionFailed.*", err)
+}
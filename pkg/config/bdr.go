@@ -0,0 +1,101 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+)
+
+const (
+	// ConflictResolutionLastWriterWins keeps the change with the greater
+	// version when a replicated change conflicts with a local change.
+	ConflictResolutionLastWriterWins = "last-writer-wins"
+	// ConflictResolutionReject skips and logs a replicated change if the
+	// downstream row is not in the state the change expects.
+	ConflictResolutionReject = "reject"
+)
+
+// BDRConfig is the config of bidirectional replication. In bdr mode, the
+// changefeed marks every transaction it writes into the downstream, so the
+// changefeed replicating in the opposite direction can drop them instead of
+// replicating them back.
+type BDRConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// ReplicaID identifies the upstream cluster of the changefeed, transactions
+	// written by the changefeed are marked with it.
+	ReplicaID uint64 `toml:"replica-id" json:"replica-id"`
+	// FilterReplicaIDs are the IDs of clusters whose transactions written into
+	// the upstream by TiCDC are not replicated.
+	FilterReplicaIDs []uint64 `toml:"filter-replica-ids" json:"filter-replica-ids"`
+	// ConflictResolution is either `last-writer-wins` or `reject`.
+	ConflictResolution string `toml:"conflict-resolution" json:"conflict-resolution"`
+	// VersionColumn is the column compared by `last-writer-wins`, it's
+	// required by `last-writer-wins` and must be a `BIGINT UNSIGNED` column
+	// of every replicated table in both clusters. Replicated changes set it
+	// to their commit ts, so local writes must set it to a TSO of the cluster
+	// too, e.g. `@@tidb_current_ts`. A downstream row with a NULL version is
+	// older than any replicated change.
+	VersionColumn string `toml:"version-column" json:"version-column"`
+	// ReplicateDDL indicates whether DDLs are replicated. It must be enabled
+	// for at most one direction, otherwise DDLs are replicated back.
+	ReplicateDDL bool `toml:"replicate-ddl" json:"replicate-ddl"`
+}
+
+func (c *BDRConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) error {
+	if !c.Enable {
+		return nil
+	}
+	if sinkURI != nil && !sink.IsMySQLCompatibleScheme(strings.ToLower(sinkURI.Scheme)) {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("bdr mode is not supported by the sink scheme %s", sinkURI.Scheme))
+	}
+	if !enableOldValue {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"bdr mode requires old value to be enabled")
+	}
+	if c.ReplicaID == 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"the replica id of bdr mode must be greater than 0")
+	}
+	if len(c.FilterReplicaIDs) == 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"the filter replica ids of bdr mode are empty")
+	}
+	for _, id := range c.FilterReplicaIDs {
+		if id == c.ReplicaID {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("the filter replica ids of bdr mode contain "+
+					"the replica id %d of the changefeed", id))
+		}
+	}
+	switch c.ConflictResolution {
+	case "":
+		c.ConflictResolution = ConflictResolutionLastWriterWins
+	case ConflictResolutionLastWriterWins, ConflictResolutionReject:
+	default:
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("unknown conflict resolution %s", c.ConflictResolution))
+	}
+	if c.ConflictResolution == ConflictResolutionLastWriterWins && c.VersionColumn == "" {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"the version column of bdr mode must be specified " +
+				"to resolve conflicts with last-writer-wins")
+	}
+	return nil
+}
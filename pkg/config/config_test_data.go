@@ -90,13 +90,20 @@ const (
     "ca-path": "",
     "cert-path": "",
     "key-path": "",
-    "cert-allowed-cn": null
+    "cert-allowed-cn": null,
+    "client-user-required": false,
+    "client-allowed-user": null,
+    "client-roles": null,
+    "client-default-role": ""
   },
   "per-table-memory-quota": 67108864,
   "kv-client": {
     "worker-concurrent": 8,
     "worker-pool-size": 0,
     "region-scan-limit": 40,
+    "region-scan-global-limit": 0,
+    "region-scan-store-limit": 0,
+    "region-scan-bandwidth": 0,
     "region-retry-duration": 60000000000
   },
   "debug": {
@@ -137,6 +144,11 @@ const (
     },
     "enable-new-sink": true
   },
+  "tracing": {
+    "enable": false,
+    "otlp-endpoint": "127.0.0.1:4317",
+    "sample-rate": 0.0001
+  },
   "cluster-id": "default"
 }`
 
@@ -144,6 +156,7 @@ const (
   "case-sensitive": false,
   "enable-old-value": true,
   "force-replicate": true,
+  "initial-load": false,
   "check-gc-safe-point": true,
   "enable-sync-point": false,
   "sync-point-interval": 600000000000,
@@ -173,14 +186,22 @@ const (
       }
     ],
     "schema-registry": "",
-    "transaction-atomicity": ""
+    "transaction-atomicity": "",
+    "computed-columns": null,
+    "large-message-handle": null
   },
   "consistent": {
     "level": "none",
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": ""
-  }
+  },
+  "routing-rules": null,
+  "bdr": null,
+  "ddl-policies": null,
+  "schema-history": null,
+  "rate-limit": null,
+  "heartbeat": null
 }`
 
 	testCfgTestReplicaConfigMarshal2 = `{
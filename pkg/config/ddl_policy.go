@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// DDLPolicyAction is the action taken on the DDLs matched by a DDL policy.
+type DDLPolicyAction string
+
+const (
+	// DDLPolicyActionSkip skips the DDLs, they are applied to the schema of
+	// TiCDC but not sent to the downstream.
+	DDLPolicyActionSkip DDLPolicyAction = "skip"
+	// DDLPolicyActionRewrite rewrites the DDLs before sending them to the
+	// downstream.
+	DDLPolicyActionRewrite DDLPolicyAction = "rewrite"
+	// DDLPolicyActionApprove holds the DDLs, the changefeed is blocked at the
+	// commit ts of the DDLs until they are approved or rejected by operators.
+	DDLPolicyActionApprove DDLPolicyAction = "approve"
+)
+
+// DDLRewrite is a built-in rewrite of DDLs.
+type DDLRewrite string
+
+const (
+	// DDLRewriteRemovePartition removes the partitioning clauses from
+	// `CREATE TABLE` and `ALTER TABLE`, partition management DDLs are skipped.
+	DDLRewriteRemovePartition DDLRewrite = "remove-partition"
+	// DDLRewriteRemoveTiFlashReplica removes the `SET TIFLASH REPLICA`
+	// clauses from `ALTER TABLE`.
+	DDLRewriteRemoveTiFlashReplica DDLRewrite = "remove-tiflash-replica"
+)
+
+// DDLPolicy decides how the DDLs matched by it are replicated. A DDL is
+// handled by the first policy matching it, DDLs matching no policies are
+// replicated as usual.
+type DDLPolicy struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// DDLTypes are the types of the matched DDLs, they are the same as the
+	// `ignore-event` of event filters. All DDLs are matched if it's empty.
+	DDLTypes []bf.EventType  `toml:"ddl-types" json:"ddl-types"`
+	Action   DDLPolicyAction `toml:"action" json:"action"`
+	// Rewrites are applied in order if the action is `rewrite`.
+	Rewrites []DDLRewrite `toml:"rewrites" json:"rewrites"`
+}
+
+func (p *DDLPolicy) validate() error {
+	if len(p.Matcher) == 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the matcher of ddl policy %v is empty", p))
+	}
+	if _, err := filter.Parse(p.Matcher); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the matcher of ddl policy %v is invalid: %s", p, err))
+	}
+	switch p.Action {
+	case DDLPolicyActionSkip, DDLPolicyActionApprove:
+		if len(p.Rewrites) != 0 {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("rewrites of ddl policy %v require the action %s",
+					p, DDLPolicyActionRewrite))
+		}
+	case DDLPolicyActionRewrite:
+		if len(p.Rewrites) == 0 {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("the rewrites of ddl policy %v are empty", p))
+		}
+		for _, rewrite := range p.Rewrites {
+			switch rewrite {
+			case DDLRewriteRemovePartition, DDLRewriteRemoveTiFlashReplica:
+			default:
+				return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+					fmt.Sprintf("unknown rewrite %s of ddl policy %v", rewrite, p))
+			}
+		}
+	default:
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("unknown action %s of ddl policy %v", p.Action, p))
+	}
+	return nil
+}
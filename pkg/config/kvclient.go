@@ -23,6 +23,12 @@ type KVClientConfig struct {
 	WorkerPoolSize int `toml:"worker-pool-size" json:"worker-pool-size"`
 	// region incremental scan limit for one table in a single store
 	RegionScanLimit int `toml:"region-scan-limit" json:"region-scan-limit"`
+	// region incremental scan limit for all tables in a capture, 0 means no limit
+	RegionScanGlobalLimit int `toml:"region-scan-global-limit" json:"region-scan-global-limit"`
+	// region incremental scan limit for all tables in a single store, 0 means no limit
+	RegionScanStoreLimit int `toml:"region-scan-store-limit" json:"region-scan-store-limit"`
+	// bytes per second of incremental scan data received by a capture, 0 means no limit
+	RegionScanBandwidth int `toml:"region-scan-bandwidth" json:"region-scan-bandwidth"`
 	// the total retry duration of connecting a region
 	RegionRetryDuration TomlDuration `toml:"region-retry-duration" json:"region-retry-duration"`
 }
@@ -37,6 +43,18 @@ func (c *KVClientConfig) ValidateAndAdjust() error {
 		return errors.ErrInvalidServerOption.GenWithStackByArgs(
 			"region-scan-limit should be at least 1")
 	}
+	if c.RegionScanGlobalLimit < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"region-scan-global-limit should not be negative")
+	}
+	if c.RegionScanStoreLimit < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"region-scan-store-limit should not be negative")
+	}
+	if c.RegionScanBandwidth < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"region-scan-bandwidth should not be negative")
+	}
 	if c.RegionRetryDuration <= 0 {
 		return errors.ErrInvalidServerOption.GenWithStackByArgs(
 			"region-scan-limit should be positive")
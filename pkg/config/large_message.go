@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// LargeMessageHandleOptionNone fails the changefeed if a single row
+	// exceeds `max-message-bytes`, which is the default behavior.
+	LargeMessageHandleOptionNone = "none"
+	// LargeMessageHandleOptionHandleKeyOnly sends only the handle key columns
+	// of a row exceeding `max-message-bytes`.
+	LargeMessageHandleOptionHandleKeyOnly = "handle-key-only"
+	// LargeMessageHandleOptionClaimCheck writes the full message of a row
+	// exceeding `max-message-bytes` to the claim-check storage, and sends a
+	// message containing the handle key columns and the location of it.
+	LargeMessageHandleOptionClaimCheck = "claim-check"
+)
+
+// LargeMessageHandleConfig is the config of how to handle the rows exceeding
+// `max-message-bytes` of MQ sinks, it's supported by open-protocol and
+// canal-json with the TiDB extension enabled.
+type LargeMessageHandleConfig struct {
+	LargeMessageHandleOption string `toml:"large-message-handle-option" json:"large-message-handle-option"`
+	// ClaimCheckStorageURI is the URI of the external storage of claim-check,
+	// such as `s3://bucket/prefix` and `file:///path`.
+	ClaimCheckStorageURI string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
+}
+
+// Enabled returns whether the large message handling is enabled.
+func (c *LargeMessageHandleConfig) Enabled() bool {
+	return c != nil && c.LargeMessageHandleOption != "" &&
+		c.LargeMessageHandleOption != LargeMessageHandleOptionNone
+}
+
+// EnableClaimCheck returns whether the claim-check is enabled.
+func (c *LargeMessageHandleConfig) EnableClaimCheck() bool {
+	return c != nil && c.LargeMessageHandleOption == LargeMessageHandleOptionClaimCheck
+}
+
+func (c *LargeMessageHandleConfig) validate(protocol string) error {
+	switch c.LargeMessageHandleOption {
+	case "", LargeMessageHandleOptionNone, LargeMessageHandleOptionHandleKeyOnly:
+		if c.ClaimCheckStorageURI != "" {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New(fmt.Sprintf("claim-check-storage-uri can only be "+
+					"configured with the %s option", LargeMessageHandleOptionClaimCheck)))
+		}
+	case LargeMessageHandleOptionClaimCheck:
+		if c.ClaimCheckStorageURI == "" {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New(fmt.Sprintf("claim-check-storage-uri must be "+
+					"configured with the %s option", LargeMessageHandleOptionClaimCheck)))
+		}
+	default:
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New(fmt.Sprintf("large message handle option %s is not supported, "+
+				"it should be %s, %s or %s", c.LargeMessageHandleOption,
+				LargeMessageHandleOptionNone, LargeMessageHandleOptionHandleKeyOnly,
+				LargeMessageHandleOptionClaimCheck)))
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	var p Protocol
+	if err := p.FromString(protocol); err != nil ||
+		(p != ProtocolOpen && p != ProtocolCanalJSON) {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New(fmt.Sprintf("large message handle option %s is not supported "+
+				"by protocol %s, only open-protocol and canal-json support it",
+				c.LargeMessageHandleOption, protocol)))
+	}
+	return nil
+}
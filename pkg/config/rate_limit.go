@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// timeOfDayFormat is the format of the start and end of rate limit schedules.
+const timeOfDayFormat = "15:04"
+
+// RateLimitConfig limits the throughput of a changefeed. The limits are
+// enforced when table sinks are flushed, transactions are never split by
+// them. They are shared evenly by the captures running the changefeed.
+// Zero means unlimited.
+type RateLimitConfig struct {
+	RowsPerSecond  uint64 `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond uint64 `toml:"bytes-per-second" json:"bytes-per-second"`
+	// TimeZone is the time zone of the schedules, the local time zone of
+	// TiCDC is used if it's empty.
+	TimeZone string `toml:"time-zone" json:"time-zone"`
+	// Schedules override the limits above during their time windows, the
+	// first schedule matching the current time is used.
+	Schedules []*RateLimitSchedule `toml:"schedules" json:"schedules"`
+}
+
+// RateLimitSchedule is the limits in a time window of every day. A schedule
+// must either limit the rows or bytes, or pause the changefeed.
+type RateLimitSchedule struct {
+	// Start and End are times of a day like `08:00`, the window crosses
+	// midnight if End is before Start.
+	Start          string `toml:"start" json:"start"`
+	End            string `toml:"end" json:"end"`
+	RowsPerSecond  uint64 `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond uint64 `toml:"bytes-per-second" json:"bytes-per-second"`
+	// Pause stops flushing table sinks during the window.
+	Pause bool `toml:"pause" json:"pause"`
+}
+
+// Location returns the time zone of the schedules.
+func (c *RateLimitConfig) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.TimeZone)
+}
+
+func (c *RateLimitConfig) validate() error {
+	if _, err := c.Location(); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the time zone %s of rate limit is invalid: %s", c.TimeZone, err))
+	}
+	for _, s := range c.Schedules {
+		start, end, err := s.Window()
+		if err != nil {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("the window of rate limit schedule %v is invalid: %s", s, err))
+		}
+		if start == end {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("the window of rate limit schedule %v is empty", s))
+		}
+		limited := s.RowsPerSecond != 0 || s.BytesPerSecond != 0
+		if limited && s.Pause {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("rate limit schedule %v can not both limit and pause", s))
+		}
+		if !limited && !s.Pause {
+			return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+				fmt.Sprintf("rate limit schedule %v neither limits nor pauses", s))
+		}
+	}
+	return nil
+}
+
+// Window returns the start and end of the schedule as the offsets from
+// the beginning of a day.
+func (s *RateLimitSchedule) Window() (time.Duration, time.Duration, error) {
+	start, err := parseTimeOfDay(s.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseTimeOfDay(s.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse(timeOfDayFormat, s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -76,17 +76,29 @@ func (d *Duration) UnmarshalText(text []byte) error {
 type ReplicaConfig replicaConfig
 
 type replicaConfig struct {
-	CaseSensitive      bool              `toml:"case-sensitive" json:"case-sensitive"`
-	EnableOldValue     bool              `toml:"enable-old-value" json:"enable-old-value"`
-	ForceReplicate     bool              `toml:"force-replicate" json:"force-replicate"`
-	CheckGCSafePoint   bool              `toml:"check-gc-safe-point" json:"check-gc-safe-point"`
-	EnableSyncPoint    bool              `toml:"enable-sync-point" json:"enable-sync-point"`
-	SyncPointInterval  time.Duration     `toml:"sync-point-interval" json:"sync-point-interval"`
-	SyncPointRetention time.Duration     `toml:"sync-point-retention" json:"sync-point-retention"`
-	Filter             *FilterConfig     `toml:"filter" json:"filter"`
-	Mounter            *MounterConfig    `toml:"mounter" json:"mounter"`
-	Sink               *SinkConfig       `toml:"sink" json:"sink"`
-	Consistent         *ConsistentConfig `toml:"consistent" json:"consistent"`
+	CaseSensitive      bool                 `toml:"case-sensitive" json:"case-sensitive"`
+	EnableOldValue     bool                 `toml:"enable-old-value" json:"enable-old-value"`
+	ForceReplicate     bool                 `toml:"force-replicate" json:"force-replicate"`
+	InitialLoad        bool                 `toml:"initial-load" json:"initial-load"`
+	CheckGCSafePoint   bool                 `toml:"check-gc-safe-point" json:"check-gc-safe-point"`
+	EnableSyncPoint    bool                 `toml:"enable-sync-point" json:"enable-sync-point"`
+	SyncPointInterval  time.Duration        `toml:"sync-point-interval" json:"sync-point-interval"`
+	SyncPointRetention time.Duration        `toml:"sync-point-retention" json:"sync-point-retention"`
+	Filter             *FilterConfig        `toml:"filter" json:"filter"`
+	Mounter            *MounterConfig       `toml:"mounter" json:"mounter"`
+	Sink               *SinkConfig          `toml:"sink" json:"sink"`
+	Consistent         *ConsistentConfig    `toml:"consistent" json:"consistent"`
+	RoutingRules       []*RoutingRule       `toml:"routing-rules" json:"routing-rules"`
+	BDR                *BDRConfig           `toml:"bdr" json:"bdr"`
+	DDLPolicies        []*DDLPolicy         `toml:"ddl-policies" json:"ddl-policies"`
+	SchemaHistory      *SchemaHistoryConfig `toml:"schema-history" json:"schema-history"`
+	RateLimit          *RateLimitConfig     `toml:"rate-limit" json:"rate-limit"`
+	Heartbeat          *HeartbeatConfig     `toml:"heartbeat" json:"heartbeat"`
+}
+
+// BDREnabled returns whether the changefeed replicates in bdr mode.
+func (c *ReplicaConfig) BDREnabled() bool {
+	return c.BDR != nil && c.BDR.Enable
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -170,6 +182,36 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error {
 						minSyncPointRetention.String()))
 		}
 	}
+	for _, rule := range c.RoutingRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	if c.BDR != nil {
+		if err := c.BDR.validateAndAdjust(sinkURI, c.EnableOldValue); err != nil {
+			return err
+		}
+	}
+	for _, policy := range c.DDLPolicies {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+	}
+	if c.SchemaHistory != nil {
+		if err := c.SchemaHistory.validate(); err != nil {
+			return err
+		}
+	}
+	if c.RateLimit != nil {
+		if err := c.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Heartbeat != nil {
+		if err := c.Heartbeat.validateAndAdjust(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -16,9 +16,11 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"net/url"
 	"testing"
 	"time"
 
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -118,6 +120,142 @@ func TestReplicaConfigValidate(t *testing.T) {
 	require.Equal(t, "d1", rules[0].PartitionRule)
 	require.Equal(t, "p1", rules[1].PartitionRule)
 	require.Equal(t, "", rules[2].PartitionRule)
+
+	// Incorrect routing configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.RoutingRules = []*RoutingRule{{Matcher: []string{"a.*"}}}
+	require.Regexp(t, ".*target schema of routing rule.*is empty.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RoutingRules = []*RoutingRule{
+		{Matcher: []string{"a.*.*"}, TargetSchema: "b"},
+	}
+	require.Regexp(t, ".*matcher of routing rule.*is invalid.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RoutingRules = []*RoutingRule{
+		{Matcher: []string{"a.*"}, TargetSchema: "b", TargetTable: "{db}_{table}"},
+	}
+	require.Regexp(t, ".*unknown placeholder \\{db\\}.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RoutingRules = []*RoutingRule{
+		{Matcher: []string{"a.*"}, TargetSchema: "{schema}_b"},
+	}
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	// Incorrect bdr configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.BDR = &BDRConfig{Enable: true, ReplicaID: 1, FilterReplicaIDs: []uint64{2}}
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/topic")
+	require.Nil(t, err)
+	require.Regexp(t, ".*bdr mode is not supported by the sink scheme.*",
+		conf.ValidateAndAdjust(sinkURI))
+	conf.EnableOldValue = false
+	require.Regexp(t, ".*bdr mode requires old value.*", conf.ValidateAndAdjust(nil))
+	conf.EnableOldValue = true
+	conf.BDR.FilterReplicaIDs = []uint64{1, 2}
+	require.Regexp(t, ".*contain the replica id 1.*", conf.ValidateAndAdjust(nil))
+	conf.BDR.FilterReplicaIDs = []uint64{2}
+	conf.BDR.ConflictResolution = "first-writer-wins"
+	require.Regexp(t, ".*unknown conflict resolution.*", conf.ValidateAndAdjust(nil))
+	conf.BDR.ConflictResolution = ""
+	sinkURI, err = url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	require.Regexp(t, ".*version column of bdr mode must be specified.*",
+		conf.ValidateAndAdjust(sinkURI))
+	conf.BDR.ConflictResolution = ""
+	conf.BDR.VersionColumn = "version"
+	require.Nil(t, conf.ValidateAndAdjust(sinkURI))
+	require.Equal(t, ConflictResolutionLastWriterWins, conf.BDR.ConflictResolution)
+	require.True(t, conf.BDREnabled())
+
+	// Incorrect ddl policies.
+	conf = GetDefaultReplicaConfig()
+	conf.DDLPolicies = []*DDLPolicy{{Action: DDLPolicyActionSkip}}
+	require.Regexp(t, ".*matcher of ddl policy.*is empty.*", conf.ValidateAndAdjust(nil))
+	conf.DDLPolicies = []*DDLPolicy{{Matcher: []string{"a.*"}, Action: "ignore"}}
+	require.Regexp(t, ".*unknown action ignore.*", conf.ValidateAndAdjust(nil))
+	conf.DDLPolicies = []*DDLPolicy{
+		{Matcher: []string{"a.*"}, Action: DDLPolicyActionRewrite},
+	}
+	require.Regexp(t, ".*rewrites of ddl policy.*are empty.*", conf.ValidateAndAdjust(nil))
+	conf.DDLPolicies[0].Rewrites = []DDLRewrite{"remove-index"}
+	require.Regexp(t, ".*unknown rewrite remove-index.*", conf.ValidateAndAdjust(nil))
+	conf.DDLPolicies = []*DDLPolicy{{
+		Matcher:  []string{"a.*"},
+		Action:   DDLPolicyActionApprove,
+		Rewrites: []DDLRewrite{DDLRewriteRemovePartition},
+	}}
+	require.Regexp(t, ".*require the action rewrite.*", conf.ValidateAndAdjust(nil))
+	conf.DDLPolicies = []*DDLPolicy{
+		{
+			Matcher:  []string{"a.*"},
+			Action:   DDLPolicyActionRewrite,
+			Rewrites: []DDLRewrite{DDLRewriteRemovePartition},
+		},
+		{Matcher: []string{"*.*"}, DDLTypes: []bf.EventType{bf.DropTable}, Action: DDLPolicyActionApprove},
+	}
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	// Incorrect schema history configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.SchemaHistory = &SchemaHistoryConfig{}
+	require.Regexp(t, ".*storage of schema history is empty.*", conf.ValidateAndAdjust(nil))
+	conf.SchemaHistory.Storage = "kafka://127.0.0.1:9092/schema"
+	require.Regexp(t, ".*is not supported.*", conf.ValidateAndAdjust(nil))
+	conf.SchemaHistory.Storage = "gcs://bucket/schema-history"
+	require.Regexp(t, ".*is not supported.*", conf.ValidateAndAdjust(nil))
+	conf.SchemaHistory.Storage = "s3://bucket/schema-history"
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	// Incorrect rate limit configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.RateLimit = &RateLimitConfig{TimeZone: "Mars/Olympus"}
+	require.Regexp(t, ".*time zone Mars/Olympus of rate limit is invalid.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RateLimit = &RateLimitConfig{
+		RowsPerSecond: 1000,
+		Schedules:     []*RateLimitSchedule{{Start: "8:00", End: "25:00"}},
+	}
+	require.Regexp(t, ".*window of rate limit schedule.*is invalid.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RateLimit.Schedules = []*RateLimitSchedule{{Start: "08:00", End: "08:00"}}
+	require.Regexp(t, ".*window of rate limit schedule.*is empty.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RateLimit.Schedules = []*RateLimitSchedule{{Start: "20:00", End: "08:00"}}
+	require.Regexp(t, ".*rate limit schedule.*neither limits nor pauses.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RateLimit.Schedules = []*RateLimitSchedule{
+		{Start: "20:00", End: "08:00", RowsPerSecond: 100, Pause: true},
+	}
+	require.Regexp(t, ".*rate limit schedule.*can not both limit and pause.*",
+		conf.ValidateAndAdjust(nil))
+	conf.RateLimit.Schedules = []*RateLimitSchedule{
+		{Start: "20:00", End: "08:00", Pause: true},
+		{Start: "08:00", End: "20:00", RowsPerSecond: 100},
+	}
+	conf.RateLimit.TimeZone = "Asia/Shanghai"
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	// Incorrect heartbeat configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.Heartbeat = &HeartbeatConfig{Enable: true}
+	require.Regexp(t, ".*upstream uri of heartbeat is empty.*", conf.ValidateAndAdjust(nil))
+	conf.Heartbeat.UpstreamURI = "kafka://127.0.0.1:9092/"
+	require.Regexp(t, ".*scheme kafka of the heartbeat upstream uri is not supported.*",
+		conf.ValidateAndAdjust(nil))
+	conf.Heartbeat.UpstreamURI = "mysql://root@127.0.0.1:4000/"
+	conf.Heartbeat.Table = "heartbeat"
+	require.Regexp(t, ".*not in the format of schema.table.*", conf.ValidateAndAdjust(nil))
+	conf.Heartbeat.Table = ""
+	conf.Heartbeat.IntervalInMs = 10
+	require.Regexp(t, ".*heartbeat interval 10ms is less than 100ms.*",
+		conf.ValidateAndAdjust(nil))
+	conf.Heartbeat.IntervalInMs = 0
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+	require.Equal(t, DefaultHeartbeatTable, conf.Heartbeat.Table)
+	require.EqualValues(t, DefaultHeartbeatIntervalInMs, conf.Heartbeat.IntervalInMs)
+	schema, table := conf.Heartbeat.TableName()
+	require.Equal(t, "tidb_cdc", schema)
+	require.Equal(t, "heartbeat", table)
 }
 
 func TestValidateAndAdjust(t *testing.T) {
@@ -0,0 +1,64 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RoutingRule routes the tables matched by Matcher to the tables of the
+// downstream with other names. TargetSchema and TargetTable are expressions
+// which can contain the placeholders `{schema}` and `{table}`, they are
+// replaced with the schema and table name of the upstream table.
+// Rows and DDLs are routed by sqlmodel.Router for MySQL sinks, MQ sinks keep
+// the upstream names.
+type RoutingRule struct {
+	Matcher      []string `toml:"matcher" json:"matcher"`
+	TargetSchema string   `toml:"target-schema" json:"target-schema"`
+	// TargetTable is `{table}` if it's empty, which keeps the table name.
+	TargetTable string `toml:"target-table" json:"target-table"`
+}
+
+// routingPlaceholderRe matches the placeholders in the target expressions of
+// routing rules, only `{schema}` and `{table}` are allowed.
+var routingPlaceholderRe = regexp.MustCompile(`\{[^{}]*\}`)
+
+func (r *RoutingRule) validate() error {
+	if len(r.Matcher) == 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the matcher of routing rule %v is empty", r))
+	}
+	if _, err := filter.Parse(r.Matcher); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the matcher of routing rule %v is invalid: %s", r, err))
+	}
+	if r.TargetSchema == "" {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the target schema of routing rule %v is empty", r))
+	}
+	for _, expr := range []string{r.TargetSchema, r.TargetTable} {
+		for _, placeholder := range routingPlaceholderRe.FindAllString(expr, -1) {
+			if placeholder != "{schema}" && placeholder != "{table}" {
+				return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+					fmt.Sprintf("unknown placeholder %s in routing rule %v",
+						placeholder, r))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// SchemaHistoryConfig is the config of persisting the versions of table
+// definitions, so consumers can reconstruct the column layouts of tables
+// at any commit ts. The versions are only written to S3 or local files, they
+// are not sent to MQ sinks.
+type SchemaHistoryConfig struct {
+	// Storage is the URI of the external storage to persist the versions,
+	// such as `s3://bucket/prefix` and `file:///path`. The versions of a
+	// changefeed are written under `<namespace>/<changefeed>/`.
+	Storage string `toml:"storage" json:"storage"`
+}
+
+func (c *SchemaHistoryConfig) validate() error {
+	if c.Storage == "" {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"the storage of schema history is empty")
+	}
+	uri, err := url.Parse(c.Storage)
+	if err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the storage of schema history %s is invalid: %s", c.Storage, err))
+	}
+	switch uri.Scheme {
+	case "s3", "file", "local":
+	default:
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("the storage of schema history %s is not supported", c.Storage))
+	}
+	return nil
+}
@@ -115,6 +115,10 @@ var defaultServerConfig = &ServerConfig{
 		WorkerConcurrent: 8,
 		WorkerPoolSize:   0, // 0 will use NumCPU() * 2
 		RegionScanLimit:  40,
+		// 0 means no limit on incremental scans across tables.
+		RegionScanGlobalLimit: 0,
+		RegionScanStoreLimit:  0,
+		RegionScanBandwidth:   0,
 		// The default TiKV region election timeout is [10s, 20s],
 		// Use 1 minute to cover region leader missing.
 		RegionRetryDuration: TomlDuration(time.Minute),
@@ -148,6 +152,11 @@ var defaultServerConfig = &ServerConfig{
 		Scheduler:         NewDefaultSchedulerConfig(),
 		EnableNewSink:     true,
 	},
+	Tracing: &TracingConfig{
+		Enable:       false,
+		OTLPEndpoint: "127.0.0.1:4317",
+		SampleRate:   0.0001,
+	},
 	ClusterID: "default",
 }
 
@@ -175,6 +184,7 @@ type ServerConfig struct {
 	PerTableMemoryQuota uint64          `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
 	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
 	Debug               *DebugConfig    `toml:"debug" json:"debug"`
+	Tracing             *TracingConfig  `toml:"tracing" json:"tracing"`
 	ClusterID           string          `toml:"cluster-id" json:"cluster-id"`
 }
 
@@ -262,6 +272,18 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 			return errors.Annotate(err, "invalidate TLS config")
 		}
 	}
+	if c.Security != nil {
+		if c.Security.ClientDefaultRole != "" && !c.Security.ClientDefaultRole.IsValid() {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"unknown client default role %s", c.Security.ClientDefaultRole)
+		}
+		for identity, role := range c.Security.ClientRoles {
+			if !role.IsValid() {
+				return cerror.ErrInvalidServerOption.GenWithStack(
+					"unknown role %s of client %s", role, identity)
+			}
+		}
+	}
 
 	defaultCfg := GetDefaultServerConfig()
 	if c.Sorter == nil {
@@ -291,6 +313,13 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		return errors.Trace(err)
 	}
 
+	if c.Tracing == nil {
+		c.Tracing = defaultCfg.Tracing
+	}
+	if err = c.Tracing.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,243 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/logutil"
+	"go.uber.org/zap"
+)
+
+// reloadableServerConfigItems are the server config items that can be
+// changed without restarting the server, they're keyed by their paths in
+// the config file. The items are either read every time they're used, or
+// read when tables are added, see newTablesOnlyServerConfigItems. Some of
+// them are only reloadable with certain sorters, see
+// unifiedSorterOnlyServerConfigItems.
+// Note sorter.max-memory-percentage is not reloadable, as it's also used to
+// set up the db sorter when the server starts.
+var reloadableServerConfigItems = map[string]func(dst, src *ServerConfig){
+	"log-level": func(dst, src *ServerConfig) {
+		dst.LogLevel = src.LogLevel
+	},
+	"per-table-memory-quota": func(dst, src *ServerConfig) {
+		dst.PerTableMemoryQuota = src.PerTableMemoryQuota
+	},
+	"sorter.max-memory-consumption": func(dst, src *ServerConfig) {
+		dst.Sorter.MaxMemoryConsumption = src.Sorter.MaxMemoryConsumption
+	},
+	"sorter.num-concurrent-worker": func(dst, src *ServerConfig) {
+		dst.Sorter.NumConcurrentWorker = src.Sorter.NumConcurrentWorker
+	},
+	"kv-client.worker-concurrent": func(dst, src *ServerConfig) {
+		dst.KVClient.WorkerConcurrent = src.KVClient.WorkerConcurrent
+	},
+	"kv-client.region-scan-limit": func(dst, src *ServerConfig) {
+		dst.KVClient.RegionScanLimit = src.KVClient.RegionScanLimit
+	},
+	"kv-client.region-retry-duration": func(dst, src *ServerConfig) {
+		dst.KVClient.RegionRetryDuration = src.KVClient.RegionRetryDuration
+	},
+}
+
+// newTablesOnlyServerConfigItems are the reloadable items which are only
+// read when tables are added, so they take effect on new tables only. The
+// tables being replicated keep the old values until they're moved or the
+// changefeeds are restarted.
+var newTablesOnlyServerConfigItems = map[string]struct{}{
+	"per-table-memory-quota":          {},
+	"sorter.num-concurrent-worker":    {},
+	"kv-client.worker-concurrent":     {},
+	"kv-client.region-scan-limit":     {},
+	"kv-client.region-retry-duration": {},
+}
+
+// unifiedSorterOnlyServerConfigItems are the reloadable items which are only
+// read by the unified sorter. The db sorter, which is used by default, doesn't
+// read them at all, and switching the sorter requires a restart, so they are
+// reloadable only if the db sorter is disabled.
+var unifiedSorterOnlyServerConfigItems = map[string]struct{}{
+	"sorter.max-memory-consumption": {},
+	"sorter.num-concurrent-worker":  {},
+}
+
+// requireRestartServerConfigItems are the items which require a restart even
+// though other items of the same sections are reloadable. The region scan
+// limiter is shared by all kv clients and created only once.
+var requireRestartServerConfigItems = map[string]struct{}{
+	"kv-client.region-scan-global-limit": {},
+	"kv-client.region-scan-store-limit":  {},
+	"kv-client.region-scan-bandwidth":    {},
+}
+
+// ServerConfigReloadResult is the result of reloading the server config.
+type ServerConfigReloadResult struct {
+	// Applied are the changed items which have taken effect.
+	Applied []string `json:"applied"`
+	// AppliedToNewTables are the changed items which take effect on the
+	// tables added from now on.
+	AppliedToNewTables []string `json:"applied-to-new-tables"`
+	// RequireRestart are the changed items which are ignored until the
+	// server is restarted.
+	RequireRestart []string `json:"require-restart"`
+}
+
+// MergeReloadableServerConfig compares the next config with the current
+// one, and returns a copy of the current config with the changed reloadable
+// items taken from the next config. The next config must be validated.
+func MergeReloadableServerConfig(
+	current, next *ServerConfig,
+) (*ServerConfig, *ServerConfigReloadResult, error) {
+	next = next.Clone()
+	// The data-dir is chosen by the server if it's not specified, and the
+	// sort-dir is always derived from it.
+	if next.DataDir == "" {
+		next.DataDir = current.DataDir
+	}
+	next.Sorter.SortDir = current.Sorter.SortDir
+
+	currentItems, err := flattenServerConfig(current)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	nextItems, err := flattenServerConfig(next)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	paths := make(map[string]struct{}, len(nextItems))
+	for path := range currentItems {
+		paths[path] = struct{}{}
+	}
+	for path := range nextItems {
+		paths[path] = struct{}{}
+	}
+	changed := make([]string, 0)
+	for path := range paths {
+		if !reflect.DeepEqual(currentItems[path], nextItems[path]) {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	merged := current.Clone()
+	result := &ServerConfigReloadResult{
+		Applied:            make([]string, 0),
+		AppliedToNewTables: make([]string, 0),
+		RequireRestart:     make([]string, 0),
+	}
+	for _, path := range changed {
+		apply, ok := reloadableServerConfigItems[path]
+		if _, restart := requireRestartServerConfigItems[path]; restart {
+			ok = false
+		}
+		if _, unifiedOnly := unifiedSorterOnlyServerConfigItems[path]; unifiedOnly &&
+			current.Debug.EnableDBSorter {
+			ok = false
+		}
+		if !ok {
+			result.RequireRestart = append(result.RequireRestart, path)
+			continue
+		}
+		apply(merged, next)
+		if _, ok := newTablesOnlyServerConfigItems[path]; ok {
+			result.AppliedToNewTables = append(result.AppliedToNewTables, path)
+		} else {
+			result.Applied = append(result.Applied, path)
+		}
+	}
+	return merged, result, nil
+}
+
+// flattenServerConfig returns the leaf items of the config keyed by their
+// paths in the config file, e.g. `sorter.max-memory-percentage`.
+func flattenServerConfig(c *ServerConfig) (map[string]interface{}, error) {
+	data, err := c.Marshal()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+	var tree map[string]interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	items := make(map[string]interface{})
+	var flatten func(prefix []string, value interface{})
+	flatten = func(prefix []string, value interface{}) {
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			for k, v := range m {
+				flatten(append(prefix[:len(prefix):len(prefix)], k), v)
+			}
+			return
+		}
+		items[strings.Join(prefix, ".")] = value
+	}
+	flatten(nil, tree)
+	return items, nil
+}
+
+var serverConfigLoader struct {
+	sync.Mutex
+	load func() (*ServerConfig, error)
+}
+
+// SetServerConfigLoader sets the function to load the server config from
+// the command line and the config file again, it's used by
+// ReloadGlobalServerConfig.
+func SetServerConfigLoader(load func() (*ServerConfig, error)) {
+	serverConfigLoader.Lock()
+	defer serverConfigLoader.Unlock()
+	serverConfigLoader.load = load
+}
+
+// ReloadGlobalServerConfig loads the server config again, and applies the
+// changed reloadable items to the global server config. Other changed items
+// are reported and take effect after the server is restarted.
+func ReloadGlobalServerConfig() (*ServerConfigReloadResult, error) {
+	serverConfigLoader.Lock()
+	defer serverConfigLoader.Unlock()
+	if serverConfigLoader.load == nil {
+		return nil, cerror.ErrServerConfigReloadFailed.GenWithStackByArgs(
+			"the server config is not loaded from the command line")
+	}
+	next, err := serverConfigLoader.load()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrServerConfigReloadFailed, err, err.Error())
+	}
+	current := GetGlobalServerConfig()
+	merged, result, err := MergeReloadableServerConfig(current, next)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrServerConfigReloadFailed, err, err.Error())
+	}
+	if merged.LogLevel != current.LogLevel {
+		if err := logutil.SetLogLevel(merged.LogLevel); err != nil {
+			return nil, cerror.WrapError(cerror.ErrServerConfigReloadFailed, err, err.Error())
+		}
+	}
+	StoreGlobalServerConfig(merged)
+	log.Info("server config reloaded",
+		zap.Strings("applied", result.Applied),
+		zap.Strings("appliedToNewTables", result.AppliedToNewTables),
+		zap.Strings("requireRestart", result.RequireRestart))
+	return result, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReloadableServerConfig(t *testing.T) {
+	t.Parallel()
+
+	current := GetDefaultServerConfig()
+	current.DataDir = "/data"
+	current.Sorter.SortDir = "/data/tmp/sorter"
+
+	// The data-dir and sort-dir adjusted by the server are not changes.
+	next := GetDefaultServerConfig()
+	merged, result, err := MergeReloadableServerConfig(current, next)
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Empty(t, result.AppliedToNewTables)
+	require.Empty(t, result.RequireRestart)
+	require.Equal(t, current, merged)
+
+	next.LogLevel = "debug"
+	next.Sorter.MaxMemoryConsumption = 1024
+	next.KVClient.RegionRetryDuration = TomlDuration(2 * time.Minute)
+	next.KVClient.RegionScanGlobalLimit = 10
+	next.KVClient.RegionScanStoreLimit = 5
+	next.KVClient.RegionScanBandwidth = 1024
+	next.Addr = "127.0.0.1:8301"
+	next.Security.CertAllowedCN = []string{"client"}
+	next.Debug.Messages.ClientMaxBatchSize = 1024
+	merged, result, err = MergeReloadableServerConfig(current, next)
+	require.Nil(t, err)
+	require.Equal(t, []string{"log-level"}, result.Applied)
+	require.Equal(t, []string{"kv-client.region-retry-duration"}, result.AppliedToNewTables)
+	// The sorter items are not read by the db sorter, which is the default.
+	require.Equal(t, []string{
+		"addr",
+		"debug.messages.client-max-batch-size",
+		"kv-client.region-scan-bandwidth",
+		"kv-client.region-scan-global-limit",
+		"kv-client.region-scan-store-limit",
+		"security.cert-allowed-cn",
+		"sorter.max-memory-consumption",
+	}, result.RequireRestart)
+
+	expected := current.Clone()
+	expected.LogLevel = "debug"
+	expected.KVClient.RegionRetryDuration = TomlDuration(2 * time.Minute)
+	require.Equal(t, expected, merged)
+	// The current config is not changed.
+	require.Equal(t, "info", current.LogLevel)
+
+	// The sorter items are reloadable with the unified sorter.
+	current.Debug.EnableDBSorter = false
+	next = current.Clone()
+	next.Sorter.MaxMemoryConsumption = 1024
+	next.Sorter.NumConcurrentWorker = 2
+	merged, result, err = MergeReloadableServerConfig(current, next)
+	require.Nil(t, err)
+	require.Equal(t, []string{"sorter.max-memory-consumption"}, result.Applied)
+	require.Equal(t, []string{"sorter.num-concurrent-worker"}, result.AppliedToNewTables)
+	require.Empty(t, result.RequireRestart)
+	require.Equal(t, next, merged)
+}
+
+func TestReloadGlobalServerConfig(t *testing.T) {
+	original := GetGlobalServerConfig()
+	defer func() {
+		StoreGlobalServerConfig(original)
+		SetServerConfigLoader(nil)
+	}()
+
+	_, err := ReloadGlobalServerConfig()
+	require.Regexp(t, ".*ErrServerConfigReloadFailed.*", err)
+
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		return nil, errors.New("invalid config file")
+	})
+	_, err = ReloadGlobalServerConfig()
+	require.Regexp(t, ".*invalid config file.*", err)
+
+	current := GetDefaultServerConfig()
+	StoreGlobalServerConfig(current)
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		next := GetDefaultServerConfig()
+		next.PerTableMemoryQuota = 1024
+		next.GcTTL = 60
+		return next, nil
+	})
+	result, err := ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Equal(t, []string{"per-table-memory-quota"}, result.AppliedToNewTables)
+	require.Equal(t, []string{"gc-ttl"}, result.RequireRestart)
+	require.Equal(t, uint64(1024), GetGlobalServerConfig().PerTableMemoryQuota)
+	require.Equal(t, current.GcTTL, GetGlobalServerConfig().GcTTL)
+}
@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/stretchr/testify/require"
 )
 
@@ -82,6 +83,13 @@ func TestServerConfigValidateAndAdjust(t *testing.T) {
 	conf.Debug.Messages.ServerWorkerPoolSize = 0
 	require.Nil(t, conf.ValidateAndAdjust())
 	require.EqualValues(t, GetDefaultServerConfig().Debug.Messages.ServerWorkerPoolSize, conf.Debug.Messages.ServerWorkerPoolSize)
+	conf.Security = &SecurityConfig{ClientDefaultRole: "admin"}
+	require.Regexp(t, ".*unknown client default role admin.*", conf.ValidateAndAdjust())
+	conf.Security.ClientDefaultRole = security.RoleReadOnly
+	conf.Security.ClientRoles = map[string]security.Role{"root": "root"}
+	require.Regexp(t, ".*unknown role root of client root.*", conf.ValidateAndAdjust())
+	conf.Security.ClientRoles = map[string]security.Role{"root": security.RoleUnsafe}
+	require.Nil(t, conf.ValidateAndAdjust())
 }
 
 func TestDBConfigValidateAndAdjust(t *testing.T) {
@@ -106,6 +114,14 @@ func TestKVClientConfigValidateAndAdjust(t *testing.T) {
 	require.Nil(t, conf.ValidateAndAdjust())
 	conf.RegionRetryDuration = -TomlDuration(time.Second)
 	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().KVClient
+	conf.RegionScanStoreLimit = 16
+	conf.RegionScanGlobalLimit = 64
+	conf.RegionScanBandwidth = 64 * 1024 * 1024
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.RegionScanStoreLimit = -1
+	require.Error(t, conf.ValidateAndAdjust())
 }
 
 func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
@@ -157,3 +173,19 @@ func TestIsValidClusterID(t *testing.T) {
 		require.Equal(t, c.valid, isValidClusterID(c.id))
 	}
 }
+
+func TestTracingConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Tracing
+	require.Nil(t, conf.ValidateAndAdjust())
+
+	conf.Enable = true
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.SampleRate = 0
+	require.Regexp(t, ".*sample-rate should be in.*", conf.ValidateAndAdjust())
+	conf.SampleRate = 1.5
+	require.Regexp(t, ".*sample-rate should be in.*", conf.ValidateAndAdjust())
+	conf.SampleRate = 1
+	conf.OTLPEndpoint = ""
+	require.Regexp(t, ".*otlp-endpoint should not be empty.*", conf.ValidateAndAdjust())
+}
@@ -16,6 +16,7 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -68,6 +69,14 @@ type SinkConfig struct {
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors"`
 	SchemaRegistry  string            `toml:"schema-registry" json:"schema-registry"`
 	TxnAtomicity    AtomicityLevel    `toml:"transaction-atomicity" json:"transaction-atomicity"`
+
+	// ComputedColumns appends columns derived from expressions to the rows
+	// of the matched tables. It's only supported by MQ sinks.
+	ComputedColumns []*ComputedColumnRule `toml:"computed-columns" json:"computed-columns"`
+
+	// LargeMessageHandle decides how to handle the rows exceeding
+	// `max-message-bytes` of MQ sinks.
+	LargeMessageHandle *LargeMessageHandleConfig `toml:"large-message-handle" json:"large-message-handle"`
 }
 
 // DispatchRule represents partition rule for a table.
@@ -79,6 +88,49 @@ type DispatchRule struct {
 	// In the future release, the DispatcherRule is expected to be removed .
 	PartitionRule string `toml:"partition" json:"partition"`
 	TopicRule     string `toml:"topic" json:"topic"`
+	// Columns are the columns to dispatch rows by if PartitionRule is `columns`.
+	Columns []string `toml:"columns" json:"columns,omitempty"`
+	// HashFunction is the hash function of the `columns` partition rule, it can
+	// be `murmur2`, which is compatible with the Java Kafka client, or `crc32`.
+	HashFunction string `toml:"hash-function" json:"hash-function,omitempty"`
+}
+
+// PartitionRuleColumns dispatches rows by the values of the specified columns.
+const PartitionRuleColumns = "columns"
+
+const (
+	// HashFunctionMurmur2 is the murmur2 hash function used by the default
+	// partitioner of the Java Kafka client.
+	HashFunctionMurmur2 = "murmur2"
+	// HashFunctionCRC32 is the IEEE CRC-32 hash function.
+	HashFunctionCRC32 = "crc32"
+)
+
+func (r *DispatchRule) validateColumns() error {
+	if !strings.EqualFold(r.PartitionRule, PartitionRuleColumns) {
+		if len(r.Columns) != 0 || r.HashFunction != "" {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New(fmt.Sprintf("columns and hash-function can only be "+
+					"configured with the columns partition rule:%v", r)))
+		}
+		return nil
+	}
+	if len(r.Columns) == 0 {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New(fmt.Sprintf("columns must be configured with the "+
+				"columns partition rule:%v", r)))
+	}
+	r.HashFunction = strings.ToLower(r.HashFunction)
+	switch r.HashFunction {
+	case "":
+		r.HashFunction = HashFunctionMurmur2
+	case HashFunctionMurmur2, HashFunctionCRC32:
+	default:
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New(fmt.Sprintf("hash function %s is not supported, "+
+				"it should be murmur2 or crc32", r.HashFunction)))
+	}
+	return nil
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -87,6 +139,20 @@ type ColumnSelector struct {
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+// ComputedColumnRule represents the computed columns of tables.
+type ComputedColumnRule struct {
+	Matcher []string          `toml:"matcher" json:"matcher"`
+	Columns []*ComputedColumn `toml:"columns" json:"columns"`
+}
+
+// ComputedColumn is a column derived from an expression on the other columns
+// of the row, such as `substr(phone, 1, 3)`. Its type is inferred from the
+// expression.
+type ComputedColumn struct {
+	Name       string `toml:"name" json:"name"`
+	Expression string `toml:"expression" json:"expression"`
+}
+
 func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) error {
 	if err := s.applyParameter(sinkURI); err != nil {
 		return err
@@ -116,6 +182,41 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 			rule.PartitionRule = rule.DispatcherRule
 			rule.DispatcherRule = ""
 		}
+		if err := rule.validateColumns(); err != nil {
+			return err
+		}
+	}
+	// The computed columns don't exist in the downstream tables of MySQL
+	// compatible sinks, so they are only supported by MQ sinks.
+	if len(s.ComputedColumns) != 0 && sinkURI != nil && !sink.IsMQScheme(sinkURI.Scheme) {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New(fmt.Sprintf("computed columns are only supported by MQ sinks, "+
+				"but the sink scheme is %s", sinkURI.Scheme)))
+	}
+	for _, rule := range s.ComputedColumns {
+		if len(rule.Matcher) == 0 {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New(fmt.Sprintf("matcher of computed columns %v is empty", rule)))
+		}
+		names := make(map[string]struct{}, len(rule.Columns))
+		for _, col := range rule.Columns {
+			if col.Name == "" || col.Expression == "" {
+				return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+					errors.New(fmt.Sprintf("name and expression of computed "+
+						"column %v must be specified", col)))
+			}
+			name := strings.ToLower(col.Name)
+			if _, ok := names[name]; ok {
+				return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+					errors.New(fmt.Sprintf("computed column %s is duplicated", col.Name)))
+			}
+			names[name] = struct{}{}
+		}
+	}
+	if s.LargeMessageHandle != nil {
+		if err := s.LargeMessageHandle.validate(s.Protocol); err != nil {
+			return err
+		}
 	}
 
 	return nil
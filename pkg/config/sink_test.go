@@ -81,6 +81,63 @@ func TestValidateOldValue(t *testing.T) {
 	}
 }
 
+func TestValidateComputedColumns(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{ComputedColumns: []*ComputedColumnRule{{
+		Columns: []*ComputedColumn{{Name: "region", Expression: "substr(phone, 1, 3)"}},
+	}}}
+	require.Regexp(t, ".*matcher of computed columns.*is empty.*",
+		cfg.validateAndAdjust(nil, true))
+
+	cfg.ComputedColumns[0].Matcher = []string{"test.*"}
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+
+	// Computed columns are only supported by MQ sinks.
+	sinkURI, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	require.Regexp(t, ".*computed columns are only supported by MQ sinks.*",
+		cfg.validateAndAdjust(sinkURI, true))
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/test?protocol=canal-json")
+	require.Nil(t, err)
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.ComputedColumns[0].Columns = append(cfg.ComputedColumns[0].Columns,
+		&ComputedColumn{Name: "updated_day"})
+	require.Regexp(t, ".*name and expression of computed column.*must be specified.*",
+		cfg.validateAndAdjust(nil, true))
+
+	cfg.ComputedColumns[0].Columns[1] = &ComputedColumn{Name: "Region", Expression: "1"}
+	require.Regexp(t, ".*computed column Region is duplicated.*",
+		cfg.validateAndAdjust(nil, true))
+}
+
+func TestValidateColumnsDispatchRule(t *testing.T) {
+	t.Parallel()
+
+	rule := &DispatchRule{Matcher: []string{"test.*"}, PartitionRule: "columns"}
+	cfg := SinkConfig{DispatchRules: []*DispatchRule{rule}}
+	require.Regexp(t, ".*columns must be configured with the columns partition rule.*",
+		cfg.validateAndAdjust(nil, true))
+
+	rule.Columns = []string{"tenant_id"}
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+	require.Equal(t, HashFunctionMurmur2, rule.HashFunction)
+
+	rule.HashFunction = "CRC32"
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+	require.Equal(t, HashFunctionCRC32, rule.HashFunction)
+
+	rule.HashFunction = "md5"
+	require.Regexp(t, ".*hash function md5 is not supported.*",
+		cfg.validateAndAdjust(nil, true))
+
+	rule.PartitionRule = "table"
+	rule.HashFunction = ""
+	require.Regexp(t, ".*columns and hash-function can only be configured.*",
+		cfg.validateAndAdjust(nil, true))
+}
+
 func TestValidateApplyParameter(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -194,3 +251,43 @@ func TestApplyParameter(t *testing.T) {
 		require.Equal(t, c.result, c.sinkConfig.Protocol)
 	}
 }
+
+func TestValidateLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "avro",
+		LargeMessageHandle: &LargeMessageHandleConfig{
+			LargeMessageHandleOption: LargeMessageHandleOptionNone,
+		},
+	}
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+
+	cfg.LargeMessageHandle.LargeMessageHandleOption = LargeMessageHandleOptionHandleKeyOnly
+	require.Regexp(t, ".*not supported by protocol avro.*",
+		cfg.validateAndAdjust(nil, true))
+
+	cfg.Protocol = "canal-json"
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+
+	cfg.Protocol = "open-protocol"
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+	require.True(t, cfg.LargeMessageHandle.Enabled())
+	require.False(t, cfg.LargeMessageHandle.EnableClaimCheck())
+
+	cfg.LargeMessageHandle.ClaimCheckStorageURI = "file:///tmp/claim-check"
+	require.Regexp(t, ".*claim-check-storage-uri can only be configured.*",
+		cfg.validateAndAdjust(nil, true))
+
+	cfg.LargeMessageHandle.LargeMessageHandleOption = LargeMessageHandleOptionClaimCheck
+	require.Nil(t, cfg.validateAndAdjust(nil, true))
+	require.True(t, cfg.LargeMessageHandle.EnableClaimCheck())
+
+	cfg.LargeMessageHandle.ClaimCheckStorageURI = ""
+	require.Regexp(t, ".*claim-check-storage-uri must be configured.*",
+		cfg.validateAndAdjust(nil, true))
+
+	cfg.LargeMessageHandle.LargeMessageHandleOption = "truncate"
+	require.Regexp(t, ".*large message handle option truncate is not supported.*",
+		cfg.validateAndAdjust(nil, true))
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/pingcap/tiflow/pkg/errors"
+
+// TracingConfig represents config for tracing row events through the
+// replication pipeline with OpenTelemetry.
+type TracingConfig struct {
+	// Enable enables tracing.
+	Enable bool `toml:"enable" json:"enable"`
+	// OTLPEndpoint is the address of the OTLP gRPC collector spans exported to.
+	OTLPEndpoint string `toml:"otlp-endpoint" json:"otlp-endpoint"`
+	// SampleRate is the fraction of transactions to be traced, transactions
+	// are sampled by their commit ts.
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+}
+
+// ValidateAndAdjust validates and adjusts the tracing configuration
+func (c *TracingConfig) ValidateAndAdjust() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.OTLPEndpoint == "" {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"tracing.otlp-endpoint should not be empty when tracing is enabled")
+	}
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"tracing.sample-rate should be in (0, 1]")
+	}
+	return nil
+}
@@ -1067,6 +1067,14 @@ var (
 		"cdc server is not ready",
 		errors.RFCCodeText("CDC:ErrServerIsNotReady"),
 	)
+	ErrUnauthenticated = errors.Normalize(
+		"client is not authenticated, %s",
+		errors.RFCCodeText("CDC:ErrUnauthenticated"),
+	)
+	ErrUnauthorized = errors.Normalize(
+		"client %s with role %s is not authorized to %s %s",
+		errors.RFCCodeText("CDC:ErrUnauthorized"),
+	)
 
 	// cli error
 	ErrCliInvalidCheckpointTs = errors.Normalize(
@@ -1107,12 +1115,82 @@ var (
 		"failed to convert ddl '%s' to filter event type",
 		errors.RFCCodeText("CDC:ErrConvertDDLToEventTypeFailed"),
 	)
+	ErrRewriteDDLFailed = errors.Normalize(
+		"failed to rewrite ddl '%s'",
+		errors.RFCCodeText("CDC:ErrRewriteDDLFailed"),
+	)
 	ErrSyncRenameTableFailed = errors.Normalize(
 		"table's old name is not in filter rule, and its new name in filter rule "+
 			"table id '%d', ddl query: [%s], it's an unexpected behavior, "+
 			"if you want to replicate this table, please add its old name to filter rule.",
 		errors.RFCCodeText("CDC:ErrSyncRenameTableFailed"),
 	)
+	ErrTableSetChangeMissed = errors.Normalize(
+		"table set change at %d is missed, ddl jobs before %d have been garbage collected",
+		errors.RFCCodeText("CDC:ErrTableSetChangeMissed"),
+	)
+	ErrRouteDDLFailed = errors.Normalize(
+		"failed to route ddl '%s'",
+		errors.RFCCodeText("CDC:ErrRouteDDLFailed"),
+	)
+	ErrBDRMarkTableNotFound = errors.Normalize(
+		"mark table %s.%s is not found in the upstream, "+
+			"it's created once the changefeed replicating to the upstream starts",
+		errors.RFCCodeText("CDC:ErrBDRMarkTableNotFound"),
+	)
+	ErrBDRMarkUnresolved = errors.Normalize(
+		"can not tell whether the transaction is written by TiCDC, "+
+			"the specified ts(%d) is more than resolvedTs(%d) of mark tables",
+		errors.RFCCodeText("CDC:ErrBDRMarkUnresolved"),
+	)
+	ErrTableNotFoundAtTs = errors.Normalize(
+		"table %s.%s is not found at ts %d",
+		errors.RFCCodeText("CDC:ErrTableNotFoundAtTs"),
+	)
+	ErrSchemaHistoryStorage = errors.Normalize(
+		"schema history storage api",
+		errors.RFCCodeText("CDC:ErrSchemaHistoryStorage"),
+	)
+	ErrComputedColumnInvalid = errors.Normalize(
+		"invalid computed column %s of table %s: %s",
+		errors.RFCCodeText("CDC:ErrComputedColumnInvalid"),
+	)
+	ErrDispatcherColumnNotFound = errors.Normalize(
+		"column %s of the columns partition rule is not found in table %s",
+		errors.RFCCodeText("CDC:ErrDispatcherColumnNotFound"),
+	)
+	ErrClaimCheckStorage = errors.Normalize(
+		"claim-check storage api",
+		errors.RFCCodeText("CDC:ErrClaimCheckStorage"),
+	)
+	ErrCompressedMessageTooLarge = errors.Normalize(
+		"compressed message too large, length %d, max-message-bytes %d",
+		errors.RFCCodeText("CDC:ErrCompressedMessageTooLarge"),
+	)
+	ErrCompressionFailed = errors.Normalize(
+		"compression failed",
+		errors.RFCCodeText("CDC:ErrCompressionFailed"),
+	)
+	ErrDecompressionFailed = errors.Normalize(
+		"decompression failed",
+		errors.RFCCodeText("CDC:ErrDecompressionFailed"),
+	)
+	ErrMetaSnapshotInvalid = errors.Normalize(
+		"invalid metadata snapshot: %s",
+		errors.RFCCodeText("CDC:ErrMetaSnapshotInvalid"),
+	)
+	ErrMetaRestoreRefused = errors.Normalize(
+		"refuse to restore metadata: %s",
+		errors.RFCCodeText("CDC:ErrMetaRestoreRefused"),
+	)
+	ErrColumnSelectorFailed = errors.Normalize(
+		"column selector failed: %s",
+		errors.RFCCodeText("CDC:ErrColumnSelectorFailed"),
+	)
+	ErrServerConfigReloadFailed = errors.Normalize(
+		"reload server config failed: %s",
+		errors.RFCCodeText("CDC:ErrServerConfigReloadFailed"),
+	)
 
 	// changefeed config error
 	ErrInvalidReplicaConfig = errors.Normalize(
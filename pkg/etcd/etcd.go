@@ -71,6 +71,26 @@ func GetEtcdKeyChangeFeedInfo(clusterID string, changefeedID model.ChangeFeedID)
 		changefeedID.Namespace), changefeedID.ID)
 }
 
+// GetEtcdKeyChangefeedEvents returns the key prefix of the events of a changefeed
+func GetEtcdKeyChangefeedEvents(clusterID string, changefeedID model.ChangeFeedID) string {
+	return NamespacedPrefix(clusterID, changefeedID.Namespace) +
+		ChangefeedEventsKey + "/" + changefeedID.ID + "/"
+}
+
+// GetEtcdKeyChangefeedEvent returns the key of an event of a changefeed
+func GetEtcdKeyChangefeedEvent(clusterID string,
+	changefeedID model.ChangeFeedID,
+	eventID string,
+) string {
+	return GetEtcdKeyChangefeedEvents(clusterID, changefeedID) + eventID
+}
+
+// ChangefeedEventID returns the ID of an event happened at the given unix
+// time in nanoseconds. IDs are zero padded so that they sort in time order.
+func ChangefeedEventID(unixNano int64) string {
+	return fmt.Sprintf("%020d", unixNano)
+}
+
 // GetEtcdKeyTaskPosition returns the key of a task position
 func GetEtcdKeyTaskPosition(clusterID string,
 	changefeedID model.ChangeFeedID,
@@ -129,6 +149,10 @@ type CDCEtcdClient interface {
 		namespace string,
 	) (*model.UpstreamInfo, error)
 
+	GetChangefeedEvents(ctx context.Context,
+		id model.ChangeFeedID,
+	) (*model.ChangefeedEventLog, error)
+
 	GetGCServiceID() string
 
 	GetEnsureGCServiceID(tag string) string
@@ -473,6 +497,12 @@ func (c *CDCEtcdClientImpl) CreateChangefeedInfo(ctx context.Context,
 	if err != nil {
 		return errors.Trace(err)
 	}
+	event := model.NewChangefeedEvent(model.ChangefeedEventCreated,
+		fmt.Sprintf("the changefeed is created with start ts %d", info.StartTs))
+	eventData, err := event.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	cmps := []clientv3.Cmp{
 		clientv3.Compare(clientv3.ModRevision(infoKey), "=", 0),
 		clientv3.Compare(clientv3.ModRevision(jobKey), "=", 0),
@@ -480,6 +510,8 @@ func (c *CDCEtcdClientImpl) CreateChangefeedInfo(ctx context.Context,
 	opsThen := []clientv3.Op{
 		clientv3.OpPut(infoKey, value),
 		clientv3.OpPut(upstreamEtcdKeyStr, string(upstreamData)),
+		clientv3.OpPut(GetEtcdKeyChangefeedEvent(c.ClusterID, changeFeedID,
+			ChangefeedEventID(event.Time.UnixNano())), eventData),
 	}
 	if len(upstreamResp.Kvs) == 0 {
 		cmps = append(cmps,
@@ -526,9 +558,17 @@ func (c *CDCEtcdClientImpl) UpdateChangefeedAndUpstream(ctx context.Context,
 	if err != nil {
 		return errors.Trace(err)
 	}
+	event := model.NewChangefeedEvent(model.ChangefeedEventUpdated,
+		"the changefeed is updated")
+	eventStr, err := event.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	opsThen := []clientv3.Op{
 		clientv3.OpPut(infoKey, changeFeedInfoStr),
 		clientv3.OpPut(upstreamKeyStr, string(upstreamInfoStr)),
+		clientv3.OpPut(GetEtcdKeyChangefeedEvent(c.ClusterID, changeFeedID,
+			ChangefeedEventID(event.Time.UnixNano())), eventStr),
 	}
 
 	resp, err := c.Client.Txn(ctx, txnEmptyCmps, opsThen, TxnEmptyOpsElse)
@@ -544,6 +584,32 @@ func (c *CDCEtcdClientImpl) UpdateChangefeedAndUpstream(ctx context.Context,
 	return nil
 }
 
+// GetChangefeedEvents queries the events of a changefeed, events are in the
+// order they happened, and only the latest MaxChangefeedEvents are returned.
+func (c *CDCEtcdClientImpl) GetChangefeedEvents(ctx context.Context,
+	id model.ChangeFeedID,
+) (*model.ChangefeedEventLog, error) {
+	key := GetEtcdKeyChangefeedEvents(c.ClusterID, id)
+	resp, err := c.Client.Get(ctx, key, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend),
+		clientv3.WithLimit(model.MaxChangefeedEvents))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	events := &model.ChangefeedEventLog{
+		Events: make([]*model.ChangefeedEvent, len(resp.Kvs)),
+	}
+	// The keys are sorted in descending order to get the latest events.
+	for i, kv := range resp.Kvs {
+		event := &model.ChangefeedEvent{}
+		if err := event.Unmarshal(kv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		events.Events[len(resp.Kvs)-1-i] = event
+	}
+	return events, nil
+}
+
 // SaveChangeFeedInfo stores change feed info into etcd
 // TODO: this should be called from outer system, such as from a TiDB client
 func (c *CDCEtcdClientImpl) SaveChangeFeedInfo(ctx context.Context,
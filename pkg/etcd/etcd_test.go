@@ -260,6 +260,11 @@ func TestCreateChangefeed(t *testing.T) {
 	err = s.client.CreateChangefeedInfo(ctx,
 		upstreamInfo, detail, model.DefaultChangeFeedID("test-id"))
 	require.True(t, cerror.ErrChangeFeedAlreadyExists.Equal(err))
+
+	events, err := s.client.GetChangefeedEvents(ctx, model.DefaultChangeFeedID("test-id"))
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	require.Equal(t, model.ChangefeedEventCreated, events.Events[0].Type)
 }
 
 func TestUpdateChangefeedAndUpstream(t *testing.T) {
@@ -292,6 +297,50 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	changefeedResult, err = s.client.GetChangeFeedInfo(ctx, changeFeedID)
 	require.NoError(t, err)
 	require.Equal(t, changeFeedInfo.SinkURI, changefeedResult.SinkURI)
+
+	err = s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	require.NoError(t, err)
+	events, err := s.client.GetChangefeedEvents(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 2)
+	for _, event := range events.Events {
+		require.Equal(t, model.ChangefeedEventUpdated, event.Type)
+	}
+}
+
+func TestGetChangefeedEvents(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	id := model.DefaultChangeFeedID("test-events")
+	// An event of another changefeed whose ID has the same prefix.
+	_, err := s.client.Client.Put(ctx, GetEtcdKeyChangefeedEvent(s.client.ClusterID,
+		model.DefaultChangeFeedID("test-events-1"), ChangefeedEventID(0)), "{}")
+	require.NoError(t, err)
+	events, err := s.client.GetChangefeedEvents(ctx, id)
+	require.NoError(t, err)
+	require.Empty(t, events.Events)
+
+	// Only the latest events are returned, in the order they happened.
+	for i := 0; i <= model.MaxChangefeedEvents; i++ {
+		event := &model.ChangefeedEvent{
+			Type:    model.ChangefeedEventDDLApplied,
+			Message: fmt.Sprintf("%d", i),
+		}
+		value, err := event.Marshal()
+		require.NoError(t, err)
+		_, err = s.client.Client.Put(ctx, GetEtcdKeyChangefeedEvent(s.client.ClusterID,
+			id, ChangefeedEventID(int64(i))), value)
+		require.NoError(t, err)
+	}
+	events, err = s.client.GetChangefeedEvents(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, events.Events, model.MaxChangefeedEvents)
+	require.Equal(t, "1", events.Events[0].Message)
+	require.Equal(t, fmt.Sprintf("%d", model.MaxChangefeedEvents),
+		events.Events[model.MaxChangefeedEvents-1].Message)
 }
 
 func TestGetAllCaptureLeases(t *testing.T) {
@@ -34,6 +34,9 @@ const (
 	ChangefeedInfoKey = "/changefeed/info"
 	// ChangefeedStatusKey is the key path for changefeed status
 	ChangefeedStatusKey = "/changefeed/status"
+	// ChangefeedEventsKey is the key path for changefeed events, every event
+	// is stored in its own key under the changefeed
+	ChangefeedEventsKey = "/changefeed/events"
 	// metaVersionKey is the key path for metadata version
 	metaVersionKey = "/meta/meta-version"
 	upstreamKey    = "/upstream"
@@ -63,6 +66,7 @@ const (
 	CDCKeyTypeTaskPosition
 	CDCKeyTypeMetaVersion
 	CDCKeyTypeUpStream
+	CDCKeyTypeChangefeedEvent
 )
 
 // CDCKey represents an etcd key which is defined by TiCDC
@@ -97,6 +101,7 @@ type CDCKey struct {
 	ClusterID    string
 	UpstreamID   model.UpstreamID
 	Namespace    string
+	EventID      string
 }
 
 // BaseKey is the common prefix of the keys with cluster id in CDC
@@ -167,6 +172,19 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 				ID:        key[len(ChangefeedStatusKey)+1:],
 			}
 			k.OwnerLeaseID = ""
+		case strings.HasPrefix(key, ChangefeedEventsKey):
+			splitKey := strings.SplitN(key[len(ChangefeedEventsKey)+1:], "/", 2)
+			if len(splitKey) != 2 {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			k.Tp = CDCKeyTypeChangefeedEvent
+			k.CaptureID = ""
+			k.ChangefeedID = model.ChangeFeedID{
+				Namespace: namespace,
+				ID:        splitKey[0],
+			}
+			k.EventID = splitKey[1]
+			k.OwnerLeaseID = ""
 		case strings.HasPrefix(key, taskPositionKey):
 			splitKey := strings.SplitN(key[len(taskPositionKey)+1:], "/", 2)
 			if len(splitKey) != 2 {
@@ -201,6 +219,9 @@ func (k *CDCKey) String() string {
 	case CDCKeyTypeChangeFeedStatus:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedStatusKey +
 			"/" + k.ChangefeedID.ID
+	case CDCKeyTypeChangefeedEvent:
+		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedEventsKey +
+			"/" + k.ChangefeedID.ID + "/" + k.EventID
 	case CDCKeyTypeTaskPosition:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + taskPositionKey +
 			"/" + k.CaptureID + "/" + k.ChangefeedID.ID
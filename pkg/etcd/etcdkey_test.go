@@ -56,6 +56,16 @@ func TestEtcdKey(t *testing.T) {
 			ClusterID:    DefaultCDCClusterID,
 			Namespace:    model.DefaultNamespace,
 		},
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/events/test-changefeed/01666170000000000000",
+		expected: &CDCKey{
+			Tp:           CDCKeyTypeChangefeedEvent,
+			ChangefeedID: model.DefaultChangeFeedID("test-changefeed"),
+			ClusterID:    DefaultCDCClusterID,
+			Namespace:    model.DefaultNamespace,
+			EventID:      "01666170000000000000",
+		},
 	}, {
 		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
 			"/changefeed/info/test/changefeed",
@@ -137,6 +147,10 @@ func TestEtcdKeyParseError(t *testing.T) {
 		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
 			"/task/position/6bbc01c8-0605-4f86-a0f9-b3119109b225",
 		error: true,
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/events/test-changefeed",
+		error: true,
 	}, {
 		key:   "/tidb/cd",
 		error: true,
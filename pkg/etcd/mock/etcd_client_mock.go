@@ -157,6 +157,21 @@ func (mr *MockCDCEtcdClientMockRecorder) GetChangeFeedStatus(ctx, id interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedStatus", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetChangeFeedStatus), ctx, id)
 }
 
+// GetChangefeedEvents mocks base method.
+func (m *MockCDCEtcdClient) GetChangefeedEvents(ctx context.Context, id model.ChangeFeedID) (*model.ChangefeedEventLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangefeedEvents", ctx, id)
+	ret0, _ := ret[0].(*model.ChangefeedEventLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangefeedEvents indicates an expected call of GetChangefeedEvents.
+func (mr *MockCDCEtcdClientMockRecorder) GetChangefeedEvents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangefeedEvents", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetChangefeedEvents), ctx, id)
+}
+
 // GetClusterID mocks base method.
 func (m *MockCDCEtcdClient) GetClusterID() string {
 	m.ctrl.T.Helper()
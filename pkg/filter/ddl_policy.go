@@ -0,0 +1,209 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strings"
+
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// partitionSpecTypes are the types of `ALTER TABLE` clauses removed by
+// config.DDLRewriteRemovePartition.
+var partitionSpecTypes = map[ast.AlterTableType]struct{}{
+	ast.AlterTableAddPartitions:       {},
+	ast.AlterTableCoalescePartitions:  {},
+	ast.AlterTableDropPartition:       {},
+	ast.AlterTableTruncatePartition:   {},
+	ast.AlterTablePartition:           {},
+	ast.AlterTableRemovePartitioning:  {},
+	ast.AlterTableRebuildPartition:    {},
+	ast.AlterTableReorganizePartition: {},
+	ast.AlterTableExchangePartition:   {},
+}
+
+type ddlPolicyRule struct {
+	tf tfilter.Filter
+	// ddlTypes is empty if the rule matches all DDLs.
+	ddlTypes map[bf.EventType]struct{}
+	action   config.DDLPolicyAction
+	rewrites []config.DDLRewrite
+}
+
+func (r *ddlPolicyRule) match(ddl *model.DDLEvent, et bf.EventType) bool {
+	if ddl.TableInfo == nil {
+		return false
+	}
+	if len(ddl.TableInfo.Table) == 0 {
+		if !r.tf.MatchSchema(ddl.TableInfo.Schema) {
+			return false
+		}
+	} else if !r.tf.MatchTable(ddl.TableInfo.Schema, ddl.TableInfo.Table) {
+		return false
+	}
+	if len(r.ddlTypes) == 0 {
+		return true
+	}
+	_, ok := r.ddlTypes[et]
+	return ok
+}
+
+// DDLPolicy decides how DDLs are replicated by the DDL policies of a
+// changefeed. It's not thread-safe.
+type DDLPolicy struct {
+	p     *parser.Parser
+	rules []*ddlPolicyRule
+}
+
+// NewDDLPolicy creates a DDLPolicy from the DDL policies of the config.
+func NewDDLPolicy(cfg *config.ReplicaConfig) (*DDLPolicy, error) {
+	res := &DDLPolicy{
+		p:     parser.New(),
+		rules: make([]*ddlPolicyRule, 0, len(cfg.DDLPolicies)),
+	}
+	for _, policy := range cfg.DDLPolicies {
+		tf, err := tfilter.Parse(policy.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, policy.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		if err := verifyIgnoreEvents(policy.DDLTypes); err != nil {
+			return nil, err
+		}
+		rule := &ddlPolicyRule{
+			tf:       tf,
+			ddlTypes: make(map[bf.EventType]struct{}, len(policy.DDLTypes)),
+			action:   policy.Action,
+			rewrites: policy.Rewrites,
+		}
+		for _, et := range policy.DDLTypes {
+			if et == bf.AllDDL {
+				// The rule matches all DDLs.
+				rule.ddlTypes = nil
+				break
+			}
+			rule.ddlTypes[et] = struct{}{}
+		}
+		res.rules = append(res.rules, rule)
+	}
+	return res, nil
+}
+
+// Apply returns the action taken on the DDL by the first policy matching it,
+// an empty action is returned if no policies match the DDL. The query of the
+// DDL is rewritten in place if the action is rewrite, and the skip action is
+// returned instead if nothing is left after the rewrite.
+func (p *DDLPolicy) Apply(ddl *model.DDLEvent) (config.DDLPolicyAction, error) {
+	if len(p.rules) == 0 {
+		return "", nil
+	}
+	et, err := ddlToEventType(p.p, ddl.Query, ddl.Type)
+	if err != nil {
+		return "", err
+	}
+	for _, rule := range p.rules {
+		if !rule.match(ddl, et) {
+			continue
+		}
+		if rule.action != config.DDLPolicyActionRewrite {
+			return rule.action, nil
+		}
+		query, ok, err := p.rewrite(ddl.Query, rule.rewrites)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return config.DDLPolicyActionSkip, nil
+		}
+		ddl.Query = query
+		return config.DDLPolicyActionRewrite, nil
+	}
+	return "", nil
+}
+
+// rewrite returns the rewritten query, false is returned if nothing is left
+// after the rewrite.
+func (p *DDLPolicy) rewrite(
+	query string, rewrites []config.DDLRewrite,
+) (string, bool, error) {
+	stmt, err := p.p.ParseOneStmt(query, "", "")
+	if err != nil {
+		return "", false, cerror.WrapError(cerror.ErrRewriteDDLFailed, err, query)
+	}
+	rewritten := false
+	for _, rewrite := range rewrites {
+		switch rewrite {
+		case config.DDLRewriteRemovePartition:
+			switch s := stmt.(type) {
+			case *ast.CreateTableStmt:
+				if s.Partition != nil {
+					s.Partition = nil
+					rewritten = true
+				}
+			case *ast.AlterTableStmt:
+				rewritten = removeAlterTableSpecs(s, func(tp ast.AlterTableType) bool {
+					_, ok := partitionSpecTypes[tp]
+					return ok
+				}) || rewritten
+			}
+		case config.DDLRewriteRemoveTiFlashReplica:
+			if s, ok := stmt.(*ast.AlterTableStmt); ok {
+				rewritten = removeAlterTableSpecs(s, func(tp ast.AlterTableType) bool {
+					return tp == ast.AlterTableSetTiFlashReplica
+				}) || rewritten
+			}
+		}
+	}
+	if !rewritten {
+		return query, true, nil
+	}
+	if s, ok := stmt.(*ast.AlterTableStmt); ok && len(s.Specs) == 0 {
+		return "", false, nil
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes
+	if err := stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", false, cerror.WrapError(cerror.ErrRewriteDDLFailed, err, query)
+	}
+	return sb.String(), true, nil
+}
+
+// removeAlterTableSpecs removes the clauses of the `ALTER TABLE` statement
+// whose types are matched, it returns true if any clause is removed.
+func removeAlterTableSpecs(
+	stmt *ast.AlterTableStmt, match func(tp ast.AlterTableType) bool,
+) bool {
+	specs := make([]*ast.AlterTableSpec, 0, len(stmt.Specs))
+	for _, spec := range stmt.Specs {
+		if !match(spec.Tp) {
+			specs = append(specs, spec)
+		}
+	}
+	removed := len(specs) != len(stmt.Specs)
+	stmt.Specs = specs
+	return removed
+}
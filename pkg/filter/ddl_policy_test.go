@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.DDLPolicies = []*config.DDLPolicy{
+		{
+			Matcher:  []string{"test.*"},
+			DDLTypes: []bf.EventType{bf.DropTable, bf.TruncateTable},
+			Action:   config.DDLPolicyActionApprove,
+		},
+		{
+			Matcher: []string{"test.*"},
+			Action:  config.DDLPolicyActionRewrite,
+			Rewrites: []config.DDLRewrite{
+				config.DDLRewriteRemovePartition,
+				config.DDLRewriteRemoveTiFlashReplica,
+			},
+		},
+		{
+			Matcher:  []string{"log.*"},
+			DDLTypes: []bf.EventType{bf.AllDDL},
+			Action:   config.DDLPolicyActionSkip,
+		},
+	}
+	policy, err := NewDDLPolicy(cfg)
+	require.Nil(t, err)
+
+	cases := []struct {
+		schema  string
+		table   string
+		tp      timodel.ActionType
+		query   string
+		action  config.DDLPolicyAction
+		rewrite string
+	}{
+		{
+			schema: "test", table: "t1", tp: timodel.ActionDropTable,
+			query: "drop table t1", action: config.DDLPolicyActionApprove,
+		},
+		{
+			schema: "test", table: "t1", tp: timodel.ActionTruncateTable,
+			query: "truncate table t1", action: config.DDLPolicyActionApprove,
+		},
+		{
+			schema: "test", table: "t1", tp: timodel.ActionCreateTable,
+			query:   "create table t1 (a int) partition by hash(a) partitions 4",
+			action:  config.DDLPolicyActionRewrite,
+			rewrite: "CREATE TABLE `t1` (`a` INT)",
+		},
+		{
+			schema: "test", table: "t1", tp: timodel.ActionAddColumn,
+			query:   "alter table t1 add column b int",
+			action:  config.DDLPolicyActionRewrite,
+			rewrite: "alter table t1 add column b int",
+		},
+		{
+			schema: "test", table: "t1", tp: timodel.ActionAddTablePartition,
+			query:  "alter table t1 add partition partitions 2",
+			action: config.DDLPolicyActionSkip,
+		},
+		{
+			schema: "test", table: "t1", tp: timodel.ActionSetTiFlashReplica,
+			query:  "alter table t1 set tiflash replica 1",
+			action: config.DDLPolicyActionSkip,
+		},
+		{
+			schema: "log", tp: timodel.ActionCreateSchema,
+			query: "create database log", action: config.DDLPolicyActionSkip,
+		},
+		{
+			schema: "log", table: "t1", tp: timodel.ActionCreateTable,
+			query: "create table t1 (a int)", action: config.DDLPolicyActionSkip,
+		},
+		{
+			schema: "other", table: "t1", tp: timodel.ActionDropTable,
+			query: "drop table t1", action: "",
+		},
+	}
+	for _, c := range cases {
+		ddl := &model.DDLEvent{
+			TableInfo: &model.SimpleTableInfo{Schema: c.schema, Table: c.table},
+			Query:     c.query,
+			Type:      c.tp,
+		}
+		action, err := policy.Apply(ddl)
+		require.Nil(t, err)
+		require.Equal(t, c.action, action, c.query)
+		if c.action == config.DDLPolicyActionRewrite {
+			require.Equal(t, c.rewrite, ddl.Query)
+		}
+	}
+
+	// Unknown ddl types are rejected.
+	cfg.DDLPolicies = []*config.DDLPolicy{{
+		Matcher:  []string{"test.*"},
+		DDLTypes: []bf.EventType{"drop everything"},
+		Action:   config.DDLPolicyActionSkip,
+	}}
+	_, err = NewDDLPolicy(cfg)
+	require.Regexp(t, ".*invalid ignore event type.*", err)
+}
@@ -17,6 +17,7 @@ import (
 	timodel "github.com/pingcap/tidb/parser/model"
 	tfilter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/bdr"
 	"github.com/pingcap/tiflow/pkg/config"
 )
 
@@ -86,6 +87,8 @@ type filter struct {
 	sqlEventFilter *sqlEventFilter
 	// ignoreTxnStartTs is used to filter out dml/ddl event by its starsTs.
 	ignoreTxnStartTs []uint64
+	// ignoreMarkTables is used to filter out mark tables in bdr mode.
+	ignoreMarkTables bool
 }
 
 // NewFilter creates a filter.
@@ -112,6 +115,7 @@ func NewFilter(cfg *config.ReplicaConfig, tz string) (Filter, error) {
 		dmlExprFilter:    dmlExprFilter,
 		sqlEventFilter:   sqlEventFilter,
 		ignoreTxnStartTs: cfg.Filter.IgnoreTxnStartTs,
+		ignoreMarkTables: cfg.BDREnabled(),
 	}, nil
 }
 
@@ -203,6 +207,9 @@ func (f *filter) ShouldIgnoreTable(db, tbl string) bool {
 	if isSysSchema(db) {
 		return true
 	}
+	if f.ignoreMarkTables && bdr.IsMarkTable(db, tbl) {
+		return true
+	}
 	return !f.tableFilter.MatchTable(db, tbl)
 }
 
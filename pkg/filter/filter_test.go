@@ -35,6 +35,15 @@ func TestShouldUseDefaultRules(t *testing.T) {
 	require.False(t, filter.ShouldIgnoreTable("metric_schema", "query_duration"))
 	require.False(t, filter.ShouldIgnoreTable("sns", "user"))
 	require.False(t, filter.ShouldIgnoreTable("tidb_cdc", "repl_mark_a_a"))
+	require.False(t, filter.ShouldIgnoreTable("tidb_cdc", "bdr_mark_1"))
+
+	// Mark tables are ignored in bdr mode.
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.BDR = &config.BDRConfig{Enable: true}
+	filter, err = NewFilter(cfg, "")
+	require.Nil(t, err)
+	require.True(t, filter.ShouldIgnoreTable("tidb_cdc", "bdr_mark_1"))
+	require.False(t, filter.ShouldIgnoreTable("sns", "user"))
 }
 
 func TestShouldUseCustomRules(t *testing.T) {
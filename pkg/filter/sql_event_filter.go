@@ -189,6 +189,31 @@ func (f *sqlEventFilter) shouldSkipDML(event *model.RowChangedEvent) (bool, erro
 	return false, nil
 }
 
+// IgnoredEvents returns the event types and the sql patterns ignored by the
+// event filter rules matching the table.
+func IgnoredEvents(
+	cfg *config.FilterConfig, schema, table string,
+) (eventTypes []bf.EventType, sqls []string, err error) {
+	f, err := newSQLEventFilter(cfg)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	seen := make(map[bf.EventType]struct{})
+	for i, rule := range f.rules {
+		if !rule.tf.MatchTable(schema, table) {
+			continue
+		}
+		for _, et := range cfg.EventFilters[i].IgnoreEvent {
+			if _, ok := seen[et]; !ok {
+				seen[et] = struct{}{}
+				eventTypes = append(eventTypes, et)
+			}
+		}
+		sqls = append(sqls, cfg.EventFilters[i].IgnoreSQL...)
+	}
+	return eventTypes, sqls, nil
+}
+
 var supportedEventTypes = []bf.EventType{
 	bf.AllDML,
 	bf.AllDDL,
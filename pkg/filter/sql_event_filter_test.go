@@ -345,3 +345,34 @@ func TestVerifyIgnoreEvents(t *testing.T) {
 		require.True(t, errors.ErrorEqual(tc.err, verifyIgnoreEvents(tc.ignoreEvent)))
 	}
 }
+
+func TestIgnoredEvents(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.FilterConfig{
+		EventFilters: []*config.EventFilterRule{
+			{
+				Matcher:     []string{"test.*"},
+				IgnoreEvent: []bf.EventType{bf.DropTable, bf.TruncateTable},
+				IgnoreSQL:   []string{"^DROP"},
+			},
+			{
+				Matcher:     []string{"test.t1"},
+				IgnoreEvent: []bf.EventType{bf.DeleteEvent, bf.DropTable},
+			},
+		},
+	}
+	eventTypes, sqls, err := IgnoredEvents(cfg, "test", "t1")
+	require.Nil(t, err)
+	require.Equal(t, []bf.EventType{bf.DropTable, bf.TruncateTable, bf.DeleteEvent}, eventTypes)
+	require.Equal(t, []string{"^DROP"}, sqls)
+
+	eventTypes, sqls, err = IgnoredEvents(cfg, "other", "t1")
+	require.Nil(t, err)
+	require.Empty(t, eventTypes)
+	require.Empty(t, sqls)
+
+	cfg.EventFilters[0].IgnoreEvent = []bf.EventType{bf.EventType("unknown")}
+	_, _, err = IgnoredEvents(cfg, "test", "t1")
+	require.True(t, errors.ErrorEqual(cerror.ErrInvalidIgnoreEventType, err))
+}
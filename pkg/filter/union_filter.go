@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"sync"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// UnionFilter is a Filter composed of several filters, an event is ignored
+// only if it is ignored by all the filters. The filters can be replaced while
+// it is in use, so tables of a running changefeed can be changed online.
+type UnionFilter struct {
+	mu      sync.RWMutex
+	filters []Filter
+}
+
+// NewUnionFilter creates a UnionFilter.
+func NewUnionFilter(filters ...Filter) *UnionFilter {
+	return &UnionFilter{filters: filters}
+}
+
+// Replace replaces all the filters in the union with the given ones.
+func (f *UnionFilter) Replace(filters ...Filter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = filters
+}
+
+// ShouldIgnoreDMLEvent implements Filter.
+func (f *UnionFilter) ShouldIgnoreDMLEvent(
+	dml *model.RowChangedEvent,
+	rawRow model.RowChangedDatums,
+	ti *model.TableInfo,
+) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, filter := range f.filters {
+		ignore, err := filter.ShouldIgnoreDMLEvent(dml, rawRow, ti)
+		if err != nil || !ignore {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ShouldIgnoreDDLEvent implements Filter.
+func (f *UnionFilter) ShouldIgnoreDDLEvent(ddl *model.DDLEvent) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, filter := range f.filters {
+		ignore, err := filter.ShouldIgnoreDDLEvent(ddl)
+		if err != nil || !ignore {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ShouldDiscardDDL implements Filter.
+func (f *UnionFilter) ShouldDiscardDDL(ddlType timodel.ActionType, schema, table string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, filter := range f.filters {
+		if !filter.ShouldDiscardDDL(ddlType, schema, table) {
+			return false
+		}
+	}
+	return true
+}
+
+// ShouldIgnoreTable implements Filter.
+func (f *UnionFilter) ShouldIgnoreTable(schema, table string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, filter := range f.filters {
+		if !filter.ShouldIgnoreTable(schema, table) {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify implements Filter.
+func (f *UnionFilter) Verify(tableInfos []*model.TableInfo) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, filter := range f.filters {
+		if err := filter.Verify(tableInfos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
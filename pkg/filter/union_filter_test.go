@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionFilter(t *testing.T) {
+	t.Parallel()
+
+	f1, err := NewFilter(&config.ReplicaConfig{
+		Filter: &config.FilterConfig{Rules: []string{"test.t1"}},
+	}, "")
+	require.Nil(t, err)
+	f2, err := NewFilter(&config.ReplicaConfig{
+		Filter: &config.FilterConfig{Rules: []string{"test.t2"}},
+	}, "")
+	require.Nil(t, err)
+
+	f := NewUnionFilter(f1)
+	require.False(t, f.ShouldIgnoreTable("test", "t1"))
+	require.True(t, f.ShouldIgnoreTable("test", "t2"))
+	require.True(t, f.ShouldDiscardDDL(timodel.ActionAddColumn, "test", "t2"))
+
+	f.Replace(f1, f2)
+	require.False(t, f.ShouldIgnoreTable("test", "t1"))
+	require.False(t, f.ShouldIgnoreTable("test", "t2"))
+	require.True(t, f.ShouldIgnoreTable("test", "t3"))
+	require.False(t, f.ShouldDiscardDDL(timodel.ActionAddColumn, "test", "t2"))
+
+	ignore, err := f.ShouldIgnoreDMLEvent(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t2"},
+		Columns: []*model.Column{{Name: "id", Value: 1}},
+	}, model.RowChangedDatums{}, nil)
+	require.Nil(t, err)
+	require.False(t, ignore)
+	ignore, err = f.ShouldIgnoreDMLEvent(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t3"},
+		Columns: []*model.Column{{Name: "id", Value: 1}},
+	}, model.RowChangedDatums{}, nil)
+	require.Nil(t, err)
+	require.True(t, ignore)
+
+	// Tables which are not matched by the new filter are ignored after the
+	// filters are replaced.
+	f.Replace(f2)
+	require.True(t, f.ShouldIgnoreTable("test", "t1"))
+	require.False(t, f.ShouldIgnoreTable("test", "t2"))
+	require.True(t, f.ShouldDiscardDDL(timodel.ActionAddColumn, "test", "t1"))
+}
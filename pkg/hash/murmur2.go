@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hash
+
+// Murmur2 returns the 32-bit murmur2 hash of the data, which is the same as
+// `org.apache.kafka.common.utils.Utils.murmur2` of the Java Kafka client.
+func Murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    int32  = 0x5bd1e995
+		r           = 24
+	)
+	length := len(data)
+	h := int32(seed ^ uint32(length))
+	for i := 0; i+4 <= length; i += 4 {
+		k := int32(data[i]) | int32(data[i+1])<<8 | int32(data[i+2])<<16 | int32(data[i+3])<<24
+		k *= m
+		k ^= int32(uint32(k) >> r)
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= int32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= int32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= int32(data[tail])
+		h *= m
+	}
+	h ^= int32(uint32(h) >> 13)
+	h *= m
+	h ^= int32(uint32(h) >> 15)
+	return h
+}
+
+// Murmur2Partition returns the partition of the key in the same way as the
+// default partitioner of the Java Kafka client.
+func Murmur2Partition(key []byte, partitionNum int32) int32 {
+	return (Murmur2(key) & 0x7fffffff) % partitionNum
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMurmur2(t *testing.T) {
+	t.Parallel()
+
+	// The cases are the same as the ones of the Java Kafka client.
+	cases := []struct {
+		data     string
+		expected int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, Murmur2([]byte(c.data)), c.data)
+	}
+	require.Equal(t, int32(479470107%8), Murmur2Partition([]byte("abc"), 8))
+	require.Equal(t, int32((-973932308&0x7fffffff)%8), Murmur2Partition([]byte("21"), 8))
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/soheilhy/cmux"
+)
+
+// LimitListener returns a Listener that accepts at most n simultaneous
+// connections from the provided Listener, like netutil.LimitListener.
+//
+// Different from netutil.LimitListener, the TLS connection state of the
+// accepted connections can be found by ConnContext, even if the TLS
+// connections are wrapped by cmux.
+func LimitListener(l net.Listener, n int) net.Listener {
+	return &limitListener{
+		Listener: l,
+		sem:      make(chan struct{}, n),
+		done:     make(chan struct{}),
+	}
+}
+
+type limitListener struct {
+	net.Listener
+	sem       chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// acquire acquires the limiting semaphore. Returns true if successfully
+// acquired, false if the listener is closed and the semaphore is not
+// acquired.
+func (l *limitListener) acquire() bool {
+	select {
+	case <-l.done:
+		return false
+	case l.sem <- struct{}{}:
+		return true
+	}
+}
+
+func (l *limitListener) release() { <-l.sem }
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	if !l.acquire() {
+		// If the semaphore isn't acquired because the listener is closed,
+		// expect that this call to accept won't block, but immediately
+		// return an error.
+		for {
+			c, err := l.Listener.Accept()
+			if err != nil {
+				return nil, err
+			}
+			c.Close()
+		}
+	}
+
+	c, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return &limitListenerConn{Conn: c, release: l.release}, nil
+}
+
+func (l *limitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (l *limitListenerConn) Close() error {
+	err := l.Conn.Close()
+	l.releaseOnce.Do(l.release)
+	return err
+}
+
+type tlsStateKey struct{}
+
+// ConnContext puts the TLS connection state of the connection into the
+// context, it's used as http.Server.ConnContext.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	for {
+		switch conn := c.(type) {
+		case *tls.Conn:
+			state := conn.ConnectionState()
+			return context.WithValue(ctx, tlsStateKey{}, &state)
+		case *limitListenerConn:
+			c = conn.Conn
+		case *cmux.MuxConn:
+			c = conn.Conn
+		default:
+			return ctx
+		}
+	}
+}
+
+// TLSStateFromRequest returns the TLS connection state of the request, it's
+// nil if the request is not sent by a TLS connection.
+func TLSStateFromRequest(req *http.Request) *tls.ConnectionState {
+	if req.TLS != nil {
+		return req.TLS
+	}
+	state, _ := req.Context().Value(tlsStateKey{}).(*tls.ConnectionState)
+	return state
+}
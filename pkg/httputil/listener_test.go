@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/soheilhy/cmux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitListenerTLSState(t *testing.T) {
+	t.Parallel()
+
+	certDir := "_certificates"
+	serverCredential := &security.Credential{
+		CAPath:        filepath.Join(certDir, "ca.pem"),
+		CertPath:      filepath.Join(certDir, "server.pem"),
+		KeyPath:       filepath.Join(certDir, "server-key.pem"),
+		CertAllowedCN: []string{"client"},
+	}
+	serverTLS, err := serverCredential.ToTLSConfigWithVerify()
+	require.Nil(t, err)
+	// cmux does not support ALPN.
+	serverTLS.NextProtos = nil
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer lis.Close()
+	// TLS connections are wrapped by cmux, like the TiCDC server does.
+	mux := cmux.New(tls.NewListener(lis, serverTLS))
+	httpLis := LimitListener(mux.Match(cmux.HTTP1Fast()), 1)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			state := TLSStateFromRequest(req)
+			if state == nil || len(state.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(state.PeerCertificates[0].Subject.CommonName))
+		}),
+		ConnContext: ConnContext,
+	}
+	go func() { _ = server.Serve(httpLis) }()
+	go func() { _ = mux.Serve() }()
+	defer server.Close()
+
+	cli, err := NewClient(&security.Credential{
+		CAPath:   filepath.Join(certDir, "ca.pem"),
+		CertPath: filepath.Join(certDir, "client.pem"),
+		KeyPath:  filepath.Join(certDir, "client-key.pem"),
+	})
+	require.Nil(t, err)
+	resp, err := cli.Get(context.Background(), fmt.Sprintf("https://%s/", lis.Addr()))
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Equal(t, "client", string(body))
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metabackup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	pd "github.com/tikv/pd/client"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// Restore writes the snapshot into the cluster of the etcd client.
+//
+// The restoring is refused if
+//   - the snapshot is corrupted,
+//   - a changefeed replicates from an upstream other than the given PD,
+//   - the checkpoint of a changefeed has been garbage collected,
+//   - a changefeed in the snapshot already exists in the cluster, or
+//   - the meta version of the cluster differs from the snapshot.
+//
+// A service GC safepoint is set at the checkpoint of each changefeed before
+// writing the metadata, so the data is not garbage collected before the
+// owner of the cluster takes over the changefeeds. The changefeeds are written
+// one by one, if the restoring fails halfway, the written changefeeds are kept
+// with their safepoints and only the safepoints of the others are undone.
+func Restore(
+	ctx context.Context,
+	etcdClient *etcd.CDCEtcdClientImpl,
+	pdClient pd.Client,
+	s *Snapshot,
+	gcTTL int64,
+) (err error) {
+	if err := s.Verify(); err != nil {
+		return err
+	}
+	infos, statuses, err := s.Changefeeds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	upstreamID := pdClient.GetClusterID(ctx)
+	for id, info := range infos {
+		if info.UpstreamID != 0 && info.UpstreamID != upstreamID {
+			return cerror.ErrMetaRestoreRefused.GenWithStackByArgs(fmt.Sprintf(
+				"changefeed %s replicates from upstream %d, but the pd belongs to %d",
+				id, info.UpstreamID, upstreamID))
+		}
+	}
+
+	// Check the conflicts before writing anything, the transactions of
+	// writing changefeeds still guard against the concurrent creations.
+	for id := range infos {
+		key := etcd.GetEtcdKeyChangeFeedInfo(etcdClient.GetClusterID(), id)
+		resp, err := etcdClient.Client.Get(ctx, key, clientv3.WithCountOnly())
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if resp.Count > 0 {
+			return cerror.ErrMetaRestoreRefused.GenWithStackByArgs(
+				fmt.Sprintf("changefeed %s already exists", id))
+		}
+	}
+
+	gcServiceID := etcdClient.GetEnsureGCServiceID(gc.EnsureGCServiceCreating)
+	var ensured []model.ChangeFeedID
+	written := make(map[model.ChangeFeedID]struct{})
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, id := range ensured {
+			// The written changefeeds are taken over by the owner, which
+			// needs the safepoints.
+			if _, ok := written[id]; ok {
+				continue
+			}
+			if err := gc.UndoEnsureChangefeedStartTsSafety(
+				ctx, pdClient, gcServiceID, id); err != nil {
+				log.Warn("failed to undo the service GC safepoint",
+					zap.String("namespace", id.Namespace),
+					zap.String("changefeed", id.ID), zap.Error(err))
+			}
+		}
+	}()
+	for id, info := range infos {
+		if info.State == model.StateRemoved || info.State == model.StateFinished {
+			continue
+		}
+		checkpointTs := info.StartTs
+		if status, ok := statuses[id]; ok {
+			checkpointTs = status.CheckpointTs
+		}
+		// The safepoint may be set even if the checkpoint is garbage
+		// collected, so it's always undone on failures.
+		ensured = append(ensured, id)
+		err := gc.EnsureChangefeedStartTsSafety(
+			ctx, pdClient, gcServiceID, id, gcTTL, checkpointTs)
+		if err != nil {
+			if cerror.ErrStartTsBeforeGC.Equal(err) {
+				return cerror.ErrMetaRestoreRefused.Wrap(err).GenWithStackByArgs(
+					fmt.Sprintf("the checkpoint of changefeed %s is garbage collected", id))
+			}
+			return errors.Trace(err)
+		}
+	}
+
+	return errors.Trace(writeKVs(ctx, etcdClient, s, written))
+}
+
+// writeKVs writes the keys of the snapshot into the cluster. The keys of each
+// changefeed are written in a transaction, which fails if the changefeed
+// exists, and the changefeed is added to written once it succeeds. The
+// upstreams are written if they are absent.
+func writeKVs(
+	ctx context.Context, etcdClient *etcd.CDCEtcdClientImpl, s *Snapshot,
+	written map[model.ChangeFeedID]struct{},
+) error {
+	baseKey := etcd.BaseKey(etcdClient.GetClusterID())
+	changefeedKVs := make(map[model.ChangeFeedID][]*KeyValue)
+	var upstreamKVs []*KeyValue
+	var metaVersion *KeyValue
+	for _, kv := range s.KVs {
+		k := new(etcd.CDCKey)
+		if err := k.Parse(s.ClusterID, etcd.BaseKey(s.ClusterID)+kv.Key); err != nil {
+			return errors.Trace(err)
+		}
+		switch k.Tp {
+		case etcd.CDCKeyTypeChangefeedInfo, etcd.CDCKeyTypeChangeFeedStatus:
+			changefeedKVs[k.ChangefeedID] = append(changefeedKVs[k.ChangefeedID], kv)
+		case etcd.CDCKeyTypeUpStream:
+			upstreamKVs = append(upstreamKVs, kv)
+		case etcd.CDCKeyTypeMetaVersion:
+			metaVersion = kv
+		}
+	}
+
+	if metaVersion != nil {
+		key := baseKey + metaVersion.Key
+		resp, err := etcdClient.Client.Txn(ctx,
+			[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(key), "=", 0)},
+			[]clientv3.Op{clientv3.OpPut(key, metaVersion.Value)},
+			[]clientv3.Op{clientv3.OpGet(key)})
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if !resp.Succeeded {
+			kvs := resp.Responses[0].GetResponseRange().Kvs
+			if len(kvs) > 0 && string(kvs[0].Value) != metaVersion.Value {
+				return cerror.ErrMetaRestoreRefused.GenWithStackByArgs(fmt.Sprintf(
+					"the meta version of the cluster is %s, but the snapshot is %s",
+					kvs[0].Value, metaVersion.Value))
+			}
+		}
+	}
+
+	for _, kv := range upstreamKVs {
+		key := baseKey + kv.Key
+		_, err := etcdClient.Client.Txn(ctx,
+			[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(key), "=", 0)},
+			[]clientv3.Op{clientv3.OpPut(key, kv.Value)}, nil)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+	}
+
+	// The changefeeds are written in order, so a failed restoring can be
+	// reasoned about from the logs.
+	ids := make([]model.ChangeFeedID, 0, len(changefeedKVs))
+	for id := range changefeedKVs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Namespace != ids[j].Namespace {
+			return ids[i].Namespace < ids[j].Namespace
+		}
+		return ids[i].ID < ids[j].ID
+	})
+	for _, id := range ids {
+		kvs := changefeedKVs[id]
+		cmps := make([]clientv3.Cmp, 0, len(kvs))
+		ops := make([]clientv3.Op, 0, len(kvs))
+		for _, kv := range kvs {
+			key := baseKey + kv.Key
+			cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+			ops = append(ops, clientv3.OpPut(key, kv.Value))
+		}
+		resp, err := etcdClient.Client.Txn(ctx, cmps, ops, nil)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if !resp.Succeeded {
+			return cerror.ErrMetaRestoreRefused.GenWithStackByArgs(
+				fmt.Sprintf("changefeed %s already exists", id))
+		}
+		written[id] = struct{}{}
+		log.Info("changefeed restored",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID))
+	}
+	return nil
+}
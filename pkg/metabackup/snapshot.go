@@ -0,0 +1,221 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metabackup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/version"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// SnapshotVersion is the version of the snapshot format.
+const SnapshotVersion = 1
+
+// KeyValue is a key of the metadata and its value.
+type KeyValue struct {
+	// Key is the key without the prefix of the cluster, so the snapshot
+	// can be restored to a cluster with another id.
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Snapshot is a consistent snapshot of the metadata of a TiCDC cluster.
+// Only the keys which are not bound to the leases of captures are in it,
+// which are the changefeed infos, changefeed statuses, upstream infos and
+// the meta version.
+type Snapshot struct {
+	Version    int         `json:"version"`
+	ClusterID  string      `json:"cluster-id"`
+	Revision   int64       `json:"revision"`
+	CreateTime time.Time   `json:"create-time"`
+	CDCVersion string      `json:"cdc-version"`
+	KVs        []*KeyValue `json:"kvs"`
+	Checksum   string      `json:"checksum"`
+}
+
+// checksum returns the sha256 checksum of the version and the KVs.
+func (s *Snapshot) checksum() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00", s.Version)
+	for _, kv := range s.KVs {
+		h.Write([]byte(kv.Key))
+		h.Write([]byte{0})
+		h.Write([]byte(kv.Value))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks the version and the checksum of the snapshot.
+func (s *Snapshot) Verify() error {
+	if s.Version != SnapshotVersion {
+		return cerror.ErrMetaSnapshotInvalid.GenWithStackByArgs(
+			fmt.Sprintf("unsupported version %d, expected %d", s.Version, SnapshotVersion))
+	}
+	if checksum := s.checksum(); checksum != s.Checksum {
+		return cerror.ErrMetaSnapshotInvalid.GenWithStackByArgs(
+			fmt.Sprintf("checksum mismatch, expected %s, got %s", s.Checksum, checksum))
+	}
+	return nil
+}
+
+// Changefeeds returns the infos and statuses of the changefeeds in the
+// snapshot, the status is nil if the changefeed is not initialized yet.
+func (s *Snapshot) Changefeeds() (
+	map[model.ChangeFeedID]*model.ChangeFeedInfo,
+	map[model.ChangeFeedID]*model.ChangeFeedStatus,
+	error,
+) {
+	infos := make(map[model.ChangeFeedID]*model.ChangeFeedInfo)
+	statuses := make(map[model.ChangeFeedID]*model.ChangeFeedStatus)
+	for _, kv := range s.KVs {
+		k := new(etcd.CDCKey)
+		if err := k.Parse(s.ClusterID, etcd.BaseKey(s.ClusterID)+kv.Key); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		switch k.Tp {
+		case etcd.CDCKeyTypeChangefeedInfo:
+			info := new(model.ChangeFeedInfo)
+			if err := info.Unmarshal([]byte(kv.Value)); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			infos[k.ChangefeedID] = info
+		case etcd.CDCKeyTypeChangeFeedStatus:
+			status := new(model.ChangeFeedStatus)
+			if err := status.Unmarshal([]byte(kv.Value)); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			statuses[k.ChangefeedID] = status
+		}
+	}
+	return infos, statuses, nil
+}
+
+// Backup takes a snapshot of the metadata of the cluster. All changefeeds
+// are in the snapshot if changefeedIDs is empty, otherwise only the given
+// changefeeds and their upstreams are in it.
+func Backup(
+	ctx context.Context,
+	etcdClient *etcd.CDCEtcdClientImpl,
+	changefeedIDs []model.ChangeFeedID,
+) (*Snapshot, error) {
+	clusterID := etcdClient.GetClusterID()
+	baseKey := etcd.BaseKey(clusterID)
+	// A single range read is served at a single revision, so the snapshot
+	// is consistent.
+	resp, err := etcdClient.Client.Get(ctx, baseKey+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+
+	selected := make(map[model.ChangeFeedID]bool, len(changefeedIDs))
+	for _, id := range changefeedIDs {
+		selected[id] = false
+	}
+	upstreams := make(map[model.UpstreamID]struct{})
+	var changefeedKVs, otherKVs []*KeyValue
+	var upstreamKVs []*KeyValue
+	var upstreamIDs []model.UpstreamID
+	for _, kv := range resp.Kvs {
+		k := new(etcd.CDCKey)
+		if err := k.Parse(clusterID, string(kv.Key)); err != nil {
+			log.Warn("skip unknown key", zap.ByteString("key", kv.Key), zap.Error(err))
+			continue
+		}
+		item := &KeyValue{
+			Key:   strings.TrimPrefix(string(kv.Key), baseKey),
+			Value: string(kv.Value),
+		}
+		switch k.Tp {
+		case etcd.CDCKeyTypeChangefeedInfo, etcd.CDCKeyTypeChangeFeedStatus:
+			if _, ok := selected[k.ChangefeedID]; len(changefeedIDs) != 0 && !ok {
+				continue
+			}
+			selected[k.ChangefeedID] = true
+			changefeedKVs = append(changefeedKVs, item)
+			if k.Tp == etcd.CDCKeyTypeChangefeedInfo {
+				info := new(model.ChangeFeedInfo)
+				if err := info.Unmarshal(kv.Value); err != nil {
+					return nil, errors.Trace(err)
+				}
+				upstreams[info.UpstreamID] = struct{}{}
+			}
+		case etcd.CDCKeyTypeUpStream:
+			upstreamKVs = append(upstreamKVs, item)
+			upstreamIDs = append(upstreamIDs, k.UpstreamID)
+		case etcd.CDCKeyTypeMetaVersion:
+			otherKVs = append(otherKVs, item)
+		default:
+			// The owner, captures and task positions are bound to the leases
+			// of the captures, they are meaningless in another cluster.
+		}
+	}
+	for id, found := range selected {
+		if !found {
+			return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id)
+		}
+	}
+	kvs := make([]*KeyValue, 0, len(otherKVs)+len(changefeedKVs)+len(upstreams))
+	kvs = append(kvs, otherKVs...)
+	kvs = append(kvs, changefeedKVs...)
+	for i, kv := range upstreamKVs {
+		if _, ok := upstreams[upstreamIDs[i]]; ok {
+			kvs = append(kvs, kv)
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+
+	s := &Snapshot{
+		Version:    SnapshotVersion,
+		ClusterID:  clusterID,
+		Revision:   resp.Header.Revision,
+		CreateTime: time.Now(),
+		CDCVersion: version.ReleaseVersion,
+		KVs:        kvs,
+	}
+	s.Checksum = s.checksum()
+	return s, nil
+}
+
+// Marshal encodes the snapshot into json.
+func (s *Snapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	return data, cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+// Unmarshal decodes the snapshot from json and verifies it.
+func Unmarshal(data []byte) (*Snapshot, error) {
+	s := new(Snapshot)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	if err := s.Verify(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
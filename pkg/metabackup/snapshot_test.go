@@ -0,0 +1,179 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metabackup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func newEtcdClient(t *testing.T, s *etcd.Tester, clusterID string) *etcd.CDCEtcdClientImpl {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{s.ClientURL.String()},
+		DialTimeout: 3 * time.Second,
+	})
+	require.NoError(t, err)
+	client, err := etcd.NewCDCEtcdClient(context.Background(), cli, clusterID)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func createChangefeed(
+	t *testing.T, client *etcd.CDCEtcdClientImpl,
+	id model.ChangeFeedID, upstreamID uint64, checkpointTs uint64,
+) {
+	ctx := context.Background()
+	info := &model.ChangeFeedInfo{
+		SinkURI: "blackhole://",
+		StartTs: 1,
+		State:   model.StateNormal,
+	}
+	err := client.CreateChangefeedInfo(ctx,
+		&model.UpstreamInfo{ID: upstreamID}, info, id)
+	require.NoError(t, err)
+	status, err := (&model.ChangeFeedStatus{CheckpointTs: checkpointTs}).Marshal()
+	require.NoError(t, err)
+	_, err = client.Client.Put(ctx, etcd.GetEtcdKeyJob(client.ClusterID, id), status)
+	require.NoError(t, err)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	s := &etcd.Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+	ctx := context.Background()
+
+	src := newEtcdClient(t, s, "src")
+	cf1 := model.DefaultChangeFeedID("cf1")
+	cf2 := model.DefaultChangeFeedID("cf2")
+	createChangefeed(t, src, cf1, 1, 100)
+	createChangefeed(t, src, cf2, 1, 200)
+	// The cluster with a prefixed id must not be in the snapshot.
+	createChangefeed(t, newEtcdClient(t, s, "src2"), cf1, 1, 100)
+
+	snap, err := Backup(ctx, src, nil)
+	require.NoError(t, err)
+	require.Equal(t, "src", snap.ClusterID)
+	infos, statuses, err := snap.Changefeeds()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, uint64(200), statuses[cf2].CheckpointTs)
+
+	// A subset of changefeeds.
+	subset, err := Backup(ctx, src, []model.ChangeFeedID{cf1})
+	require.NoError(t, err)
+	infos, _, err = subset.Changefeeds()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Contains(t, infos, cf1)
+	_, err = Backup(ctx, src, []model.ChangeFeedID{model.DefaultChangeFeedID("cf3")})
+	require.Regexp(t, ".*ErrChangeFeedNotExists.*", err)
+
+	// Encoding round trip and tamper detection.
+	data, err := snap.Marshal()
+	require.NoError(t, err)
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, snap.KVs, decoded.KVs)
+	decoded.KVs[0].Value = "tampered"
+	require.Regexp(t, ".*checksum mismatch.*", decoded.Verify())
+
+	safepoints := make(map[string]uint64)
+	pdClient := &gc.MockPDClient{
+		ClusterID: 1,
+		UpdateServiceGCSafePointFunc: func(
+			ctx context.Context, serviceID string, ttl int64, safePoint uint64,
+		) (uint64, error) {
+			if ttl == 0 {
+				delete(safepoints, serviceID)
+			} else {
+				safepoints[serviceID] = safePoint
+			}
+			return 50, nil
+		},
+	}
+
+	// Restore to another cluster id.
+	dst := newEtcdClient(t, s, "dst")
+	require.NoError(t, Restore(ctx, dst, pdClient, snap, 3600))
+	require.Len(t, safepoints, 2)
+	restored, err := Backup(ctx, dst, nil)
+	require.NoError(t, err)
+	require.Equal(t, snap.KVs, restored.KVs)
+	_, err = dst.GetUpstreamInfo(ctx, 1, model.DefaultNamespace)
+	require.NoError(t, err)
+
+	// Existing changefeeds are never overwritten.
+	err = Restore(ctx, dst, pdClient, snap, 3600)
+	require.Regexp(t, ".*changefeed.*already exists.*", err)
+
+	// The upstream must match.
+	pdClient.ClusterID = 2
+	err = Restore(ctx, newEtcdClient(t, s, "dst2"), pdClient, snap, 3600)
+	require.Regexp(t, ".*ErrMetaRestoreRefused.*upstream 1.*", err)
+
+	// The checkpoints must not be garbage collected.
+	pdClient.ClusterID = 1
+	for k := range safepoints {
+		delete(safepoints, k)
+	}
+	pdClient.UpdateServiceGCSafePointFunc = func(
+		ctx context.Context, serviceID string, ttl int64, safePoint uint64,
+	) (uint64, error) {
+		if ttl == 0 {
+			delete(safepoints, serviceID)
+		} else {
+			safepoints[serviceID] = safePoint
+		}
+		return 150, nil
+	}
+	dst3 := newEtcdClient(t, s, "dst3")
+	err = Restore(ctx, dst3, pdClient, snap, 3600)
+	require.Regexp(t, ".*ErrMetaRestoreRefused.*garbage collected.*", err)
+	require.Empty(t, safepoints)
+	restored, err = Backup(ctx, dst3, nil)
+	require.NoError(t, err)
+	require.Empty(t, restored.KVs)
+
+	// A changefeed fails to be written, the written one keeps its safepoint.
+	pdClient.UpdateServiceGCSafePointFunc = func(
+		ctx context.Context, serviceID string, ttl int64, safePoint uint64,
+	) (uint64, error) {
+		if ttl == 0 {
+			delete(safepoints, serviceID)
+		} else {
+			safepoints[serviceID] = safePoint
+		}
+		return 50, nil
+	}
+	dst4 := newEtcdClient(t, s, "dst4")
+	_, err = dst4.Client.Put(ctx, etcd.GetEtcdKeyJob(dst4.ClusterID, cf2), "{}")
+	require.NoError(t, err)
+	err = Restore(ctx, dst4, pdClient, snap, 3600)
+	require.Regexp(t, ".*changefeed.*cf2.*already exists.*", err)
+	gcServiceID := dst4.GetEnsureGCServiceID(gc.EnsureGCServiceCreating)
+	require.Equal(t, map[string]uint64{
+		gcServiceID + cf1.Namespace + "_" + cf1.ID: 100,
+	}, safepoints)
+	_, err = dst4.GetChangeFeedInfo(ctx, cf1)
+	require.NoError(t, err)
+}
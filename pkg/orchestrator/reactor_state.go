@@ -16,6 +16,7 @@ package orchestrator
 import (
 	"encoding/json"
 	"reflect"
+	"sort"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -90,7 +91,8 @@ func (s *GlobalReactorState) Update(key util.EtcdKey, value []byte, _ bool) erro
 		s.Captures[k.CaptureID] = &newCaptureInfo
 	case etcd.CDCKeyTypeChangefeedInfo,
 		etcd.CDCKeyTypeChangeFeedStatus,
-		etcd.CDCKeyTypeTaskPosition:
+		etcd.CDCKeyTypeTaskPosition,
+		etcd.CDCKeyTypeChangefeedEvent:
 		changefeedState, exist := s.Changefeeds[k.ChangefeedID]
 		if !exist {
 			if value == nil {
@@ -159,6 +161,13 @@ type ChangefeedReactorState struct {
 	Status        *model.ChangeFeedStatus
 	TaskPositions map[model.CaptureID]*model.TaskPosition
 
+	// eventIDs are the sorted IDs of the events of the changefeed, the
+	// events themselves are not cached in the state.
+	eventIDs []string
+	// lastEventTs is the time of the last event appended by this state, it
+	// keeps the IDs of the events appended in the same nanosecond unique.
+	lastEventTs int64
+
 	pendingPatches        []DataPatch
 	skipPatchesInThisTick bool
 }
@@ -222,6 +231,16 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 		position := new(model.TaskPosition)
 		s.TaskPositions[key.CaptureID] = position
 		e = position
+	case etcd.CDCKeyTypeChangefeedEvent:
+		if key.ChangefeedID != s.ID {
+			return nil
+		}
+		if value == nil {
+			s.removeEventID(key.EventID)
+		} else {
+			s.addEventID(key.EventID)
+		}
+		return nil
 	default:
 		return nil
 	}
@@ -238,7 +257,8 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 
 // Exist returns false if all keys of this changefeed in ETCD is not exist
 func (s *ChangefeedReactorState) Exist() bool {
-	return s.Info != nil || s.Status != nil || len(s.TaskPositions) != 0
+	return s.Info != nil || s.Status != nil || len(s.TaskPositions) != 0 ||
+		len(s.eventIDs) != 0
 }
 
 // Active return true if the changefeed is ready to be processed
@@ -354,7 +374,78 @@ func (s *ChangefeedReactorState) PatchTaskPosition(captureID model.CaptureID, fn
 	})
 }
 
+// PatchEvent appends a DataPatch which can modify an event of the changefeed
+func (s *ChangefeedReactorState) PatchEvent(eventID string, fn func(*model.ChangefeedEvent) (*model.ChangefeedEvent, bool, error)) {
+	key := &etcd.CDCKey{
+		ClusterID:    s.ClusterID,
+		Tp:           etcd.CDCKeyTypeChangefeedEvent,
+		ChangefeedID: s.ID,
+		EventID:      eventID,
+	}
+	s.patchAny(key.String(), changefeedEventTPI, func(e interface{}) (interface{}, bool, error) {
+		// e == nil means that the key is not exist before this patch
+		if e == nil {
+			return fn(nil)
+		}
+		return fn(e.(*model.ChangefeedEvent))
+	})
+}
+
+// AppendEvents appends events to the changefeed, every event is written to its
+// own key. The oldest events are removed if there are more than
+// model.MaxChangefeedEvents.
+func (s *ChangefeedReactorState) AppendEvents(events ...*model.ChangefeedEvent) {
+	for _, event := range events {
+		ts := event.Time.UnixNano()
+		if ts <= s.lastEventTs {
+			ts = s.lastEventTs + 1
+		}
+		s.lastEventTs = ts
+		eventID := etcd.ChangefeedEventID(ts)
+		truncated := event.Truncate()
+		s.PatchEvent(eventID, func(*model.ChangefeedEvent) (*model.ChangefeedEvent, bool, error) {
+			return truncated, true, nil
+		})
+		s.addEventID(eventID)
+	}
+	for len(s.eventIDs) > model.MaxChangefeedEvents {
+		s.removeEvent(s.eventIDs[0])
+	}
+}
+
+// RemoveEvents removes all events of the changefeed.
+func (s *ChangefeedReactorState) RemoveEvents() {
+	for len(s.eventIDs) > 0 {
+		s.removeEvent(s.eventIDs[0])
+	}
+}
+
+func (s *ChangefeedReactorState) removeEvent(eventID string) {
+	s.PatchEvent(eventID, func(event *model.ChangefeedEvent) (*model.ChangefeedEvent, bool, error) {
+		return nil, event != nil, nil
+	})
+	s.removeEventID(eventID)
+}
+
+func (s *ChangefeedReactorState) addEventID(eventID string) {
+	i := sort.SearchStrings(s.eventIDs, eventID)
+	if i < len(s.eventIDs) && s.eventIDs[i] == eventID {
+		return
+	}
+	s.eventIDs = append(s.eventIDs, "")
+	copy(s.eventIDs[i+1:], s.eventIDs[i:])
+	s.eventIDs[i] = eventID
+}
+
+func (s *ChangefeedReactorState) removeEventID(eventID string) {
+	i := sort.SearchStrings(s.eventIDs, eventID)
+	if i < len(s.eventIDs) && s.eventIDs[i] == eventID {
+		s.eventIDs = append(s.eventIDs[:i], s.eventIDs[i+1:]...)
+	}
+}
+
 var (
+	changefeedEventTPI  *model.ChangefeedEvent
 	taskPositionTPI     *model.TaskPosition
 	changefeedStatusTPI *model.ChangeFeedStatus
 	changefeedInfoTPI   *model.ChangeFeedInfo
@@ -16,6 +16,8 @@ package orchestrator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -364,6 +366,64 @@ func TestPatchStatus(t *testing.T) {
 	require.Nil(t, state.Status)
 }
 
+func TestAppendEvents(t *testing.T) {
+	id := model.DefaultChangeFeedID("test1")
+	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID, id)
+	stateTester := NewReactorStateTester(t, state, nil)
+	prefix := etcd.GetEtcdKeyChangefeedEvents(etcd.DefaultCDCClusterID, id)
+	getEvents := func() []*model.ChangefeedEvent {
+		keys := make([]string, 0)
+		for key := range stateTester.KVEntries() {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		events := make([]*model.ChangefeedEvent, 0, len(keys))
+		for _, key := range keys {
+			event := &model.ChangefeedEvent{}
+			require.Nil(t, event.Unmarshal([]byte(stateTester.KVEntries()[key])))
+			events = append(events, event)
+		}
+		return events
+	}
+
+	// Every event is written to its own key, even if they happen at the
+	// same time.
+	paused := model.NewChangefeedEvent(model.ChangefeedEventPaused, "")
+	state.AppendEvents(paused, paused)
+	stateTester.MustApplyPatches()
+	require.Len(t, getEvents(), 2)
+	require.True(t, state.Exist())
+
+	// The oldest events are removed.
+	for i := 0; i < model.MaxChangefeedEvents; i++ {
+		state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventResumed, ""))
+	}
+	stateTester.MustApplyPatches()
+	events := getEvents()
+	require.Len(t, events, model.MaxChangefeedEvents)
+	require.Equal(t, model.ChangefeedEventResumed, events[0].Type)
+
+	// Events written by others are tracked too.
+	event := model.NewChangefeedEvent(model.ChangefeedEventUpdated, "")
+	value, err := event.Marshal()
+	require.Nil(t, err)
+	stateTester.MustUpdate(etcd.GetEtcdKeyChangefeedEvent(etcd.DefaultCDCClusterID, id,
+		etcd.ChangefeedEventID(event.Time.UnixNano())), []byte(value))
+	state.AppendEvents(model.NewChangefeedEvent(model.ChangefeedEventPaused, ""))
+	stateTester.MustApplyPatches()
+	events = getEvents()
+	require.Len(t, events, model.MaxChangefeedEvents)
+	require.Equal(t, model.ChangefeedEventUpdated, events[len(events)-2].Type)
+	require.Equal(t, model.ChangefeedEventPaused, events[len(events)-1].Type)
+
+	state.RemoveEvents()
+	stateTester.MustApplyPatches()
+	require.Empty(t, getEvents())
+	require.False(t, state.Exist())
+}
+
 func TestPatchTaskPosition(t *testing.T) {
 	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
 		model.DefaultChangeFeedID("test1"))
@@ -0,0 +1,237 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type limits struct {
+	rows   uint64
+	bytes  uint64
+	paused bool
+}
+
+// share returns the limits of one of the n processors of a changefeed.
+func (l limits) share(n int) limits {
+	if n <= 1 {
+		return l
+	}
+	divide := func(limit uint64) uint64 {
+		if limit == 0 {
+			return 0
+		}
+		// Round up so that a share never becomes unlimited.
+		return (limit + uint64(n) - 1) / uint64(n)
+	}
+	return limits{rows: divide(l.rows), bytes: divide(l.bytes), paused: l.paused}
+}
+
+type window struct {
+	start time.Duration
+	end   time.Duration
+	limits
+}
+
+// contains returns whether the offset from the beginning of a day is
+// in the window.
+func (w *window) contains(offset time.Duration) bool {
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	// The window crosses midnight.
+	return offset >= w.start || offset < w.end
+}
+
+// bucket is a token bucket which holds tokens of at most one second.
+// Its tokens can be negative, so transactions larger than the limit
+// can still be admitted.
+type bucket struct {
+	tokens float64
+}
+
+func (b *bucket) refill(limit uint64, elapsed time.Duration) {
+	if limit == 0 {
+		b.tokens = 0
+		return
+	}
+	b.tokens += float64(limit) * elapsed.Seconds()
+	if b.tokens > float64(limit) {
+		b.tokens = float64(limit)
+	}
+}
+
+// Limiter limits the rows and bytes flushed by the table sinks of a
+// changefeed in a processor. The configured limits are shared evenly by
+// the processors of the changefeed, see SetProcessorCount.
+type Limiter struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	location   *time.Location
+	defaults   limits
+	windows    []window
+	processors int
+	current    limits
+	lastTime   time.Time
+	rows       bucket
+	bytes      bucket
+
+	throttledCounter prometheus.Counter
+	rowsLimitGauge   prometheus.Gauge
+	bytesLimitGauge  prometheus.Gauge
+	changefeedID     model.ChangeFeedID
+}
+
+// NewLimiter creates a Limiter, it returns nil if the rate limit is
+// not configured. All methods of a nil Limiter are no-ops.
+func NewLimiter(
+	changefeedID model.ChangeFeedID, cfg *config.RateLimitConfig,
+) (*Limiter, error) {
+	return newLimiter(changefeedID, cfg, clock.New())
+}
+
+func newLimiter(
+	changefeedID model.ChangeFeedID, cfg *config.RateLimitConfig, clk clock.Clock,
+) (*Limiter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	location, err := cfg.Location()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrInvalidReplicaConfig, err)
+	}
+	l := &Limiter{
+		clock:      clk,
+		location:   location,
+		defaults:   limits{rows: cfg.RowsPerSecond, bytes: cfg.BytesPerSecond},
+		processors: 1,
+		lastTime:   clk.Now(),
+
+		throttledCounter: throttledCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		rowsLimitGauge: limitGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID, limitTypeRows),
+		bytesLimitGauge: limitGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID, limitTypeBytes),
+		changefeedID: changefeedID,
+	}
+	for _, s := range cfg.Schedules {
+		start, end, err := s.Window()
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrInvalidReplicaConfig, err)
+		}
+		l.windows = append(l.windows, window{
+			start: start,
+			end:   end,
+			limits: limits{
+				rows: s.RowsPerSecond, bytes: s.BytesPerSecond, paused: s.Pause,
+			},
+		})
+	}
+	if len(l.windows) == 0 && l.defaults.rows == 0 && l.defaults.bytes == 0 {
+		l.Close()
+		return nil, nil
+	}
+	l.current = l.limitsAt(l.lastTime)
+	l.rows.refill(l.current.rows, time.Second)
+	l.bytes.refill(l.current.bytes, time.Second)
+	l.updateMetrics()
+	return l, nil
+}
+
+// limitsAt returns the share of the limits at the time.
+func (l *Limiter) limitsAt(now time.Time) limits {
+	now = now.In(l.location)
+	offset := time.Duration(now.Hour())*time.Hour +
+		time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second
+	for i := range l.windows {
+		if l.windows[i].contains(offset) {
+			return l.windows[i].limits.share(l.processors)
+		}
+	}
+	return l.defaults.share(l.processors)
+}
+
+// SetProcessorCount sets the number of processors running the changefeed,
+// each of them is limited to an even share of the configured limits.
+func (l *Limiter) SetProcessorCount(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.processors = n
+}
+
+func (l *Limiter) updateMetrics() {
+	l.rowsLimitGauge.Set(float64(l.current.rows))
+	l.bytesLimitGauge.Set(float64(l.current.bytes))
+}
+
+// Admit returns whether the rows and bytes can be flushed now. They are
+// admitted as long as the limits are not exhausted, so a transaction larger
+// than the limits is admitted once the tokens of previous ones are paid off.
+// Nothing is admitted in a paused window.
+func (l *Limiter) Admit(rows, bytes int) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	elapsed := now.Sub(l.lastTime)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	l.lastTime = now
+	if current := l.limitsAt(now); current != l.current {
+		l.current = current
+		l.updateMetrics()
+	}
+	l.rows.refill(l.current.rows, elapsed)
+	l.bytes.refill(l.current.bytes, elapsed)
+
+	if l.current.paused ||
+		(l.current.rows != 0 && l.rows.tokens <= 0) ||
+		(l.current.bytes != 0 && l.bytes.tokens <= 0) {
+		l.throttledCounter.Inc()
+		return false
+	}
+	if l.current.rows != 0 {
+		l.rows.tokens -= float64(rows)
+	}
+	if l.current.bytes != 0 {
+		l.bytes.tokens -= float64(bytes)
+	}
+	return true
+}
+
+// Close releases the metrics of the Limiter.
+func (l *Limiter) Close() {
+	if l == nil {
+		return
+	}
+	throttledCounter.DeleteLabelValues(l.changefeedID.Namespace, l.changefeedID.ID)
+	limitGauge.DeleteLabelValues(l.changefeedID.Namespace, l.changefeedID.ID, limitTypeRows)
+	limitGauge.DeleteLabelValues(l.changefeedID.Namespace, l.changefeedID.ID, limitTypeBytes)
+}
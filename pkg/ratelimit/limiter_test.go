@@ -0,0 +1,156 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNilLimiter(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewLimiter(model.DefaultChangeFeedID("test"), nil)
+	require.Nil(t, err)
+	require.Nil(t, l)
+	l, err = NewLimiter(model.DefaultChangeFeedID("test"), &config.RateLimitConfig{})
+	require.Nil(t, err)
+	require.Nil(t, l)
+	require.True(t, l.Admit(1000, 1000))
+	l.Close()
+}
+
+func TestLimiterAdmit(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	l, err := newLimiter(model.DefaultChangeFeedID("test"), &config.RateLimitConfig{
+		RowsPerSecond:  100,
+		BytesPerSecond: 1000,
+	}, clk)
+	require.Nil(t, err)
+	defer l.Close()
+
+	require.True(t, l.Admit(60, 100))
+	require.True(t, l.Admit(60, 100))
+	// The rows are exhausted.
+	require.False(t, l.Admit(1, 1))
+	clk.Add(100 * time.Millisecond)
+	require.False(t, l.Admit(1, 1))
+	clk.Add(200 * time.Millisecond)
+	require.True(t, l.Admit(1, 1))
+
+	// A transaction larger than the limits is admitted once the debts
+	// are paid off.
+	clk.Add(time.Second)
+	require.True(t, l.Admit(1, 5000))
+	clk.Add(3 * time.Second)
+	require.False(t, l.Admit(1, 1))
+	clk.Add(2 * time.Second)
+	require.True(t, l.Admit(1, 1))
+}
+
+func TestLimiterSchedules(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	// The mock clock starts at 00:00:00 UTC.
+	l, err := newLimiter(model.DefaultChangeFeedID("test"), &config.RateLimitConfig{
+		TimeZone: "UTC",
+		Schedules: []*config.RateLimitSchedule{
+			{Start: "22:00", End: "01:00", RowsPerSecond: 10},
+			{Start: "09:00", End: "18:00", BytesPerSecond: 100},
+		},
+	}, clk)
+	require.Nil(t, err)
+	defer l.Close()
+
+	// In the window crossing midnight.
+	require.Equal(t, limits{rows: 10}, l.current)
+	require.True(t, l.Admit(10, 1<<20))
+	require.False(t, l.Admit(1, 1))
+
+	// Out of all windows, it's unlimited.
+	clk.Add(2 * time.Hour)
+	for i := 0; i < 10; i++ {
+		require.True(t, l.Admit(1000, 1<<20))
+	}
+	require.Equal(t, limits{}, l.current)
+
+	// In the window of business hours.
+	clk.Add(8 * time.Hour)
+	require.True(t, l.Admit(1000, 100))
+	require.False(t, l.Admit(1, 1))
+	require.Equal(t, limits{bytes: 100}, l.current)
+}
+
+func TestLimiterProcessorShare(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	l, err := newLimiter(model.DefaultChangeFeedID("test"), &config.RateLimitConfig{
+		RowsPerSecond: 100,
+	}, clk)
+	require.Nil(t, err)
+	defer l.Close()
+
+	// The limits are shared by 3 processors, and rounded up.
+	l.SetProcessorCount(3)
+	clk.Add(time.Second)
+	require.True(t, l.Admit(34, 1))
+	require.Equal(t, limits{rows: 34}, l.current)
+	require.False(t, l.Admit(1, 1))
+	clk.Add(time.Second)
+	require.True(t, l.Admit(1, 1))
+
+	// Invalid counts are ignored.
+	l.SetProcessorCount(0)
+	clk.Add(time.Second)
+	require.True(t, l.Admit(1, 1))
+	require.Equal(t, limits{rows: 34}, l.current)
+
+	l.SetProcessorCount(1)
+	clk.Add(time.Second)
+	require.True(t, l.Admit(1, 1))
+	require.Equal(t, limits{rows: 100}, l.current)
+}
+
+func TestLimiterPause(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	// The mock clock starts at 00:00:00 UTC.
+	l, err := newLimiter(model.DefaultChangeFeedID("test"), &config.RateLimitConfig{
+		TimeZone: "UTC",
+		Schedules: []*config.RateLimitSchedule{
+			{Start: "00:00", End: "01:00", Pause: true},
+		},
+	}, clk)
+	require.Nil(t, err)
+	defer l.Close()
+
+	require.False(t, l.Admit(1, 1))
+	clk.Add(30 * time.Minute)
+	require.False(t, l.Admit(1, 1))
+
+	// It's unlimited after the paused window.
+	clk.Add(30 * time.Minute)
+	require.True(t, l.Admit(1000, 1<<20))
+	require.True(t, l.Admit(1000, 1<<20))
+}
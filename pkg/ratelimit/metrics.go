@@ -0,0 +1,46 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	throttledCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "rate_limit",
+			Name:      "throttled_count",
+			Help:      "The total count of flushes of table sinks delayed by the rate limit.",
+		}, []string{"namespace", "changefeed"})
+	limitGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "rate_limit",
+			Name:      "limit",
+			Help:      "The rate limit in effect, 0 means unlimited.",
+		}, []string{"namespace", "changefeed", "type"})
+)
+
+const (
+	limitTypeRows  = "rows"
+	limitTypeBytes = "bytes"
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(throttledCounter)
+	registry.MustRegister(limitGauge)
+}
@@ -32,6 +32,20 @@ type Credential struct {
 	CertPath      string   `toml:"cert-path" json:"cert-path"`
 	KeyPath       string   `toml:"key-path" json:"key-path"`
 	CertAllowedCN []string `toml:"cert-allowed-cn" json:"cert-allowed-cn"`
+
+	// ClientUserRequired indicates whether clients of the HTTP API must
+	// provide the username and password of a user of the upstream TiDB.
+	ClientUserRequired bool `toml:"client-user-required" json:"client-user-required"`
+	// ClientAllowedUser is the list of users allowed to call the HTTP API,
+	// all users of the upstream TiDB are allowed if it's empty.
+	ClientAllowedUser []string `toml:"client-allowed-user" json:"client-allowed-user"`
+	// ClientRoles maps users or common names of client certificates to
+	// their roles of the HTTP API.
+	ClientRoles map[string]Role `toml:"client-roles" json:"client-roles"`
+	// ClientDefaultRole is the role of authenticated clients not in
+	// ClientRoles. It's RoleReadOnly if it's empty, so clients must be
+	// granted a role explicitly to change anything.
+	ClientDefaultRole Role `toml:"client-default-role" json:"client-default-role"`
 }
 
 // IsTLSEnabled checks whether TLS is enabled or not.
@@ -44,6 +58,44 @@ func (s *Credential) IsEmpty() bool {
 	return len(s.CAPath) == 0 && len(s.CertPath) == 0 && len(s.KeyPath) == 0
 }
 
+// IsMutualTLSEnabled returns whether clients must provide certificates with
+// the allowed common names.
+func (s *Credential) IsMutualTLSEnabled() bool {
+	return s.IsTLSEnabled() && len(s.CertAllowedCN) != 0
+}
+
+// IsClientAuthEnabled returns whether clients of the HTTP API must be
+// authenticated, either by certificates or by TiDB users.
+func (s *Credential) IsClientAuthEnabled() bool {
+	return s.IsMutualTLSEnabled() || s.ClientUserRequired
+}
+
+// IsClientUserAllowed returns whether the user is allowed to call the HTTP
+// API.
+func (s *Credential) IsClientUserAllowed(user string) bool {
+	if len(s.ClientAllowedUser) == 0 {
+		return true
+	}
+	for _, u := range s.ClientAllowedUser {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientRole returns the role of an authenticated client of the HTTP API,
+// the identity is either a user or a common name of a client certificate.
+func (s *Credential) ClientRole(identity string) Role {
+	if role, ok := s.ClientRoles[identity]; ok {
+		return role
+	}
+	if s.ClientDefaultRole == "" {
+		return RoleReadOnly
+	}
+	return s.ClientDefaultRole
+}
+
 // PDSecurityOption creates a new pd SecurityOption from Security
 func (s *Credential) PDSecurityOption() pd.SecurityOption {
 	return pd.SecurityOption{
@@ -75,7 +127,9 @@ func (s *Credential) ToTLSConfigWithVerify() (*tls.Config, error) {
 	return cfg, cerror.WrapError(cerror.ErrToTLSConfigFailed, err)
 }
 
-func (s *Credential) getSelfCommonName() (string, error) {
+// SelfCommonName returns the Common Name in certificate that specified by
+// s.CertPath, it's empty if the certificate is not specified.
+func (s *Credential) SelfCommonName() (string, error) {
 	if s.CertPath == "" {
 		return "", nil
 	}
@@ -97,7 +151,7 @@ func (s *Credential) getSelfCommonName() (string, error) {
 // AddSelfCommonName add Common Name in certificate that specified by s.CertPath
 // to s.CertAllowedCN
 func (s *Credential) AddSelfCommonName() error {
-	cn, err := s.getSelfCommonName()
+	cn, err := s.SelfCommonName()
 	if err != nil {
 		return err
 	}
@@ -25,12 +25,12 @@ func TestGetCommonName(t *testing.T) {
 		CertPath: "../../tests/integration_tests/_certificates/server.pem",
 		KeyPath:  "../../tests/integration_tests/_certificates/server-key.pem",
 	}
-	cn, err := cd.getSelfCommonName()
+	cn, err := cd.SelfCommonName()
 	require.Nil(t, err)
 	require.Equal(t, "tidb-server", cn)
 
 	cd.CertPath = "../../tests/integration_tests/_certificates/server-key.pem"
-	_, err = cd.getSelfCommonName()
+	_, err = cd.SelfCommonName()
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "failed to decode PEM block to certificate")
 }
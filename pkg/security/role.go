@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+// Role is the role of a client of the HTTP API, a role is granted all
+// permissions of the roles lower than it.
+type Role string
+
+const (
+	// RoleReadOnly can only query the status of the cluster.
+	RoleReadOnly Role = "read-only"
+	// RoleOperator can also manage changefeeds and captures.
+	RoleOperator Role = "operator"
+	// RoleUnsafe can also call the unsafe APIs.
+	RoleUnsafe Role = "unsafe"
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleReadOnly:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleUnsafe:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// IsValid returns whether the role is known.
+func (r Role) IsValid() bool {
+	return r.level() > 0
+}
+
+// Allows returns whether the role is granted the permissions of the required
+// role.
+func (r Role) Allows(required Role) bool {
+	return r.IsValid() && r.level() >= required.level()
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, RoleReadOnly.Allows(RoleReadOnly))
+	require.False(t, RoleReadOnly.Allows(RoleOperator))
+	require.True(t, RoleOperator.Allows(RoleReadOnly))
+	require.False(t, RoleOperator.Allows(RoleUnsafe))
+	require.True(t, RoleUnsafe.Allows(RoleOperator))
+	require.False(t, Role("admin").IsValid())
+	require.False(t, Role("admin").Allows(RoleReadOnly))
+}
+
+func TestClientRole(t *testing.T) {
+	t.Parallel()
+
+	cd := &Credential{}
+	require.False(t, cd.IsClientAuthEnabled())
+	require.True(t, cd.IsClientUserAllowed("root"))
+	require.Equal(t, RoleReadOnly, cd.ClientRole("root"))
+
+	cd.ClientUserRequired = true
+	cd.ClientAllowedUser = []string{"root", "reader"}
+	cd.ClientRoles = map[string]Role{"reader": RoleReadOnly}
+	cd.ClientDefaultRole = RoleOperator
+	require.True(t, cd.IsClientAuthEnabled())
+	require.True(t, cd.IsClientUserAllowed("reader"))
+	require.False(t, cd.IsClientUserAllowed("writer"))
+	require.Equal(t, RoleReadOnly, cd.ClientRole("reader"))
+	require.Equal(t, RoleOperator, cd.ClientRole("root"))
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	tifilter "github.com/pingcap/tidb/util/filter"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	dmparser "github.com/pingcap/tiflow/dm/pkg/parser"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	schemaPlaceholder = "{schema}"
+	tablePlaceholder  = "{table}"
+)
+
+type rule struct {
+	filter       tfilter.Filter
+	targetSchema string
+	targetTable  string
+}
+
+// Router routes tables of the upstream to tables of the downstream with
+// other names. A table is routed by the first rule matching it, tables
+// matching no rules keep their names. A nil Router routes nothing.
+type Router struct {
+	rules []*rule
+	// cache caches the routed names of tables, the key is the quoted
+	// upstream table name.
+	cache sync.Map
+}
+
+// NewRouter creates a Router from the routing rules.
+func NewRouter(caseSensitive bool, rules []*config.RoutingRule) (*Router, error) {
+	r := &Router{rules: make([]*rule, 0, len(rules))}
+	for _, ruleConfig := range rules {
+		f, err := tfilter.Parse(ruleConfig.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, ruleConfig)
+		}
+		if !caseSensitive {
+			f = tfilter.CaseInsensitive(f)
+		}
+		targetTable := ruleConfig.TargetTable
+		if targetTable == "" {
+			targetTable = tablePlaceholder
+		}
+		r.rules = append(r.rules, &rule{
+			filter:       f,
+			targetSchema: ruleConfig.TargetSchema,
+			targetTable:  targetTable,
+		})
+	}
+	return r, nil
+}
+
+// Route returns the downstream schema and table of the upstream table.
+// If table is empty, only the schema is routed.
+func (r *Router) Route(schema, table string) (string, string) {
+	if r == nil {
+		return schema, table
+	}
+	for _, rule := range r.rules {
+		if table == "" {
+			if rule.filter.MatchSchema(schema) {
+				return substitute(rule.targetSchema, schema, table), ""
+			}
+			continue
+		}
+		if rule.filter.MatchTable(schema, table) {
+			return substitute(rule.targetSchema, schema, table),
+				substitute(rule.targetTable, schema, table)
+		}
+	}
+	return schema, table
+}
+
+// RouteTableName returns the routed name of the table, the returned name is
+// the same one if the table is not routed.
+func (r *Router) RouteTableName(name *model.TableName) *model.TableName {
+	if r == nil || len(r.rules) == 0 {
+		return name
+	}
+	key := name.QuoteString()
+	if routed, ok := r.cache.Load(key); ok {
+		return routed.(*model.TableName)
+	}
+	schema, table := r.Route(name.Schema, name.Table)
+	routed := name
+	if schema != name.Schema || table != name.Table {
+		routed = &model.TableName{
+			Schema:      schema,
+			Table:       table,
+			TableID:     name.TableID,
+			IsPartition: name.IsPartition,
+		}
+	}
+	r.cache.Store(key, routed)
+	return routed
+}
+
+// RouteTableNames returns the routed names of the tables.
+func (r *Router) RouteTableNames(names []model.TableName) []model.TableName {
+	if r == nil || len(r.rules) == 0 {
+		return names
+	}
+	res := make([]model.TableName, 0, len(names))
+	for i := range names {
+		schema, table := r.Route(names[i].Schema, names[i].Table)
+		res = append(res, model.TableName{
+			Schema:      schema,
+			Table:       table,
+			TableID:     names[i].TableID,
+			IsPartition: names[i].IsPartition,
+		})
+	}
+	return res
+}
+
+// NewRowChange creates a RowChange of the upstream table whose target table
+// is the routed table, writers should create RowChanges by the Router so that
+// rows are routed consistently. downstreamTableInfo and tiCtx can be nil, see
+// the function NewRowChange.
+func (r *Router) NewRowChange(
+	sourceTable *model.TableName,
+	preValues []interface{},
+	postValues []interface{},
+	sourceTableInfo *timodel.TableInfo,
+	downstreamTableInfo *timodel.TableInfo,
+	tiCtx sessionctx.Context,
+) *RowChange {
+	return NewRowChange(sourceTable, r.RouteTableName(sourceTable),
+		preValues, postValues, sourceTableInfo, downstreamTableInfo, tiCtx)
+}
+
+// RouteDDL returns the DDL event executed on the routed tables. The event
+// itself is not changed, a copy is returned if any tables are routed.
+func (r *Router) RouteDDL(ddl *model.DDLEvent) (*model.DDLEvent, error) {
+	if r == nil || len(r.rules) == 0 {
+		return ddl, nil
+	}
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRouteDDLFailed, err, ddl.Query)
+	}
+	schema := ""
+	if ddl.TableInfo != nil {
+		schema = ddl.TableInfo.Schema
+	}
+	tables, err := dmparser.FetchDDLTables(schema, stmt, utils.LCTableNamesSensitive)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRouteDDLFailed, err, ddl.Query)
+	}
+	targets := make([]*tifilter.Table, 0, len(tables))
+	routed := false
+	for _, t := range tables {
+		targetSchema, targetTable := r.Route(t.Schema, t.Name)
+		if targetSchema != t.Schema || targetTable != t.Name {
+			routed = true
+		}
+		targets = append(targets, &tifilter.Table{Schema: targetSchema, Name: targetTable})
+	}
+	// The tables referenced by foreign keys are not returned by FetchDDLTables,
+	// they are routed in place so that the constraints point to the routed
+	// tables too.
+	refs := &referenceRouter{router: r, schema: schema}
+	stmt.Accept(refs)
+	if !routed && !refs.routed {
+		return ddl, nil
+	}
+	query, err := dmparser.RenameDDLTable(stmt, targets)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRouteDDLFailed, err, ddl.Query)
+	}
+	res := *ddl
+	res.Query = query
+	res.TableInfo = r.routeTableInfo(ddl.TableInfo)
+	res.PreTableInfo = r.routeTableInfo(ddl.PreTableInfo)
+	return &res, nil
+}
+
+func (r *Router) routeTableInfo(info *model.SimpleTableInfo) *model.SimpleTableInfo {
+	if info == nil {
+		return nil
+	}
+	res := *info
+	res.Schema, res.Table = r.Route(info.Schema, info.Table)
+	return &res
+}
+
+// referenceRouter routes the tables referenced by foreign keys in place.
+type referenceRouter struct {
+	router *Router
+	schema string
+	routed bool
+}
+
+func (v *referenceRouter) Enter(in ast.Node) (ast.Node, bool) {
+	ref, ok := in.(*ast.ReferenceDef)
+	if !ok || ref.Table == nil {
+		return in, false
+	}
+	schema := ref.Table.Schema.O
+	if schema == "" {
+		schema = v.schema
+	}
+	targetSchema, targetTable := v.router.Route(schema, ref.Table.Name.O)
+	if targetSchema != schema || targetTable != ref.Table.Name.O {
+		ref.Table.Schema = timodel.NewCIStr(targetSchema)
+		ref.Table.Name = timodel.NewCIStr(targetTable)
+		v.routed = true
+	}
+	return in, true
+}
+
+func (v *referenceRouter) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func substitute(expr, schema, table string) string {
+	return strings.NewReplacer(
+		schemaPlaceholder, schema, tablePlaceholder, table).Replace(expr)
+}
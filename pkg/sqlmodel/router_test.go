@@ -0,0 +1,171 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRouter(true, []*config.RoutingRule{
+		{Matcher: []string{"shard_*.orders"}, TargetSchema: "merged", TargetTable: "orders"},
+		{Matcher: []string{"prod.*"}, TargetSchema: "{schema}_replica"},
+	})
+	require.Nil(t, err)
+
+	schema, table := r.Route("shard_1", "orders")
+	require.Equal(t, "merged", schema)
+	require.Equal(t, "orders", table)
+	schema, table = r.Route("prod", "t1")
+	require.Equal(t, "prod_replica", schema)
+	require.Equal(t, "t1", table)
+	schema, table = r.Route("prod", "")
+	require.Equal(t, "prod_replica", schema)
+	require.Equal(t, "", table)
+	schema, table = r.Route("test", "t1")
+	require.Equal(t, "test", schema)
+	require.Equal(t, "t1", table)
+
+	name := &model.TableName{Schema: "prod", Table: "t1", TableID: 1}
+	routed := r.RouteTableName(name)
+	require.Equal(t, &model.TableName{Schema: "prod_replica", Table: "t1", TableID: 1}, routed)
+	require.Same(t, routed, r.RouteTableName(name))
+	name = &model.TableName{Schema: "test", Table: "t1", TableID: 2}
+	require.Same(t, name, r.RouteTableName(name))
+
+	require.Equal(t, []model.TableName{
+		{Schema: "merged", Table: "orders", TableID: 3},
+		{Schema: "test", Table: "t1", TableID: 2},
+	}, r.RouteTableNames([]model.TableName{
+		{Schema: "shard_2", Table: "orders", TableID: 3},
+		{Schema: "test", Table: "t1", TableID: 2},
+	}))
+
+	// Tables are not routed case insensitively by default.
+	schema, _ = r.Route("PROD", "t1")
+	require.Equal(t, "PROD", schema)
+	r, err = NewRouter(false, []*config.RoutingRule{
+		{Matcher: []string{"prod.*"}, TargetSchema: "prod_replica"},
+	})
+	require.Nil(t, err)
+	schema, _ = r.Route("PROD", "t1")
+	require.Equal(t, "prod_replica", schema)
+
+	_, err = NewRouter(true, []*config.RoutingRule{
+		{Matcher: []string{"prod.*.*"}, TargetSchema: "prod_replica"},
+	})
+	require.NotNil(t, err)
+}
+
+func TestRouteDDL(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRouter(true, []*config.RoutingRule{
+		{Matcher: []string{"prod.*"}, TargetSchema: "prod_replica"},
+	})
+	require.Nil(t, err)
+
+	ddl := &model.DDLEvent{
+		Query:     "ALTER TABLE t1 ADD COLUMN c1 INT",
+		TableInfo: &model.SimpleTableInfo{Schema: "prod", Table: "t1"},
+	}
+	routed, err := r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Equal(t, "ALTER TABLE `prod_replica`.`t1` ADD COLUMN `c1` INT", routed.Query)
+	require.Equal(t, "prod_replica", routed.TableInfo.Schema)
+	// The event itself is not changed.
+	require.Equal(t, "ALTER TABLE t1 ADD COLUMN c1 INT", ddl.Query)
+	require.Equal(t, "prod", ddl.TableInfo.Schema)
+
+	ddl = &model.DDLEvent{
+		Query:        "RENAME TABLE `prod`.`t1` TO `prod`.`t2`",
+		TableInfo:    &model.SimpleTableInfo{Schema: "prod", Table: "t2"},
+		PreTableInfo: &model.SimpleTableInfo{Schema: "prod", Table: "t1"},
+	}
+	routed, err = r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Equal(t, "RENAME TABLE `prod_replica`.`t1` TO `prod_replica`.`t2`", routed.Query)
+	require.Equal(t, "prod_replica", routed.PreTableInfo.Schema)
+
+	ddl = &model.DDLEvent{
+		Query:     "CREATE DATABASE `prod`",
+		TableInfo: &model.SimpleTableInfo{Schema: "prod"},
+	}
+	routed, err = r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Equal(t, "CREATE DATABASE `prod_replica`", routed.Query)
+
+	// Tables referenced by foreign keys are routed too.
+	ddl = &model.DDLEvent{
+		Query: "CREATE TABLE `prod`.`t3` (`a` INT, FOREIGN KEY (`a`) " +
+			"REFERENCES `prod`.`t1`(`id`))",
+		TableInfo: &model.SimpleTableInfo{Schema: "prod", Table: "t3"},
+	}
+	routed, err = r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Contains(t, routed.Query, "CREATE TABLE `prod_replica`.`t3`")
+	require.Contains(t, routed.Query, "REFERENCES `prod_replica`.`t1`")
+
+	// Views are created on the routed tables.
+	ddl = &model.DDLEvent{
+		Query:     "CREATE VIEW `prod`.`v1` AS SELECT * FROM `prod`.`t1`",
+		TableInfo: &model.SimpleTableInfo{Schema: "prod", Table: "v1"},
+	}
+	routed, err = r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Contains(t, routed.Query, "`prod_replica`.`v1`")
+	require.Contains(t, routed.Query, "FROM `prod_replica`.`t1`")
+
+	// The query is kept if no tables are routed.
+	query := "alter table test.t1 add column c1 int"
+	ddl = &model.DDLEvent{
+		Query:     query,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	}
+	routed, err = r.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Same(t, ddl, routed)
+
+	ddl = &model.DDLEvent{Query: "not a ddl"}
+	_, err = r.RouteDDL(ddl)
+	require.NotNil(t, err)
+}
+
+func TestRouterNewRowChange(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRouter(true, []*config.RoutingRule{
+		{Matcher: []string{"prod.*"}, TargetSchema: "{schema}_replica"},
+	})
+	require.Nil(t, err)
+	source := &model.TableName{Schema: "prod", Table: "tbl"}
+	sourceTI := mockTableInfo(t, "CREATE TABLE tbl (id INT PRIMARY KEY, name INT)")
+
+	change := r.NewRowChange(source, nil, []interface{}{1, 2}, sourceTI, nil, nil)
+	require.Equal(t, "`prod_replica`.`tbl`", change.TargetTableID())
+	sql, args := change.GenSQL(DMLInsert)
+	require.Equal(t, "INSERT INTO `prod_replica`.`tbl` (`id`,`name`) VALUES (?,?)", sql)
+	require.Equal(t, []interface{}{1, 2}, args)
+
+	// A nil Router routes nothing.
+	r = nil
+	change = r.NewRowChange(source, nil, []interface{}{1, 2}, sourceTI, nil, nil)
+	require.Equal(t, "`prod`.`tbl`", change.TargetTableID())
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	serviceName        = "ticdc"
+	instrumentationLib = "github.com/pingcap/tiflow"
+	// shutdownTimeout is the timeout for exporting the remaining spans
+	// when tracing is stopped.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Run enables tracing and exports the spans to the OTLP collector until the
+// context is done. The connection is secured by tlsConfig if it's not nil.
+func Run(ctx context.Context, cfg *config.TracingConfig, tlsConfig *tls.Config) error {
+	opts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(cfg.OTLPEndpoint)}
+	if tlsConfig != nil {
+		opts = append(opts, otlpgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlpgrpc.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(serviceName))),
+	)
+	setTracer(newTracer(provider.Tracer(instrumentationLib), cfg.SampleRate))
+	log.Info("tracing is enabled",
+		zap.String("endpoint", cfg.OTLPEndpoint),
+		zap.Float64("sampleRate", cfg.SampleRate))
+
+	<-ctx.Done()
+	setTracer(nil)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := provider.Shutdown(shutdownCtx); err != nil {
+		log.Warn("failed to shutdown tracing", zap.Error(err))
+	}
+	return errors.Trace(ctx.Err())
+}
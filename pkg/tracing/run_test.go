@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// mockCollector is a stand-in of the OTLP collector, it keeps the names of
+// the received spans.
+type mockCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (c *mockCollector) Export(
+	_ context.Context, req *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, span := range ils.Spans {
+				c.names = append(c.names, span.Name)
+			}
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func (c *mockCollector) spanNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.names...)
+}
+
+func TestRunExportsToCollector(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	collector := &mockCollector{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, collector)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, &config.TracingConfig{
+			Enable:       true,
+			OTLPEndpoint: lis.Addr().String(),
+			SampleRate:   1,
+		}, nil)
+	}()
+	require.Eventually(t, func() bool { return Sampled(1) }, 5*time.Second, 10*time.Millisecond)
+
+	changefeed := model.DefaultChangeFeedID("test")
+	commitTs := uint64(time.Now().UnixMilli()) << physicalShiftBits
+	RecordStage(changefeed, 1, commitTs, StageKVClient)
+	RecordStage(changefeed, 1, commitTs, StageMounter)
+	FinishTxns(changefeed, 1, commitTs)
+
+	// The remaining spans are exported when tracing is stopped.
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.False(t, Sampled(1))
+	require.ElementsMatch(t, []string{
+		StageKVClient, StageMounter, StageSinkFlush, txnSpanName,
+	}, collector.spanNames())
+}
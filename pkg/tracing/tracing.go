@@ -0,0 +1,246 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Stages of the replication pipeline that row events go through, each of
+// them is traced as a span of the transaction.
+const (
+	StageKVClient  = "kv-client"
+	StagePuller    = "puller"
+	StageSorter    = "sorter"
+	StageMounter   = "mounter"
+	StagePipeline  = "pipeline"
+	StageSinkFlush = "sink-flush"
+)
+
+const (
+	// txnSpanName is the name of the root span of a transaction, it starts
+	// at the physical time of the commit ts and ends when it's flushed.
+	txnSpanName = "txn"
+	// maxInflightTxns is the maximum number of transactions being traced.
+	maxInflightTxns = 10000
+	// inflightTxnTTL is the duration after which a transaction not flushed
+	// is given up, e.g., its table is removed from the capture.
+	inflightTxnTTL = 10 * time.Minute
+	// physicalShiftBits is the number of bits of the logical part of a ts.
+	physicalShiftBits = 18
+)
+
+// global is the tracer of the process, it's nil if tracing is disabled.
+var global atomic.Value
+
+func getTracer() *tracer {
+	t, _ := global.Load().(*tracer)
+	return t
+}
+
+func setTracer(t *tracer) {
+	global.Store(t)
+}
+
+type tableKey struct {
+	changefeed model.ChangeFeedID
+	tableID    model.TableID
+}
+
+// txnTrace is a transaction of a table being traced.
+type txnTrace struct {
+	ctx     context.Context
+	root    trace.Span
+	lastEnd time.Time
+	stages  map[string]struct{}
+}
+
+// tracer records spans of transactions sampled by their commit ts. Stages of
+// a table pipeline run in different goroutines and pass events by channels,
+// so the transactions being traced are kept in the tracer, and each stage
+// is recorded once for a transaction no matter how many rows it has.
+type tracer struct {
+	tracer trace.Tracer
+	// A transaction is sampled if its commit ts is hashed below threshold.
+	threshold uint64
+
+	inflight int64
+	mu       sync.Mutex
+	tables   map[tableKey]map[uint64]*txnTrace
+}
+
+func newTracer(t trace.Tracer, sampleRate float64) *tracer {
+	threshold := uint64(math.MaxUint64)
+	if sampleRate < 1 {
+		threshold = uint64(sampleRate * math.MaxUint64)
+	}
+	return &tracer{
+		tracer:    t,
+		threshold: threshold,
+		tables:    make(map[tableKey]map[uint64]*txnTrace),
+	}
+}
+
+// mix is the finalizer of splitmix64, which spreads the commit ts evenly,
+// since the low bits of a ts are the logical part which are mostly zero.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (t *tracer) sampled(commitTs uint64) bool {
+	return t.threshold == math.MaxUint64 || mix(commitTs) < t.threshold
+}
+
+func (t *tracer) recordStage(key tableKey, commitTs uint64, stage string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txns := t.tables[key]
+	txn, ok := txns[commitTs]
+	if !ok {
+		if !t.reserve(now) {
+			return
+		}
+		start := time.UnixMilli(int64(commitTs >> physicalShiftBits))
+		if start.After(now) {
+			start = now
+		}
+		ctx, root := t.tracer.Start(context.Background(), txnSpanName,
+			trace.WithTimestamp(start),
+			trace.WithAttributes(
+				attribute.String("namespace", key.changefeed.Namespace),
+				attribute.String("changefeed", key.changefeed.ID),
+				attribute.Int64("table_id", key.tableID),
+				attribute.Int64("commit_ts", int64(commitTs)),
+			))
+		txn = &txnTrace{
+			ctx:     ctx,
+			root:    root,
+			lastEnd: start,
+			stages:  make(map[string]struct{}),
+		}
+		if txns == nil {
+			txns = make(map[uint64]*txnTrace)
+			t.tables[key] = txns
+		}
+		txns[commitTs] = txn
+		atomic.AddInt64(&t.inflight, 1)
+	}
+	t.record(txn, stage, now)
+}
+
+// record records the stage of the transaction, which starts at the end of
+// the previous stage.
+func (t *tracer) record(txn *txnTrace, stage string, now time.Time) {
+	if _, ok := txn.stages[stage]; ok {
+		return
+	}
+	txn.stages[stage] = struct{}{}
+	if now.Before(txn.lastEnd) {
+		now = txn.lastEnd
+	}
+	_, span := t.tracer.Start(txn.ctx, stage, trace.WithTimestamp(txn.lastEnd))
+	span.End(trace.WithTimestamp(now))
+	txn.lastEnd = now
+}
+
+// reserve makes room for a new transaction by giving up expired ones, and
+// returns false if there are too many transactions being traced.
+func (t *tracer) reserve(now time.Time) bool {
+	if atomic.LoadInt64(&t.inflight) < maxInflightTxns {
+		return true
+	}
+	for key, txns := range t.tables {
+		for commitTs, txn := range txns {
+			if now.Sub(txn.lastEnd) < inflightTxnTTL {
+				continue
+			}
+			txn.root.SetAttributes(attribute.Bool("incomplete", true))
+			txn.root.End(trace.WithTimestamp(txn.lastEnd))
+			delete(txns, commitTs)
+			atomic.AddInt64(&t.inflight, -1)
+		}
+		if len(txns) == 0 {
+			delete(t.tables, key)
+		}
+	}
+	return atomic.LoadInt64(&t.inflight) < maxInflightTxns
+}
+
+func (t *tracer) finishTxns(key tableKey, checkpointTs uint64, now time.Time) {
+	if atomic.LoadInt64(&t.inflight) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txns := t.tables[key]
+	for commitTs, txn := range txns {
+		if commitTs > checkpointTs {
+			continue
+		}
+		t.record(txn, StageSinkFlush, now)
+		txn.root.End(trace.WithTimestamp(txn.lastEnd))
+		delete(txns, commitTs)
+		atomic.AddInt64(&t.inflight, -1)
+	}
+	if len(txns) == 0 {
+		delete(t.tables, key)
+	}
+}
+
+// Sampled returns true if tracing is enabled and the transaction of the
+// commit ts is sampled.
+func Sampled(commitTs uint64) bool {
+	t := getTracer()
+	return t != nil && t.sampled(commitTs)
+}
+
+// RecordStage records that a row event of the transaction has gone through
+// the stage, if the transaction is sampled. The span of the stage starts when
+// the previous stage is recorded, and ends now.
+func RecordStage(
+	changefeed model.ChangeFeedID, tableID model.TableID, commitTs uint64, stage string,
+) {
+	t := getTracer()
+	if t == nil || !t.sampled(commitTs) {
+		return
+	}
+	key := tableKey{changefeed: changefeed, tableID: tableID}
+	t.recordStage(key, commitTs, stage, time.Now())
+}
+
+// FinishTxns records the sink flush stage of the transactions of the table
+// that are not greater than the checkpoint ts, and ends their traces.
+func FinishTxns(changefeed model.ChangeFeedID, tableID model.TableID, checkpointTs uint64) {
+	t := getTracer()
+	if t == nil {
+		return
+	}
+	key := tableKey{changefeed: changefeed, tableID: tableID}
+	t.finishTxns(key, checkpointTs, time.Now())
+}
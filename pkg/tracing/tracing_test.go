@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracer(sampleRate float64) (*tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return newTracer(provider.Tracer(instrumentationLib), sampleRate), exporter
+}
+
+func TestSampled(t *testing.T) {
+	t.Parallel()
+
+	tr, _ := newTestTracer(1)
+	for ts := uint64(0); ts < 100; ts++ {
+		require.True(t, tr.sampled(ts<<physicalShiftBits))
+	}
+
+	// Commit ts are sampled evenly even if their logical parts are zero.
+	tr, _ = newTestTracer(0.1)
+	sampled := 0
+	for ts := uint64(1); ts <= 10000; ts++ {
+		if tr.sampled(ts << physicalShiftBits) {
+			sampled++
+		}
+	}
+	require.InDelta(t, 1000, sampled, 150)
+
+	// Tracing is disabled by default.
+	require.False(t, Sampled(1))
+	RecordStage(model.DefaultChangeFeedID("test"), 1, 1, StageKVClient)
+	FinishTxns(model.DefaultChangeFeedID("test"), 1, 1)
+}
+
+func TestRecordStages(t *testing.T) {
+	t.Parallel()
+
+	tr, exporter := newTestTracer(1)
+	key := tableKey{changefeed: model.DefaultChangeFeedID("test"), tableID: 42}
+	commitTime := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+	commitTs := uint64(commitTime.UnixMilli()) << physicalShiftBits
+
+	now := commitTime
+	for _, stage := range []string{
+		StageKVClient, StagePuller, StageSorter, StageMounter, StagePipeline,
+	} {
+		now = now.Add(100 * time.Millisecond)
+		// Each stage is recorded once for a transaction of multiple rows.
+		tr.recordStage(key, commitTs, stage, now)
+		tr.recordStage(key, commitTs, stage, now.Add(time.Millisecond))
+	}
+	// Another transaction is not flushed.
+	tr.recordStage(key, commitTs+1, StageKVClient, now)
+	require.Len(t, exporter.GetSpans(), 6)
+
+	tr.finishTxns(key, commitTs, now.Add(100*time.Millisecond))
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 8)
+	root := spans[7]
+	require.Equal(t, txnSpanName, root.Name)
+	require.Equal(t, commitTime, root.StartTime)
+	require.Equal(t, commitTime.Add(600*time.Millisecond), root.EndTime)
+
+	stages := make([]string, 0, 6)
+	for _, span := range spans {
+		if span.SpanContext.TraceID() != root.SpanContext.TraceID() || span == root {
+			continue
+		}
+		require.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID())
+		require.Equal(t, 100*time.Millisecond, span.EndTime.Sub(span.StartTime))
+		stages = append(stages, span.Name)
+	}
+	require.Equal(t, []string{
+		StageKVClient, StagePuller, StageSorter, StageMounter, StagePipeline, StageSinkFlush,
+	}, stages)
+	require.EqualValues(t, 1, tr.inflight)
+
+	tr.finishTxns(key, commitTs+1, now)
+	require.EqualValues(t, 0, tr.inflight)
+	require.Empty(t, tr.tables)
+}
+
+func TestGiveUpExpiredTxns(t *testing.T) {
+	t.Parallel()
+
+	tr, exporter := newTestTracer(1)
+	key := tableKey{changefeed: model.DefaultChangeFeedID("test"), tableID: 42}
+	now := time.Now()
+	for i := 0; i < maxInflightTxns; i++ {
+		tr.recordStage(key, uint64(i), StageKVClient, now)
+	}
+	// No room for new transactions.
+	tr.recordStage(key, maxInflightTxns, StageKVClient, now)
+	require.EqualValues(t, maxInflightTxns, tr.inflight)
+	require.NotContains(t, tr.tables[key], uint64(maxInflightTxns))
+
+	// Expired transactions are given up.
+	exporter.Reset()
+	tr.recordStage(key, maxInflightTxns, StageKVClient, now.Add(inflightTxnTTL))
+	require.EqualValues(t, 1, tr.inflight)
+	require.Len(t, exporter.GetSpans(), maxInflightTxns+1)
+}
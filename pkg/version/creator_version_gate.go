@@ -92,3 +92,27 @@ func (g *CreatorVersionGate) ChangefeedAcceptProtocolInMysqlSinURI() bool {
 	creatorVersion := semver.New(removeVAndHash(g.version))
 	return creatorVersion.LessThan(changefeedAcceptProtocolInMysqlSinURI)
 }
+
+// CaptureCompatible determines whether tables can be scheduled onto a capture
+// of the given version, when the gate is created by the version of the owner.
+// In a mixed-version cluster during rolling upgrades, a capture older than the
+// owner in major or minor version may not support what the owner requires, so
+// it should only keep the tables it has until it's drained.
+func (g *CreatorVersionGate) CaptureCompatible(captureVersion string) bool {
+	if g.version == "" || captureVersion == "" {
+		return true
+	}
+
+	ownerVersion, err := semver.NewVersion(removeVAndHash(g.version))
+	if err != nil {
+		return true
+	}
+	version, err := semver.NewVersion(removeVAndHash(captureVersion))
+	if err != nil {
+		return false
+	}
+	if version.Major != ownerVersion.Major {
+		return version.Major > ownerVersion.Major
+	}
+	return version.Minor >= ownerVersion.Minor
+}
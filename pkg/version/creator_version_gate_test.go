@@ -195,3 +195,29 @@ func TestChangefeedAcceptProtocolInMysqlSinURI(t *testing.T) {
 		require.Equal(t, tc.expected, creatorVersionGate.ChangefeedAcceptProtocolInMysqlSinURI())
 	}
 }
+
+func TestCaptureCompatible(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		ownerVersion   string
+		captureVersion string
+		expected       bool
+	}{
+		{ownerVersion: "", captureVersion: "6.3.0", expected: true},
+		{ownerVersion: "6.4.0", captureVersion: "", expected: true},
+		{ownerVersion: "6.4.0", captureVersion: "6.4.0", expected: true},
+		{ownerVersion: "v6.4.1", captureVersion: "v6.4.0-1-g1234567", expected: true},
+		{ownerVersion: "6.4.0", captureVersion: "6.5.0-alpha", expected: true},
+		{ownerVersion: "6.4.0", captureVersion: "7.0.0", expected: true},
+		{ownerVersion: "6.4.0-alpha", captureVersion: "6.3.0", expected: false},
+		{ownerVersion: "7.0.0", captureVersion: "6.5.0", expected: false},
+		{ownerVersion: "6.4.0", captureVersion: "invalid", expected: false},
+	}
+
+	for _, tc := range testCases {
+		gate := NewCreatorVersionGate(tc.ownerVersion)
+		require.Equal(t, tc.expected, gate.CaptureCompatible(tc.captureVersion),
+			"owner %s, capture %s", tc.ownerVersion, tc.captureVersion)
+	}
+}